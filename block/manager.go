@@ -1014,22 +1014,28 @@ func (m *manager) finalize(bn *bnode, updatePCM bool) error {
 		m.bntr.TraceRef(bn)
 	}
 
-	err = block.(base.BlockVersionSpec).FinalizeHeader(m.chain.Database())
+	// header, height index and last height are written in a batch, so
+	// they are kept consistent on failure.
+	writer := db.NewWriter(m.db())
+	err = block.(base.BlockVersionSpec).FinalizeHeader(writer.Database())
 	if err != nil {
 		return err
 	}
-	nextVer := m.sm.GetNextBlockVersion(m.finalized.in.mtransition().Result())
-	if m.activeHandlers.last().Version() != nextVer {
-		m.activeHandlers = m.handlers.upTo(nextVer)
-	}
-
-	chainProp, err := m.bucketFor(db.ChainProperty)
+	chainProp, err := db.NewCodedBucket(writer.Database(), db.ChainProperty, nil)
 	if err != nil {
 		return err
 	}
 	if err = chainProp.Set(db.Raw(keyLastBlockHeight), block.Height()); err != nil {
 		return err
 	}
+	if err = writer.Flush(); err != nil {
+		return err
+	}
+
+	nextVer := m.sm.GetNextBlockVersion(m.finalized.in.mtransition().Result())
+	if m.activeHandlers.last().Version() != nextVer {
+		m.activeHandlers = m.handlers.upTo(nextVer)
+	}
 
 	if updatePCM {
 		nextPCM, err := m.nextPCM.Update(m.finalized.block)
@@ -347,7 +347,8 @@ func TestManager_ChangePubKey(t_ *testing.T) {
 	assert.NoError(err)
 	assert.True(ns.NextProofContextChanged())
 
-	_, err = nts.NextProofContext().NewProofPart(make([]byte, 32), wp)
+	pc := nts.NextProofContext()
+	_, err = pc.NewProofPart(pc.NewDecision([]byte("src"), 1, 1, 0, make([]byte, 32)), wp)
 	assert.NoError(err)
 }
 
@@ -46,13 +46,14 @@ func (r *finalizeRequest) finalize(blk module.BlockData) error {
 		return err
 	}
 
-	if err = blk.(base.BlockVersionSpec).FinalizeHeader(r.dbase); err != nil {
+	writer := db.NewWriter(r.dbase)
+	if err = blk.(base.BlockVersionSpec).FinalizeHeader(writer.Database()); err != nil {
 		return err
 	}
-	if err = txlocator.WriteTransactionLocators(r.dbase, blk.Height(), blk.PatchTransactions(), blk.NormalTransactions()); err != nil {
+	if err = txlocator.WriteTransactionLocators(writer.Database(), blk.Height(), blk.PatchTransactions(), blk.NormalTransactions()); err != nil {
 		return err
 	}
-	return nil
+	return writer.Flush()
 }
 
 func (r *finalizeRequest) OnValidate(t module.Transition, err error) {
@@ -0,0 +1,51 @@
+/*
+ * Copyright 2026 ICON Foundation
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package ntm
+
+import (
+	"github.com/icon-project/goloop/common/crypto"
+)
+
+const (
+	BLSDSA = "bls/bls12-381"
+)
+
+type blsDSAModule struct {
+}
+
+func (s blsDSAModule) Name() string {
+	return BLSDSA
+}
+
+func (s blsDSAModule) Verify(pubKey []byte) error {
+	_, err := crypto.ParseBLSPublicKey(pubKey)
+	return err
+}
+
+func (s blsDSAModule) Canonicalize(pubKey []byte) ([]byte, error) {
+	pk, err := crypto.ParseBLSPublicKey(pubKey)
+	if err != nil {
+		return nil, err
+	}
+	return pk.Bytes(), nil
+}
+
+var blsDSAModuleInstance blsDSAModule
+
+func init() {
+	registerDSAModule(blsDSAModuleInstance)
+}
@@ -0,0 +1,98 @@
+/*
+ * Copyright 2026 ICON Foundation
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package ntm
+
+import (
+	"crypto/sha256"
+
+	"github.com/icon-project/goloop/common/crypto"
+	"github.com/icon-project/goloop/common/db"
+	"github.com/icon-project/goloop/module"
+)
+
+// BLS module makes a proof with one aggregated signature and a bitmap of
+// signers, so the size of the proof doesn't grow with signatures. The
+// destination verifies the proof with public keys of validators, so keys
+// are used as addresses of validators. It uses SHA-256 for hashes as the
+// signature scheme does.
+
+const (
+	blsUID = "bls"
+
+	blsBytesByHash = "b" + db.BytesByHash
+	blsListByRoot  = "b" + db.ListByMerkleRootBase
+)
+
+func appendSHA256(out []byte, data ...[]byte) []byte {
+	d := sha256.New()
+	for _, b := range data {
+		d.Write(b)
+	}
+	return d.Sum(out)
+}
+
+var blsModuleInstance *networkTypeModule
+
+type blsModuleCore struct{}
+
+func (m *blsModuleCore) UID() string {
+	return blsUID
+}
+
+func (m *blsModuleCore) AppendHash(out []byte, data []byte) []byte {
+	return appendSHA256(out, data)
+}
+
+func (m *blsModuleCore) DSAModule() module.DSAModule {
+	return blsDSAModuleInstance
+}
+
+func (m *blsModuleCore) NewProofContextFromBytes(bs []byte) (proofContextCore, error) {
+	return newBLSProofContextFromBytes(blsModuleInstance, bs)
+}
+
+func (m *blsModuleCore) NewProofContext(keys [][]byte) (proofContextCore, error) {
+	return newBLSProofContext(blsModuleInstance, keys)
+}
+
+func (m *blsModuleCore) AddressFromPubKey(pubKey []byte) ([]byte, error) {
+	pk, err := crypto.ParseBLSPublicKey(pubKey)
+	if err != nil {
+		return nil, err
+	}
+	return pk.Bytes(), nil
+}
+
+func (m *blsModuleCore) BytesByHashBucket() db.BucketID {
+	return blsBytesByHash
+}
+
+func (m *blsModuleCore) ListByMerkleRootBucket() db.BucketID {
+	return blsListByRoot
+}
+
+func (m *blsModuleCore) NewProofFromBytes(bs []byte) (module.BTPProof, error) {
+	return newBLSProofFromBytes(bs)
+}
+
+func (m *blsModuleCore) NetworkTypeKeyFromDSAKey(key []byte) ([]byte, error) {
+	return key, nil
+}
+
+func init() {
+	blsModuleInstance = register(blsUID, &blsModuleCore{})
+}
@@ -0,0 +1,198 @@
+/*
+ * Copyright 2026 ICON Foundation
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package ntm
+
+import (
+	"crypto/sha256"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/icon-project/goloop/common/crypto"
+	"github.com/icon-project/goloop/module"
+)
+
+func sha256Of(data ...[]byte) []byte {
+	return appendSHA256(nil, data...)
+}
+
+type testBLSWallet struct {
+	skey *crypto.BLSPrivateKey
+}
+
+func (w *testBLSWallet) Sign(data []byte) ([]byte, error) {
+	sig, err := w.skey.Sign(data)
+	if err != nil {
+		return nil, err
+	}
+	return sig.Bytes(), nil
+}
+
+func (w *testBLSWallet) PublicKey() []byte {
+	return w.skey.PublicKey().Bytes()
+}
+
+func newBLSTestSetup(t *testing.T, count int) *testSetup {
+	s := &testSetup{
+		assert:  assert.New(t),
+		count:   count,
+		wallets: make([]*walletProvider, 0, count),
+		pubKeys: make([][]byte, 0, count),
+	}
+	for i := 0; i < count; i++ {
+		sk, _ := crypto.GenerateBLSKeyPair()
+		w := &testBLSWallet{skey: sk}
+		s.wallets = append(s.wallets, &walletProvider{
+			wallets: map[string]module.BaseWallet{BLSDSA: w},
+		})
+		s.pubKeys = append(s.pubKeys, w.PublicKey())
+	}
+	var err error
+	s.pc, err = blsModuleInstance.NewProofContext(s.pubKeys)
+	s.assert.NoError(err)
+	return s
+}
+
+func TestBLSModule_Basics(t *testing.T) {
+	assert := assert.New(t)
+	mod := ForUID(blsUID)
+	assert.NotNil(mod)
+	assert.Contains(Modules(), blsUID)
+	assert.EqualValues(BLSDSA, mod.DSA())
+
+	s := newBLSTestSetup(t, 4)
+	assert.EqualValues(blsUID, s.pc.UID())
+	assert.EqualValues(BLSDSA, s.pc.DSA())
+	assert.EqualValues(mod.Hash(s.pc.Bytes()), s.pc.Hash())
+	pc2, err := mod.NewProofContextFromBytes(s.pc.Bytes())
+	assert.NoError(err)
+	assert.EqualValues(s.pc.Bytes(), pc2.Bytes())
+
+	addr, err := mod.AddressFromPubKey(s.pubKeys[0])
+	assert.NoError(err)
+	assert.EqualValues(s.pubKeys[0], addr)
+	_, err = mod.AddressFromPubKey(newTestWallet().PublicKey())
+	assert.Error(err)
+}
+
+func TestBLSModule_MerkleRoot(t *testing.T) {
+	mod := ForUID(blsUID)
+	var h = func(b byte) []byte {
+		return mod.Hash([]byte{b})
+	}
+	assert := assert.New(t)
+	sum := sha256.Sum256([]byte{1})
+	assert.EqualValues(sum[:], h(1))
+
+	in := module.BytesSlice{h(1), h(2), h(3)}
+	exp := sha256Of(sha256Of(h(1), h(2)), h(3))
+	assert.EqualValues(exp, mod.MerkleRoot(&in))
+	assert.EqualValues([]module.MerkleNode{
+		{Dir: module.DirLeft, Value: h(1)},
+		{Dir: module.DirRight, Value: h(3)},
+	}, mod.MerkleProof(&in, 1))
+	assert.EqualValues([]module.MerkleNode{
+		{Dir: module.DirRight, Value: nil},
+		{Dir: module.DirLeft, Value: sha256Of(h(1), h(2))},
+	}, mod.MerkleProof(&in, 2))
+}
+
+func TestBLSProofContext_NewProofPart(t *testing.T) {
+	s := newBLSTestSetup(t, 4)
+	msgHash := sha256Of([]byte("abc"))
+	for i := 0; i < s.count; i++ {
+		pp, err := s.pc.NewProofPart(testDecision(msgHash), s.wallets[i])
+		s.assert.NoError(err)
+		pp2, err := s.pc.NewProofPartFromBytes(pp.Bytes())
+		s.assert.NoError(err)
+		idx, err := s.pc.VerifyPart(msgHash, pp2)
+		s.assert.NoError(err)
+		s.assert.Equal(i, idx)
+		_, err = s.pc.VerifyPart(sha256Of([]byte("abcd")), pp2)
+		s.assert.Error(err)
+	}
+
+	s2 := newBLSTestSetup(t, 1)
+	_, err := s.pc.NewProofPart(testDecision(msgHash), s2.wallets[0])
+	s.assert.Error(err)
+	wp, _ := newSecp256k1WalletProvider()
+	_, err = s.pc.NewProofPart(testDecision(msgHash), wp)
+	s.assert.Error(err)
+}
+
+func TestBLSProofContext_Verify(t *testing.T) {
+	msgHash := sha256Of([]byte("abc"))
+	testCase := []struct {
+		ok      bool
+		ppCount int
+		pkCount int
+	}{
+		{false, 0, 1},
+		{true, 1, 1},
+		{false, 2, 3},
+		{true, 3, 3},
+		{false, 2, 4},
+		{true, 3, 4},
+		{false, 4, 7},
+		{true, 5, 7},
+	}
+	for _, c := range testCase {
+		s := newBLSTestSetup(t, c.pkCount)
+		p := s.newProofOfLen(c.ppCount, msgHash)
+		s.assert.Equal(c.pkCount, p.ValidatorCount())
+		err := s.pc.Verify(msgHash, p)
+		if c.ok {
+			s.assert.NoError(err, "Verify ppCount=%d pkCount=%d", c.ppCount, c.pkCount)
+		} else {
+			s.assert.Error(err, "Verify ppCount=%d pkCount=%d", c.ppCount, c.pkCount)
+		}
+		p2, err := blsModuleInstance.NewProofFromBytes(p.Bytes())
+		s.assert.NoError(err)
+		s.assert.EqualValues(p.Bytes(), p2.Bytes())
+		err = s.pc.Verify(msgHash, p2)
+		if c.ok {
+			s.assert.NoError(err, "VerifyByProofBytes ppCount=%d pkCount=%d", c.ppCount, c.pkCount)
+		} else {
+			s.assert.Error(err, "VerifyByProofBytes ppCount=%d pkCount=%d", c.ppCount, c.pkCount)
+		}
+	}
+}
+
+func TestBLSProof_Compact(t *testing.T) {
+	msgHash := sha256Of([]byte("abc"))
+	s := newBLSTestSetup(t, 100)
+	p100 := s.newProofOfLen(100, msgHash)
+	s.assert.NoError(s.pc.Verify(msgHash, p100))
+	s.assert.Less(len(p100.Bytes()), crypto.BLSSignatureLen+100/8+16)
+	s.assert.Nil(p100.ProofPartAt(0))
+
+	// parts added after Bytes() are aggregated again
+	p := s.newProofOfLen(60, msgHash)
+	s.assert.Error(s.pc.Verify(msgHash, p))
+	for i := 60; i < 70; i++ {
+		pp, err := s.pc.NewProofPart(testDecision(msgHash), s.wallets[i])
+		s.assert.NoError(err)
+		p.Add(pp)
+		p.Add(pp)
+	}
+	s.assert.NoError(s.pc.Verify(msgHash, p))
+
+	// tampered signers
+	bp := p.(*blsProof)
+	bp.Signers[0] ^= 1
+	s.assert.Error(s.pc.Verify(msgHash, p))
+}
@@ -0,0 +1,330 @@
+/*
+ * Copyright 2026 ICON Foundation
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package ntm
+
+import (
+	"sync"
+
+	"github.com/icon-project/goloop/common/cache"
+	"github.com/icon-project/goloop/common/codec"
+	"github.com/icon-project/goloop/common/crypto"
+	"github.com/icon-project/goloop/common/errors"
+	"github.com/icon-project/goloop/module"
+)
+
+type blsProofPart struct {
+	Index     int
+	Signature []byte
+	sig       *crypto.BLSSignature
+}
+
+func newBLSProofPart(idx int, sig *crypto.BLSSignature) *blsProofPart {
+	return &blsProofPart{
+		Index:     idx,
+		Signature: sig.Bytes(),
+		sig:       sig,
+	}
+}
+
+func (pp *blsProofPart) Bytes() []byte {
+	return codec.MustMarshalToBytes(pp)
+}
+
+// blsProof has the aggregation of signatures of validators marked in
+// Signers. Signatures can't be taken from the aggregation, so ProofPartAt
+// returns nil.
+type blsProof struct {
+	Validators int
+	Signers    []byte
+	Signature  []byte
+
+	lock  sync.Mutex
+	parts []*crypto.BLSSignature
+	bytes []byte
+}
+
+func newBLSProofFromBytes(bs []byte) (*blsProof, error) {
+	var p blsProof
+	_, err := codec.UnmarshalFromBytes(bs, &p)
+	if err != nil {
+		return nil, err
+	}
+	if p.Validators < 0 || len(p.Signers) != (p.Validators+7)/8 {
+		return nil, errors.Errorf("invalid signers validators=%d len(signers)=%d",
+			p.Validators, len(p.Signers))
+	}
+	return &p, nil
+}
+
+func (p *blsProof) hasSigner(i int) bool {
+	return p.Signers[i/8]&(1<<uint(i%8)) != 0
+}
+
+// aggregate aggregates added signatures into Signature.
+func (p *blsProof) aggregate() error {
+	if len(p.parts) == 0 {
+		return nil
+	}
+	sigs := p.parts
+	if p.Signature != nil {
+		sig, err := crypto.ParseBLSSignature(p.Signature)
+		if err != nil {
+			return err
+		}
+		sigs = append(sigs, sig)
+	}
+	p.Signature = crypto.AggregateBLSSignatures(sigs).Bytes()
+	p.parts = nil
+	return nil
+}
+
+func (p *blsProof) Bytes() []byte {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+	if p.bytes == nil {
+		if err := p.aggregate(); err != nil {
+			return nil
+		}
+		p.bytes = codec.MustMarshalToBytes(p)
+	}
+	return p.bytes
+}
+
+func (p *blsProof) Add(pp module.BTPProofPart) {
+	bpp := pp.(*blsProofPart)
+	p.lock.Lock()
+	defer p.lock.Unlock()
+	if bpp.Index < 0 || bpp.Index >= p.Validators || p.hasSigner(bpp.Index) {
+		return
+	}
+	p.Signers[bpp.Index/8] |= 1 << uint(bpp.Index%8)
+	p.parts = append(p.parts, bpp.sig)
+	p.bytes = nil
+}
+
+func (p *blsProof) ValidatorCount() int {
+	return p.Validators
+}
+
+func (p *blsProof) ProofPartAt(i int) module.BTPProofPart {
+	return nil
+}
+
+// blsProofContext has BLS public keys of validators. Validators without
+// keys have nil.
+type blsProofContext struct {
+	Validators [][]byte
+	mod        *networkTypeModule
+	bytes      cache.ByteSlice
+
+	lock       sync.Mutex
+	keyToIndex map[string]int
+	pubKeys    []*crypto.BLSPublicKey
+}
+
+func newBLSProofContext(
+	mod *networkTypeModule,
+	keys [][]byte,
+) (*blsProofContext, error) {
+	pc := &blsProofContext{
+		Validators: make([][]byte, 0, len(keys)),
+		mod:        mod,
+	}
+	for i, key := range keys {
+		var addr []byte
+		var err error
+		if key != nil {
+			addr, err = mod.AddressFromPubKey(key)
+			if err != nil {
+				return nil, errors.Wrapf(err, "fail to converted key to address index=%d key=%x", i, key)
+			}
+		}
+		pc.Validators = append(pc.Validators, addr)
+	}
+	return pc, nil
+}
+
+func newBLSProofContextFromBytes(
+	mod *networkTypeModule,
+	bytes []byte,
+) (*blsProofContext, error) {
+	pc := &blsProofContext{
+		mod: mod,
+	}
+	if bytes != nil {
+		_, err := codec.UnmarshalFromBytes(bytes, pc)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return pc, nil
+}
+
+func (pc *blsProofContext) init() {
+	if pc.keyToIndex != nil {
+		return
+	}
+	pc.keyToIndex = make(map[string]int, len(pc.Validators))
+	pc.pubKeys = make([]*crypto.BLSPublicKey, len(pc.Validators))
+	for i, key := range pc.Validators {
+		if key == nil {
+			continue
+		}
+		pk, err := crypto.ParseBLSPublicKey(key)
+		if err != nil {
+			continue
+		}
+		pc.keyToIndex[string(key)] = i
+		pc.pubKeys[i] = pk
+	}
+}
+
+func (pc *blsProofContext) indexOf(key []byte) (int, bool) {
+	pc.lock.Lock()
+	defer pc.lock.Unlock()
+	pc.init()
+	idx, ok := pc.keyToIndex[string(key)]
+	return idx, ok
+}
+
+func (pc *blsProofContext) publicKeyAt(i int) *crypto.BLSPublicKey {
+	pc.lock.Lock()
+	defer pc.lock.Unlock()
+	pc.init()
+	return pc.pubKeys[i]
+}
+
+func (pc *blsProofContext) NetworkTypeModule() module.NetworkTypeModule {
+	return pc.mod
+}
+
+func (pc *blsProofContext) Bytes() []byte {
+	return pc.bytes.Get(func() []byte {
+		if pc.Validators == nil {
+			return nil
+		}
+		return codec.MustMarshalToBytes(pc)
+	})
+}
+
+// VerifyPart returns validator index and error
+func (pc *blsProofContext) VerifyPart(dHash []byte, pp module.BTPProofPart) (int, error) {
+	bpp := pp.(*blsProofPart)
+	if bpp.Index < 0 || bpp.Index >= len(pc.Validators) {
+		return -1, errors.Errorf("invalid proof part index=%d numValidators=%d", bpp.Index, len(pc.Validators))
+	}
+	pk := pc.publicKeyAt(bpp.Index)
+	if pk == nil {
+		return -1, errors.Errorf("invalid proof part. no key for validator index=%d", bpp.Index)
+	}
+	if !pk.Verify(dHash, bpp.sig) {
+		return -1, errors.Errorf("invalid proof part. bad signature index=%d key=%x", bpp.Index, pc.Validators[bpp.Index])
+	}
+	return bpp.Index, nil
+}
+
+func (pc *blsProofContext) NewProofPartFromBytes(ppBytes []byte) (module.BTPProofPart, error) {
+	var pp blsProofPart
+	_, err := codec.UnmarshalFromBytes(ppBytes, &pp)
+	if err != nil {
+		return nil, err
+	}
+	pp.sig, err = crypto.ParseBLSSignature(pp.Signature)
+	if err != nil {
+		return nil, err
+	}
+	return &pp, nil
+}
+
+func (pc *blsProofContext) Verify(dHash []byte, p module.BTPProof) error {
+	bp := p.(*blsProof)
+	if bp.Validators != len(pc.Validators) {
+		return errors.Errorf("invalid proof numValidators=%d proof.validators=%d", len(pc.Validators), bp.Validators)
+	}
+	bp.lock.Lock()
+	err := bp.aggregate()
+	bp.lock.Unlock()
+	if err != nil {
+		return err
+	}
+	var pks []*crypto.BLSPublicKey
+	var msgs [][]byte
+	for i := 0; i < bp.Validators; i++ {
+		if !bp.hasSigner(i) {
+			continue
+		}
+		pk := pc.publicKeyAt(i)
+		if pk == nil {
+			return errors.Errorf("invalid proof. no key for validator index=%d", i)
+		}
+		pks = append(pks, pk)
+		msgs = append(msgs, dHash)
+	}
+	if len(pks) <= 2*len(pc.Validators)/3 {
+		return errors.Errorf("not enough signers numValidator=%d numSigners=%d", len(pc.Validators), len(pks))
+	}
+	sig, err := crypto.ParseBLSSignature(bp.Signature)
+	if err != nil {
+		return err
+	}
+	if !crypto.VerifyAggregatedBLSSignature(pks, msgs, sig) {
+		return errors.Errorf("invalid aggregated signature numSigners=%d", len(pks))
+	}
+	return nil
+}
+
+func (pc *blsProofContext) NewProofFromBytes(proofBytes []byte) (module.BTPProof, error) {
+	return newBLSProofFromBytes(proofBytes)
+}
+
+func (pc *blsProofContext) NewProofPart(
+	decision module.BytesHasher,
+	wp module.WalletProvider,
+) (module.BTPProofPart, error) {
+	w := wp.WalletFor(BLSDSA)
+	if w == nil {
+		return nil, errors.Errorf("no wallet for uid=%s dsa=%s", pc.mod.UID(), BLSDSA)
+	}
+	key, err := pc.mod.AddressFromPubKey(w.PublicKey())
+	if err != nil {
+		return nil, err
+	}
+	idx, ok := pc.indexOf(key)
+	if !ok {
+		return nil, errors.Errorf("not validator key=%x", key)
+	}
+	sigBytes, err := signDecision(w, pc.mod, decision)
+	if err != nil {
+		return nil, err
+	}
+	sig, err := crypto.ParseBLSSignature(sigBytes)
+	if err != nil {
+		return nil, err
+	}
+	return newBLSProofPart(idx, sig), nil
+}
+
+func (pc *blsProofContext) DSA() string {
+	return BLSDSA
+}
+
+func (pc *blsProofContext) NewProof() module.BTPProof {
+	return &blsProof{
+		Validators: len(pc.Validators),
+		Signers:    make([]byte, (len(pc.Validators)+7)/8),
+	}
+}
@@ -20,8 +20,6 @@ import (
 	"testing"
 
 	"github.com/stretchr/testify/assert"
-
-	"github.com/icon-project/goloop/common/wallet"
 )
 
 func testModuleBasics(t *testing.T, uid, dsa string) {
@@ -33,7 +31,7 @@ func testModuleBasics(t *testing.T, uid, dsa string) {
 
 	addrs := make([][]byte, 0, count)
 	for i := 0; i < count; i++ {
-		w := wallet.New()
+		w := newTestWallet()
 		addr, err := mod.AddressFromPubKey(w.PublicKey())
 		assert.NoError(err)
 		addrs = append(addrs, addr)
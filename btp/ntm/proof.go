@@ -17,15 +17,18 @@
 package ntm
 
 import (
+	"bytes"
+
 	"github.com/icon-project/goloop/common/cache"
 	"github.com/icon-project/goloop/common/codec"
+	"github.com/icon-project/goloop/common/errors"
 	"github.com/icon-project/goloop/module"
 )
 
 type proofContextCore interface {
 	NetworkTypeModule() module.NetworkTypeModule
 	Bytes() []byte
-	NewProofPart(decisionHash []byte, wp module.WalletProvider) (module.BTPProofPart, error)
+	NewProofPart(decision module.BytesHasher, wp module.WalletProvider) (module.BTPProofPart, error)
 	NewProofPartFromBytes(ppBytes []byte) (module.BTPProofPart, error)
 	// VerifyPart returns validator index and error
 	VerifyPart(decisionHash []byte, pp module.BTPProofPart) (int, error)
@@ -54,8 +57,8 @@ func (pc *proofContext) Bytes() []byte {
 	return pc.core.Bytes()
 }
 
-func (pc *proofContext) NewProofPart(decisionHash []byte, wp module.WalletProvider) (module.BTPProofPart, error) {
-	return pc.core.NewProofPart(decisionHash, wp)
+func (pc *proofContext) NewProofPart(decision module.BytesHasher, wp module.WalletProvider) (module.BTPProofPart, error) {
+	return pc.core.NewProofPart(decision, wp)
 }
 
 func (pc *proofContext) NewProofPartFromBytes(ppBytes []byte) (module.BTPProofPart, error) {
@@ -121,3 +124,56 @@ func (pc *proofContext) NewDecision(srcUID []byte, dstNTID int64, height int64,
 		mod:                    pc.core.NetworkTypeModule(),
 	}
 }
+
+type decisionMessage struct {
+	UID      string
+	Decision []byte
+}
+
+// NewDecisionMessage returns the message for signing the decision with
+// module.SignNTSDecision. It has UID of the network type module, so the
+// signer can get the hash of the decision by itself.
+func NewDecisionMessage(uid string, decision []byte) []byte {
+	return codec.MustMarshalToBytes(&decisionMessage{
+		UID:      uid,
+		Decision: decision,
+	})
+}
+
+// ParseDecisionMessage returns the source network UID, the destination
+// network type, the height and the round of the decision in the message made
+// by NewDecisionMessage with the hash of the decision.
+func ParseDecisionMessage(data []byte) ([]byte, int64, int64, int32, []byte, error) {
+	var msg decisionMessage
+	if _, err := codec.UnmarshalFromBytes(data, &msg); err != nil {
+		return nil, 0, 0, 0, nil, errors.IllegalArgumentError.Wrap(err, "InvalidDecisionMessage")
+	}
+	mod := ForUID(msg.UID)
+	if mod == nil {
+		return nil, 0, 0, 0, nil, errors.IllegalArgumentError.Errorf(
+			"UnknownNetworkType(uid=%s)", msg.UID)
+	}
+	var d networkTypeSectionDecision
+	if _, err := codec.UnmarshalFromBytes(msg.Decision, &d); err != nil {
+		return nil, 0, 0, 0, nil, errors.IllegalArgumentError.Wrap(err, "InvalidDecision")
+	}
+	if !bytes.Equal(codec.MustMarshalToBytes(&d), msg.Decision) {
+		return nil, 0, 0, 0, nil, errors.IllegalArgumentError.New("InvalidDecision")
+	}
+	if d.Height <= 0 || d.Round < 0 || len(d.NetworkTypeSectionHash) == 0 {
+		return nil, 0, 0, 0, nil, errors.IllegalArgumentError.Errorf(
+			"InvalidDecision(height=%d,round=%d,nts=%x)",
+			d.Height, d.Round, d.NetworkTypeSectionHash)
+	}
+	return d.SrcNetworkID, d.DstType, d.Height, d.Round, mod.Hash(msg.Decision), nil
+}
+
+// signDecision signs the decision. If the wallet inspects messages before
+// signing, it passes the decision instead of its hash.
+func signDecision(w module.BaseWallet, mod module.NetworkTypeModule, decision module.BytesHasher) ([]byte, error) {
+	if ms, ok := w.(module.MessageSigner); ok {
+		return ms.SignMessage(module.SignNTSDecision,
+			NewDecisionMessage(mod.UID(), decision.Bytes()))
+	}
+	return w.Sign(decision.Hash())
+}
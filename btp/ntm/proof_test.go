@@ -22,7 +22,7 @@ import (
 	"github.com/stretchr/testify/assert"
 
 	"github.com/icon-project/goloop/common/codec"
-	"github.com/icon-project/goloop/common/wallet"
+	"github.com/icon-project/goloop/common/crypto"
 	"github.com/icon-project/goloop/module"
 )
 
@@ -43,8 +43,41 @@ func (w walletProvider) WalletFor(keyType string) module.BaseWallet {
 	return w.wallets[keyType]
 }
 
-func newSecp256k1WalletProvider() (*walletProvider, module.Wallet) {
-	w := wallet.New()
+// testDecision is the decision of which hash is itself.
+type testDecision []byte
+
+func (d testDecision) Bytes() []byte {
+	return d
+}
+
+func (d testDecision) Hash() []byte {
+	return d
+}
+
+type testWallet struct {
+	skey *crypto.PrivateKey
+	pkey *crypto.PublicKey
+}
+
+func newTestWallet() *testWallet {
+	sk, pk := crypto.GenerateKeyPair()
+	return &testWallet{skey: sk, pkey: pk}
+}
+
+func (w *testWallet) Sign(data []byte) ([]byte, error) {
+	sig, err := crypto.NewSignature(data, w.skey)
+	if err != nil {
+		return nil, err
+	}
+	return sig.SerializeRSV()
+}
+
+func (w *testWallet) PublicKey() []byte {
+	return w.pkey.SerializeCompressed()
+}
+
+func newSecp256k1WalletProvider() (*walletProvider, module.BaseWallet) {
+	w := newTestWallet()
 	wp := walletProvider{
 		wallets: map[string]module.BaseWallet{
 			secp256k1DSA: w,
@@ -82,7 +115,7 @@ func newTestSetup(t *testing.T, mod *networkTypeModule, count int) *testSetup {
 func (s *testSetup) newProofOfLen(l int, msgHash []byte) module.BTPProof {
 	p := s.pc.NewProof()
 	for i := 0; i < l; i++ {
-		pp, err := s.pc.NewProofPart(msgHash, s.wallets[i])
+		pp, err := s.pc.NewProofPart(testDecision(msgHash), s.wallets[i])
 		s.assert.NoError(err)
 		p.Add(pp)
 	}
@@ -93,7 +126,7 @@ func TestEthProofContext_NewProofPart_OK(t *testing.T) {
 	s := newEthTestSetup(t, 4)
 	msgHash := keccak256([]byte("abc"))
 	for i := 0; i < s.count; i++ {
-		pp, err := s.pc.NewProofPart(msgHash, s.wallets[i])
+		pp, err := s.pc.NewProofPart(testDecision(msgHash), s.wallets[i])
 		s.assert.NoError(err)
 		_, err = s.pc.VerifyPart(msgHash, pp)
 		s.assert.NoError(err)
@@ -104,13 +137,13 @@ func TestEthProofContext_NewProofPart_FailInvalidPK(t *testing.T) {
 	s := newEthTestSetup(t, 4)
 	msgHash := keccak256([]byte("abc"))
 	wp, _ := newSecp256k1WalletProvider()
-	_, err := s.pc.NewProofPart(msgHash, wp)
+	_, err := s.pc.NewProofPart(testDecision(msgHash), wp)
 	s.assert.Error(err)
 }
 
 func TestEthProofContext_VerifyPart_FailWrongMessage(t *testing.T) {
 	s := newEthTestSetup(t, 4)
-	pp, err := s.pc.NewProofPart(keccak256([]byte("abc")), s.wallets[0])
+	pp, err := s.pc.NewProofPart(testDecision(keccak256([]byte("abc"))), s.wallets[0])
 	s.assert.NoError(err)
 	_, err = s.pc.VerifyPart(keccak256([]byte("abcd")), pp)
 	s.assert.Error(err)
@@ -119,7 +152,7 @@ func TestEthProofContext_VerifyPart_FailWrongMessage(t *testing.T) {
 func TestEthProofPart_codec(t *testing.T) {
 	s := newEthTestSetup(t, 4)
 	msgHash := keccak256([]byte("abc"))
-	pp, err := s.pc.NewProofPart(msgHash, s.wallets[2])
+	pp, err := s.pc.NewProofPart(testDecision(msgHash), s.wallets[2])
 	s.assert.NoError(err)
 	epp := pp.(*secp256k1ProofPart)
 	ppBytes := codec.MustMarshalToBytes(epp)
@@ -210,7 +243,7 @@ func TestEthProofContext_Verify_FailInvalidPK(t *testing.T) {
 	s2 := newEthTestSetup(t, 4)
 	msgHash := keccak256([]byte("abc"))
 	p := s.newProofOfLen(2, msgHash)
-	pp, err := s2.pc.NewProofPart(msgHash, s2.wallets[0])
+	pp, err := s2.pc.NewProofPart(testDecision(msgHash), s2.wallets[0])
 	s.assert.NoError(err)
 	p.Add(pp)
 	s.assert.Error(s.pc.Verify(msgHash, p))
@@ -220,8 +253,68 @@ func TestEthProofContext_Verify_FailDuplicatedPK(t *testing.T) {
 	s := newEthTestSetup(t, 4)
 	msgHash := keccak256([]byte("abc"))
 	p := s.newProofOfLen(2, msgHash)
-	pp, err := s.pc.NewProofPart(msgHash, s.wallets[0])
+	pp, err := s.pc.NewProofPart(testDecision(msgHash), s.wallets[0])
 	s.assert.NoError(err)
 	p.Add(pp)
 	s.assert.Error(s.pc.Verify(msgHash, p))
 }
+
+type messageSigner struct {
+	module.BaseWallet
+	kinds []module.SignKind
+}
+
+func (w *messageSigner) SignMessage(kind module.SignKind, data []byte) ([]byte, error) {
+	w.kinds = append(w.kinds, kind)
+	_, _, _, _, hash, err := ParseDecisionMessage(data)
+	if err != nil {
+		return nil, err
+	}
+	return w.Sign(hash)
+}
+
+func TestEthProofContext_NewProofPart_MessageSigner(t *testing.T) {
+	s := newEthTestSetup(t, 1)
+	ms := &messageSigner{BaseWallet: s.wallets[0].wallets[secp256k1DSA]}
+	s.wallets[0].wallets[secp256k1DSA] = ms
+
+	d := s.pc.NewDecision([]byte("src"), 1, 10, 2, []byte("nts"))
+	pp, err := s.pc.NewProofPart(d, s.wallets[0])
+	s.assert.NoError(err)
+	s.assert.Equal([]module.SignKind{module.SignNTSDecision}, ms.kinds)
+	_, err = s.pc.VerifyPart(d.Hash(), pp)
+	s.assert.NoError(err)
+}
+
+func TestParseDecisionMessage(t *testing.T) {
+	pc, err := ethModuleInstance.NewProofContext(nil)
+	assert.NoError(t, err)
+	d := pc.NewDecision([]byte("src"), 1, 10, 2, []byte("nts"))
+	src, ntid, height, round, hash, err := ParseDecisionMessage(
+		NewDecisionMessage(ethUID, d.Bytes()))
+	assert.NoError(t, err)
+	assert.Equal(t, []byte("src"), src)
+	assert.EqualValues(t, 1, ntid)
+	assert.EqualValues(t, 10, height)
+	assert.EqualValues(t, 2, round)
+	assert.Equal(t, d.Hash(), hash)
+
+	invalids := []struct {
+		name string
+		data []byte
+	}{
+		{"InvalidMessage", []byte("abc")},
+		{"UnknownType", NewDecisionMessage("unknown", d.Bytes())},
+		{"InvalidDecision", NewDecisionMessage(ethUID, []byte("abc"))},
+		{"InvalidHeight", NewDecisionMessage(ethUID,
+			pc.NewDecision([]byte("src"), 1, 0, 0, []byte("nts")).Bytes())},
+		{"TrailingBytes", NewDecisionMessage(ethUID,
+			append(append([]byte{}, d.Bytes()...), 0x01))},
+	}
+	for _, c := range invalids {
+		t.Run(c.name, func(t *testing.T) {
+			_, _, _, _, _, err := ParseDecisionMessage(c.data)
+			assert.Error(t, err)
+		})
+	}
+}
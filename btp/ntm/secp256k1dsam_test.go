@@ -22,13 +22,12 @@ import (
 	"github.com/stretchr/testify/assert"
 
 	"github.com/icon-project/goloop/common/crypto"
-	"github.com/icon-project/goloop/common/wallet"
 )
 
 func TestSecp256k1DSAModule_Verify(t *testing.T) {
 	assert := assert.New(t)
 
-	w := wallet.New()
+	w := newTestWallet()
 	dsam := DSAModuleForName(secp256k1DSA)
 	assert.NoError(dsam.Verify(w.PublicKey()))
 
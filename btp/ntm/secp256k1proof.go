@@ -223,7 +223,7 @@ func (pc *secp256k1ProofContext) NewProofFromBytes(proofBytes []byte) (module.BT
 }
 
 func (pc *secp256k1ProofContext) NewProofPart(
-	dHash []byte,
+	decision module.BytesHasher,
 	wp module.WalletProvider,
 ) (module.BTPProofPart, error) {
 	w := wp.WalletFor(secp256k1DSA)
@@ -234,7 +234,7 @@ func (pc *secp256k1ProofContext) NewProofPart(
 	if err != nil {
 		return nil, err
 	}
-	sig, err := w.Sign(dHash)
+	sig, err := signDecision(w, pc.mod, decision)
 	if err != nil {
 		return nil, err
 	}
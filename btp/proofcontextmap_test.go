@@ -236,7 +236,7 @@ func newPCMVerifyTest(t_ *testing.T) *pcmVerifyTest {
 		assert.NoError(err)
 		dcs := pc.NewDecision(srcUID, ntid, 3, 0, nts.Hash())
 		for j := 0; j < count; j++ {
-			pp, err := pc.NewProofPart(dcs.Hash(), t.WPs[j])
+			pp, err := pc.NewProofPart(dcs, t.WPs[j])
 			assert.NoError(err)
 			pf.Add(pp)
 		}
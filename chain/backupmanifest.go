@@ -0,0 +1,128 @@
+/*
+ * Copyright 2026 ICON Foundation
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package chain
+
+import (
+	"archive/zip"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"hash"
+	"io"
+
+	"github.com/icon-project/goloop/common/errors"
+)
+
+const (
+	// BackupManifestFile is the entry of the backup having the manifest.
+	// It's not extracted on restore.
+	BackupManifestFile = ".manifest"
+
+	BackupTypeFull         = "full"
+	BackupTypeIncremental  = "incremental"
+	BackupTypeDifferential = "differential"
+)
+
+// BackupFile is a file of the chain in the backup. Backup is the name of
+// the backup having the content of the file. It's empty if the backup
+// having the manifest has it.
+type BackupFile struct {
+	Name    string `json:"name"`
+	Size    int64  `json:"size"`
+	ModTime int64  `json:"modTime"`
+	SHA256  string `json:"sha256"`
+	Backup  string `json:"backup,omitempty"`
+}
+
+// BackupManifest lists all files of the chain at the height of the backup.
+// Incremental and differential backups have only changed files since their
+// parents, and others refer to the backups having them.
+type BackupManifest struct {
+	Files []*BackupFile `json:"files"`
+}
+
+func (m *BackupManifest) fileMap() map[string]*BackupFile {
+	files := make(map[string]*BackupFile, len(m.Files))
+	for _, f := range m.Files {
+		files[f.Name] = f
+	}
+	return files
+}
+
+// Backups returns names of other backups referred by the manifest.
+func (m *BackupManifest) Backups() []string {
+	var names []string
+	seen := make(map[string]bool)
+	for _, f := range m.Files {
+		if f.Backup != "" && !seen[f.Backup] {
+			seen[f.Backup] = true
+			names = append(names, f.Backup)
+		}
+	}
+	return names
+}
+
+func writeBackupManifest(zw *zip.Writer, m *BackupManifest) error {
+	w, err := zw.Create(BackupManifestFile)
+	if err != nil {
+		return err
+	}
+	return json.NewEncoder(w).Encode(m)
+}
+
+// ReadBackupManifest returns the manifest of the backup. It returns nil
+// if the backup doesn't have it (backups of old versions).
+func ReadBackupManifest(zr *zip.Reader) (*BackupManifest, error) {
+	for _, f := range zr.File {
+		if f.Name != BackupManifestFile {
+			continue
+		}
+		rc, err := f.Open()
+		if err != nil {
+			return nil, err
+		}
+		defer rc.Close()
+		m := new(BackupManifest)
+		if err := json.NewDecoder(rc).Decode(m); err != nil {
+			return nil, errors.CriticalFormatError.Wrap(err, "InvalidBackupManifest")
+		}
+		return m, nil
+	}
+	return nil, nil
+}
+
+// ChecksumReader calculates the checksum of the file while it's read.
+type ChecksumReader struct {
+	r io.Reader
+	h hash.Hash
+}
+
+func (r *ChecksumReader) Read(p []byte) (int, error) {
+	n, err := r.r.Read(p)
+	r.h.Write(p[:n])
+	return n, err
+}
+
+// Checksum returns the checksum of read bytes in the format of the
+// manifest.
+func (r *ChecksumReader) Checksum() string {
+	return hex.EncodeToString(r.h.Sum(nil))
+}
+
+func NewChecksumReader(r io.Reader) *ChecksumReader {
+	return &ChecksumReader{r: r, h: sha256.New()}
+}
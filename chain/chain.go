@@ -11,11 +11,15 @@ import (
 	"time"
 
 	"github.com/icon-project/goloop/block"
+	"github.com/icon-project/goloop/btp/ntm"
 	"github.com/icon-project/goloop/chain/base"
 	"github.com/icon-project/goloop/chain/gs"
 	"github.com/icon-project/goloop/common/db"
 	"github.com/icon-project/goloop/common/errors"
 	"github.com/icon-project/goloop/common/log"
+	"github.com/icon-project/goloop/common/logsindex"
+	"github.com/icon-project/goloop/common/merkle"
+	"github.com/icon-project/goloop/common/statepruner"
 	"github.com/icon-project/goloop/common/trie/cache"
 	"github.com/icon-project/goloop/common/txlocator"
 	"github.com/icon-project/goloop/consensus"
@@ -82,7 +86,8 @@ type simpleTask interface {
 }
 
 type singleChain struct {
-	wallet module.Wallet
+	wallet    module.Wallet
+	blsWallet module.BaseWallet
 
 	dbLock   sync.RWMutex
 	database db.Database
@@ -95,8 +100,14 @@ type singleChain struct {
 	nt       module.NetworkTransport
 	nm       module.NetworkManager
 	lm       module.LocatorManager
+	li       *logsindex.Indexer
+	pdb      *statepruner.Database
+	sp       *statepruner.Pruner
 	plt      base.Platform
 
+	// pinned snapshots of the database
+	snapshots dbSnapshots
+
 	cid int
 	cfg Config
 	pm  eeproxy.Manager
@@ -270,6 +281,74 @@ func (c *singleChain) ValidateTxOnSend() bool {
 	return c.cfg.ValidateTxOnSend
 }
 
+func (c *singleChain) FlatStateSync() bool {
+	return c.cfg.StateSync == StateSyncFlat
+}
+
+func (c *singleChain) TxPoolPolicy() string {
+	if len(c.cfg.TxPoolPolicy) > 0 {
+		return c.cfg.TxPoolPolicy
+	}
+	return TxPoolPolicyDefault
+}
+
+func (c *singleChain) SenderTxCount() int {
+	return c.cfg.SenderTxCount
+}
+
+func (c *singleChain) SenderTxBytes() int {
+	return c.cfg.SenderTxBytes
+}
+
+func (c *singleChain) PeerTxRate() int {
+	return c.cfg.PeerTxRate
+}
+
+// LogsIndex returns the index of logs blooms. It returns nil if the index
+// is disabled or the chain is not running.
+func (c *singleChain) LogsIndex() *logsindex.Index {
+	if c.li == nil {
+		return nil
+	}
+	return c.li.Index
+}
+
+type stateResolver interface {
+	ResolveState(result []byte, bd merkle.Builder) error
+}
+
+func (c *singleChain) newStatePruner() (*statepruner.Pruner, error) {
+	sr, ok := c.sm.(stateResolver)
+	if !ok {
+		return nil, errors.UnsupportedError.Errorf("StatePruningNotSupported(sm=%T)", c.sm)
+	}
+	if c.pdb == nil {
+		return nil, errors.InvalidStateError.New("DatabaseNotTracked")
+	}
+	return statepruner.New(c.pdb, c.bm, sr.ResolveState, c.cfg.StatePruning, c.logger)
+}
+
+// StatePruner returns the online pruner of world states. It returns nil if
+// the pruning is disabled or the chain is not running.
+func (c *singleChain) StatePruner() *statepruner.Pruner {
+	return c.sp
+}
+
+// StateFloor returns the lowest height of intact world states. World states
+// of lower heights may be broken by the online pruning.
+func (c *singleChain) StateFloor() (int64, error) {
+	if sp := c.sp; sp != nil {
+		return sp.Floor(), nil
+	}
+	c.dbLock.RLock()
+	defer c.dbLock.RUnlock()
+
+	if c.database == nil {
+		return 0, errors.InvalidStateError.New("NoDatabase")
+	}
+	return statepruner.ReadFloor(c.database)
+}
+
 func (c *singleChain) State() (string, int64, error) {
 	c.mtx.RLock()
 	defer c.mtx.RUnlock()
@@ -428,6 +507,10 @@ func (c *singleChain) prepareDatabase(chainDir string) error {
 		_ = cdb.Close()
 		return errors.Wrapf(err, "UnknownCacheStrategy(%s)", c.cfg.NodeCache)
 	}
+	if c.cfg.StatePruning > 0 {
+		c.pdb = statepruner.WrapDatabase(cdb)
+		cdb = c.pdb
+	}
 	cacheDir := path.Join(chainDir, DefaultCacheDir)
 	cdb = cache.AttachManager(cdb, cacheDir, mLevel, fLevel, stores)
 	cdb, err = state.AttachAPIInfoCache(cdb, ConfigDefaultAPIInfoCacheSize)
@@ -443,8 +526,10 @@ func (c *singleChain) releaseDatabase() {
 	c.dbLock.Lock()
 	defer c.dbLock.Unlock()
 	if c.database != nil {
+		c.snapshots.releaseAll()
 		c.database.Close()
 		c.database = nil
+		c.pdb = nil
 	}
 }
 
@@ -479,6 +564,7 @@ func (c *singleChain) _init() error {
 	if c.vld == nil {
 		c.vld = consensus.NewCommitVoteSetFromBytes
 	}
+	c.vld = consensus.NewCommitVoteSetDecoder(c.vld, consensus.NewBLSKeyContext(c))
 	c.pd = consensus.DecodePatch
 	c.metricCtx = metric.GetMetricContextByCID(c.CID())
 	return nil
@@ -509,6 +595,14 @@ func (c *singleChain) prepareManagers() error {
 }
 
 func (c *singleChain) releaseManagers() {
+	if c.li != nil {
+		c.li.Term()
+		c.li = nil
+	}
+	if c.sp != nil {
+		c.sp.Term()
+		c.sp = nil
+	}
 	if c.cs != nil {
 		c.cs.Term()
 		c.cs = nil
@@ -725,6 +819,8 @@ func (c *singleChain) WalletFor(dsa string) module.BaseWallet {
 	switch dsa {
 	case "ecdsa/secp256k1":
 		return c.wallet
+	case ntm.BLSDSA:
+		return c.blsWallet
 	}
 	return nil
 }
@@ -746,6 +842,7 @@ func (c *singleChain) PlatformName() string {
 
 func NewChain(
 	wallet module.Wallet,
+	blsWallet module.BaseWallet,
 	transport module.NetworkTransport,
 	srv *server.Manager,
 	pm eeproxy.Manager,
@@ -758,6 +855,7 @@ func NewChain(
 	})
 	c := &singleChain{
 		wallet:    wallet,
+		blsWallet: blsWallet,
 		nt:        transport,
 		srv:       srv,
 		cid:       cid,
@@ -11,6 +11,7 @@ import (
 	"github.com/icon-project/goloop/common/crypto"
 	"github.com/icon-project/goloop/common/errors"
 	"github.com/icon-project/goloop/module"
+	"github.com/icon-project/goloop/service"
 )
 
 const (
@@ -30,6 +31,19 @@ const (
 	NodeCacheDefault = NodeCacheNone
 )
 
+const (
+	StateSyncNode    = "node"
+	StateSyncFlat    = "flat"
+	StateSyncDefault = StateSyncNode
+)
+
+const (
+	TxPoolPolicyFIFO     = service.TxPoolPolicyFIFO
+	TxPoolPolicyPriority = service.TxPoolPolicyPriority
+	TxPoolPolicyFairness = service.TxPoolPolicyFairness
+	TxPoolPolicyDefault  = TxPoolPolicyFIFO
+)
+
 type Config struct {
 	// fixed
 	NID    int    `json:"nid"`
@@ -49,6 +63,13 @@ type Config struct {
 	ChildrenLimit    *int   `json:"children_limit,omitempty"`
 	NephewsLimit     *int   `json:"nephews_limit,omitempty"`
 	ValidateTxOnSend bool   `json:"validate_tx_on_send,omitempty"`
+	StateSync        string `json:"state_sync,omitempty"`
+	TxPoolPolicy     string `json:"tx_pool_policy,omitempty"`
+	SenderTxCount    int    `json:"sender_tx_count,omitempty"`
+	SenderTxBytes    int    `json:"sender_tx_bytes,omitempty"`
+	PeerTxRate       int    `json:"peer_tx_rate,omitempty"`
+	LogsIndex        bool   `json:"logs_index,omitempty"`
+	StatePruning     int    `json:"state_pruning,omitempty"`
 
 	// runtime
 	Channel        string `json:"channel"`
@@ -143,6 +164,19 @@ func IsNodeCacheOption(s string) bool {
 	return err == nil
 }
 
+func IsStateSyncOption(s string) bool {
+	switch s {
+	case StateSyncNode, StateSyncFlat:
+		return true
+	default:
+		return false
+	}
+}
+
+func IsTxPoolPolicyOption(s string) bool {
+	return service.IsTxPoolPolicy(s)
+}
+
 func ParseNodeCacheOption(s string) (int, int, int, error) {
 	switch s {
 	case NodeCacheNone:
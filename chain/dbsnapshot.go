@@ -0,0 +1,191 @@
+/*
+ * Copyright 2024 ICON Foundation
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package chain
+
+import (
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/icon-project/goloop/block"
+	"github.com/icon-project/goloop/common/db"
+	"github.com/icon-project/goloop/common/errors"
+	"github.com/icon-project/goloop/module"
+)
+
+const (
+	// DBSnapshotLimit is the maximum number of pinned snapshots of a chain.
+	DBSnapshotLimit = 8
+	// DBSnapshotTTL is the duration after which a pinned snapshot is
+	// released automatically.
+	DBSnapshotTTL = time.Hour
+)
+
+type dbSnapshot struct {
+	id       string
+	height   int64
+	database db.Database
+	created  time.Time
+	timer    *time.Timer
+}
+
+func (s *dbSnapshot) ID() string {
+	return s.id
+}
+
+func (s *dbSnapshot) Height() int64 {
+	return s.height
+}
+
+func (s *dbSnapshot) Database() db.Database {
+	return s.database
+}
+
+func (s *dbSnapshot) CreatedAt() time.Time {
+	return s.created
+}
+
+func (s *dbSnapshot) ExpiresAt() time.Time {
+	return s.created.Add(DBSnapshotTTL)
+}
+
+type dbSnapshots struct {
+	lock      sync.Mutex
+	last      int64
+	snapshots map[string]*dbSnapshot
+}
+
+func (s *dbSnapshots) add(dbase db.Database) (*dbSnapshot, error) {
+	sdb, err := db.NewSnapshot(dbase)
+	if err != nil {
+		return nil, err
+	}
+	height, err := block.GetLastHeight(sdb)
+	if err != nil {
+		sdb.Close()
+		return nil, err
+	}
+
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	if len(s.snapshots) >= DBSnapshotLimit {
+		sdb.Close()
+		return nil, errors.InvalidStateError.Errorf(
+			"TooManySnapshots(limit=%d)", DBSnapshotLimit)
+	}
+	if s.snapshots == nil {
+		s.snapshots = make(map[string]*dbSnapshot)
+	}
+	s.last += 1
+	snapshot := &dbSnapshot{
+		id:       strconv.FormatInt(s.last, 10),
+		height:   height,
+		database: sdb,
+		created:  time.Now(),
+	}
+	snapshot.timer = time.AfterFunc(DBSnapshotTTL, func() {
+		s.expire(snapshot)
+	})
+	s.snapshots[snapshot.id] = snapshot
+	return snapshot, nil
+}
+
+// expire releases the snapshot if it's not released yet.
+func (s *dbSnapshots) expire(snapshot *dbSnapshot) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	if s.snapshots[snapshot.id] != snapshot {
+		return
+	}
+	delete(s.snapshots, snapshot.id)
+	snapshot.database.Close()
+}
+
+func (s *dbSnapshots) get(id string) (*dbSnapshot, error) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	if snapshot, ok := s.snapshots[id]; ok {
+		return snapshot, nil
+	}
+	return nil, errors.NotFoundError.Errorf("SnapshotNotFound(id=%s)", id)
+}
+
+func (s *dbSnapshots) release(id string) error {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	snapshot, ok := s.snapshots[id]
+	if !ok {
+		return errors.NotFoundError.Errorf("SnapshotNotFound(id=%s)", id)
+	}
+	snapshot.timer.Stop()
+	delete(s.snapshots, id)
+	return snapshot.database.Close()
+}
+
+func (s *dbSnapshots) releaseAll() {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	for id, snapshot := range s.snapshots {
+		snapshot.timer.Stop()
+		snapshot.database.Close()
+		delete(s.snapshots, id)
+	}
+}
+
+func (s *dbSnapshots) list() []module.DBSnapshot {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	snapshots := make([]module.DBSnapshot, 0, len(s.snapshots))
+	for _, snapshot := range s.snapshots {
+		snapshots = append(snapshots, snapshot)
+	}
+	sort.Slice(snapshots, func(i, j int) bool {
+		return snapshots[i].CreatedAt().Before(snapshots[j].CreatedAt())
+	})
+	return snapshots
+}
+
+var _ module.DBSnapshotManager = (*singleChain)(nil)
+
+func (c *singleChain) PinDBSnapshot() (module.DBSnapshot, error) {
+	c.dbLock.RLock()
+	defer c.dbLock.RUnlock()
+
+	if c.database == nil {
+		return nil, errors.InvalidStateError.New("NoDatabase")
+	}
+	return c.snapshots.add(c.database)
+}
+
+func (c *singleChain) GetDBSnapshot(id string) (module.DBSnapshot, error) {
+	return c.snapshots.get(id)
+}
+
+func (c *singleChain) ReleaseDBSnapshot(id string) error {
+	return c.snapshots.release(id)
+}
+
+func (c *singleChain) DBSnapshots() []module.DBSnapshot {
+	return c.snapshots.list()
+}
@@ -0,0 +1,70 @@
+/*
+ * Copyright 2026 ICON Foundation
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package chain
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/icon-project/goloop/common/db"
+	"github.com/icon-project/goloop/common/errors"
+)
+
+func TestDBSnapshots_Limit(t *testing.T) {
+	dbase := db.NewMapDB()
+	var ss dbSnapshots
+	defer ss.releaseAll()
+
+	for i := 0; i < DBSnapshotLimit; i++ {
+		_, err := ss.add(dbase)
+		assert.NoError(t, err)
+	}
+	_, err := ss.add(dbase)
+	assert.True(t, errors.InvalidStateError.Equals(err), "err=%+v", err)
+
+	assert.NoError(t, ss.release("1"))
+	_, err = ss.add(dbase)
+	assert.NoError(t, err)
+	assert.Len(t, ss.list(), DBSnapshotLimit)
+}
+
+func TestDBSnapshots_Expire(t *testing.T) {
+	dbase := db.NewMapDB()
+	var ss dbSnapshots
+	defer ss.releaseAll()
+
+	s1, err := ss.add(dbase)
+	assert.NoError(t, err)
+	s2, err := ss.add(dbase)
+	assert.NoError(t, err)
+	assert.Equal(t, s1.CreatedAt().Add(DBSnapshotTTL), s1.ExpiresAt())
+
+	ss.expire(s1)
+	_, err = ss.get(s1.ID())
+	assert.True(t, errors.NotFoundError.Equals(err), "err=%+v", err)
+	assert.True(t, errors.NotFoundError.Equals(ss.release(s1.ID())))
+
+	s, err := ss.get(s2.ID())
+	assert.NoError(t, err)
+	assert.Equal(t, s2, s)
+
+	// expire after release doesn't affect others
+	assert.NoError(t, ss.release(s2.ID()))
+	ss.expire(s2)
+	assert.Len(t, ss.list(), 0)
+}
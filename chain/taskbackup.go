@@ -34,12 +34,28 @@ import (
 
 const TemporalBackupFile = ".backup"
 
+const BackupTask = "backup"
+
 type BackupInfo struct {
 	NID     common.HexInt32 `json:"nid"`
 	CID     common.HexInt32 `json:"cid"`
 	Channel string          `json:"channel"`
 	Height  int64           `json:"height"`
 	Codec   string          `json:"codec"`
+
+	// Type is empty for backups of old versions without manifest.
+	Type         string `json:"type,omitempty"`
+	Parent       string `json:"parent,omitempty"`
+	ParentHeight int64  `json:"parentHeight,omitempty"`
+}
+
+// BackupParams is parameters of BackupTask. If Parent is specified, then
+// only files changed since the parent are written.
+type BackupParams struct {
+	File   string   `json:"file"`
+	Extra  []string `json:"extra,omitempty"`
+	Type   string   `json:"type,omitempty"`
+	Parent string   `json:"parent,omitempty"`
 }
 
 var backupStates = map[State]string{
@@ -53,12 +69,17 @@ type taskBackup struct {
 	chain   *singleChain
 	file    string
 	extra   []string
+	typ     string
+	parent  string
 	fd      io.WriteCloser
 	zw      *zip.Writer
 	current int32
 	total   int32
 	stop    int32
 	result  resultStore
+
+	parentFiles map[string]*BackupFile
+	manifest    BackupManifest
 }
 
 func (t *taskBackup) String() string {
@@ -107,16 +128,27 @@ func (t *taskBackup) Start() (ret error) {
 		return err
 	}
 
-	t.fd = tmp
-	t.zw = zip.NewWriter(tmp)
-
-	if err := writeBackupInfo(t.zw, &BackupInfo{
+	info := &BackupInfo{
 		NID:     common.HexInt32{Value: int32(t.chain.NID())},
 		CID:     common.HexInt32{Value: int32(t.chain.CID())},
 		Channel: t.chain.Channel(),
 		Height:  t.chain.lastBlockHeight(),
 		Codec:   codec.BC.Name(),
-	}); err != nil {
+		Type:    t.typ,
+	}
+	if t.parent != "" {
+		height, err := t._loadParent(info)
+		if err != nil {
+			return err
+		}
+		info.Parent = path.Base(t.parent)
+		info.ParentHeight = height
+	}
+
+	t.fd = tmp
+	t.zw = zip.NewWriter(tmp)
+
+	if err := writeBackupInfo(t.zw, info); err != nil {
 		return err
 	}
 
@@ -135,7 +167,85 @@ func (t *taskBackup) Start() (ret error) {
 	return nil
 }
 
-func zipWrite(writer *zip.Writer, p, n string, on func(int64) error) error {
+// _loadParent loads the manifest of the parent backup. It returns the
+// height of the parent.
+func (t *taskBackup) _loadParent(info *BackupInfo) (int64, error) {
+	zr, err := zip.OpenReader(t.parent)
+	if err != nil {
+		return 0, errors.IllegalArgumentError.Wrapf(err,
+			"ZipOpenFailure(parent=%s)", t.parent)
+	}
+	defer zr.Close()
+
+	pi, err := ReadBackupInfo(&zr.Reader)
+	if err != nil {
+		return 0, errors.IllegalArgumentError.Wrap(err, "InvalidBackupInfo")
+	}
+	if pi.NID != info.NID || pi.CID != info.CID || pi.Channel != info.Channel ||
+		pi.Codec != info.Codec || pi.Height > info.Height {
+		return 0, errors.IllegalArgumentError.Errorf(
+			"IncompatibleParent(parent=%s,height=%d)", path.Base(t.parent), pi.Height)
+	}
+	manifest, err := ReadBackupManifest(&zr.Reader)
+	if err != nil {
+		return 0, err
+	}
+	if manifest == nil {
+		return 0, errors.IllegalArgumentError.Errorf(
+			"NoManifestInParent(parent=%s)", path.Base(t.parent))
+	}
+	t.parentFiles = manifest.fileMap()
+	for _, f := range manifest.Files {
+		if f.Backup == "" {
+			f.Backup = path.Base(t.parent)
+		}
+	}
+	return pi.Height, nil
+}
+
+// _writeFile writes the file to the backup and adds it to the manifest.
+// If the file is not changed since the parent, then it refers to the backup
+// having it.
+func (t *taskBackup) _writeFile(p, n string, st fs.FileInfo) error {
+	bf := &BackupFile{
+		Name:    n,
+		Size:    st.Size(),
+		ModTime: st.ModTime().UnixNano(),
+	}
+	if pf, ok := t.parentFiles[n]; ok && pf.Size == bf.Size && pf.ModTime == bf.ModTime {
+		bf.SHA256 = pf.SHA256
+		bf.Backup = pf.Backup
+		t.manifest.Files = append(t.manifest.Files, bf)
+		return t.OnWrite(0)
+	}
+
+	p2 := path.Join(p, n)
+	fd, err := os.Open(p2)
+	if err != nil {
+		return errors.Wrapf(err, "writeToZip: fail to open %s", p2)
+	}
+	defer fd.Close()
+
+	fh, err := zip.FileInfoHeader(st)
+	if err != nil {
+		return errors.Wrapf(err, "writeToZip: fail to make header for %s", p2)
+	}
+	fh.Name = n
+	fh.Method = zip.Deflate
+	zf, err := t.zw.CreateHeader(fh)
+	if err != nil {
+		return errors.Wrapf(err, "writeToZip: fail to create entry %s", n)
+	}
+	cr := NewChecksumReader(fd)
+	if _, err := io.Copy(zf, cr); err != nil {
+		return errors.Wrap(err, "writeToZip: fail to copy")
+	}
+	bf.SHA256 = cr.Checksum()
+	t.manifest.Files = append(t.manifest.Files, bf)
+	return t.OnWrite(st.Size())
+}
+
+func walkFiles(p, n string, on func(p, n string, st fs.FileInfo) error) error {
 	p2 := path.Join(p, n)
 	st, err := os.Stat(p2)
 	if errors.Is(err, fs.ErrNotExist) {
@@ -144,32 +254,7 @@ func zipWrite(writer *zip.Writer, p, n string, on func(int64) error) error {
 		return errors.Wrap(err, "writeToZip: FAIL on os.State")
 	}
 	if st.Mode().IsRegular() {
-		fd, err := os.Open(p2)
-		defer fd.Close()
-		if err != nil {
-			return errors.Wrapf(err, "writeToZip: fail to open %s", p2)
-		}
-
-		fh, err := zip.FileInfoHeader(st)
-		if err != nil {
-			return errors.Wrapf(err, "writeToZip: fail to make header for %s", p2)
-		}
-		fh.Name = n
-		fh.Method = zip.Deflate
-		zf, err := writer.CreateHeader(fh)
-		if err != nil {
-			return err
-		}
-		if err != nil {
-			return errors.Wrapf(err, "writeToZip: fail to create entry %s", n)
-		}
-		if _, err := io.Copy(zf, fd); err != nil {
-			return errors.Wrap(err, "writeToZip: fail to copy")
-		}
-		if err := on(st.Size()); err != nil {
-			return err
-		}
-		return nil
+		return on(p, n, st)
 	} else if !st.IsDir() {
 		return nil
 	}
@@ -183,7 +268,7 @@ func zipWrite(writer *zip.Writer, p, n string, on func(int64) error) error {
 		return fis[i].Name() < fis[j].Name()
 	})
 	for _, fi := range fis {
-		if err := zipWrite(writer, p, path.Join(n, fi.Name()), on); err != nil {
+		if err := walkFiles(p, path.Join(n, fi.Name()), on); err != nil {
 			return err
 		}
 	}
@@ -260,12 +345,12 @@ func (t *taskBackup) _backup() error {
 	}
 
 	for _, name := range names {
-		if err := zipWrite(t.zw, chainDir, name, t.OnWrite); err != nil {
+		if err := walkFiles(chainDir, name, t._writeFile); err != nil {
 			return err
 		}
 	}
 
-	return nil
+	return writeBackupManifest(t.zw, &t.manifest)
 }
 
 func (t *taskBackup) Stop() {
@@ -287,7 +372,44 @@ func newTaskBackup(chain *singleChain, file string, extra []string) chainTask {
 		chain: chain,
 		file:  file,
 		extra: extra,
+		typ:   BackupTypeFull,
+	}
+}
+
+func taskBackupFactory(chain *singleChain, params json.RawMessage) (chainTask, error) {
+	var p BackupParams
+	if err := json.Unmarshal(params, &p); err != nil {
+		return nil, err
+	}
+	if p.File == "" {
+		return nil, errors.IllegalArgumentError.New("NoBackupFile")
 	}
+	switch p.Type {
+	case "", BackupTypeFull:
+		if p.Parent != "" {
+			return nil, errors.IllegalArgumentError.New("ParentForFullBackup")
+		}
+		p.Type = BackupTypeFull
+	case BackupTypeIncremental, BackupTypeDifferential:
+		if p.Parent == "" {
+			return nil, errors.IllegalArgumentError.Errorf(
+				"NoParentBackup(type=%s)", p.Type)
+		}
+	default:
+		return nil, errors.IllegalArgumentError.Errorf(
+			"InvalidBackupType(type=%s)", p.Type)
+	}
+	return &taskBackup{
+		chain:  chain,
+		file:   p.File,
+		extra:  p.Extra,
+		typ:    p.Type,
+		parent: p.Parent,
+	}, nil
+}
+
+func init() {
+	registerTaskFactory(BackupTask, taskBackupFactory)
 }
 
 func writeBackupInfo(zw *zip.Writer, info *BackupInfo) error {
@@ -0,0 +1,121 @@
+/*
+ * Copyright 2026 ICON Foundation
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package chain
+
+import (
+	"archive/zip"
+	"os"
+	"path"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/icon-project/goloop/common"
+	"github.com/icon-project/goloop/common/codec"
+	"github.com/icon-project/goloop/common/errors"
+)
+
+func writeTestFile(t *testing.T, p string, content string, mt time.Time) {
+	assert.NoError(t, os.MkdirAll(path.Dir(p), 0755))
+	assert.NoError(t, os.WriteFile(p, []byte(content), 0644))
+	assert.NoError(t, os.Chtimes(p, mt, mt))
+}
+
+// testBackup writes the backup of files in the directory like taskBackup
+// does, and returns the manifest.
+func testBackup(t *testing.T, dir, file, typ, parent string, height int64) (*BackupManifest, error) {
+	info := &BackupInfo{
+		NID:     common.HexInt32{Value: 1},
+		CID:     common.HexInt32{Value: 1},
+		Channel: "test",
+		Height:  height,
+		Codec:   codec.BC.Name(),
+		Type:    typ,
+	}
+	task := &taskBackup{file: file, typ: typ, parent: parent}
+	if parent != "" {
+		ph, err := task._loadParent(info)
+		if err != nil {
+			return nil, err
+		}
+		info.Parent = path.Base(parent)
+		info.ParentHeight = ph
+	}
+	fd, err := os.Create(file)
+	assert.NoError(t, err)
+	defer fd.Close()
+	task.zw = zip.NewWriter(fd)
+	assert.NoError(t, writeBackupInfo(task.zw, info))
+	assert.NoError(t, walkFiles(dir, DefaultDBDir, task._writeFile))
+	assert.NoError(t, writeBackupManifest(task.zw, &task.manifest))
+	assert.NoError(t, task.zw.Close())
+	return &task.manifest, nil
+}
+
+func zipNamesOf(t *testing.T, file string) []string {
+	zr, err := zip.OpenReader(file)
+	assert.NoError(t, err)
+	defer zr.Close()
+	var names []string
+	for _, f := range zr.File {
+		names = append(names, f.Name)
+	}
+	return names
+}
+
+func TestBackup_Incremental(t *testing.T) {
+	base := t.TempDir()
+	dir := path.Join(base, "chain")
+	mt := time.Unix(1700000000, 123456789)
+	writeTestFile(t, path.Join(dir, DefaultDBDir, "a"), "a0", mt)
+	writeTestFile(t, path.Join(dir, DefaultDBDir, "b"), "b0", mt)
+
+	full := path.Join(base, "full.zip")
+	m1, err := testBackup(t, dir, full, BackupTypeFull, "", 10)
+	assert.NoError(t, err)
+	assert.Len(t, m1.Files, 2)
+	assert.Equal(t, mt.UnixNano(), m1.Files[0].ModTime)
+	assert.Equal(t, []string{"db/a", "db/b", BackupManifestFile}, zipNamesOf(t, full))
+
+	writeTestFile(t, path.Join(dir, DefaultDBDir, "b"), "b1", mt.Add(time.Second))
+	writeTestFile(t, path.Join(dir, DefaultDBDir, "c"), "c1", mt)
+
+	inc := path.Join(base, "inc.zip")
+	m2, err := testBackup(t, dir, inc, BackupTypeIncremental, full, 20)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"db/b", "db/c", BackupManifestFile}, zipNamesOf(t, inc))
+	if assert.Len(t, m2.Files, 3) {
+		assert.Equal(t, "full.zip", m2.Files[0].Backup)
+		assert.Equal(t, m1.Files[0].SHA256, m2.Files[0].SHA256)
+		assert.Empty(t, m2.Files[1].Backup)
+		assert.NotEqual(t, m1.Files[1].SHA256, m2.Files[1].SHA256)
+		assert.Empty(t, m2.Files[2].Backup)
+	}
+	assert.Equal(t, []string{"full.zip"}, m2.Backups())
+
+	// unchanged files of the incremental refer to the backup having them
+	inc2 := path.Join(base, "inc2.zip")
+	m3, err := testBackup(t, dir, inc2, BackupTypeIncremental, inc, 30)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{BackupManifestFile}, zipNamesOf(t, inc2))
+	assert.Equal(t, []string{"full.zip", "inc.zip"}, m3.Backups())
+
+	// parent can't be higher than the backup
+	_, err = testBackup(t, dir, path.Join(base, "low.zip"), BackupTypeIncremental, inc, 15)
+	assert.True(t, errors.IllegalArgumentError.Equals(err), "err=%+v", err)
+}
@@ -18,6 +18,7 @@ package chain
 
 import (
 	"github.com/icon-project/goloop/common/errors"
+	"github.com/icon-project/goloop/common/logsindex"
 )
 
 type taskConsensus struct {
@@ -62,6 +63,24 @@ func (t *taskConsensus) _start(c *singleChain) error {
 	if err := c.cs.Start(); err != nil {
 		return err
 	}
+	if c.cfg.LogsIndex {
+		li, err := logsindex.NewIndexer(c.database, c.bm, c.GenesisStorage().Height(), c.logger)
+		if err != nil {
+			return err
+		}
+		li.Start()
+		c.li = li
+	}
+	if c.cfg.StatePruning > 0 {
+		sp, err := c.newStatePruner()
+		if err != nil {
+			return err
+		}
+		if err := sp.Start(); err != nil {
+			return err
+		}
+		c.sp = sp
+	}
 	c.srv.SetChain(c.cfg.Channel, c)
 	if err := c.nm.Start(); err != nil {
 		return err
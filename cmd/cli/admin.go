@@ -145,6 +145,13 @@ func NewChainCmd(parentCmd *cobra.Command, parentVc *viper.Viper) (*cobra.Comman
 				param.NephewsLimit = &nephewsLimit
 			}
 			param.ValidateTxOnSend, _ = fs.GetBool("validate_tx_on_send")
+			param.StateSync, _ = fs.GetString("state_sync")
+			param.TxPoolPolicy, _ = fs.GetString("tx_pool_policy")
+			param.SenderTxCount, _ = fs.GetInt("sender_tx_count")
+			param.SenderTxBytes, _ = fs.GetInt("sender_tx_bytes")
+			param.PeerTxRate, _ = fs.GetInt("peer_tx_rate")
+			param.LogsIndex, _ = fs.GetBool("logs_index")
+			param.StatePruning, _ = fs.GetInt("state_pruning")
 
 			var buf *bytes.Buffer
 			if len(genesisZip) > 0 {
@@ -202,6 +209,13 @@ func NewChainCmd(parentCmd *cobra.Command, parentVc *viper.Viper) (*cobra.Comman
 	joinFlags.Int("children_limit", -1, "Maximum number of child connections (-1: uses system default value)")
 	joinFlags.Int("nephews_limit", -1, "Maximum number of nephew connections (-1: uses system default value)")
 	joinFlags.Bool("validate_tx_on_send", false, "Validate transaction on send")
+	joinFlags.String("state_sync", chain.StateSyncDefault, "State sync mode (node,flat)")
+	joinFlags.String("tx_pool_policy", chain.TxPoolPolicyDefault, "Transaction pool policy (fifo,priority,fairness)")
+	joinFlags.Int("sender_tx_count", 0, "Maximum number of pending transactions of a sender (0: no limit)")
+	joinFlags.Int("sender_tx_bytes", 0, "Maximum bytes of pending transactions of a sender (0: no limit)")
+	joinFlags.Int("peer_tx_rate", 0, "Maximum number of transactions per second from a peer (0: no limit)")
+	joinFlags.Bool("logs_index", false, "Index logs blooms of blocks for icx_getLogs")
+	joinFlags.Int("state_pruning", 0, "Number of recent world states to keep with online pruning (0: disable)")
 
 	leaveCmd := &cobra.Command{
 		Use:   "leave CID",
@@ -391,8 +405,12 @@ func NewChainCmd(parentCmd *cobra.Command, parentVc *viper.Viper) (*cobra.Comman
 		RunE: func(cmd *cobra.Command, args []string) error {
 			fs := cmd.Flags()
 			manual, _ := fs.GetBool("manual")
+			backupType, _ := fs.GetString("type")
+			base, _ := fs.GetString("base")
 			param := &node.ChainBackupParam{
 				Manual: manual,
+				Type:   backupType,
+				Base:   base,
 			}
 			var v string
 			reqUrl := node.UrlChain + "/" + args[0] + "/backup"
@@ -407,6 +425,8 @@ func NewChainCmd(parentCmd *cobra.Command, parentVc *viper.Viper) (*cobra.Comman
 	rootCmd.AddCommand(backupCmd)
 	backupFlags := backupCmd.Flags()
 	backupFlags.Bool("manual", false, "Manual backup mode (just release database)")
+	backupFlags.String("type", "full", "Backup type(full, incremental, differential)")
+	backupFlags.String("base", "", "Parent backup for incremental or differential backup (default:latest one)")
 
 	genesisCmd := &cobra.Command{
 		Use:   "genesis CID FILE",
@@ -577,6 +597,7 @@ func NewSystemCmd(parentCmd *cobra.Command, parentVc *viper.Viper) (*cobra.Comma
 
 	NewBackupCmd(rootCmd, &adminClient)
 	NewRestoreCmd(rootCmd, &adminClient)
+	NewDBCmd(rootCmd, &adminClient)
 
 	return rootCmd, vc
 }
@@ -662,6 +683,165 @@ func NewRestoreCmd(parent *cobra.Command, client *node.UnixDomainSockHttpClient)
 	rootCmd.AddCommand(stopCmd)
 }
 
+func NewDBCmd(parent *cobra.Command, client *node.UnixDomainSockHttpClient) {
+	rootCmd := &cobra.Command{
+		Use:   "db",
+		Short: "Browse chain database",
+	}
+	parent.AddCommand(rootCmd)
+
+	getCmd := &cobra.Command{
+		Use:   "get CID BUCKET KEY",
+		Short: "Get the value of the key in the bucket",
+		Args:  ArgsWithDefaultErrorFunc(cobra.ExactArgs(3)),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			params := &url.Values{}
+			if snapshot, _ := cmd.Flags().GetString("snapshot"); snapshot != "" {
+				params.Add(node.ParamSnapshot, snapshot)
+			}
+			var v []byte
+			key := strings.TrimPrefix(args[2], "0x")
+			reqUrl := node.UrlDB + "/" + args[0] + "/" + args[1] + "/" + key
+			if _, err := client.Get(reqUrl, &v, params); err != nil {
+				return err
+			}
+			if v == nil {
+				fmt.Println("null")
+			} else {
+				fmt.Println("0x" + hex.EncodeToString(v))
+			}
+			return nil
+		},
+	}
+	rootCmd.AddCommand(getCmd)
+	getCmd.Flags().String("snapshot", "", "ID of pinned database snapshot")
+
+	listCmd := &cobra.Command{
+		Use:   "ls CID BUCKET",
+		Short: "List entries in the bucket",
+		Args:  ArgsWithDefaultErrorFunc(cobra.ExactArgs(2)),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			fs := cmd.Flags()
+			params := &url.Values{}
+			for _, name := range []string{
+				node.ParamPrefix, node.ParamCursor, node.ParamSnapshot,
+			} {
+				if v, _ := fs.GetString(name); v != "" {
+					params.Add(name, v)
+				}
+			}
+			if limit, _ := fs.GetInt(node.ParamLimit); limit > 0 {
+				params.Add(node.ParamLimit, strconv.Itoa(limit))
+			}
+			for _, name := range []string{node.ParamReverse, node.ParamDecode} {
+				if v, _ := fs.GetBool(name); v {
+					params.Add(name, "true")
+				}
+			}
+			reqUrl := node.UrlDB + "/" + args[0] + "/" + args[1]
+			resp, err := client.Get(reqUrl, nil, params)
+			if err != nil {
+				return err
+			}
+			return JsonPrettyCopyAndClose(os.Stdout, resp.Body)
+		},
+	}
+	rootCmd.AddCommand(listCmd)
+	listFlags := listCmd.Flags()
+	listFlags.String(node.ParamPrefix, "", "Prefix of keys in hex")
+	listFlags.String(node.ParamCursor, "", "Cursor returned as 'next' by the previous listing")
+	listFlags.Int(node.ParamLimit, node.DefaultDBListLimit, "Maximum number of entries")
+	listFlags.Bool(node.ParamReverse, false, "List in descending order of keys")
+	listFlags.Bool(node.ParamDecode, false, "Decode entries of known buckets")
+	listFlags.String(node.ParamSnapshot, "", "ID of pinned database snapshot")
+
+	exportCmd := &cobra.Command{
+		Use:   "export CID BUCKET FILE",
+		Short: "Export entries in the bucket to the file",
+		Args:  ArgsWithDefaultErrorFunc(cobra.ExactArgs(3)),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			fs := cmd.Flags()
+			params := &url.Values{}
+			for _, name := range []string{node.ParamPrefix, node.ParamSnapshot} {
+				if v, _ := fs.GetString(name); v != "" {
+					params.Add(name, v)
+				}
+			}
+			f, err := os.OpenFile(args[2], os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0644)
+			if err != nil {
+				return err
+			}
+			defer f.Close()
+			reqUrl := node.UrlDB + "/" + args[0] + "/" + args[1] + node.UrlExport
+			resp, err := client.Get(reqUrl, nil, params)
+			if err != nil {
+				return err
+			}
+			defer resp.Body.Close()
+			if _, err = io.Copy(f, resp.Body); err != nil {
+				return fmt.Errorf("fail to write file err:%+v", err)
+			}
+			fmt.Println(args[2])
+			return nil
+		},
+	}
+	rootCmd.AddCommand(exportCmd)
+	exportFlags := exportCmd.Flags()
+	exportFlags.String(node.ParamPrefix, "", "Prefix of keys in hex")
+	exportFlags.String(node.ParamSnapshot, "", "ID of pinned database snapshot")
+
+	NewDBSnapshotCmd(rootCmd, client)
+}
+
+func NewDBSnapshotCmd(parent *cobra.Command, client *node.UnixDomainSockHttpClient) {
+	rootCmd := &cobra.Command{
+		Use:   "snapshot",
+		Short: "Manage pinned database snapshots",
+	}
+	parent.AddCommand(rootCmd)
+
+	rootCmd.AddCommand(
+		&cobra.Command{
+			Use:   "ls CID",
+			Short: "List pinned snapshots",
+			Args:  ArgsWithDefaultErrorFunc(cobra.ExactArgs(1)),
+			RunE: func(cmd *cobra.Command, args []string) error {
+				resp, err := client.Get(node.UrlDB+"/"+args[0]+node.UrlSnapshot, nil)
+				if err != nil {
+					return err
+				}
+				return JsonPrettyCopyAndClose(os.Stdout, resp.Body)
+			},
+		},
+		&cobra.Command{
+			Use:   "pin CID",
+			Short: "Pin a snapshot of current database",
+			Args:  ArgsWithDefaultErrorFunc(cobra.ExactArgs(1)),
+			RunE: func(cmd *cobra.Command, args []string) error {
+				v := new(node.DBSnapshotView)
+				if _, err := client.PostWithJson(node.UrlDB+"/"+args[0]+node.UrlSnapshot, nil, v); err != nil {
+					return err
+				}
+				return JsonPrettyPrintln(os.Stdout, v)
+			},
+		},
+		&cobra.Command{
+			Use:   "release CID ID",
+			Short: "Release the pinned snapshot",
+			Args:  ArgsWithDefaultErrorFunc(cobra.ExactArgs(2)),
+			RunE: func(cmd *cobra.Command, args []string) error {
+				var v string
+				reqUrl := node.UrlDB + "/" + args[0] + node.UrlSnapshot + "/" + args[1]
+				if _, err := client.Delete(reqUrl, &v); err != nil {
+					return err
+				}
+				fmt.Println(v)
+				return nil
+			},
+		},
+	)
+}
+
 func NewUserCmd(parentCmd *cobra.Command, parentVc *viper.Viper) (*cobra.Command, *viper.Viper) {
 	var adminClient node.UnixDomainSockHttpClient
 	rootCmd, vc := NewCommand(parentCmd, parentVc, "user", "User management")
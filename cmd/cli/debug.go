@@ -17,6 +17,8 @@
 package cli
 
 import (
+	"encoding/json"
+	"fmt"
 	"net/http"
 	"os"
 
@@ -24,6 +26,7 @@ import (
 	"github.com/spf13/viper"
 
 	"github.com/icon-project/goloop/client"
+	"github.com/icon-project/goloop/common/intconv"
 	"github.com/icon-project/goloop/server/jsonrpc"
 	v3 "github.com/icon-project/goloop/server/v3"
 )
@@ -68,5 +71,48 @@ func NewDebugCmd(parentCmd *cobra.Command, parentVc *viper.Viper) (*cobra.Comman
 	}
 	rootCmd.AddCommand(traceCmd)
 
+	traceBlockCmd := &cobra.Command{
+		Use:   "traceblock HEIGHT",
+		Short: "Get call trees and step profile of the block",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			height, err := intconv.ParseInt(args[0], 64)
+			if err != nil {
+				return err
+			}
+			param := &v3.BlockHeightParam{
+				Height: jsonrpc.HexInt(intconv.FormatInt(height)),
+			}
+			trace, err := debugClient.Do("debug_traceBlock", param, nil)
+			if err != nil {
+				return err
+			}
+			return JsonPrettyPrintln(os.Stdout, trace.Result)
+		},
+	}
+	rootCmd.AddCommand(traceBlockCmd)
+
+	simulateCmd := &cobra.Command{
+		Use:   "simulate FILE",
+		Short: "Simulate transactions with the parameters in the file",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			bs, err := os.ReadFile(args[0])
+			if err != nil {
+				return err
+			}
+			param := json.RawMessage(bs)
+			if !json.Valid(param) {
+				return fmt.Errorf("invalid JSON file=%s", args[0])
+			}
+			result, err := debugClient.Do("debug_simulateTransactions", param, nil)
+			if err != nil {
+				return err
+			}
+			return JsonPrettyPrintln(os.Stdout, result.Result)
+		},
+	}
+	rootCmd.AddCommand(simulateCmd)
+
 	return rootCmd, vc
 }
@@ -11,6 +11,7 @@ import (
 
 	"github.com/spf13/cobra"
 
+	"github.com/icon-project/goloop/common/crypto"
 	"github.com/icon-project/goloop/common/wallet"
 )
 
@@ -50,9 +51,23 @@ func newKeystoreGenCmd(c string) *cobra.Command {
 	interactive := flags.BoolP("interactive", "i", false, "Interactive mode for password input")
 	secret := flags.StringP("secret", "s", "", "KeySecret file path")
 	pass := flags.StringP("password", "p", "gochain", "Password for the keystore")
+	bls := flags.Bool("bls", false, "Generate BLS12-381 keystore")
 
 	cmd.Run = func(cmd *cobra.Command, args []string) {
 		pb := getPasswordFromFlags("Password: ", interactive, secret, pass)
+		if *bls {
+			sk, pk := crypto.GenerateBLSKeyPair()
+			ks, err := wallet.EncryptBLSKeyAsKeyStore(sk, pb)
+			if err != nil {
+				log.Panicf("Fail to generate keystore err=%+v", err)
+			}
+			if err := os.WriteFile(*out, ks, 0600); err != nil {
+				log.Panicf("Fail to write keystore err=%+v", err)
+			}
+			fmt.Printf("0x%s ==> %s\n",
+				hex.EncodeToString(pk.Bytes()), *out)
+			return
+		}
 		w := wallet.New()
 		ks, err := wallet.KeyStoreFromWallet(w, pb)
 		if err != nil {
@@ -9,6 +9,7 @@ import (
 	"github.com/spf13/cobra"
 	"github.com/spf13/viper"
 
+	"github.com/icon-project/goloop/common"
 	"github.com/icon-project/goloop/common/crypto"
 	"github.com/icon-project/goloop/common/errors"
 	"github.com/icon-project/goloop/common/log"
@@ -27,7 +28,20 @@ type ServerConfig struct {
 	KeyPlugin     string            `json:"key_plugin,omitempty"`
 	KeyPlgOptions map[string]string `json:"key_plugin_options,omitempty"`
 
-	Wallet module.Wallet `json:"-"`
+	KeyPKCS11Lib   string `json:"key_pkcs11_lib,omitempty"`
+	KeyPKCS11Token string `json:"key_pkcs11_token,omitempty"`
+	KeyPKCS11Label string `json:"key_pkcs11_label,omitempty"`
+	KeyPKCS11ID    string `json:"key_pkcs11_id,omitempty"`
+
+	KeySigner        string `json:"key_signer,omitempty"`
+	KeySignerAddress string `json:"key_signer_address,omitempty"`
+
+	BLSKeyStoreData  json.RawMessage `json:"bls_key_store,omitempty"`
+	BLSKeyStorePass  string          `json:"bls_key_password,omitempty"`
+	isPresentBLSPass bool
+
+	Wallet    module.Wallet     `json:"-"`
+	BLSWallet module.BaseWallet `json:"-"`
 
 	LogLevel     string               `json:"log_level"`
 	ConsoleLevel string               `json:"console_level"`
@@ -39,6 +53,12 @@ func (cfg *ServerConfig) GetAddress() module.Address {
 	if cfg.Wallet != nil {
 		return cfg.Wallet.Address()
 	}
+	if cfg.KeySigner != "" {
+		if addr, err := common.NewAddressFromString(cfg.KeySignerAddress); err == nil {
+			return addr
+		}
+		return nil
+	}
 	if len(cfg.KeyStoreData) > 0 {
 		if addr, err := wallet.ReadAddressFromKeyStore(cfg.KeyStoreData); err == nil {
 			return addr
@@ -66,6 +86,20 @@ func (cfg *ServerConfig) MakesureWallet(gen bool) error {
 			return nil
 		}
 	}
+	if cfg.KeyPKCS11Lib != "" {
+		if w, err := wallet.OpenPKCS11(&wallet.PKCS11Config{
+			Library: cfg.KeyPKCS11Lib,
+			Token:   cfg.KeyPKCS11Token,
+			Label:   cfg.KeyPKCS11Label,
+			ID:      cfg.KeyPKCS11ID,
+			PIN:     cfg.KeyStorePass,
+		}); err != nil {
+			return err
+		} else {
+			cfg.Wallet = w
+			return nil
+		}
+	}
 
 	var privateKey *crypto.PrivateKey
 	if len(cfg.KeyStoreData) > 0 {
@@ -98,11 +132,44 @@ func (cfg *ServerConfig) MakesureWallet(gen bool) error {
 		cfg.KeyStoreData = ks
 	}
 
-	if w, err := wallet.NewFromPrivateKey(privateKey); err != nil {
+	w, err := wallet.NewFromPrivateKey(privateKey)
+	if err != nil {
 		return err
-	} else {
-		cfg.Wallet = w
 	}
+	if cfg.KeySigner != "" {
+		// the key in KeyStore is used to authenticate the node to the signer.
+		addr, err := common.NewAddressFromString(cfg.KeySignerAddress)
+		if err != nil {
+			return errors.Errorf("invalid key_signer_address=%q err=%+v",
+				cfg.KeySignerAddress, err)
+		}
+		if w, err = wallet.OpenRemote(&wallet.RemoteConfig{
+			Address: cfg.KeySigner,
+			Signer:  addr,
+			Client:  w,
+		}); err != nil {
+			return err
+		}
+	}
+	cfg.Wallet = w
+	return nil
+}
+
+// MakesureBLSWallet loads the BLS wallet from the BLS KeyStore if it's
+// configured. The node works without BLS wallet.
+func (cfg *ServerConfig) MakesureBLSWallet() error {
+	if cfg.BLSWallet != nil || len(cfg.BLSKeyStoreData) == 0 {
+		return nil
+	}
+	pass := cfg.BLSKeyStorePass
+	if pass == "" {
+		pass = DefaultKeyStorePass
+	}
+	w, err := wallet.NewBLSFromKeyStore(cfg.BLSKeyStoreData, []byte(pass))
+	if err != nil {
+		return errors.Errorf("fail to decrypt BLS KeyStore err=%+v", err)
+	}
+	cfg.BLSWallet = w
 	return nil
 }
 
@@ -132,6 +199,9 @@ func NewServerCmd(parentCmd *cobra.Command, parentVc *viper.Viper, version, buil
 		if err := cfg.MakesureWallet(true); err != nil {
 			return err
 		}
+		if err := cfg.MakesureBLSWallet(); err != nil {
+			return err
+		}
 		return nil
 	}
 	rootPFlags := rootCmd.PersistentFlags()
@@ -155,13 +225,21 @@ func NewServerCmd(parentCmd *cobra.Command, parentVc *viper.Viper, version, buil
 	rootPFlags.String("key_secret", "", "Secret (password) file for KeyStore")
 	rootPFlags.String("key_plugin", "", "KeyPlugin file for wallet")
 	rootPFlags.StringToString("key_plugin_options", nil, "KeyPlugin options")
+	rootPFlags.String("key_pkcs11_lib", "", "PKCS#11 library for wallet (key_secret for PIN)")
+	rootPFlags.String("key_pkcs11_token", "", "PKCS#11 token label (default: first token)")
+	rootPFlags.String("key_pkcs11_label", "", "PKCS#11 label of the secp256k1 key")
+	rootPFlags.String("key_pkcs11_id", "", "PKCS#11 ID of the secp256k1 key in hex")
+	rootPFlags.String("key_signer", "", "Remote signer for wallet (unix://<path> or tcp://<host>:<port>), KeyStore is used for authentication")
+	rootPFlags.String("key_signer_address", "", "Address of the key in the remote signer")
+	rootPFlags.String("bls_key_store", "", "KeyStore file for BLS wallet")
+	rootPFlags.String("bls_key_password", "", "Password for the BLS KeyStore file")
 	//
 	rootPFlags.String("log_forwarder_vendor", "", "LogForwarder vendor (fluentd,logstash)")
 	rootPFlags.String("log_forwarder_address", "", "LogForwarder address")
 	rootPFlags.String("log_forwarder_level", "info", "LogForwarder level")
 	rootPFlags.String("log_forwarder_name", "", "LogForwarder name")
 	rootPFlags.StringToString("log_forwarder_options", nil, "LogForwarder options, comma-separated 'key=value'")
-	rootPFlags.String("engines", "python", "Execution engines, comma-separated (python,java)")
+	rootPFlags.String("engines", "python", "Execution engines, comma-separated (python,java,wasm)")
 
 	rootPFlags.String("log_writer_filename", "", "Log filename (rotated files resides in same directory)")
 	rootPFlags.Int("log_writer_maxsize", 100, "Maximum log file size in MiB")
@@ -180,6 +258,9 @@ func NewServerCmd(parentCmd *cobra.Command, parentVc *viper.Viper, version, buil
 			if cfg.isPresentPass {
 				cfg.KeyStorePass = ""
 			}
+			if cfg.isPresentBLSPass {
+				cfg.BLSKeyStorePass = ""
+			}
 		},
 		RunE: func(cmd *cobra.Command, args []string) error {
 			saveFilePath := args[0]
@@ -272,7 +353,7 @@ func NewServerCmd(parentCmd *cobra.Command, parentVc *viper.Viper, version, buil
 			log.Printf("Version : %s", version)
 			log.Printf("Build   : %s", build)
 
-			n := node.NewNode(cfg.Wallet, &cfg.StaticConfig, logger)
+			n := node.NewNode(cfg.Wallet, cfg.BLSWallet, &cfg.StaticConfig, logger)
 			n.Start()
 			return nil
 		},
@@ -299,6 +380,9 @@ func MergeWithViper(vc *viper.Viper, cfg *ServerConfig) error {
 	if vc.GetString("key_secret") != "" || vc.GetString("key_password") != "" {
 		cfg.isPresentPass = true
 	}
+	if vc.GetString("bls_key_password") != "" {
+		cfg.isPresentBLSPass = true
+	}
 	cfgFilePath := vc.GetString("config")
 	//relative path from flag, env
 	nodeDir := vc.GetString("node_dir")
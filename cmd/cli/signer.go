@@ -0,0 +1,76 @@
+package cli
+
+import (
+	"encoding/hex"
+	"os"
+
+	"github.com/spf13/cobra"
+
+	"github.com/icon-project/goloop/common"
+	"github.com/icon-project/goloop/common/errors"
+	"github.com/icon-project/goloop/common/log"
+	"github.com/icon-project/goloop/common/wallet"
+	"github.com/icon-project/goloop/module"
+)
+
+func NewSignerCmd(c string) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   c,
+		Short: "Start remote signer for the validator key",
+		Args:  cobra.NoArgs,
+	}
+	flags := cmd.Flags()
+	keyStore := flags.String("key_store", "", "KeyStore file for the validator key")
+	interactive := flags.BoolP("interactive", "i", false, "Interactive mode for password input")
+	secret := flags.String("key_secret", "", "Secret (password) file for KeyStore")
+	pass := flags.String("key_password", "", "Password for the KeyStore file")
+	listen := flags.String("listen", "", "Listen address (unix://<path> or tcp://<host>:<port>)")
+	clients := flags.StringSlice("client", nil, "Addresses of allowed nodes (KeyStore of the node), comma-separated")
+	state := flags.String("state", "signer_state.json", "File path to keep last signed messages")
+	_ = cmd.MarkFlagRequired("key_store")
+	_ = cmd.MarkFlagRequired("listen")
+	_ = cmd.MarkFlagRequired("client")
+
+	cmd.RunE = func(cmd *cobra.Command, args []string) error {
+		ks, err := os.ReadFile(*keyStore)
+		if err != nil {
+			return errors.Wrapf(err, "fail to read KeyStore file=%s", *keyStore)
+		}
+		pb := getPasswordFromFlags("Password: ", interactive, secret, pass)
+		if len(pb) == 0 {
+			pb = []byte(DefaultKeyStorePass)
+		}
+		w, err := wallet.NewFromKeyStore(ks, pb)
+		if err != nil {
+			return errors.Wrap(err, "fail to decrypt KeyStore")
+		}
+		var addrs []module.Address
+		for _, client := range *clients {
+			addr, err := common.NewAddressFromString(client)
+			if err != nil {
+				return errors.Wrapf(err, "invalid client address=%s", client)
+			}
+			addrs = append(addrs, addr)
+		}
+
+		logger := log.WithFields(log.Fields{
+			log.FieldKeyWallet: hex.EncodeToString(w.Address().ID()),
+		})
+		s, err := wallet.NewRemoteSigner(w, addrs, *state, logger)
+		if err != nil {
+			return err
+		}
+		if err := s.Listen(*listen); err != nil {
+			return err
+		}
+		OnInterrupt(func() {
+			s.Close()
+		})
+		logger.Infof("Signer for %s listens on %s", w.Address(), *listen)
+		if err := s.Serve(); err != nil {
+			logger.Infof("Signer terminated err=%v", err)
+		}
+		return nil
+	}
+	return cmd
+}
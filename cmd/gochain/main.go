@@ -140,6 +140,13 @@ func main() {
 	flag.IntVar(&cfg.MaxBlockTxBytes, "max_block_tx_bytes", 0, "Maximum size of transactions in a block")
 	flag.StringVar(&cfg.NodeCache, "node_cache", chain.NodeCacheDefault, "Node cache (none,small,large)")
 	flag.BoolVar(&cfg.ValidateTxOnSend, "validate_tx_on_send", false, "Validate transaction on send")
+	flag.StringVar(&cfg.StateSync, "state_sync", chain.StateSyncDefault, "State sync mode (node,flat)")
+	flag.StringVar(&cfg.TxPoolPolicy, "tx_pool_policy", chain.TxPoolPolicyDefault, "Transaction pool policy (fifo,priority,fairness)")
+	flag.IntVar(&cfg.SenderTxCount, "sender_tx_count", 0, "Maximum number of pending transactions of a sender (0: no limit)")
+	flag.IntVar(&cfg.SenderTxBytes, "sender_tx_bytes", 0, "Maximum bytes of pending transactions of a sender (0: no limit)")
+	flag.IntVar(&cfg.PeerTxRate, "peer_tx_rate", 0, "Maximum number of transactions per second from a peer (0: no limit)")
+	flag.BoolVar(&cfg.LogsIndex, "logs_index", false, "Index logs blooms of blocks for icx_getLogs")
+	flag.IntVar(&cfg.StatePruning, "state_pruning", 0, "Number of recent world states to keep with online pruning (0: disable)")
 	cfg.ChildrenLimit = flag.Int("children_limit", -1, "Maximum number of child connections (-1: uses system default value)")
 	cfg.NephewsLimit = flag.Int("nephews_limit", -1, "Maximum number of nephew connections (-1: uses system default value)")
 	flag.StringVar(&cfg.LogLevel, "log_level", "debug", "Main log level")
@@ -153,7 +160,7 @@ func main() {
 	flag.Int64Var(&cfg.DefWaitTimeout, "default_wait_timeout", 0, "Default wait timeout in milli-second (0: disable)")
 	flag.Int64Var(&cfg.MaxWaitTimeout, "max_wait_timeout", 0, "Max wait timeout in milli-second (0: uses same value of default_wait_timeout)")
 	flag.Int64Var(&cfg.TxTimeout, "tx_timeout", 0, "Transaction timeout in milli-second (0: uses system default value)")
-	flag.StringVar(&cfg.Engines, "engines", "python", "Execution engines, comma-separated (python,java)")
+	flag.StringVar(&cfg.Engines, "engines", "python", "Execution engines, comma-separated (python,java,wasm)")
 	flag.IntVar(&cfg.WSMaxSession, "ws_max_session", server.DefaultWSMaxSession, "Websocket session limit (use -1 to disable)")
 	flag.StringVar(&lwCfg.Filename, "log_writer_filename", "", "Log filename")
 	flag.IntVar(&lwCfg.MaxSize, "log_writer_maxsize", 100, "Log file max size")
@@ -503,7 +510,7 @@ func Execute(cmd *cobra.Command, args []string) {
 	}
 	srv := server.NewManager(config, wallet, logger)
 	hex.EncodeToString(wallet.Address().ID())
-	c := chain.NewChain(wallet, nt, srv, pm, logger, &cfg.Config)
+	c := chain.NewChain(wallet, nil, nt, srv, pm, logger, &cfg.Config)
 	err = c.Init()
 	if err != nil {
 		log.Panicf("FAIL to initialize Chain err=%+v", err)
@@ -0,0 +1,230 @@
+/*
+ * Copyright 2026 ICON Foundation
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package crypto
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"io"
+	"math/big"
+
+	bls12381 "github.com/kilic/bls12-381"
+	"golang.org/x/crypto/hkdf"
+)
+
+// BLS signatures on BLS12-381 with public keys in G1 and signatures in G2.
+// Messages are augmented with public keys of signers, so signatures of the
+// same message can be aggregated without proofs of possession. Points are
+// kept in affine form, so they can be shared by goroutines.
+
+const (
+	// BLSPrivateKeyLen is the byte length of a BLS private key
+	BLSPrivateKeyLen = 32
+	// BLSPublicKeyLen is the byte length of a compressed BLS public key
+	BLSPublicKeyLen = 48
+	// BLSSignatureLen is the byte length of a compressed BLS signature
+	BLSSignatureLen = 96
+)
+
+var (
+	blsDST     = []byte("BLS_SIG_BLS12381G2_XMD:SHA-256_SSWU_RO_AUG_")
+	blsKeySalt = []byte("BLS-SIG-KEYGEN-SALT-")
+)
+
+// BLSPrivateKey is a type representing a BLS private key.
+type BLSPrivateKey struct {
+	real *big.Int
+}
+
+// BLSPublicKey is a type representing a BLS public key.
+type BLSPublicKey struct {
+	real *bls12381.PointG1
+}
+
+// BLSSignature is a type representing a BLS signature or an aggregation of
+// BLS signatures.
+type BLSSignature struct {
+	real *bls12381.PointG2
+}
+
+// Bytes returns bytes form of private key.
+func (key *BLSPrivateKey) Bytes() []byte {
+	bs := make([]byte, BLSPrivateKeyLen)
+	return key.real.FillBytes(bs)
+}
+
+// String returns the string representation.
+func (key *BLSPrivateKey) String() string {
+	return "0x" + hex.EncodeToString(key.Bytes())
+}
+
+// PublicKey generates a public key paired with itself.
+func (key *BLSPrivateKey) PublicKey() *BLSPublicKey {
+	g1 := bls12381.NewG1()
+	p := g1.MulScalarBig(g1.New(), g1.One(), key.real)
+	return &BLSPublicKey{real: g1.Affine(p)}
+}
+
+func blsHashToG2(pk *BLSPublicKey, msg []byte) (*bls12381.PointG2, error) {
+	return bls12381.NewG2().HashToCurve(append(pk.Bytes(), msg...), blsDST)
+}
+
+// Sign returns the signature of the message.
+func (key *BLSPrivateKey) Sign(msg []byte) (*BLSSignature, error) {
+	g2 := bls12381.NewG2()
+	h, err := blsHashToG2(key.PublicKey(), msg)
+	if err != nil {
+		return nil, err
+	}
+	p := g2.MulScalarBig(g2.New(), h, key.real)
+	return &BLSSignature{real: g2.Affine(p)}, nil
+}
+
+// Bytes returns the public key in a 48-byte compressed format.
+func (key *BLSPublicKey) Bytes() []byte {
+	return bls12381.NewG1().ToCompressed(key.real)
+}
+
+// Equal returns true if the given public key is same as this instance
+// semantically
+func (key *BLSPublicKey) Equal(key2 *BLSPublicKey) bool {
+	return bls12381.NewG1().Equal(key.real, key2.real)
+}
+
+// String returns the string representation.
+func (key *BLSPublicKey) String() string {
+	return "0x" + hex.EncodeToString(key.Bytes())
+}
+
+// Verify returns true if the signature is made for the message by the
+// private key of this public key.
+func (key *BLSPublicKey) Verify(msg []byte, sig *BLSSignature) bool {
+	return VerifyAggregatedBLSSignature([]*BLSPublicKey{key}, [][]byte{msg}, sig)
+}
+
+// Bytes returns the signature in a 96-byte compressed format.
+func (sig *BLSSignature) Bytes() []byte {
+	return bls12381.NewG2().ToCompressed(sig.real)
+}
+
+// String returns the string representation.
+func (sig *BLSSignature) String() string {
+	return "0x" + hex.EncodeToString(sig.Bytes())
+}
+
+// GenerateBLSKeyPair generates a BLS private and public key pair.
+func GenerateBLSKeyPair() (*BLSPrivateKey, *BLSPublicKey) {
+	ikm := make([]byte, 32)
+	if _, err := io.ReadFull(rand.Reader, ikm); err != nil {
+		panic(err)
+	}
+	sk, err := NewBLSPrivateKeyFromSeed(ikm)
+	if err != nil {
+		panic(err)
+	}
+	return sk, sk.PublicKey()
+}
+
+// NewBLSPrivateKeyFromSeed derives the private key from the seed at least
+// 32 bytes long. It follows KeyGen of the IETF BLS signature draft, so the
+// same seed always makes the same key.
+func NewBLSPrivateKeyFromSeed(ikm []byte) (*BLSPrivateKey, error) {
+	if len(ikm) < 32 {
+		return nil, errors.New("InvalidSeedLength")
+	}
+	q := bls12381.NewG1().Q()
+	salt := blsKeySalt
+	for {
+		h := sha256.Sum256(salt)
+		salt = h[:]
+		prk := hkdf.Extract(sha256.New, append(ikm[:len(ikm):len(ikm)], 0), salt)
+		okm := make([]byte, 48)
+		if _, err := io.ReadFull(hkdf.Expand(sha256.New, prk, []byte{0, 48}), okm); err != nil {
+			return nil, err
+		}
+		sk := new(big.Int).SetBytes(okm)
+		sk.Mod(sk, q)
+		if sk.Sign() != 0 {
+			return &BLSPrivateKey{real: sk}, nil
+		}
+	}
+}
+
+// ParseBLSPrivateKey parses private key and returns private key object.
+func ParseBLSPrivateKey(b []byte) (*BLSPrivateKey, error) {
+	if len(b) != BLSPrivateKeyLen {
+		return nil, errors.New("InvalidKeyLength")
+	}
+	sk := new(big.Int).SetBytes(b)
+	if sk.Sign() == 0 || sk.Cmp(bls12381.NewG1().Q()) >= 0 {
+		return nil, errors.New("InvalidKey")
+	}
+	return &BLSPrivateKey{real: sk}, nil
+}
+
+// ParseBLSPublicKey parses the public key in compressed format. It rejects
+// the identity.
+func ParseBLSPublicKey(b []byte) (*BLSPublicKey, error) {
+	g1 := bls12381.NewG1()
+	p, err := g1.FromCompressed(b)
+	if err != nil {
+		return nil, err
+	}
+	if g1.IsZero(p) {
+		return nil, errors.New("InvalidPublicKey")
+	}
+	return &BLSPublicKey{real: p}, nil
+}
+
+// ParseBLSSignature parses the signature in compressed format.
+func ParseBLSSignature(b []byte) (*BLSSignature, error) {
+	p, err := bls12381.NewG2().FromCompressed(b)
+	if err != nil {
+		return nil, err
+	}
+	return &BLSSignature{real: p}, nil
+}
+
+// AggregateBLSSignatures returns the aggregation of signatures.
+func AggregateBLSSignatures(sigs []*BLSSignature) *BLSSignature {
+	g2 := bls12381.NewG2()
+	agg := g2.Zero()
+	for _, sig := range sigs {
+		g2.Add(agg, agg, sig.real)
+	}
+	return &BLSSignature{real: g2.Affine(agg)}
+}
+
+// VerifyAggregatedBLSSignature returns true if the signature is the
+// aggregation of signatures of messages by the private keys of public keys.
+func VerifyAggregatedBLSSignature(pks []*BLSPublicKey, msgs [][]byte, sig *BLSSignature) bool {
+	if len(pks) == 0 || len(pks) != len(msgs) {
+		return false
+	}
+	e := bls12381.NewEngine()
+	for i, pk := range pks {
+		h, err := blsHashToG2(pk, msgs[i])
+		if err != nil {
+			return false
+		}
+		e.AddPair(pk.real, h)
+	}
+	e.AddPairInv(e.G1.One(), sig.real)
+	return e.Check()
+}
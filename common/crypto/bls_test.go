@@ -0,0 +1,120 @@
+/*
+ * Copyright 2026 ICON Foundation
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package crypto
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBLS_SignAndVerify(t *testing.T) {
+	sk, pk := GenerateBLSKeyPair()
+	msg := []byte("TEST Data")
+	sig, err := sk.Sign(msg)
+	assert.NoError(t, err)
+	assert.Len(t, sig.Bytes(), BLSSignatureLen)
+	assert.True(t, pk.Verify(msg, sig))
+	assert.False(t, pk.Verify([]byte("Other Data"), sig))
+
+	_, pk2 := GenerateBLSKeyPair()
+	assert.False(t, pk2.Verify(msg, sig))
+}
+
+func TestBLS_KeyFromSeed(t *testing.T) {
+	_, err := NewBLSPrivateKeyFromSeed(make([]byte, 31))
+	assert.Error(t, err)
+
+	seed := bytes.Repeat([]byte{0x5a}, 32)
+	sk1, err := NewBLSPrivateKeyFromSeed(seed)
+	assert.NoError(t, err)
+	sk2, err := NewBLSPrivateKeyFromSeed(seed)
+	assert.NoError(t, err)
+	assert.Equal(t, sk1.Bytes(), sk2.Bytes())
+
+	sk3, err := NewBLSPrivateKeyFromSeed(append(seed, 0))
+	assert.NoError(t, err)
+	assert.NotEqual(t, sk1.Bytes(), sk3.Bytes())
+}
+
+func TestBLS_Parse(t *testing.T) {
+	sk, pk := GenerateBLSKeyPair()
+
+	sk2, err := ParseBLSPrivateKey(sk.Bytes())
+	assert.NoError(t, err)
+	assert.True(t, pk.Equal(sk2.PublicKey()))
+	_, err = ParseBLSPrivateKey(make([]byte, BLSPrivateKeyLen))
+	assert.Error(t, err)
+	_, err = ParseBLSPrivateKey(sk.Bytes()[1:])
+	assert.Error(t, err)
+
+	pkBytes := pk.Bytes()
+	assert.Len(t, pkBytes, BLSPublicKeyLen)
+	pk2, err := ParseBLSPublicKey(pkBytes)
+	assert.NoError(t, err)
+	assert.True(t, pk.Equal(pk2))
+	assert.Equal(t, pkBytes, pk2.Bytes())
+	_, err = ParseBLSPublicKey(pkBytes[1:])
+	assert.Error(t, err)
+
+	// compressed form of the identity
+	identity := make([]byte, BLSPublicKeyLen)
+	identity[0] = 0xc0
+	_, err = ParseBLSPublicKey(identity)
+	assert.Error(t, err)
+
+	msg := []byte("TEST Data")
+	sig, err := sk.Sign(msg)
+	assert.NoError(t, err)
+	sig2, err := ParseBLSSignature(sig.Bytes())
+	assert.NoError(t, err)
+	assert.True(t, pk2.Verify(msg, sig2))
+	_, err = ParseBLSSignature(sig.Bytes()[1:])
+	assert.Error(t, err)
+}
+
+func TestBLS_Aggregate(t *testing.T) {
+	var pks []*BLSPublicKey
+	var msgs [][]byte
+	var sigs []*BLSSignature
+	for i := 0; i < 4; i++ {
+		sk, pk := GenerateBLSKeyPair()
+		msg := []byte{byte(i)}
+		if i%2 == 0 {
+			// signers may sign the same message
+			msg = []byte("same")
+		}
+		sig, err := sk.Sign(msg)
+		assert.NoError(t, err)
+		pks = append(pks, pk)
+		msgs = append(msgs, msg)
+		sigs = append(sigs, sig)
+	}
+	agg := AggregateBLSSignatures(sigs)
+	assert.True(t, VerifyAggregatedBLSSignature(pks, msgs, agg))
+
+	agg2, err := ParseBLSSignature(agg.Bytes())
+	assert.NoError(t, err)
+	assert.True(t, VerifyAggregatedBLSSignature(pks, msgs, agg2))
+
+	assert.False(t, VerifyAggregatedBLSSignature(pks[:3], msgs[:3], agg))
+	assert.False(t, VerifyAggregatedBLSSignature(pks, msgs[:3], agg))
+	assert.False(t, VerifyAggregatedBLSSignature(nil, nil, agg))
+	msgs[1] = []byte("other")
+	assert.False(t, VerifyAggregatedBLSSignature(pks, msgs, agg))
+}
@@ -0,0 +1,106 @@
+/*
+ * Copyright 2024 ICON Foundation
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package db
+
+// Batch groups Set and Delete operations across buckets, then writes
+// them at once. If the backend supports native write batch, all operations
+// are applied atomically. Operations are applied in the order they are added.
+type Batch interface {
+	Set(id BucketID, key, value []byte)
+	Delete(id BucketID, key []byte)
+	Len() int
+	Write() error
+	Reset()
+}
+
+// Batcher is a Database supporting native write batch.
+type Batcher interface {
+	NewBatch() Batch
+}
+
+// NewBatch returns a new batch for the database. It returns a native batch
+// if the database supports it. Otherwise, it returns a batch applying
+// operations one by one through the buckets of the database.
+func NewBatch(dbase Database) Batch {
+	if b, ok := dbase.(Batcher); ok {
+		return b.NewBatch()
+	}
+	return &emulatedBatch{dbase: dbase}
+}
+
+type batchOperation struct {
+	id    BucketID
+	key   []byte
+	value []byte
+}
+
+// batchOperations is a list of operations shared by batch implementations.
+// Nil value means deletion.
+type batchOperations []batchOperation
+
+func (ops *batchOperations) Set(id BucketID, key, value []byte) {
+	v := make([]byte, len(value))
+	copy(v, value)
+	*ops = append(*ops, batchOperation{id, append([]byte{}, key...), v})
+}
+
+func (ops *batchOperations) Delete(id BucketID, key []byte) {
+	*ops = append(*ops, batchOperation{id, append([]byte{}, key...), nil})
+}
+
+func (ops *batchOperations) Len() int {
+	return len(*ops)
+}
+
+func (ops *batchOperations) Reset() {
+	*ops = nil
+}
+
+// emulatedBatch applies operations through buckets. It ensures that all
+// buckets are available before applying any operation, but it can't
+// prevent partial writes on failure of the underlying database.
+type emulatedBatch struct {
+	batchOperations
+	dbase Database
+}
+
+func (b *emulatedBatch) Write() error {
+	buckets := make(map[BucketID]Bucket)
+	for _, op := range b.batchOperations {
+		if _, ok := buckets[op.id]; !ok {
+			bk, err := b.dbase.GetBucket(op.id)
+			if err != nil {
+				return err
+			}
+			buckets[op.id] = bk
+		}
+	}
+	for _, op := range b.batchOperations {
+		bk := buckets[op.id]
+		if op.value != nil {
+			if err := bk.Set(op.key, op.value); err != nil {
+				return err
+			}
+		} else {
+			if err := bk.Delete(op.key); err != nil {
+				return err
+			}
+		}
+	}
+	b.Reset()
+	return nil
+}
@@ -66,6 +66,15 @@ const (
 	// ListByMerkleRootBase is the base for the bucket that maps list
 	// from network type dependent merkle root(list)
 	ListByMerkleRootBase BucketID = "L"
+
+	// LogsBloomIndex maps bitmaps of blocks having a bit of logs bloom
+	// from section and bit position.
+	LogsBloomIndex BucketID = "B"
+
+	// ReferenceCount maps the number of references from world states kept
+	// by online pruning from the key of MerkleTrie or BytesByHash prefixed
+	// with its bucket ID.
+	ReferenceCount BucketID = "R"
 )
 
 // internalKey returns key prefixed with the bucket's id.
@@ -67,6 +67,19 @@ func (c *databaseContext) WithFlags(flags Flags) Context {
 	return &databaseContext{c.Database, newFlags}
 }
 
+func (c *databaseContext) NewBatch() Batch {
+	return NewBatch(c.Database)
+}
+
+// Snapshot returns a snapshot of the database keeping flags of the context.
+func (c *databaseContext) Snapshot() (Database, error) {
+	snapshot, err := NewSnapshot(c.Database)
+	if err != nil {
+		return nil, err
+	}
+	return &databaseContext{snapshot, c.flags}, nil
+}
+
 func (c *databaseContext) GetFlag(name string) interface{} {
 	return c.flags.Get(name)
 }
@@ -75,6 +75,9 @@ func (e *errorBucket) Get(key []byte) ([]byte, error)     { return nil, e.error
 func (e *errorBucket) Has(key []byte) (bool, error)       { return false, e.error }
 func (e *errorBucket) Set(key []byte, value []byte) error { return e.error }
 func (e *errorBucket) Delete(key []byte) error            { return e.error }
+func (e *errorBucket) NewIterator(r *Range) (Iterator, error) {
+	return nil, e.error
+}
 
 // BucketOf returns valid bucket always, but it
 func BucketOf(database Database, id BucketID) Bucket {
@@ -258,3 +258,188 @@ func TestDatabase_OperationAfterClose(t *testing.T) {
 		})
 	}
 }
+
+func collectIterator(t *testing.T, bk Bucket, r *Range) []string {
+	it, err := NewIterator(bk, r)
+	assert.NoError(t, err)
+	defer it.Release()
+	var keys []string
+	for ; it.Has(); assert.NoError(t, it.Next()) {
+		keys = append(keys, string(it.Key())+"="+string(it.Value()))
+	}
+	return keys
+}
+
+func testDatabase_Iterator(t *testing.T, creator dbCreator) {
+	dir := t.TempDir()
+	testDB, err := creator("test", dir)
+	assert.NoError(t, err)
+	defer testDB.Close()
+
+	other, err := testDB.GetBucket("hellp")
+	assert.NoError(t, err)
+	assert.NoError(t, other.Set([]byte("a1"), []byte("other")))
+
+	bucket, err := testDB.GetBucket("hello")
+	assert.NoError(t, err)
+	for _, k := range []string{"b2", "a1", "b1", "c1", "a2", "b3"} {
+		assert.NoError(t, bucket.Set([]byte(k), []byte("v"+k)))
+	}
+
+	assert.Equal(t, []string{"a1=va1", "a2=va2", "b1=vb1", "b2=vb2", "b3=vb3", "c1=vc1"},
+		collectIterator(t, bucket, nil))
+	assert.Equal(t, []string{"c1=vc1", "b3=vb3", "b2=vb2", "b1=vb1", "a2=va2", "a1=va1"},
+		collectIterator(t, bucket, &Range{Reverse: true}))
+	assert.Equal(t, []string{"b1=vb1", "b2=vb2", "b3=vb3"},
+		collectIterator(t, bucket, &Range{Prefix: []byte("b")}))
+	assert.Equal(t, []string{"b3=vb3", "b2=vb2", "b1=vb1"},
+		collectIterator(t, bucket, &Range{Prefix: []byte("b"), Reverse: true}))
+	assert.Equal(t, []string{"a2=va2", "b1=vb1"},
+		collectIterator(t, bucket, &Range{Start: []byte("a2"), Limit: []byte("b2")}))
+	assert.Equal(t, []string{"b1=vb1", "a2=va2"},
+		collectIterator(t, bucket, &Range{Start: []byte("a2"), Limit: []byte("b2"), Reverse: true}))
+	assert.Equal(t, []string{"b2=vb2"},
+		collectIterator(t, bucket, &Range{Prefix: []byte("b"), Start: []byte("b2"), Limit: []byte("b3")}))
+	assert.Empty(t, collectIterator(t, bucket, &Range{Prefix: []byte("d")}))
+}
+
+func TestDatabase_Iterator(t *testing.T) {
+	for name, be := range backends {
+		t.Run(string(name), func(t *testing.T) {
+			testDatabase_Iterator(t, be)
+		})
+	}
+	t.Run("layerdb", func(t *testing.T) {
+		var creator dbCreator = func(name string, dir string) (Database, error) {
+			origin := NewMapDB()
+			return NewLayerDB(origin), nil
+		}
+		testDatabase_Iterator(t, creator)
+	})
+}
+
+func testDatabase_Batch(t *testing.T, creator dbCreator) {
+	dir := t.TempDir()
+	testDB, err := creator("test", dir)
+	assert.NoError(t, err)
+	defer testDB.Close()
+
+	bk1, err := testDB.GetBucket(BlockHeaderHashByHeight)
+	assert.NoError(t, err)
+	bk2, err := testDB.GetBucket(ChainProperty)
+	assert.NoError(t, err)
+	assert.NoError(t, bk2.Set([]byte("k2"), []byte("v2")))
+
+	batch := NewBatch(testDB)
+	batch.Set(BlockHeaderHashByHeight, []byte("k1"), []byte("v1"))
+	batch.Delete(ChainProperty, []byte("k2"))
+	batch.Set(ChainProperty, []byte("k3"), []byte("v3"))
+	batch.Set(ChainProperty, []byte("k4"), []byte{})
+	assert.Equal(t, 4, batch.Len())
+
+	// nothing is written before Write
+	has, err := bk1.Has([]byte("k1"))
+	assert.NoError(t, err)
+	assert.False(t, has)
+	has, err = bk2.Has([]byte("k2"))
+	assert.NoError(t, err)
+	assert.True(t, has)
+
+	assert.NoError(t, batch.Write())
+	assert.Equal(t, 0, batch.Len())
+
+	value, err := bk1.Get([]byte("k1"))
+	assert.NoError(t, err)
+	assert.Equal(t, []byte("v1"), value)
+	has, err = bk2.Has([]byte("k2"))
+	assert.NoError(t, err)
+	assert.False(t, has)
+	value, err = bk2.Get([]byte("k3"))
+	assert.NoError(t, err)
+	assert.Equal(t, []byte("v3"), value)
+	value, err = bk2.Get([]byte("k4"))
+	assert.NoError(t, err)
+	assert.NotNil(t, value)
+	assert.Zero(t, len(value))
+}
+
+func TestDatabase_Batch(t *testing.T) {
+	for name, be := range backends {
+		t.Run(string(name), func(t *testing.T) {
+			testDatabase_Batch(t, be)
+		})
+	}
+	t.Run("layerdb", func(t *testing.T) {
+		var creator dbCreator = func(name string, dir string) (Database, error) {
+			origin := NewMapDB()
+			return NewLayerDB(origin), nil
+		}
+		testDatabase_Batch(t, creator)
+	})
+}
+
+func testDatabase_Snapshot(t *testing.T, creator dbCreator) {
+	dir := t.TempDir()
+	testDB, err := creator("test", dir)
+	assert.NoError(t, err)
+	defer testDB.Close()
+
+	bucket, err := testDB.GetBucket(ChainProperty)
+	assert.NoError(t, err)
+	assert.NoError(t, bucket.Set([]byte("k1"), []byte("v1")))
+	assert.NoError(t, bucket.Set([]byte("k2"), []byte("v2")))
+
+	snapshot, err := NewSnapshot(testDB)
+	assert.NoError(t, err)
+
+	assert.NoError(t, bucket.Set([]byte("k1"), []byte("v1x")))
+	assert.NoError(t, bucket.Delete([]byte("k2")))
+	assert.NoError(t, bucket.Set([]byte("k3"), []byte("v3")))
+	other, err := testDB.GetBucket(BytesByHash)
+	assert.NoError(t, err)
+	assert.NoError(t, other.Set([]byte("k1"), []byte("v1")))
+
+	sbk, err := snapshot.GetBucket(ChainProperty)
+	assert.NoError(t, err)
+	value, err := sbk.Get([]byte("k1"))
+	assert.NoError(t, err)
+	assert.Equal(t, []byte("v1"), value)
+	has, err := sbk.Has([]byte("k2"))
+	assert.NoError(t, err)
+	assert.True(t, has)
+	has, err = sbk.Has([]byte("k3"))
+	assert.NoError(t, err)
+	assert.False(t, has)
+	assert.Equal(t, []string{"k1=v1", "k2=v2"}, collectIterator(t, sbk, nil))
+
+	sother, err := snapshot.GetBucket(BytesByHash)
+	assert.NoError(t, err)
+	value, err = sother.Get([]byte("k1"))
+	assert.NoError(t, err)
+	assert.Nil(t, value)
+
+	// snapshot is read-only
+	assert.Error(t, sbk.Set([]byte("k4"), []byte("v4")))
+	assert.Error(t, sbk.Delete([]byte("k1")))
+
+	// origin keeps changes
+	value, err = bucket.Get([]byte("k1"))
+	assert.NoError(t, err)
+	assert.Equal(t, []byte("v1x"), value)
+
+	assert.NoError(t, snapshot.Close())
+}
+
+func TestDatabase_Snapshot(t *testing.T) {
+	for name, be := range backends {
+		t.Run(string(name), func(t *testing.T) {
+			testDatabase_Snapshot(t, be)
+		})
+	}
+	t.Run("context", func(t *testing.T) {
+		var creator dbCreator = func(name string, dir string) (Database, error) {
+			return WithFlags(NewMapDB(), Flags{"test": true}), nil
+		}
+		testDatabase_Snapshot(t, creator)
+	})
+}
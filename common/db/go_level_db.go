@@ -5,7 +5,9 @@ import (
 	"sync"
 
 	"github.com/syndtr/goleveldb/leveldb"
+	"github.com/syndtr/goleveldb/leveldb/iterator"
 	"github.com/syndtr/goleveldb/leveldb/opt"
+	"github.com/syndtr/goleveldb/leveldb/util"
 )
 
 const GoLevelDBBackend BackendType = "goleveldb"
@@ -38,6 +40,8 @@ func NewGoLevelDBWithOpts(name string, dir string, o *opt.Options) (*GoLevelDB,
 // Database
 
 var _ Database = (*GoLevelDB)(nil)
+var _ Batcher = (*GoLevelDB)(nil)
+var _ Snapshotter = (*GoLevelDB)(nil)
 
 type GoLevelDB struct {
 	lock    sync.Mutex
@@ -65,6 +69,27 @@ func (db *GoLevelDB) GetBucket(id BucketID) (Bucket, error) {
 	}
 }
 
+func (db *GoLevelDB) Snapshot() (Database, error) {
+	db.lock.Lock()
+	defer db.lock.Unlock()
+
+	if db.db == nil {
+		return nil, leveldb.ErrClosed
+	}
+	snapshot, err := db.db.GetSnapshot()
+	if err != nil {
+		return nil, err
+	}
+	return &goLevelSnapshot{
+		snapshot: snapshot,
+		buckets:  make(map[BucketID]Bucket),
+	}, nil
+}
+
+func (db *GoLevelDB) NewBatch() Batch {
+	return &goLevelBatch{db: db}
+}
+
 func (db *GoLevelDB) Close() error {
 	db.lock.Lock()
 	defer db.lock.Unlock()
@@ -79,10 +104,51 @@ func (db *GoLevelDB) Close() error {
 	return nil
 }
 
+//----------------------------------------
+// Batch
+
+var _ Batch = (*goLevelBatch)(nil)
+
+type goLevelBatch struct {
+	db    *GoLevelDB
+	batch leveldb.Batch
+}
+
+func (b *goLevelBatch) Set(id BucketID, key, value []byte) {
+	b.batch.Put(internalKey(id, key), value)
+}
+
+func (b *goLevelBatch) Delete(id BucketID, key []byte) {
+	b.batch.Delete(internalKey(id, key))
+}
+
+func (b *goLevelBatch) Len() int {
+	return b.batch.Len()
+}
+
+func (b *goLevelBatch) Write() error {
+	b.db.lock.Lock()
+	ldb := b.db.db
+	b.db.lock.Unlock()
+
+	if ldb == nil {
+		return leveldb.ErrClosed
+	}
+	if err := ldb.Write(&b.batch, nil); err != nil {
+		return err
+	}
+	b.batch.Reset()
+	return nil
+}
+
+func (b *goLevelBatch) Reset() {
+	b.batch.Reset()
+}
+
 //----------------------------------------
 // GetBucket
 
-var _ Bucket = (*goLevelBucket)(nil)
+var _ IterableBucket = (*goLevelBucket)(nil)
 
 type goLevelBucket struct {
 	id BucketID
@@ -109,3 +175,144 @@ func (bucket *goLevelBucket) Set(key []byte, value []byte) error {
 func (bucket *goLevelBucket) Delete(key []byte) error {
 	return bucket.db.Delete(internalKey(bucket.id, key), nil)
 }
+
+// NewIterator returns an iterator over the bucket.
+// Note that all buckets share the key space in GoLevelDB, so
+// the bucket with empty ID (MerkleTrie) iterates keys of other buckets too.
+func (bucket *goLevelBucket) NewIterator(r *Range) (Iterator, error) {
+	return newGoLevelIterator(bucket.db, bucket.id, r), nil
+}
+
+type goLevelIteratorSource interface {
+	NewIterator(slice *util.Range, ro *opt.ReadOptions) iterator.Iterator
+}
+
+func newGoLevelIterator(src goLevelIteratorSource, id BucketID, r *Range) Iterator {
+	lower, upper := r.bounds()
+	ur := &util.Range{Start: internalKey(id, lower)}
+	if upper != nil {
+		ur.Limit = internalKey(id, upper)
+	} else {
+		ur.Limit = prefixLimit([]byte(id))
+	}
+	it := src.NewIterator(ur, nil)
+	if r.Reverse {
+		it.Last()
+	} else {
+		it.First()
+	}
+	return &goLevelIterator{
+		it:      it,
+		prefix:  len(id),
+		reverse: r.Reverse,
+	}
+}
+
+type goLevelIterator struct {
+	it      iterator.Iterator
+	prefix  int
+	reverse bool
+}
+
+func (it *goLevelIterator) Has() bool {
+	return it.it.Valid()
+}
+
+func (it *goLevelIterator) Next() error {
+	if it.reverse {
+		it.it.Prev()
+	} else {
+		it.it.Next()
+	}
+	return it.it.Error()
+}
+
+func (it *goLevelIterator) Key() []byte {
+	if !it.it.Valid() {
+		return nil
+	}
+	key := it.it.Key()[it.prefix:]
+	return append([]byte{}, key...)
+}
+
+func (it *goLevelIterator) Value() []byte {
+	if !it.it.Valid() {
+		return nil
+	}
+	return append([]byte{}, it.it.Value()...)
+}
+
+func (it *goLevelIterator) Release() {
+	it.it.Release()
+}
+
+//----------------------------------------
+// Snapshot
+
+type goLevelSnapshot struct {
+	lock     sync.Mutex
+	snapshot *leveldb.Snapshot
+	buckets  map[BucketID]Bucket
+}
+
+func (s *goLevelSnapshot) GetBucket(id BucketID) (Bucket, error) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	if s.snapshot == nil {
+		return nil, leveldb.ErrSnapshotReleased
+	}
+	if bk, ok := s.buckets[id]; ok {
+		return bk, nil
+	}
+	bk := &goLevelSnapshotBucket{
+		id:       id,
+		snapshot: s.snapshot,
+	}
+	s.buckets[id] = bk
+	return bk, nil
+}
+
+func (s *goLevelSnapshot) Close() error {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	if s.snapshot == nil {
+		return leveldb.ErrSnapshotReleased
+	}
+	s.snapshot.Release()
+	s.snapshot = nil
+	return nil
+}
+
+var _ IterableBucket = (*goLevelSnapshotBucket)(nil)
+
+type goLevelSnapshotBucket struct {
+	id       BucketID
+	snapshot *leveldb.Snapshot
+}
+
+func (bucket *goLevelSnapshotBucket) Get(key []byte) ([]byte, error) {
+	value, err := bucket.snapshot.Get(internalKey(bucket.id, key), nil)
+	if err == leveldb.ErrNotFound {
+		return nil, nil
+	} else {
+		return value, err
+	}
+}
+
+func (bucket *goLevelSnapshotBucket) Has(key []byte) (bool, error) {
+	return bucket.snapshot.Has(internalKey(bucket.id, key), nil)
+}
+
+func (bucket *goLevelSnapshotBucket) Set(key []byte, value []byte) error {
+	return errReadOnlySnapshot()
+}
+
+func (bucket *goLevelSnapshotBucket) Delete(key []byte) error {
+	return errReadOnlySnapshot()
+}
+
+func (bucket *goLevelSnapshotBucket) NewIterator(r *Range) (Iterator, error) {
+	return newGoLevelIterator(bucket.snapshot, bucket.id, r), nil
+}
@@ -0,0 +1,157 @@
+/*
+ * Copyright 2024 ICON Foundation
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package db
+
+import (
+	"bytes"
+	"sort"
+
+	"github.com/icon-project/goloop/common/errors"
+)
+
+// Range specifies the set of keys to be returned by an Iterator.
+// Keys are matched against Prefix, then restricted to [Start, Limit).
+// Nil Start or Limit means no bound on that side. If Reverse is true,
+// keys are returned in descending order.
+type Range struct {
+	Prefix  []byte
+	Start   []byte
+	Limit   []byte
+	Reverse bool
+}
+
+// bounds returns the effective [lower, upper) bounds of the range.
+// nil upper means there is no upper bound.
+func (r *Range) bounds() (lower []byte, upper []byte) {
+	lower = r.Prefix
+	if len(r.Prefix) > 0 {
+		upper = prefixLimit(r.Prefix)
+	}
+	if r.Start != nil && bytes.Compare(r.Start, lower) > 0 {
+		lower = r.Start
+	}
+	if r.Limit != nil && (upper == nil || bytes.Compare(r.Limit, upper) < 0) {
+		upper = r.Limit
+	}
+	return
+}
+
+// Contains returns true if the key is in the range.
+func (r *Range) Contains(key []byte) bool {
+	lower, upper := r.bounds()
+	if bytes.Compare(key, lower) < 0 {
+		return false
+	}
+	return upper == nil || bytes.Compare(key, upper) < 0
+}
+
+// prefixLimit returns the smallest key which is greater than all keys
+// with the prefix. It returns nil if there is no such key.
+func prefixLimit(prefix []byte) []byte {
+	for i := len(prefix) - 1; i >= 0; i-- {
+		if c := prefix[i]; c < 0xff {
+			limit := make([]byte, i+1)
+			copy(limit, prefix)
+			limit[i] = c + 1
+			return limit
+		}
+	}
+	return nil
+}
+
+// Iterator iterates key-value pairs of a bucket in key order.
+// It starts at the first entry (if any), so the caller should check Has
+// before calling Key or Value. Release must be called after use.
+type Iterator interface {
+	Has() bool
+	Next() error
+	Key() []byte
+	Value() []byte
+	Release()
+}
+
+// IterableBucket is a Bucket supporting ordered range iteration.
+type IterableBucket interface {
+	Bucket
+	NewIterator(r *Range) (Iterator, error)
+}
+
+// NewIterator returns an iterator over the bucket for the range.
+// It returns UnsupportedError if the bucket doesn't support iteration.
+// Nil range iterates all keys in the bucket.
+func NewIterator(bk Bucket, r *Range) (Iterator, error) {
+	if r == nil {
+		r = &Range{}
+	}
+	if ib, ok := bk.(IterableBucket); ok {
+		return ib.NewIterator(r)
+	}
+	return nil, errors.UnsupportedError.Errorf("IterationNotSupported(bk=%T)", bk)
+}
+
+type kvItem struct {
+	key   []byte
+	value []byte
+}
+
+// sliceIterator iterates over pre-collected items in iteration order.
+type sliceIterator struct {
+	items []kvItem
+	idx   int
+}
+
+func (it *sliceIterator) Has() bool {
+	return it.idx < len(it.items)
+}
+
+func (it *sliceIterator) Next() error {
+	if it.idx >= len(it.items) {
+		return errors.InvalidStateError.New("NoMoreItems")
+	}
+	it.idx += 1
+	return nil
+}
+
+func (it *sliceIterator) Key() []byte {
+	if it.idx < len(it.items) {
+		return it.items[it.idx].key
+	}
+	return nil
+}
+
+func (it *sliceIterator) Value() []byte {
+	if it.idx < len(it.items) {
+		return it.items[it.idx].value
+	}
+	return nil
+}
+
+func (it *sliceIterator) Release() {
+	it.items = nil
+}
+
+// newSliceIterator sorts items in iteration order of the range, and
+// returns an iterator over them.
+func newSliceIterator(items []kvItem, reverse bool) *sliceIterator {
+	sort.Slice(items, func(i, j int) bool {
+		if reverse {
+			return bytes.Compare(items[i].key, items[j].key) > 0
+		}
+		return bytes.Compare(items[i].key, items[j].key) < 0
+	})
+	return &sliceIterator{items: items}
+}
@@ -1,6 +1,7 @@
 package db
 
 import (
+	"bytes"
 	"container/list"
 	"sync"
 
@@ -34,6 +35,7 @@ func (l *layerBucketItems) MoveToBack(element *list.Element) {
 
 type layerBucket struct {
 	lock sync.Mutex
+	id   BucketID
 	data map[string]*list.Element
 	list *layerBucketItems
 	real Bucket
@@ -103,6 +105,123 @@ func (bk *layerBucket) Delete(key []byte) error {
 	}
 }
 
+// NewIterator returns an iterator merging pending writes of the layer
+// with entries of the underlying bucket. Pending writes are captured
+// at the moment of the call.
+func (bk *layerBucket) NewIterator(r *Range) (Iterator, error) {
+	bk.lock.Lock()
+	defer bk.lock.Unlock()
+
+	if bk.data == nil {
+		return NewIterator(bk.real, r)
+	}
+	var items []kvItem
+	for k, element := range bk.data {
+		if key := []byte(k); r.Contains(key) {
+			items = append(items, kvItem{key, element.Value.(*layerBucketItem).value})
+		}
+	}
+	real, err := NewIterator(bk.real, r)
+	if err != nil {
+		return nil, err
+	}
+	it := &layerIterator{
+		layer:   newSliceIterator(items, r.Reverse),
+		real:    real,
+		reverse: r.Reverse,
+	}
+	if err := it.settle(); err != nil {
+		it.Release()
+		return nil, err
+	}
+	return it, nil
+}
+
+// layerIterator merges pending items of the layer with the real iterator.
+// Pending items take precedence over the real entries with the same key,
+// and pending deletions (nil value) hide them.
+type layerIterator struct {
+	layer   *sliceIterator
+	real    Iterator
+	reverse bool
+
+	// current is the iterator providing current entry.
+	current Iterator
+}
+
+func (it *layerIterator) compare(k1, k2 []byte) int {
+	if it.reverse {
+		return bytes.Compare(k2, k1)
+	}
+	return bytes.Compare(k1, k2)
+}
+
+// settle selects the iterator for the current entry, skipping deleted ones.
+func (it *layerIterator) settle() error {
+	for {
+		if !it.layer.Has() {
+			if it.real.Has() {
+				it.current = it.real
+			} else {
+				it.current = nil
+			}
+			return nil
+		}
+		if it.real.Has() {
+			c := it.compare(it.real.Key(), it.layer.Key())
+			if c < 0 {
+				it.current = it.real
+				return nil
+			} else if c == 0 {
+				if err := it.real.Next(); err != nil {
+					return err
+				}
+			}
+		}
+		if it.layer.Value() != nil {
+			it.current = it.layer
+			return nil
+		}
+		if err := it.layer.Next(); err != nil {
+			return err
+		}
+	}
+}
+
+func (it *layerIterator) Has() bool {
+	return it.current != nil
+}
+
+func (it *layerIterator) Next() error {
+	if it.current == nil {
+		return errors.InvalidStateError.New("NoMoreItems")
+	}
+	if err := it.current.Next(); err != nil {
+		return err
+	}
+	return it.settle()
+}
+
+func (it *layerIterator) Key() []byte {
+	if it.current != nil {
+		return it.current.Key()
+	}
+	return nil
+}
+
+func (it *layerIterator) Value() []byte {
+	if it.current != nil {
+		return it.current.Value()
+	}
+	return nil
+}
+
+func (it *layerIterator) Release() {
+	it.current = nil
+	it.layer.Release()
+	it.real.Release()
+}
+
 type layerDB struct {
 	lock sync.Mutex
 
@@ -128,6 +247,7 @@ func (ldb *layerDB) GetBucket(id BucketID) (Bucket, error) {
 		return realbk, nil
 	}
 	bk := &layerBucket{
+		id:   id,
 		data: make(map[string]*list.Element),
 		list: &ldb.list,
 		real: realbk,
@@ -159,19 +279,19 @@ func (ldb *layerDB) Flush(write bool) error {
 	}()
 
 	if write {
+		batch := NewBatch(ldb.real)
 		for element := ldb.list.Front() ; element != nil ; element = element.Next() {
 			item := element.Value.(*layerBucketItem)
 
 			if item.value != nil {
-				if err := item.bk.real.Set([]byte(item.key), item.value ); err != nil {
-					return err
-				}
+				batch.Set(item.bk.id, []byte(item.key), item.value)
 			} else {
-				if err := item.bk.real.Delete([]byte(item.key)); err != nil {
-					return err
-				}
+				batch.Delete(item.bk.id, []byte(item.key))
 			}
 		}
+		if err := batch.Write(); err != nil {
+			return err
+		}
 		for _, bk := range ldb.buckets {
 			bk.data = nil
 		}
@@ -199,6 +319,10 @@ func (c *layerDBContext) WithFlags(flags Flags) Context {
 	return &layerDBContext{c.LayerDB, newFlags}
 }
 
+func (c *layerDBContext) NewBatch() Batch {
+	return NewBatch(c.LayerDB)
+}
+
 func (c *layerDBContext) GetFlag(name string) interface{} {
 	return c.flags.Get(name)
 }
@@ -147,3 +147,40 @@ func TestLayerDB_FlushInOrder(t *testing.T) {
 
 	assert.Equal(t, Unwrap(ldb), dbase)
 }
+
+func TestLayerDB_IteratorMerge(t *testing.T) {
+	dbase := NewMapDB()
+	bk, err := dbase.GetBucket(ChainProperty)
+	assert.NoError(t, err)
+	for _, k := range []string{"k1", "k3", "k5", "k7"} {
+		assert.NoError(t, bk.Set([]byte(k), []byte("real-"+k)))
+	}
+
+	ldb := NewLayerDB(dbase)
+	lbk, err := ldb.GetBucket(ChainProperty)
+	assert.NoError(t, err)
+	assert.NoError(t, lbk.Set([]byte("k2"), []byte("layer-k2")))
+	assert.NoError(t, lbk.Set([]byte("k3"), []byte("layer-k3")))
+	assert.NoError(t, lbk.Delete([]byte("k5")))
+	assert.NoError(t, lbk.Delete([]byte("k6")))
+	assert.NoError(t, lbk.Set([]byte("k8"), []byte("layer-k8")))
+
+	exp := []string{
+		"k1=real-k1", "k2=layer-k2", "k3=layer-k3", "k7=real-k7", "k8=layer-k8",
+	}
+	assert.Equal(t, exp, collectIterator(t, lbk, nil))
+
+	rexp := make([]string, len(exp))
+	for i, v := range exp {
+		rexp[len(exp)-1-i] = v
+	}
+	assert.Equal(t, rexp, collectIterator(t, lbk, &Range{Reverse: true}))
+
+	// real bucket is not changed until flush
+	assert.Equal(t, []string{"k1=real-k1", "k3=real-k3", "k5=real-k5", "k7=real-k7"},
+		collectIterator(t, bk, nil))
+
+	assert.NoError(t, ldb.Flush(true))
+	assert.Equal(t, exp, collectIterator(t, bk, nil))
+	assert.Equal(t, exp, collectIterator(t, lbk, nil))
+}
@@ -32,6 +32,7 @@ func NewMapDB() Database {
 // DB
 
 var _ Database = (*mapDatabase)(nil)
+var _ Snapshotter = (*mapDatabase)(nil)
 
 type mapDatabase struct {
 	lock sync.Mutex
@@ -58,15 +59,93 @@ func (t *mapDatabase) Close() error {
 	return nil
 }
 
+// Snapshot returns a read-only snapshot of the database. Maps of the
+// buckets are shared with the snapshot, and they are copied on next write.
+func (t *mapDatabase) Snapshot() (Database, error) {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	snapshot := &mapSnapshot{
+		name: t.name + "@snapshot",
+		bks:  make(map[BucketID]*mapBucket, len(t.bks)),
+	}
+	for id, bk := range t.bks {
+		snapshot.bks[id] = bk.share(fmt.Sprintf("%s:%s", snapshot.name, id))
+	}
+	return snapshot, nil
+}
+
+type mapSnapshot struct {
+	lock sync.Mutex
+	name string
+	bks  map[BucketID]*mapBucket
+}
+
+func (s *mapSnapshot) GetBucket(id BucketID) (Bucket, error) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	if bk, ok := s.bks[id]; ok {
+		return bk, nil
+	}
+	bk := &mapBucket{
+		id:       fmt.Sprintf("%s:%s", s.name, id),
+		real:     make(map[string]string),
+		readOnly: true,
+	}
+	s.bks[id] = bk
+	return bk, nil
+}
+
+func (s *mapSnapshot) Close() error {
+	return nil
+}
+
 //----------------------------------------
 // Bucket
 
-var _ Bucket = (*mapBucket)(nil)
+var _ IterableBucket = (*mapBucket)(nil)
 
 type mapBucket struct {
 	id    string
 	real  map[string]string
 	mutex sync.Mutex
+
+	// shared is true if real is shared with a snapshot.
+	shared bool
+
+	// readOnly is true for buckets of a snapshot.
+	readOnly bool
+}
+
+// share returns a read-only bucket sharing the map with the bucket.
+func (t *mapBucket) share(id string) *mapBucket {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	t.shared = true
+	return &mapBucket{
+		id:       id,
+		real:     t.real,
+		shared:   true,
+		readOnly: true,
+	}
+}
+
+// prepareWriteInLock copies the map if it's shared with a snapshot.
+func (t *mapBucket) prepareWriteInLock() error {
+	if t.readOnly {
+		return errReadOnlySnapshot()
+	}
+	if t.shared {
+		real := make(map[string]string, len(t.real))
+		for k, v := range t.real {
+			real[k] = v
+		}
+		t.real = real
+		t.shared = false
+	}
+	return nil
 }
 
 func (t *mapBucket) Get(k []byte) ([]byte, error) {
@@ -102,6 +181,9 @@ func (t *mapBucket) Set(k, v []byte) error {
 	}
 	t.mutex.Lock()
 	defer t.mutex.Unlock()
+	if err := t.prepareWriteInLock(); err != nil {
+		return err
+	}
 	t.real[string(k)] = string(v)
 	return nil
 }
@@ -112,6 +194,23 @@ func (t *mapBucket) Delete(k []byte) error {
 	}
 	t.mutex.Lock()
 	defer t.mutex.Unlock()
+	if err := t.prepareWriteInLock(); err != nil {
+		return err
+	}
 	delete(t.real, string(k))
 	return nil
 }
+
+// NewIterator returns an iterator over the entries at the moment of the call.
+// Later changes of the bucket are not visible to the iterator.
+func (t *mapBucket) NewIterator(r *Range) (Iterator, error) {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+	var items []kvItem
+	for k, v := range t.real {
+		if r.Contains([]byte(k)) {
+			items = append(items, kvItem{[]byte(k), []byte(v)})
+		}
+	}
+	return newSliceIterator(items, r.Reverse), nil
+}
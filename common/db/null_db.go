@@ -33,3 +33,7 @@ func (*nullBucket) Delete(key []byte) error {
 func NewNullDB() *nullDB {
 	return &nullDB{}
 }
+
+func (*nullBucket) NewIterator(r *Range) (Iterator, error) {
+	return &sliceIterator{}, nil
+}
@@ -36,6 +36,13 @@ func (bk *proxyBucket) Delete(key []byte) error {
 	return errors.New("ProxyIsNotRealized")
 }
 
+func (bk *proxyBucket) NewIterator(r *Range) (Iterator, error) {
+	if bk.real != nil {
+		return NewIterator(bk.real, r)
+	}
+	return nil, errors.New("ProxyIsNotRealized")
+}
+
 type proxyDB struct {
 	real    Database
 	buckets map[string]*proxyBucket
@@ -57,6 +64,20 @@ func (pdb *proxyDB) GetBucket(id BucketID) (Bucket, error) {
 	return bk, nil
 }
 
+func (pdb *proxyDB) NewBatch() Batch {
+	if pdb.real != nil {
+		return NewBatch(pdb.real)
+	}
+	return &emulatedBatch{dbase: pdb}
+}
+
+func (pdb *proxyDB) Snapshot() (Database, error) {
+	if pdb.real != nil {
+		return NewSnapshot(pdb.real)
+	}
+	return nil, errors.New("ProxyIsNotRealized")
+}
+
 func (pdb *proxyDB) Close() error {
 	return nil
 }
@@ -20,6 +20,7 @@
 package db
 
 import (
+	"bytes"
 	"errors"
 	"os"
 	"path"
@@ -208,6 +209,10 @@ func unsafePointerOf(p []byte) unsafe.Pointer {
 }
 
 func (db *RocksDB) getValue(cf *C.rocksdb_column_family_handle_t, k []byte) ([]byte, error) {
+	return db.getValueWith(db.ro, cf, k)
+}
+
+func (db *RocksDB) getValueWith(ro *C.rocksdb_readoptions_t, cf *C.rocksdb_column_family_handle_t, k []byte) ([]byte, error) {
 	db.lock.RLock()
 	defer db.lock.RUnlock()
 
@@ -219,7 +224,7 @@ func (db *RocksDB) getValue(cf *C.rocksdb_column_family_handle_t, k []byte) ([]b
 		cValLen C.size_t
 		cKey    = (*C.char)(unsafePointerOf(k))
 	)
-	cValue := C.rocksdb_get_cf(db.db, db.ro, cf, cKey, C.size_t(len(k)), &cValLen, &cErr)
+	cValue := C.rocksdb_get_cf(db.db, ro, cf, cKey, C.size_t(len(k)), &cValLen, &cErr)
 	if cErr != nil {
 		defer C.rocksdb_free(unsafe.Pointer(cErr))
 		return nil, errors.New(C.GoString(cErr))
@@ -237,6 +242,10 @@ func (db *RocksDB) getValue(cf *C.rocksdb_column_family_handle_t, k []byte) ([]b
 }
 
 func (db *RocksDB) hasValue(cf *C.rocksdb_column_family_handle_t, k []byte) (bool, error) {
+	return db.hasValueWith(db.ro, cf, k)
+}
+
+func (db *RocksDB) hasValueWith(ro *C.rocksdb_readoptions_t, cf *C.rocksdb_column_family_handle_t, k []byte) (bool, error) {
 	db.lock.RLock()
 	defer db.lock.RUnlock()
 
@@ -248,7 +257,7 @@ func (db *RocksDB) hasValue(cf *C.rocksdb_column_family_handle_t, k []byte) (boo
 		cValLen C.size_t
 		cKey    = (*C.char)(unsafePointerOf(k))
 	)
-	cValue := C.rocksdb_get_cf(db.db, db.ro, cf, cKey, C.size_t(len(k)), &cValLen, &cErr)
+	cValue := C.rocksdb_get_cf(db.db, ro, cf, cKey, C.size_t(len(k)), &cValLen, &cErr)
 	if cErr != nil {
 		defer C.rocksdb_free(unsafe.Pointer(cErr))
 		return false, errors.New(C.GoString(cErr))
@@ -296,6 +305,171 @@ func (db *RocksDB) deleteValue(cf *C.rocksdb_column_family_handle_t, k []byte) e
 	return nil
 }
 
+func (db *RocksDB) Snapshot() (Database, error) {
+	db.lock.RLock()
+	defer db.lock.RUnlock()
+
+	if db.db == nil {
+		return nil, ErrAlreadyClosed
+	}
+	snapshot := C.rocksdb_create_snapshot(db.db)
+	ro := C.rocksdb_readoptions_create()
+	C.rocksdb_readoptions_set_snapshot(ro, snapshot)
+	return &rocksSnapshot{
+		db:       db,
+		snapshot: snapshot,
+		ro:       ro,
+		buckets:  make(map[BucketID]*rocksSnapshotBucket),
+	}, nil
+}
+
+type rocksSnapshot struct {
+	lock     sync.Mutex
+	db       *RocksDB
+	snapshot *C.rocksdb_snapshot_t
+	ro       *C.rocksdb_readoptions_t
+	buckets  map[BucketID]*rocksSnapshotBucket
+}
+
+func (s *rocksSnapshot) GetBucket(id BucketID) (Bucket, error) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	if s.snapshot == nil {
+		return nil, ErrAlreadyClosed
+	}
+	if bk, ok := s.buckets[id]; ok {
+		return bk, nil
+	}
+	rbk, err := s.db.GetBucket(id)
+	if err != nil {
+		return nil, err
+	}
+	bk := &rocksSnapshotBucket{
+		snapshot: s,
+		cf:       rbk.(*RocksBucket).cf,
+	}
+	s.buckets[id] = bk
+	return bk, nil
+}
+
+func (s *rocksSnapshot) Close() error {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	if s.snapshot == nil {
+		return ErrAlreadyClosed
+	}
+
+	s.db.lock.RLock()
+	defer s.db.lock.RUnlock()
+
+	if s.db.db != nil {
+		C.rocksdb_release_snapshot(s.db.db, s.snapshot)
+	}
+	C.rocksdb_readoptions_destroy(s.ro)
+	s.snapshot = nil
+	s.ro = nil
+	return nil
+}
+
+func (s *rocksSnapshot) readOptions() (*C.rocksdb_readoptions_t, error) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	if s.snapshot == nil {
+		return nil, ErrAlreadyClosed
+	}
+	return s.ro, nil
+}
+
+type rocksSnapshotBucket struct {
+	snapshot *rocksSnapshot
+	cf       *C.rocksdb_column_family_handle_t
+}
+
+func (b *rocksSnapshotBucket) Get(key []byte) ([]byte, error) {
+	ro, err := b.snapshot.readOptions()
+	if err != nil {
+		return nil, err
+	}
+	return b.snapshot.db.getValueWith(ro, b.cf, key)
+}
+
+func (b *rocksSnapshotBucket) Has(key []byte) (bool, error) {
+	ro, err := b.snapshot.readOptions()
+	if err != nil {
+		return false, err
+	}
+	return b.snapshot.db.hasValueWith(ro, b.cf, key)
+}
+
+func (b *rocksSnapshotBucket) Set(key []byte, value []byte) error {
+	return errReadOnlySnapshot()
+}
+
+func (b *rocksSnapshotBucket) Delete(key []byte) error {
+	return errReadOnlySnapshot()
+}
+
+func (b *rocksSnapshotBucket) NewIterator(r *Range) (Iterator, error) {
+	ro, err := b.snapshot.readOptions()
+	if err != nil {
+		return nil, err
+	}
+	return b.snapshot.db.newIterator(ro, b.cf, r)
+}
+
+func (db *RocksDB) NewBatch() Batch {
+	return &rocksBatch{db: db}
+}
+
+// rocksBatch keeps operations in memory, and builds a native write batch
+// on Write, so it doesn't need to be released.
+type rocksBatch struct {
+	batchOperations
+	db *RocksDB
+}
+
+func (b *rocksBatch) Write() error {
+	cfs := make(map[BucketID]*C.rocksdb_column_family_handle_t)
+	for _, op := range b.batchOperations {
+		if _, ok := cfs[op.id]; !ok {
+			bk, err := b.db.GetBucket(op.id)
+			if err != nil {
+				return err
+			}
+			cfs[op.id] = bk.(*RocksBucket).cf
+		}
+	}
+
+	b.db.lock.RLock()
+	defer b.db.lock.RUnlock()
+
+	if b.db.db == nil {
+		return ErrAlreadyClosed
+	}
+	wb := C.rocksdb_writebatch_create()
+	defer C.rocksdb_writebatch_destroy(wb)
+	for _, op := range b.batchOperations {
+		cKey := (*C.char)(unsafePointerOf(op.key))
+		if op.value != nil {
+			cValue := (*C.char)(unsafePointerOf(op.value))
+			C.rocksdb_writebatch_put_cf(wb, cfs[op.id], cKey, C.size_t(len(op.key)), cValue, C.size_t(len(op.value)))
+		} else {
+			C.rocksdb_writebatch_delete_cf(wb, cfs[op.id], cKey, C.size_t(len(op.key)))
+		}
+	}
+	var cErr *C.char
+	C.rocksdb_write(b.db.db, b.db.wo, wb, &cErr)
+	if cErr != nil {
+		defer C.rocksdb_free(unsafe.Pointer(cErr))
+		return errors.New(C.GoString(cErr))
+	}
+	b.Reset()
+	return nil
+}
+
 type RocksBucket struct {
 	cf *C.rocksdb_column_family_handle_t
 	db *RocksDB
@@ -316,3 +490,143 @@ func (b *RocksBucket) Set(key []byte, value []byte) error {
 func (b *RocksBucket) Delete(key []byte) error {
 	return b.db.deleteValue(b.cf, key)
 }
+
+func (b *RocksBucket) NewIterator(r *Range) (Iterator, error) {
+	return b.db.newIterator(b.db.ro, b.cf, r)
+}
+
+func bytesOf(p *C.char, l C.size_t) []byte {
+	if p == nil {
+		return nil
+	}
+	return C.GoBytes(unsafe.Pointer(p), C.int(l))
+}
+
+// newIterator returns an iterator over the column family.
+// All iterators should be released before closing the database.
+func (db *RocksDB) newIterator(ro *C.rocksdb_readoptions_t, cf *C.rocksdb_column_family_handle_t, r *Range) (Iterator, error) {
+	db.lock.RLock()
+	defer db.lock.RUnlock()
+
+	if db.db == nil {
+		return nil, ErrAlreadyClosed
+	}
+	lower, upper := r.bounds()
+	it := &rocksIterator{
+		db:      db,
+		it:      C.rocksdb_create_iterator_cf(db.db, ro, cf),
+		lower:   lower,
+		upper:   upper,
+		reverse: r.Reverse,
+	}
+	if r.Reverse {
+		if upper != nil {
+			C.rocksdb_iter_seek_for_prev(it.it, (*C.char)(unsafePointerOf(upper)), C.size_t(len(upper)))
+			if it.valid() && bytes.Equal(it.key(), upper) {
+				C.rocksdb_iter_prev(it.it)
+			}
+		} else {
+			C.rocksdb_iter_seek_to_last(it.it)
+		}
+	} else {
+		C.rocksdb_iter_seek(it.it, (*C.char)(unsafePointerOf(lower)), C.size_t(len(lower)))
+	}
+	if err := it.load(); err != nil {
+		it.Release()
+		return nil, err
+	}
+	return it, nil
+}
+
+type rocksIterator struct {
+	db      *RocksDB
+	it      *C.rocksdb_iterator_t
+	lower   []byte
+	upper   []byte
+	reverse bool
+
+	has  bool
+	k, v []byte
+}
+
+func (it *rocksIterator) valid() bool {
+	return C.rocksdb_iter_valid(it.it) != 0
+}
+
+func (it *rocksIterator) key() []byte {
+	var kLen C.size_t
+	k := C.rocksdb_iter_key(it.it, &kLen)
+	return bytesOf(k, kLen)
+}
+
+// load reads current entry of the native iterator checking bounds.
+func (it *rocksIterator) load() error {
+	var cErr *C.char
+	C.rocksdb_iter_get_error(it.it, &cErr)
+	if cErr != nil {
+		defer C.rocksdb_free(unsafe.Pointer(cErr))
+		it.has = false
+		return errors.New(C.GoString(cErr))
+	}
+	it.has, it.k, it.v = false, nil, nil
+	if !it.valid() {
+		return nil
+	}
+	k := it.key()
+	if it.reverse {
+		if bytes.Compare(k, it.lower) < 0 {
+			return nil
+		}
+	} else {
+		if it.upper != nil && bytes.Compare(k, it.upper) >= 0 {
+			return nil
+		}
+	}
+	var vLen C.size_t
+	v := C.rocksdb_iter_value(it.it, &vLen)
+	it.k = k
+	it.v = bytesOf(v, vLen)
+	if it.v == nil {
+		it.v = []byte{}
+	}
+	it.has = true
+	return nil
+}
+
+func (it *rocksIterator) Has() bool {
+	return it.has
+}
+
+func (it *rocksIterator) Next() error {
+	it.db.lock.RLock()
+	defer it.db.lock.RUnlock()
+
+	if it.db.db == nil {
+		return ErrAlreadyClosed
+	}
+	if !it.has {
+		return errors.New("NoMoreItems")
+	}
+	if it.reverse {
+		C.rocksdb_iter_prev(it.it)
+	} else {
+		C.rocksdb_iter_next(it.it)
+	}
+	return it.load()
+}
+
+func (it *rocksIterator) Key() []byte {
+	return it.k
+}
+
+func (it *rocksIterator) Value() []byte {
+	return it.v
+}
+
+func (it *rocksIterator) Release() {
+	if it.it != nil {
+		C.rocksdb_iter_destroy(it.it)
+		it.it = nil
+	}
+	it.has, it.k, it.v = false, nil, nil
+}
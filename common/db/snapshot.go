@@ -0,0 +1,41 @@
+/*
+ * Copyright 2024 ICON Foundation
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package db
+
+import "github.com/icon-project/goloop/common/errors"
+
+// Snapshotter is a Database supporting point-in-time snapshots.
+type Snapshotter interface {
+	// Snapshot returns a read-only database reflecting the state at the
+	// moment of the call. Writes to the database after the call are
+	// not visible through it. Close of the returned database releases
+	// the snapshot, and it should be closed before the origin.
+	Snapshot() (Database, error)
+}
+
+// NewSnapshot returns a read-only snapshot of the database.
+// It returns UnsupportedError if the database doesn't support it.
+func NewSnapshot(dbase Database) (Database, error) {
+	if s, ok := dbase.(Snapshotter); ok {
+		return s.Snapshot()
+	}
+	return nil, errors.UnsupportedError.Errorf("SnapshotNotSupported(db=%T)", dbase)
+}
+
+func errReadOnlySnapshot() error {
+	return errors.InvalidStateError.New("ReadOnlySnapshot")
+}
@@ -0,0 +1,216 @@
+/*
+ * Copyright 2026 ICON Foundation
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+// Package logsindex provides the index of logs blooms of blocks. For each
+// section of blocks, it stores a bitmap of blocks for each bit of the logs
+// bloom, so blocks possibly containing logs can be found without loading
+// headers of all blocks.
+package logsindex
+
+import (
+	"encoding/binary"
+	"sync"
+
+	"github.com/icon-project/goloop/common/codec"
+	"github.com/icon-project/goloop/common/db"
+	"github.com/icon-project/goloop/common/errors"
+)
+
+const (
+	// SectionSize is the number of blocks in a section.
+	SectionSize = 4096
+	bitmapBytes = SectionSize / 8
+)
+
+var keyRange = []byte("range")
+
+type indexRange struct {
+	Start int64
+	Next  int64
+}
+
+// Index is the bitmap index of logs blooms. It covers blocks of heights
+// in [start, next), and blocks are added in height order.
+type Index struct {
+	lock   sync.Mutex
+	dbase  db.Database
+	bucket db.Bucket
+	rng    *indexRange
+
+	// bitmaps of the section including the next block
+	section int64
+	bitmaps map[uint16][]byte
+}
+
+func keyOf(section int64, bit uint16) []byte {
+	key := make([]byte, 10)
+	binary.BigEndian.PutUint64(key, uint64(section))
+	binary.BigEndian.PutUint16(key[8:], bit)
+	return key
+}
+
+// bitsOf returns positions of set bits in the bloom.
+func bitsOf(bloom []byte) []uint16 {
+	var bits []uint16
+	for i, b := range bloom {
+		for j := 0; j < 8; j++ {
+			if b&(0x80>>j) != 0 {
+				bits = append(bits, uint16(i*8+j))
+			}
+		}
+	}
+	return bits
+}
+
+// bitmapInLock returns a copy of the bitmap for the bit in the section.
+func (idx *Index) bitmapInLock(section int64, bit uint16) ([]byte, error) {
+	bs, ok := idx.bitmaps[bit]
+	if section != idx.section || !ok {
+		var err error
+		if bs, err = idx.bucket.Get(keyOf(section, bit)); err != nil {
+			return nil, err
+		}
+	}
+	bm := make([]byte, bitmapBytes)
+	copy(bm, bs)
+	return bm, nil
+}
+
+// Range returns the range of indexed heights, [start, next). If nothing is
+// indexed, then start and next are same.
+func (idx *Index) Range() (int64, int64) {
+	idx.lock.Lock()
+	defer idx.lock.Unlock()
+
+	if idx.rng == nil {
+		return 0, 0
+	}
+	return idx.rng.Start, idx.rng.Next
+}
+
+// Add adds the logs bloom of the block at the height. The first block can be
+// at any height, but following blocks should be added in height order.
+func (idx *Index) Add(height int64, bloom []byte) error {
+	idx.lock.Lock()
+	defer idx.lock.Unlock()
+
+	if height < 0 {
+		return errors.IllegalArgumentError.Errorf("InvalidHeight(height=%d)", height)
+	}
+	rng := indexRange{Start: height, Next: height}
+	if idx.rng != nil {
+		rng = *idx.rng
+	}
+	if height != rng.Next {
+		return errors.InvalidStateError.Errorf(
+			"InvalidHeight(exp=%d,height=%d)", rng.Next, height)
+	}
+
+	section := height / SectionSize
+	if section != idx.section || idx.bitmaps == nil {
+		idx.section = section
+		idx.bitmaps = make(map[uint16][]byte)
+	}
+	offset := height % SectionSize
+
+	batch := db.NewBatch(idx.dbase)
+	updated := make(map[uint16][]byte)
+	for _, bit := range bitsOf(bloom) {
+		bm, err := idx.bitmapInLock(section, bit)
+		if err != nil {
+			return err
+		}
+		bm[offset/8] |= 0x80 >> (offset % 8)
+		batch.Set(db.LogsBloomIndex, keyOf(section, bit), bm)
+		updated[bit] = bm
+	}
+	rng.Next = height + 1
+	batch.Set(db.LogsBloomIndex, keyRange, codec.BC.MustMarshalToBytes(&rng))
+	if err := batch.Write(); err != nil {
+		return err
+	}
+	for bit, bm := range updated {
+		idx.bitmaps[bit] = bm
+	}
+	idx.rng = &rng
+	return nil
+}
+
+// Match returns heights of blocks in [from, to] whose logs blooms contain
+// all bits of the bloom. The range should be in the indexed range.
+func (idx *Index) Match(bloom []byte, from, to int64) ([]int64, error) {
+	idx.lock.Lock()
+	defer idx.lock.Unlock()
+
+	if idx.rng == nil || from < idx.rng.Start || to >= idx.rng.Next || from > to {
+		return nil, errors.IllegalArgumentError.Errorf(
+			"OutOfIndexedRange(from=%d,to=%d)", from, to)
+	}
+
+	bits := bitsOf(bloom)
+	var heights []int64
+	for section := from / SectionSize; section <= to/SectionSize; section++ {
+		matched := make([]byte, bitmapBytes)
+		for i := range matched {
+			matched[i] = 0xff
+		}
+		for _, bit := range bits {
+			bm, err := idx.bitmapInLock(section, bit)
+			if err != nil {
+				return nil, err
+			}
+			for i := range matched {
+				matched[i] &= bm[i]
+			}
+		}
+		base := section * SectionSize
+		for i, b := range matched {
+			if b == 0 {
+				continue
+			}
+			for j := 0; j < 8; j++ {
+				h := base + int64(i*8+j)
+				if b&(0x80>>j) != 0 && h >= from && h <= to {
+					heights = append(heights, h)
+				}
+			}
+		}
+	}
+	return heights, nil
+}
+
+func NewIndex(dbase db.Database) (*Index, error) {
+	bk, err := dbase.GetBucket(db.LogsBloomIndex)
+	if err != nil {
+		return nil, err
+	}
+	idx := &Index{
+		dbase:  dbase,
+		bucket: bk,
+	}
+	bs, err := bk.Get(keyRange)
+	if err != nil {
+		return nil, err
+	}
+	if bs != nil {
+		rng := new(indexRange)
+		if _, err := codec.BC.UnmarshalFromBytes(bs, rng); err != nil {
+			return nil, errors.CriticalFormatError.Wrap(err, "InvalidIndexRange")
+		}
+		idx.rng = rng
+	}
+	return idx, nil
+}
@@ -0,0 +1,97 @@
+/*
+ * Copyright 2026 ICON Foundation
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package logsindex
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/icon-project/goloop/common/db"
+)
+
+func bloomOf(bits ...int) []byte {
+	bloom := make([]byte, 256)
+	for _, bit := range bits {
+		bloom[bit/8] |= 0x80 >> (bit % 8)
+	}
+	return bloom
+}
+
+func TestIndex_AddMatch(t *testing.T) {
+	dbase := db.NewMapDB()
+	idx, err := NewIndex(dbase)
+	assert.NoError(t, err)
+
+	start, next := idx.Range()
+	assert.Equal(t, start, next)
+
+	const base = SectionSize - 10
+	for h := int64(base); h < base+20; h++ {
+		var bloom []byte
+		switch h % 3 {
+		case 0:
+			bloom = bloomOf(1, 2)
+		case 1:
+			bloom = bloomOf(2, 3)
+		default:
+			bloom = bloomOf()
+		}
+		assert.NoError(t, idx.Add(h, bloom))
+	}
+	assert.Error(t, idx.Add(base+30, bloomOf(1)))
+
+	start, next = idx.Range()
+	assert.Equal(t, int64(base), start)
+	assert.Equal(t, int64(base+20), next)
+
+	heights, err := idx.Match(bloomOf(1), base, base+19)
+	assert.NoError(t, err)
+	for _, h := range heights {
+		assert.Equal(t, int64(0), h%3)
+	}
+	assert.Len(t, heights, 7)
+
+	heights, err = idx.Match(bloomOf(2), base+5, base+14)
+	assert.NoError(t, err)
+	for _, h := range heights {
+		assert.NotEqual(t, int64(2), h%3)
+		assert.True(t, h >= base+5 && h <= base+14)
+	}
+	assert.Len(t, heights, 6)
+
+	heights, err = idx.Match(bloomOf(1, 3), base, base+19)
+	assert.NoError(t, err)
+	assert.Len(t, heights, 0)
+
+	_, err = idx.Match(bloomOf(1), base-1, base+19)
+	assert.Error(t, err)
+	_, err = idx.Match(bloomOf(1), base, base+20)
+	assert.Error(t, err)
+
+	// reopen the index
+	idx2, err := NewIndex(dbase)
+	assert.NoError(t, err)
+	start, next = idx2.Range()
+	assert.Equal(t, int64(base), start)
+	assert.Equal(t, int64(base+20), next)
+	heights2, err := idx2.Match(bloomOf(1), base, base+19)
+	assert.NoError(t, err)
+	heights, _ = idx.Match(bloomOf(1), base, base+19)
+	assert.Equal(t, heights, heights2)
+	assert.NoError(t, idx2.Add(base+20, bloomOf(1)))
+}
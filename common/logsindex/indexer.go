@@ -0,0 +1,89 @@
+/*
+ * Copyright 2026 ICON Foundation
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package logsindex
+
+import (
+	"github.com/icon-project/goloop/common/db"
+	"github.com/icon-project/goloop/common/log"
+	"github.com/icon-project/goloop/module"
+)
+
+// Indexer adds logs blooms of finalized blocks to the index in background.
+// If the index is empty, it starts from the given height.
+type Indexer struct {
+	*Index
+	bm     module.BlockManager
+	start  int64
+	logger log.Logger
+
+	stop chan struct{}
+	done chan struct{}
+}
+
+func (ix *Indexer) run() {
+	defer close(ix.done)
+
+	start, next := ix.Range()
+	if start == next {
+		next = ix.start
+	}
+	for {
+		bch, err := ix.bm.WaitForBlock(next)
+		if err != nil {
+			ix.logger.Warnf("LogsIndexer stops height=%d err=%+v", next, err)
+			return
+		}
+		select {
+		case <-ix.stop:
+			return
+		case blk, ok := <-bch:
+			if !ok {
+				return
+			}
+			if err := ix.Add(next, blk.LogsBloom().LogBytes()); err != nil {
+				ix.logger.Warnf("LogsIndexer fails to add height=%d err=%+v", next, err)
+				return
+			}
+			next += 1
+		}
+	}
+}
+
+func (ix *Indexer) Start() {
+	go ix.run()
+}
+
+// Term stops the indexer and waits for it.
+func (ix *Indexer) Term() {
+	close(ix.stop)
+	<-ix.done
+}
+
+func NewIndexer(dbase db.Database, bm module.BlockManager, start int64, logger log.Logger) (*Indexer, error) {
+	idx, err := NewIndex(dbase)
+	if err != nil {
+		return nil, err
+	}
+	return &Indexer{
+		Index:  idx,
+		bm:     bm,
+		start:  start,
+		logger: logger,
+		stop:   make(chan struct{}),
+		done:   make(chan struct{}),
+	}, nil
+}
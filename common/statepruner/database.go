@@ -0,0 +1,156 @@
+/*
+ * Copyright 2026 ICON Foundation
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package statepruner
+
+import (
+	"sync"
+
+	"github.com/icon-project/goloop/common/db"
+)
+
+func isPrunable(id db.BucketID) bool {
+	return id == db.MerkleTrie || id == db.BytesByHash
+}
+
+func refKey(id db.BucketID, key []byte) string {
+	return string(id) + string(key)
+}
+
+// Database tracks writes of MerkleTrie and BytesByHash entries while the
+// pruner is running. States are written on finalization of blocks, so an
+// entry losing all references may be written again by the state of a block
+// which is finalized but not counted yet. The pruner doesn't delete entries
+// written after the finalization of the last counted block.
+type Database struct {
+	db.Database
+
+	lock    sync.Mutex
+	height  int64
+	written map[int64]map[string]struct{}
+}
+
+func (d *Database) GetBucket(id db.BucketID) (db.Bucket, error) {
+	bk, err := d.Database.GetBucket(id)
+	if err != nil || !isPrunable(id) {
+		return bk, err
+	}
+	return &trackedBucket{Bucket: bk, id: id, db: d}, nil
+}
+
+func (d *Database) Snapshot() (db.Database, error) {
+	return db.NewSnapshot(d.Database)
+}
+
+func (d *Database) NewBatch() db.Batch {
+	return &trackedBatch{Batch: db.NewBatch(d.Database), db: d}
+}
+
+func (d *Database) mark(id db.BucketID, key []byte) {
+	d.lock.Lock()
+	defer d.lock.Unlock()
+	if d.written == nil {
+		return
+	}
+	keys, ok := d.written[d.height]
+	if !ok {
+		keys = make(map[string]struct{})
+		d.written[d.height] = keys
+	}
+	keys[refKey(id, key)] = struct{}{}
+}
+
+// track starts or stops tracking writes.
+func (d *Database) track(on bool, height int64) {
+	d.lock.Lock()
+	defer d.lock.Unlock()
+	if on {
+		d.height = height
+		d.written = make(map[int64]map[string]struct{})
+	} else {
+		d.written = nil
+	}
+}
+
+// setHeight sets the height of the last finalized block.
+func (d *Database) setHeight(height int64) {
+	d.lock.Lock()
+	defer d.lock.Unlock()
+	d.height = height
+}
+
+// forgetBefore drops writes before the height. It should be called with
+// the lock.
+func (d *Database) forgetBefore(height int64) {
+	for h := range d.written {
+		if h < height {
+			delete(d.written, h)
+		}
+	}
+}
+
+// isWritten returns whether the entry is written after dropped ones. It
+// should be called with the lock.
+func (d *Database) isWritten(key string) bool {
+	for _, keys := range d.written {
+		if _, ok := keys[key]; ok {
+			return true
+		}
+	}
+	return false
+}
+
+func (d *Database) writtenCount() int {
+	d.lock.Lock()
+	defer d.lock.Unlock()
+	cnt := 0
+	for _, keys := range d.written {
+		cnt += len(keys)
+	}
+	return cnt
+}
+
+// WrapDatabase returns the database tracking writes for the pruner.
+func WrapDatabase(dbase db.Database) *Database {
+	return &Database{Database: dbase}
+}
+
+type trackedBucket struct {
+	db.Bucket
+	id db.BucketID
+	db *Database
+}
+
+func (bk *trackedBucket) Set(key []byte, value []byte) error {
+	bk.db.mark(bk.id, key)
+	return bk.Bucket.Set(key, value)
+}
+
+func (bk *trackedBucket) NewIterator(r *db.Range) (db.Iterator, error) {
+	return db.NewIterator(bk.Bucket, r)
+}
+
+type trackedBatch struct {
+	db.Batch
+	db *Database
+}
+
+func (b *trackedBatch) Set(id db.BucketID, key, value []byte) {
+	if isPrunable(id) {
+		b.db.mark(id, key)
+	}
+	b.Batch.Set(id, key, value)
+}
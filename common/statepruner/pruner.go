@@ -0,0 +1,390 @@
+/*
+ * Copyright 2026 ICON Foundation
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+// Package statepruner removes old world states while the chain is running.
+// It counts references to MerkleTrie and BytesByHash entries from world
+// states of the last N blocks, and deletes entries losing all references.
+// Entries of states before the pruner starts are not counted, but they may
+// share entries with counted states. So once a state is released, states of
+// lower heights may be broken. The lowest height of intact states is kept
+// as the floor, and queries for lower heights should be rejected.
+package statepruner
+
+import (
+	"sync"
+
+	"github.com/icon-project/goloop/common/codec"
+	"github.com/icon-project/goloop/common/db"
+	"github.com/icon-project/goloop/common/errors"
+	"github.com/icon-project/goloop/common/log"
+	"github.com/icon-project/goloop/common/merkle"
+	"github.com/icon-project/goloop/module"
+)
+
+const (
+	// MinKeep is the minimum number of world states to keep.
+	MinKeep = 2
+
+	// writeMargin is the number of blocks to keep tracking writes after
+	// the block is counted. It covers delay of the height of writes.
+	writeMargin = 2
+
+	resetBatchSize = 10000
+)
+
+var (
+	keyStatus = []byte("state_pruner")
+	keyFloor  = []byte("state_pruner_floor")
+)
+
+// Resolver requests entries of the world state of the block result to the
+// builder. It shouldn't request entries referred by blocks.
+type Resolver func(result []byte, bd merkle.Builder) error
+
+// Chain is the part of the block manager used by the pruner.
+type Chain interface {
+	GetLastBlock() (module.Block, error)
+	GetBlockByHeight(height int64) (module.Block, error)
+	WaitForBlock(height int64) (<-chan module.Block, error)
+}
+
+// status is the progress of the pruner. World states of blocks in
+// [Released, Next) are counted.
+type status struct {
+	Start     int64
+	Next      int64
+	Released  int64
+	Reclaimed int64
+	Deleted   int64
+}
+
+type Pruner struct {
+	dbase  *Database
+	chain  Chain
+	resolv Resolver
+	keep   int64
+	logger log.Logger
+
+	lock       sync.Mutex
+	status     status
+	floor      int64
+	candidates map[string]*entry
+
+	stop chan struct{}
+	done sync.WaitGroup
+}
+
+func readStatus(dbase db.Database) (*status, error) {
+	bk, err := dbase.GetBucket(db.ChainProperty)
+	if err != nil {
+		return nil, err
+	}
+	bs, err := bk.Get(keyStatus)
+	if err != nil || bs == nil {
+		return nil, err
+	}
+	s := new(status)
+	if _, err := codec.BC.UnmarshalFromBytes(bs, s); err != nil {
+		return nil, errors.CriticalFormatError.Wrap(err, "InvalidPrunerStatus")
+	}
+	return s, nil
+}
+
+// ReadFloor returns the lowest height of intact world states. It returns 0
+// if no world state is released. The floor is kept even if the counts are
+// reset or the pruning is disabled, because released states are not
+// restored.
+func ReadFloor(dbase db.Database) (int64, error) {
+	bk, err := dbase.GetBucket(db.ChainProperty)
+	if err != nil {
+		return 0, err
+	}
+	bs, err := bk.Get(keyFloor)
+	if err != nil || bs == nil {
+		return 0, err
+	}
+	var floor int64
+	if _, err := codec.BC.UnmarshalFromBytes(bs, &floor); err != nil {
+		return 0, errors.CriticalFormatError.Wrap(err, "InvalidPrunerFloor")
+	}
+	return floor, nil
+}
+
+// resetCounts removes all reference counts and the status.
+func resetCounts(dbase db.Database) error {
+	bk, err := dbase.GetBucket(db.ReferenceCount)
+	if err != nil {
+		return err
+	}
+	for {
+		itr, err := db.NewIterator(bk, nil)
+		if err != nil {
+			return err
+		}
+		b := db.NewBatch(dbase)
+		for itr.Has() && b.Len() < resetBatchSize {
+			b.Delete(db.ReferenceCount, itr.Key())
+			if err = itr.Next(); err != nil {
+				break
+			}
+		}
+		itr.Release()
+		if err != nil {
+			return err
+		}
+		if b.Len() == 0 {
+			break
+		}
+		if err := b.Write(); err != nil {
+			return err
+		}
+	}
+	pbk, err := dbase.GetBucket(db.ChainProperty)
+	if err != nil {
+		return err
+	}
+	return pbk.Delete(keyStatus)
+}
+
+// count adds the delta to reference counts of entries of the state. It
+// returns entries losing the last reference.
+func (p *Pruner) count(rc *refCounts, result []byte, delta int64) ([]*entry, error) {
+	bd := newRefBuilder(p.dbase.Database, rc, delta)
+	if err := p.resolv(result, bd); err != nil {
+		return nil, err
+	}
+	if err := bd.run(); err != nil {
+		return nil, err
+	}
+	return bd.released, nil
+}
+
+// step counts the world state of the block at the height, and releases
+// world states of old blocks. Then it deletes entries without references
+// unless they are written recently.
+func (p *Pruner) step(height int64, result []byte, released [][]byte) error {
+	rc, err := newRefCounts(p.dbase.Database)
+	if err != nil {
+		return err
+	}
+	if _, err := p.count(rc, result, 1); err != nil {
+		return errors.Wrapf(err, "fail to count state height=%d", height)
+	}
+	var es []*entry
+	for _, r := range released {
+		res, err := p.count(rc, r, -1)
+		if err != nil {
+			return errors.Wrapf(err, "fail to release state height=%d", height)
+		}
+		es = append(es, res...)
+	}
+
+	b := db.NewBatch(p.dbase.Database)
+	rc.writeTo(b)
+
+	p.lock.Lock()
+	defer p.lock.Unlock()
+	p.dbase.lock.Lock()
+	defer p.dbase.lock.Unlock()
+
+	for _, e := range es {
+		p.candidates[refKey(e.id, e.key)] = e
+	}
+	p.dbase.forgetBefore(height - writeMargin)
+	st := p.status
+	for key, e := range p.candidates {
+		if cnt, err := rc.get(key); err != nil {
+			return err
+		} else if cnt > 0 {
+			delete(p.candidates, key)
+		} else if !p.dbase.isWritten(key) {
+			b.Delete(e.id, e.key)
+			st.Reclaimed += int64(e.size)
+			st.Deleted += 1
+			delete(p.candidates, key)
+		}
+	}
+	st.Next = height + 1
+	st.Released += int64(len(released))
+	b.Set(db.ChainProperty, keyStatus, codec.BC.MustMarshalToBytes(&st))
+	floor := p.floor
+	if len(released) > 0 && st.Released > floor {
+		floor = st.Released
+		b.Set(db.ChainProperty, keyFloor, codec.BC.MustMarshalToBytes(floor))
+	}
+	if err := b.Write(); err != nil {
+		return err
+	}
+	p.status = st
+	p.floor = floor
+	return nil
+}
+
+func (p *Pruner) resultOf(height int64) ([]byte, error) {
+	blk, err := p.chain.GetBlockByHeight(height)
+	if err != nil {
+		return nil, err
+	}
+	return blk.Result(), nil
+}
+
+func (p *Pruner) run() {
+	defer p.done.Done()
+
+	for {
+		next, released := p.status.Next, p.status.Released
+		bch, err := p.chain.WaitForBlock(next)
+		if err != nil {
+			p.logger.Warnf("StatePruner stops height=%d err=%+v", next, err)
+			return
+		}
+		select {
+		case <-p.stop:
+			return
+		case blk, ok := <-bch:
+			if !ok {
+				return
+			}
+			var results [][]byte
+			for h := released; h <= next-p.keep; h++ {
+				result, err := p.resultOf(h)
+				if err != nil {
+					p.logger.Warnf("StatePruner fails to get block height=%d err=%+v", h, err)
+					return
+				}
+				results = append(results, result)
+			}
+			if err := p.step(next, blk.Result(), results); err != nil {
+				p.logger.Warnf("StatePruner fails to prune height=%d err=%+v", next, err)
+				return
+			}
+		}
+	}
+}
+
+// watch updates the height of writes on finalization of blocks.
+func (p *Pruner) watch(height int64) {
+	defer p.done.Done()
+
+	for h := height + 1; ; h++ {
+		bch, err := p.chain.WaitForBlock(h)
+		if err != nil {
+			return
+		}
+		select {
+		case <-p.stop:
+			return
+		case _, ok := <-bch:
+			if !ok {
+				return
+			}
+			p.dbase.setHeight(h)
+		}
+	}
+}
+
+func (p *Pruner) Start() error {
+	blk, err := p.chain.GetLastBlock()
+	if err != nil {
+		return err
+	}
+	p.dbase.track(true, blk.Height())
+	p.done.Add(2)
+	go p.watch(blk.Height())
+	go p.run()
+	return nil
+}
+
+// Term stops the pruner and waits for it. Entries without references but
+// not deleted yet are kept until the offline pruning.
+func (p *Pruner) Term() {
+	close(p.stop)
+	p.done.Wait()
+	p.dbase.track(false, 0)
+}
+
+// Floor returns the lowest height of intact world states.
+func (p *Pruner) Floor() int64 {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+
+	return p.floor
+}
+
+// Inspect returns the progress of the pruner.
+func (p *Pruner) Inspect(informal bool) map[string]interface{} {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+
+	m := make(map[string]interface{})
+	m["keep"] = p.keep
+	m["start"] = p.status.Start
+	m["height"] = p.status.Next - 1
+	m["released"] = p.status.Released
+	m["floor"] = p.floor
+	m["reclaimedBytes"] = p.status.Reclaimed
+	m["deletedEntries"] = p.status.Deleted
+	if informal {
+		m["candidates"] = len(p.candidates)
+		m["trackedWrites"] = p.dbase.writtenCount()
+	}
+	return m
+}
+
+// New returns the pruner keeping world states of the last keep blocks.
+// It starts from the last block if it has no progress, or the progress is
+// beyond the last block (e.g. the chain is reset).
+func New(dbase *Database, chain Chain, resolv Resolver, keep int, logger log.Logger) (*Pruner, error) {
+	if keep < MinKeep {
+		return nil, errors.IllegalArgumentError.Errorf(
+			"InvalidKeep(keep=%d,min=%d)", keep, MinKeep)
+	}
+	blk, err := chain.GetLastBlock()
+	if err != nil {
+		return nil, err
+	}
+	last := blk.Height()
+
+	st, err := readStatus(dbase.Database)
+	if err != nil {
+		return nil, err
+	}
+	if st != nil && st.Next > last+1 {
+		logger.Warnf("StatePruner resets counts next=%d last=%d", st.Next, last)
+		if err := resetCounts(dbase.Database); err != nil {
+			return nil, err
+		}
+		st = nil
+	}
+	if st == nil {
+		st = &status{Start: last, Next: last, Released: last}
+	}
+	floor, err := ReadFloor(dbase.Database)
+	if err != nil {
+		return nil, err
+	}
+	return &Pruner{
+		dbase:      dbase,
+		chain:      chain,
+		resolv:     resolv,
+		keep:       int64(keep),
+		logger:     logger,
+		status:     *st,
+		floor:      floor,
+		candidates: make(map[string]*entry),
+		stop:       make(chan struct{}),
+	}, nil
+}
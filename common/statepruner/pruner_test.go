@@ -0,0 +1,190 @@
+/*
+ * Copyright 2026 ICON Foundation
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package statepruner
+
+import (
+	"bytes"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/icon-project/goloop/common/db"
+	"github.com/icon-project/goloop/common/log"
+	"github.com/icon-project/goloop/common/merkle"
+	"github.com/icon-project/goloop/common/trie"
+	"github.com/icon-project/goloop/common/trie/trie_manager"
+)
+
+func resolveTrie(result []byte, bd merkle.Builder) error {
+	if len(result) > 0 {
+		trie_manager.NewImmutable(bd.Database(), result).Resolve(bd)
+	}
+	return nil
+}
+
+func newTestPruner(dbase *Database, keep int) *Pruner {
+	return &Pruner{
+		dbase:      dbase,
+		resolv:     resolveTrie,
+		keep:       int64(keep),
+		logger:     log.GlobalLogger(),
+		candidates: make(map[string]*entry),
+		stop:       make(chan struct{}),
+	}
+}
+
+func valueOf(v int) []byte {
+	return bytes.Repeat([]byte{byte(v)}, 40)
+}
+
+// testChain writes states of blocks to the database with heights of
+// finalization, and steps the pruner.
+type testChain struct {
+	t      *testing.T
+	dbase  *Database
+	p      *Pruner
+	m      trie.Mutable
+	states [][]byte
+}
+
+func (c *testChain) finalize(values map[string]int) {
+	for k, v := range values {
+		_, err := c.m.Set([]byte(k), valueOf(v))
+		assert.NoError(c.t, err)
+	}
+	c.dbase.setHeight(int64(len(c.states)) - 1)
+	ss := c.m.GetSnapshot()
+	assert.NoError(c.t, ss.Flush())
+	c.states = append(c.states, ss.Hash())
+}
+
+func (c *testChain) step(height int64) {
+	var released [][]byte
+	for h := c.p.status.Released; h <= height-c.p.keep; h++ {
+		released = append(released, c.states[h])
+	}
+	assert.NoError(c.t, c.p.step(height, c.states[height], released))
+}
+
+func (c *testChain) assertState(height int64, values map[string]int) {
+	s := trie_manager.NewImmutable(c.dbase.Database, c.states[height])
+	for k, v := range values {
+		value, err := s.Get([]byte(k))
+		assert.NoError(c.t, err, "height=%d key=%s", height, k)
+		assert.Equal(c.t, valueOf(v), value)
+	}
+}
+
+func newTestChain(t *testing.T, keep int) *testChain {
+	dbase := WrapDatabase(db.NewMapDB())
+	dbase.track(true, 0)
+	return &testChain{
+		t:     t,
+		dbase: dbase,
+		p:     newTestPruner(dbase, keep),
+		m:     trie_manager.NewMutable(dbase, nil),
+	}
+}
+
+func TestPruner_Step(t *testing.T) {
+	c := newTestChain(t, 2)
+
+	all := make(map[string]int)
+	for i := 0; i < 32; i++ {
+		all[fmt.Sprintf("key%d", i)] = 0
+	}
+	c.finalize(all)
+	c.step(0)
+
+	for h := 1; h <= 8; h++ {
+		c.finalize(map[string]int{fmt.Sprintf("key%d", h): h})
+		all[fmt.Sprintf("key%d", h)] = h
+		c.step(int64(h))
+		c.assertState(int64(h), all)
+	}
+	assert.EqualValues(t, 7, c.p.status.Released)
+	assert.True(t, c.p.status.Reclaimed > 0)
+	assert.True(t, c.p.status.Deleted > 0)
+	assert.EqualValues(t, 7, c.p.Floor())
+
+	// floor is kept after reset of counts
+	assert.NoError(t, resetCounts(c.dbase.Database))
+	floor, err := ReadFloor(c.dbase.Database)
+	assert.NoError(t, err)
+	assert.EqualValues(t, 7, floor)
+
+	// released state is not available
+	_, err = trie_manager.NewImmutable(c.dbase.Database, c.states[1]).Get([]byte("key1"))
+	assert.Error(t, err)
+}
+
+func TestPruner_RecentWrite(t *testing.T) {
+	c := newTestChain(t, 2)
+
+	c.finalize(map[string]int{"a": 1, "b": 1})
+	c.step(0)
+	c.finalize(map[string]int{"a": 2})
+	c.step(1)
+	c.finalize(map[string]int{"a": 3})
+
+	// the chain is ahead of the pruner, and the state of the block 3 is
+	// same as the state of the block 0.
+	c.finalize(map[string]int{"a": 1})
+	assert.Equal(t, c.states[0], c.states[3])
+
+	c.step(2)
+	c.assertState(3, map[string]int{"a": 1, "b": 1})
+	c.step(3)
+	c.assertState(3, map[string]int{"a": 1, "b": 1})
+	assert.Empty(t, c.p.candidates)
+
+	// it's deleted after the state is released.
+	for h := 4; h <= 6; h++ {
+		c.finalize(map[string]int{"a": h})
+		c.step(int64(h))
+	}
+	assert.Empty(t, c.p.candidates)
+	_, err := trie_manager.NewImmutable(c.dbase.Database, c.states[3]).Get([]byte("a"))
+	assert.Error(t, err)
+}
+
+func TestPruner_Untracked(t *testing.T) {
+	c := newTestChain(t, 2)
+
+	// states before the start are not counted, so entries only
+	// referred by them are not deleted.
+	c.finalize(map[string]int{"a": 1, "b": 1})
+	c.finalize(map[string]int{"a": 2})
+	c.p.status = status{Start: 1, Next: 1, Released: 1}
+	c.step(1)
+	assert.EqualValues(t, 0, c.p.Floor())
+	for h := 2; h <= 4; h++ {
+		c.finalize(map[string]int{"b": h})
+		c.step(int64(h))
+	}
+	c.assertState(4, map[string]int{"a": 2, "b": 4})
+	assert.EqualValues(t, 3, c.p.Floor())
+	_, err := trie_manager.NewImmutable(c.dbase.Database, c.states[1]).Get([]byte("b"))
+	assert.Error(t, err)
+
+	bk, err := c.dbase.Database.GetBucket(db.MerkleTrie)
+	assert.NoError(t, err)
+	value, err := bk.Get(c.states[0])
+	assert.NoError(t, err)
+	assert.NotNil(t, value)
+}
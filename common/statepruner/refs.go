@@ -0,0 +1,208 @@
+/*
+ * Copyright 2026 ICON Foundation
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package statepruner
+
+import (
+	"github.com/icon-project/goloop/common/codec"
+	"github.com/icon-project/goloop/common/db"
+	"github.com/icon-project/goloop/common/errors"
+	"github.com/icon-project/goloop/common/merkle"
+)
+
+// refCounts keeps changes of reference counts until they are written.
+type refCounts struct {
+	bk    db.Bucket
+	dirty map[string]int64
+}
+
+func (rc *refCounts) get(key string) (int64, error) {
+	if cnt, ok := rc.dirty[key]; ok {
+		return cnt, nil
+	}
+	bs, err := rc.bk.Get([]byte(key))
+	if err != nil || bs == nil {
+		return 0, err
+	}
+	var cnt int64
+	if _, err := codec.BC.UnmarshalFromBytes(bs, &cnt); err != nil {
+		return 0, errors.CriticalFormatError.Wrapf(err, "InvalidReferenceCount(key=%x)", key)
+	}
+	return cnt, nil
+}
+
+func (rc *refCounts) set(key string, cnt int64) {
+	rc.dirty[key] = cnt
+}
+
+func (rc *refCounts) writeTo(b db.Batch) {
+	for key, cnt := range rc.dirty {
+		if cnt > 0 {
+			b.Set(db.ReferenceCount, []byte(key), codec.BC.MustMarshalToBytes(cnt))
+		} else {
+			b.Delete(db.ReferenceCount, []byte(key))
+		}
+	}
+}
+
+func newRefCounts(dbase db.Database) (*refCounts, error) {
+	bk, err := dbase.GetBucket(db.ReferenceCount)
+	if err != nil {
+		return nil, err
+	}
+	return &refCounts{bk: bk, dirty: make(map[string]int64)}, nil
+}
+
+// entry is an entry of MerkleTrie or BytesByHash which lost all references.
+type entry struct {
+	id   db.BucketID
+	key  []byte
+	size int
+}
+
+type refRequest struct {
+	id        db.BucketID
+	key       []byte
+	requester merkle.DataRequester
+}
+
+// refBuilder counts references with requesters of the state. It provides
+// an empty database, so requesters request all entries they refer. An
+// entry is traversed only when it gets the first reference, or when it
+// loses the last reference. Entries of other buckets are always traversed
+// without counting.
+type refBuilder struct {
+	src      db.Database
+	rc       *refCounts
+	delta    int64
+	requests []*refRequest
+	resolved int
+	released []*entry
+	err      error
+}
+
+func (b *refBuilder) OnData(bid db.BucketID, value []byte) error {
+	return merkle.ErrNoRequester
+}
+
+func (b *refBuilder) UnresolvedCount() int {
+	return len(b.requests)
+}
+
+func (b *refBuilder) ResolvedCount() int {
+	return b.resolved
+}
+
+func (b *refBuilder) Requests() merkle.RequestIterator {
+	return &requestIterator{requests: b.requests, idx: -1}
+}
+
+func (b *refBuilder) RequestData(id db.BucketID, key []byte, requester merkle.DataRequester) {
+	if key == nil || b.err != nil {
+		return
+	}
+	if isPrunable(id) {
+		rk := refKey(id, key)
+		cnt, err := b.rc.get(rk)
+		if err != nil {
+			b.err = err
+			return
+		}
+		if cnt == 0 && b.delta < 0 {
+			// it's not counted, so it's not deleted either.
+			return
+		}
+		cnt += b.delta
+		b.rc.set(rk, cnt)
+		if (b.delta > 0 && cnt > 1) || (b.delta < 0 && cnt > 0) {
+			return
+		}
+	}
+	b.requests = append(b.requests, &refRequest{
+		id:        id,
+		key:       key,
+		requester: requester,
+	})
+}
+
+func (b *refBuilder) Database() db.Database {
+	return db.NewNullDB()
+}
+
+func (b *refBuilder) Flush(write bool) error {
+	return nil
+}
+
+// run traverses requested entries until no more requests.
+func (b *refBuilder) run() error {
+	for b.err == nil && len(b.requests) > 0 {
+		req := b.requests[len(b.requests)-1]
+		b.requests = b.requests[:len(b.requests)-1]
+
+		bk, err := b.src.GetBucket(req.id)
+		if err != nil {
+			return err
+		}
+		value, err := bk.Get(req.key)
+		if err != nil {
+			return err
+		}
+		if value == nil {
+			if b.delta > 0 {
+				return errors.NotFoundError.Errorf("FailToFindValue(key=%x)", req.key)
+			}
+			continue
+		}
+		if b.delta < 0 && isPrunable(req.id) {
+			b.released = append(b.released, &entry{
+				id:   req.id,
+				key:  req.key,
+				size: len(value),
+			})
+		}
+		b.resolved += 1
+		if err := req.requester.OnData(value, b); err != nil {
+			return err
+		}
+	}
+	return b.err
+}
+
+func newRefBuilder(src db.Database, rc *refCounts, delta int64) *refBuilder {
+	return &refBuilder{
+		src:   src,
+		rc:    rc,
+		delta: delta,
+	}
+}
+
+type requestIterator struct {
+	requests []*refRequest
+	idx      int
+}
+
+func (i *requestIterator) Next() bool {
+	i.idx += 1
+	return i.idx < len(i.requests)
+}
+
+func (i *requestIterator) Key() []byte {
+	return i.requests[i.idx].key
+}
+
+func (i *requestIterator) BucketIDs() []db.BucketID {
+	return []db.BucketID{i.requests[i.idx].id}
+}
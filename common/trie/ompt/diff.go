@@ -0,0 +1,280 @@
+package ompt
+
+import (
+	"bytes"
+	"errors"
+
+	"github.com/icon-project/goloop/common/trie"
+)
+
+// diffCursor visits nodes of a tree in key order like iterator, but it
+// doesn't realize nodes until it needs to look into them. So identical
+// subtrees can be skipped by comparing links without reading them.
+type diffCursor struct {
+	m     *mpt
+	stack []iteratorItem
+	key   string
+	value trie.Object
+}
+
+func newDiffCursor(m *mpt) *diffCursor {
+	c := &diffCursor{m: m}
+	if m == nil {
+		return c
+	}
+	m.mutex.RLock()
+	root := m.root
+	m.mutex.RUnlock()
+	if root != nil {
+		c.stack = []iteratorItem{{k: "", n: root}}
+	}
+	return c
+}
+
+func (c *diffCursor) push(k string, n node) (node, error) {
+	c.stack = append(c.stack, iteratorItem{k: k, n: n})
+	return n, nil
+}
+
+func (c *diffCursor) end() bool {
+	return c.value == nil && len(c.stack) == 0
+}
+
+func (c *diffCursor) top() iteratorItem {
+	return c.stack[len(c.stack)-1]
+}
+
+func (c *diffCursor) pop() iteratorItem {
+	l := len(c.stack)
+	ii := c.stack[l-1]
+	c.stack = c.stack[:l-1]
+	return ii
+}
+
+// expand replaces the top node with its children and its value.
+func (c *diffCursor) expand() error {
+	ii := c.pop()
+	key, value, err := ii.n.traverse(c.m, ii.k, c.push)
+	if err != nil {
+		return err
+	}
+	if value != nil {
+		c.key, c.value = key, value
+	}
+	return nil
+}
+
+func (c *diffCursor) take() (string, trie.Object) {
+	key, value := c.key, c.value
+	c.key, c.value = "", nil
+	return key, value
+}
+
+type diffIterator struct {
+	from, to *diffCursor
+
+	op    trie.DiffOp
+	key   []byte
+	old   trie.Object
+	new   trie.Object
+	error error
+}
+
+func sameLink(n1, n2 node) bool {
+	return bytes.Equal(n1.getLink(false), n2.getLink(false))
+}
+
+func (i *diffIterator) set(op trie.DiffOp, key string, old, new trie.Object) {
+	i.op = op
+	i.key = keysToBytes(key)
+	i.old = old
+	i.new = new
+}
+
+// step advances cursors until it finds a difference or reaches the end.
+// Pending value of a cursor always precedes nodes in its stack, and the
+// node at top of the stack precedes other nodes in the stack. So it
+// expands the node only if it may have keys before the other side.
+func (i *diffIterator) step() (bool, error) {
+	from, to := i.from, i.to
+	for !from.end() || !to.end() {
+		switch {
+		case from.value == nil && to.value == nil:
+			if from.end() {
+				if err := to.expand(); err != nil {
+					return false, err
+				}
+				continue
+			}
+			if to.end() {
+				if err := from.expand(); err != nil {
+					return false, err
+				}
+				continue
+			}
+			fi, ti := from.top(), to.top()
+			switch {
+			case fi.k == ti.k:
+				if sameLink(fi.n, ti.n) {
+					from.pop()
+					to.pop()
+					continue
+				}
+				if err := from.expand(); err != nil {
+					return false, err
+				}
+				if err := to.expand(); err != nil {
+					return false, err
+				}
+			case fi.k < ti.k:
+				if err := from.expand(); err != nil {
+					return false, err
+				}
+			default:
+				if err := to.expand(); err != nil {
+					return false, err
+				}
+			}
+		case from.value == nil:
+			if len(from.stack) > 0 && from.top().k <= to.key {
+				if err := from.expand(); err != nil {
+					return false, err
+				}
+				continue
+			}
+			key, value := to.take()
+			i.set(trie.DiffAdded, key, nil, value)
+			return true, nil
+		case to.value == nil:
+			if len(to.stack) > 0 && to.top().k <= from.key {
+				if err := to.expand(); err != nil {
+					return false, err
+				}
+				continue
+			}
+			key, value := from.take()
+			i.set(trie.DiffRemoved, key, value, nil)
+			return true, nil
+		default:
+			switch {
+			case from.key < to.key:
+				key, value := from.take()
+				i.set(trie.DiffRemoved, key, value, nil)
+				return true, nil
+			case from.key > to.key:
+				key, value := to.take()
+				i.set(trie.DiffAdded, key, nil, value)
+				return true, nil
+			}
+			key, old := from.take()
+			_, value := to.take()
+			if !bytes.Equal(old.Bytes(), value.Bytes()) {
+				i.set(trie.DiffModified, key, old, value)
+				return true, nil
+			}
+		}
+	}
+	return false, nil
+}
+
+func (i *diffIterator) Next() error {
+	if i.error != nil {
+		return i.error
+	}
+	if i.key == nil {
+		return errors.New("NoMore")
+	}
+	i.key, i.old, i.new = nil, nil, nil
+	if ok, err := i.step(); err != nil {
+		i.error = err
+	} else if !ok {
+		i.key = nil
+	}
+	return nil
+}
+
+func (i *diffIterator) Has() bool {
+	return i.key != nil || i.error != nil
+}
+
+func (i *diffIterator) Get() (trie.DiffOp, []byte, trie.Object, trie.Object, error) {
+	return i.op, i.key, i.old, i.new, i.error
+}
+
+func newDiffIterator(from, to *mpt) *diffIterator {
+	i := &diffIterator{
+		from: newDiffCursor(from),
+		to:   newDiffCursor(to),
+	}
+	if ok, err := i.step(); err != nil {
+		i.error = err
+	} else if !ok {
+		i.key = nil
+	}
+	return i
+}
+
+// NewDiffIteratorForObject returns an iterator over keys changed from one
+// tree to the other. Subtrees with the same hash are skipped without
+// reading them. Nil can be used as an empty tree.
+func NewDiffIteratorForObject(from, to trie.ImmutableForObject) (trie.DiffIteratorForObject, error) {
+	m1, err := mptOf(from)
+	if err != nil {
+		return nil, err
+	}
+	m2, err := mptOf(to)
+	if err != nil {
+		return nil, err
+	}
+	return newDiffIterator(m1, m2), nil
+}
+
+func mptOf(o trie.ImmutableForObject) (*mpt, error) {
+	if o == nil {
+		return nil, nil
+	}
+	if m, ok := o.(*mpt); ok {
+		return m, nil
+	}
+	return nil, errors.New("InvalidImmutable")
+}
+
+type diffIteratorForBytes struct {
+	*diffIterator
+}
+
+func (i *diffIteratorForBytes) Get() (trie.DiffOp, []byte, []byte, []byte, error) {
+	op, key, old, new, err := i.diffIterator.Get()
+	var ob, nb []byte
+	if old != nil {
+		ob = old.Bytes()
+	}
+	if new != nil {
+		nb = new.Bytes()
+	}
+	return op, key, ob, nb, err
+}
+
+// NewDiffIterator is the same as NewDiffIteratorForObject except that it
+// returns values in bytes.
+func NewDiffIterator(from, to trie.Immutable) (trie.DiffIterator, error) {
+	m1, err := mptForBytesOf(from)
+	if err != nil {
+		return nil, err
+	}
+	m2, err := mptForBytesOf(to)
+	if err != nil {
+		return nil, err
+	}
+	return &diffIteratorForBytes{newDiffIterator(m1, m2)}, nil
+}
+
+func mptForBytesOf(o trie.Immutable) (*mpt, error) {
+	if o == nil {
+		return nil, nil
+	}
+	if m, ok := o.(*mptForBytes); ok {
+		return m.mpt, nil
+	}
+	return nil, errors.New("InvalidImmutable")
+}
@@ -0,0 +1,170 @@
+package ompt
+
+import (
+	"fmt"
+	"math/rand"
+	"sort"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/icon-project/goloop/common/db"
+	"github.com/icon-project/goloop/common/trie"
+)
+
+type diffEntry struct {
+	op       trie.DiffOp
+	key      string
+	old, new string
+}
+
+func collectDiff(t *testing.T, from, to trie.Immutable) []diffEntry {
+	it, err := NewDiffIterator(from, to)
+	assert.NoError(t, err)
+	var diffs []diffEntry
+	for ; it.Has(); it.Next() {
+		op, key, old, new, err := it.Get()
+		assert.NoError(t, err)
+		diffs = append(diffs, diffEntry{op, string(key), string(old), string(new)})
+	}
+	return diffs
+}
+
+func expectDiff(from, to map[string]string) []diffEntry {
+	var diffs []diffEntry
+	for k, v := range from {
+		if nv, ok := to[k]; !ok {
+			diffs = append(diffs, diffEntry{trie.DiffRemoved, k, v, ""})
+		} else if nv != v {
+			diffs = append(diffs, diffEntry{trie.DiffModified, k, v, nv})
+		}
+	}
+	for k, v := range to {
+		if _, ok := from[k]; !ok {
+			diffs = append(diffs, diffEntry{trie.DiffAdded, k, "", v})
+		}
+	}
+	sort.Slice(diffs, func(i, j int) bool {
+		return diffs[i].key < diffs[j].key
+	})
+	return diffs
+}
+
+func newTestTrie(t *testing.T, dbase db.Database, values map[string]string) trie.Immutable {
+	m := NewMPTForBytes(dbase, nil)
+	for k, v := range values {
+		_, err := m.Set([]byte(k), []byte(v))
+		assert.NoError(t, err)
+	}
+	s := m.GetSnapshot()
+	assert.NoError(t, s.Flush())
+	return s
+}
+
+func TestDiffIterator_Basic(t *testing.T) {
+	dbase := db.NewMapDB()
+	v1 := map[string]string{
+		"\x01":         "a",
+		"\x01\x22":     "b",
+		"\x01\x23\x44": "c",
+		"\x01\x23\x45": "d",
+		"\x02":         "e",
+	}
+	v2 := map[string]string{
+		"\x01\x22":     "b",
+		"\x01\x23\x44": "cc",
+		"\x01\x23":     "f",
+		"\x02":         "e",
+		"\x03\x00":     "g",
+	}
+	t1 := newTestTrie(t, dbase, v1)
+	t2 := newTestTrie(t, dbase, v2)
+
+	assert.Equal(t, expectDiff(v1, v2), collectDiff(t, t1, t2))
+	assert.Equal(t, expectDiff(v2, v1), collectDiff(t, t2, t1))
+	assert.Empty(t, collectDiff(t, t1, t1))
+	assert.Equal(t, expectDiff(nil, v1), collectDiff(t, nil, t1))
+	assert.Equal(t, expectDiff(v1, nil), collectDiff(t, t1, NewMPTForBytes(dbase, nil)))
+}
+
+func TestDiffIterator_Random(t *testing.T) {
+	dbase := db.NewMapDB()
+	r := rand.New(rand.NewSource(1))
+	randKey := func() string {
+		k := make([]byte, 1+r.Intn(3))
+		r.Read(k)
+		return string(k)
+	}
+	for tc := 0; tc < 20; tc++ {
+		v1 := make(map[string]string)
+		for i := 0; i < 200; i++ {
+			v1[randKey()] = fmt.Sprint("v", r.Intn(1000))
+		}
+		v2 := make(map[string]string)
+		for k, v := range v1 {
+			switch r.Intn(10) {
+			case 0:
+			case 1:
+				v2[k] = v + "x"
+			default:
+				v2[k] = v
+			}
+		}
+		for i := 0; i < 20; i++ {
+			v2[randKey()] = fmt.Sprint("n", r.Intn(1000))
+		}
+		t1 := newTestTrie(t, dbase, v1)
+		t2 := newTestTrie(t, dbase, v2)
+		assert.Equal(t, expectDiff(v1, v2), collectDiff(t, t1, t2))
+	}
+}
+
+type countingDB struct {
+	db.Database
+	reads int
+}
+
+type countingBucket struct {
+	db.Bucket
+	cdb *countingDB
+}
+
+func (b *countingBucket) Get(k []byte) ([]byte, error) {
+	b.cdb.reads += 1
+	return b.Bucket.Get(k)
+}
+
+func (d *countingDB) GetBucket(id db.BucketID) (db.Bucket, error) {
+	bk, err := d.Database.GetBucket(id)
+	if err != nil {
+		return nil, err
+	}
+	return &countingBucket{bk, d}, nil
+}
+
+func TestDiffIterator_SkipSameSubtree(t *testing.T) {
+	dbase := db.NewMapDB()
+	v1 := make(map[string]string)
+	for i := 0; i < 1000; i++ {
+		v1[fmt.Sprintf("key%04d", i)] = fmt.Sprintf("value%04d", i)
+	}
+	v2 := make(map[string]string)
+	for k, v := range v1 {
+		v2[k] = v
+	}
+	v2["key0500"] = "changed"
+	h1 := newTestTrie(t, dbase, v1).Hash()
+	h2 := newTestTrie(t, dbase, v2).Hash()
+
+	cdb := &countingDB{Database: dbase}
+	diffs := collectDiff(t, NewMPTForBytes(cdb, h1), NewMPTForBytes(cdb, h2))
+	assert.Equal(t, []diffEntry{
+		{trie.DiffModified, "key0500", "value0500", "changed"},
+	}, diffs)
+	assert.True(t, cdb.reads < 100, "too many reads=%d", cdb.reads)
+}
+
+func TestDiffIterator_InvalidImmutable(t *testing.T) {
+	_, err := NewDiffIterator(nil, &struct{ trie.Immutable }{})
+	assert.Error(t, err)
+}
@@ -0,0 +1,349 @@
+package ompt
+
+import (
+	"bytes"
+	"reflect"
+	"strings"
+
+	"github.com/icon-project/goloop/common/db"
+	"github.com/icon-project/goloop/common/errors"
+	"github.com/icon-project/goloop/common/trie"
+)
+
+// keyRange is a range of keys in nibbles. Keys in [left, right] are
+// in the range. If hasRight is false, it has no upper bound.
+type keyRange struct {
+	left     string
+	right    string
+	hasRight bool
+}
+
+const (
+	rangeOut = iota
+	rangePartial
+	rangeIn
+)
+
+// classify returns whether all keys with the prefix are in the range, out of
+// the range, or partially in the range.
+func (r *keyRange) classify(prefix string) int {
+	if prefix < r.left && !strings.HasPrefix(r.left, prefix) {
+		return rangeOut
+	}
+	if r.hasRight && prefix > r.right {
+		return rangeOut
+	}
+	if prefix >= r.left {
+		if !r.hasRight || (prefix < r.right && !strings.HasPrefix(r.right, prefix)) {
+			return rangeIn
+		}
+	}
+	return rangePartial
+}
+
+func (r *keyRange) contains(key string) bool {
+	return key >= r.left && (!r.hasRight || key <= r.right)
+}
+
+func nibblesOf(k []byte) string {
+	nibs := bytesToNibs(k)
+	defer freeNibbles(nibs)
+	return string(nibs)
+}
+
+// collectRange appends entries from the left of the range in key order
+// until it has limit entries.
+func (m *mpt) collectRange(n node, prefix string, r *keyRange, limit int,
+	keys, values [][]byte) ([][]byte, [][]byte, error) {
+	if n == nil || len(keys) >= limit || r.classify(prefix) == rangeOut {
+		return keys, values, nil
+	}
+	n, err := n.realize(m)
+	if err != nil {
+		return keys, values, err
+	}
+	switch nn := n.(type) {
+	case *leaf:
+		lock := nn.rlock()
+		key, value := prefix+string(nn.keys), nn.value
+		lock.Unlock()
+		if r.contains(key) {
+			keys = append(keys, keysToBytes(key))
+			values = append(values, value.Bytes())
+		}
+	case *extension:
+		lock := nn.rlock()
+		key, next := prefix+string(nn.keys), nn.next
+		lock.Unlock()
+		return m.collectRange(next, key, r, limit, keys, values)
+	case *branch:
+		lock := nn.rlock()
+		children, value := nn.children, nn.value
+		lock.Unlock()
+		if value != nil && r.contains(prefix) {
+			keys = append(keys, keysToBytes(prefix))
+			values = append(values, value.Bytes())
+		}
+		for i, child := range children {
+			keys, values, err = m.collectRange(child, prefix+string([]byte{byte(i)}),
+				r, limit, keys, values)
+			if err != nil {
+				return keys, values, err
+			}
+		}
+	}
+	return keys, values, nil
+}
+
+// collectPath appends serialized nodes on the path to the key. Nodes
+// embedded in its parent are not appended.
+func (m *mpt) collectPath(n node, nibs string, proof [][]byte, seen map[string]bool) ([][]byte, error) {
+	var depth int
+	for n != nil {
+		var err error
+		if n, err = n.realize(m); err != nil {
+			return nil, err
+		}
+		n.getLink(false)
+
+		var next node
+		var nb *nodeBase
+		switch nn := n.(type) {
+		case *leaf:
+			nb = &nn.nodeBase
+		case *extension:
+			nb = &nn.nodeBase
+			lock := nn.rlock()
+			if strings.HasPrefix(nibs[depth:], string(nn.keys)) {
+				depth += len(nn.keys)
+				next = nn.next
+			}
+			lock.Unlock()
+		case *branch:
+			nb = &nn.nodeBase
+			lock := nn.rlock()
+			if depth < len(nibs) {
+				next = nn.children[nibs[depth]]
+				depth += 1
+			}
+			lock.Unlock()
+		}
+		lock := nb.rlock()
+		if nb.hashValue != nil && !seen[string(nb.hashValue)] {
+			seen[string(nb.hashValue)] = true
+			proof = append(proof, nb.serialized)
+		}
+		lock.Unlock()
+		n = next
+	}
+	return proof, nil
+}
+
+func (m *mpt) getRangeProof(start []byte, limit int) (keys, values, proof [][]byte, err error) {
+	if limit <= 0 {
+		return nil, nil, nil, errors.IllegalArgumentError.Errorf("InvalidLimit(limit=%d)", limit)
+	}
+	m.mutex.RLock()
+	root := m.root
+	m.mutex.RUnlock()
+	if root == nil {
+		return nil, nil, nil, nil
+	}
+	root.getLink(true)
+
+	r := &keyRange{left: nibblesOf(start)}
+	keys, values, err = m.collectRange(root, "", r, limit, nil, nil)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	seen := make(map[string]bool)
+	if proof, err = m.collectPath(root, r.left, proof, seen); err != nil {
+		return nil, nil, nil, err
+	}
+	if len(keys) > 0 {
+		last := nibblesOf(keys[len(keys)-1])
+		if proof, err = m.collectPath(root, last, proof, seen); err != nil {
+			return nil, nil, nil, err
+		}
+	}
+	return keys, values, proof, nil
+}
+
+// GetRangeProof returns up to limit entries of the tree from the start key
+// in key order, and the proof for them. The proof contains nodes on the
+// path to the start key and on the path to the last returned key, so it
+// proves absence of other keys in the range, and it's verified by
+// VerifyRangeProof.
+func GetRangeProof(immutable trie.Immutable, start []byte, limit int) (keys, values, proof [][]byte, err error) {
+	m, err := mptForBytesOf(immutable)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	if m == nil {
+		return nil, nil, nil, nil
+	}
+	return m.getRangeProof(start, limit)
+}
+
+// GetRangeProofForObject is the same as GetRangeProof except that it
+// returns bytes of objects as values.
+func GetRangeProofForObject(immutable trie.ImmutableForObject, start []byte, limit int) (keys, values, proof [][]byte, err error) {
+	m, err := mptOf(immutable)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	if m == nil {
+		return nil, nil, nil, nil
+	}
+	return m.getRangeProof(start, limit)
+}
+
+type rangeVerifier struct {
+	nodes map[string][]byte
+	r     *keyRange
+}
+
+func (v *rangeVerifier) realize(n node) (node, error) {
+	if h, ok := n.(*hash); ok {
+		serialized, ok := v.nodes[string(h.value)]
+		if !ok {
+			return nil, errors.NotFoundError.Errorf("MissingProofNode(hash=%#x)", h.value)
+		}
+		return deserialize(nil, serialized, stateDirty)
+	}
+	return n, nil
+}
+
+// clear removes all entries in the range, so that it has only entries out
+// of the range.
+func (v *rangeVerifier) clear(n node, prefix string) (node, error) {
+	if n == nil {
+		return nil, nil
+	}
+	switch v.r.classify(prefix) {
+	case rangeOut:
+		return n, nil
+	case rangeIn:
+		return nil, nil
+	}
+	n, err := v.realize(n)
+	if err != nil {
+		return nil, err
+	}
+	switch nn := n.(type) {
+	case *leaf:
+		if v.r.contains(prefix + string(nn.keys)) {
+			return nil, nil
+		}
+		return nn, nil
+	case *extension:
+		next, err := v.clear(nn.next, prefix+string(nn.keys))
+		if err != nil || next == nil {
+			return nil, err
+		}
+		nn.next = next
+		return nn, nil
+	case *branch:
+		if nn.value != nil && v.r.contains(prefix) {
+			nn.value = nil
+		}
+		empty := nn.value == nil
+		for i, child := range nn.children {
+			child, err := v.clear(child, prefix+string([]byte{byte(i)}))
+			if err != nil {
+				return nil, err
+			}
+			nn.children[i] = child
+			empty = empty && child == nil
+		}
+		if empty {
+			return nil, nil
+		}
+		return nn, nil
+	default:
+		return nil, errors.InvalidStateError.Errorf("UnknownNode(%T)", n)
+	}
+}
+
+// hasRight returns true if there is an entry after the range.
+func (v *rangeVerifier) hasRight(n node, prefix string) bool {
+	if n == nil || !v.r.hasRight {
+		return false
+	}
+	if prefix > v.r.right {
+		return true
+	}
+	if !strings.HasPrefix(v.r.right, prefix) {
+		return false
+	}
+	switch nn := n.(type) {
+	case *leaf:
+		return prefix+string(nn.keys) > v.r.right
+	case *extension:
+		return v.hasRight(nn.next, prefix+string(nn.keys))
+	case *branch:
+		for i, child := range nn.children {
+			if v.hasRight(child, prefix+string([]byte{byte(i)})) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// VerifyRangeProof verifies entries returned by GetRangeProof against the
+// root hash. Keys should be sorted, and the first key should not be less
+// than the start key. It returns whether the tree has more entries after
+// the last key.
+func VerifyRangeProof(root []byte, start []byte, keys, values, proof [][]byte) (bool, error) {
+	if len(keys) != len(values) {
+		return false, errors.IllegalArgumentError.Errorf(
+			"InvalidEntries(keys=%d,values=%d)", len(keys), len(values))
+	}
+	for i, k := range keys {
+		if i == 0 && bytes.Compare(k, start) < 0 {
+			return false, errors.IllegalArgumentError.Errorf("KeyBeforeStart(key=%#x)", k)
+		}
+		if i > 0 && bytes.Compare(keys[i-1], k) >= 0 {
+			return false, errors.IllegalArgumentError.Errorf("UnsortedKeys(key=%#x)", k)
+		}
+		if len(values[i]) == 0 {
+			return false, errors.IllegalArgumentError.Errorf("EmptyValue(key=%#x)", k)
+		}
+	}
+	if len(root) == 0 {
+		if len(keys) > 0 {
+			return false, errors.InvalidStateError.New("EntriesInEmptyTree")
+		}
+		return false, nil
+	}
+
+	v := &rangeVerifier{
+		nodes: make(map[string][]byte, len(proof)),
+		r:     &keyRange{left: nibblesOf(start)},
+	}
+	for _, serialized := range proof {
+		v.nodes[string(calcHash(serialized))] = serialized
+	}
+	if len(keys) > 0 {
+		v.r.right = nibblesOf(keys[len(keys)-1])
+		v.r.hasRight = true
+	}
+
+	rootNode, err := v.clear(nodeFromHash(root), "")
+	if err != nil {
+		return false, err
+	}
+	m := NewMPT(db.NewNullDB(), nil, reflect.TypeOf(bytesObject(nil)))
+	m.root = rootNode
+	for i, k := range keys {
+		if _, err := m.Set(k, bytesObject(values[i])); err != nil {
+			return false, err
+		}
+	}
+	if h := m.Hash(); !bytes.Equal(h, root) {
+		return false, errors.InvalidStateError.Errorf(
+			"InvalidRangeProof(exp=%#x,calc=%#x)", root, h)
+	}
+	return v.hasRight(m.root, ""), nil
+}
@@ -0,0 +1,119 @@
+package ompt
+
+import (
+	"bytes"
+	"fmt"
+	"math/rand"
+	"sort"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/icon-project/goloop/common/db"
+	"github.com/icon-project/goloop/common/trie"
+)
+
+func newRangeTestTrie(t *testing.T, n int, seed int64) (trie.Immutable, [][]byte) {
+	r := rand.New(rand.NewSource(seed))
+	m := NewMPTForBytes(db.NewMapDB(), nil)
+	seen := make(map[string]bool)
+	var keys [][]byte
+	for len(keys) < n {
+		k := make([]byte, 1+r.Intn(4))
+		r.Read(k)
+		if seen[string(k)] {
+			continue
+		}
+		seen[string(k)] = true
+		keys = append(keys, k)
+		_, err := m.Set(k, []byte(fmt.Sprintf("value-%x", k)))
+		assert.NoError(t, err)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		return bytes.Compare(keys[i], keys[j]) < 0
+	})
+	s := m.GetSnapshot()
+	assert.NoError(t, s.Flush())
+	return NewMPTForBytes(s.Database(), s.Hash()), keys
+}
+
+func TestRangeProof_Chunks(t *testing.T) {
+	tr, all := newRangeTestTrie(t, 500, 1)
+	root := tr.Hash()
+	for _, limit := range []int{1, 7, 64, 1000} {
+		var start []byte
+		var collected [][]byte
+		for {
+			keys, values, proof, err := GetRangeProof(tr, start, limit)
+			assert.NoError(t, err)
+			more, err := VerifyRangeProof(root, start, keys, values, proof)
+			assert.NoError(t, err)
+			collected = append(collected, keys...)
+			if !more {
+				break
+			}
+			assert.Len(t, keys, limit)
+			last := keys[len(keys)-1]
+			start = append(append([]byte{}, last...), 0)
+		}
+		assert.Equal(t, all, collected, "limit=%d", limit)
+	}
+}
+
+func TestRangeProof_Absence(t *testing.T) {
+	tr, all := newRangeTestTrie(t, 100, 2)
+	root := tr.Hash()
+
+	// no keys after the last key
+	start := append(append([]byte{}, all[len(all)-1]...), 0)
+	keys, values, proof, err := GetRangeProof(tr, start, 10)
+	assert.NoError(t, err)
+	assert.Empty(t, keys)
+	more, err := VerifyRangeProof(root, start, keys, values, proof)
+	assert.NoError(t, err)
+	assert.False(t, more)
+
+	// missing entry in the middle
+	keys, values, proof, err = GetRangeProof(tr, nil, 10)
+	assert.NoError(t, err)
+	_, err = VerifyRangeProof(root, nil,
+		append(append([][]byte{}, keys[:4]...), keys[5:]...),
+		append(append([][]byte{}, values[:4]...), values[5:]...),
+		proof)
+	assert.Error(t, err)
+
+	// hiding entries at the end with claiming no more entries
+	_, err = VerifyRangeProof(root, start, nil, nil, nil)
+	assert.Error(t, err)
+	keys, values, proof, err = GetRangeProof(tr, all[len(all)-3], 10)
+	assert.NoError(t, err)
+	assert.Len(t, keys, 3)
+	_, err = VerifyRangeProof(root, all[len(all)-3], nil, nil, proof)
+	assert.Error(t, err)
+
+	// modified value
+	keys, values, proof, err = GetRangeProof(tr, all[10], 5)
+	assert.NoError(t, err)
+	values[2] = []byte("invalid")
+	_, err = VerifyRangeProof(root, all[10], keys, values, proof)
+	assert.Error(t, err)
+
+	// missing proof
+	keys, values, proof, err = GetRangeProof(tr, all[10], 5)
+	assert.NoError(t, err)
+	_, err = VerifyRangeProof(root, all[10], keys, values, proof[1:])
+	assert.Error(t, err)
+}
+
+func TestRangeProof_Empty(t *testing.T) {
+	tr := NewMPTForBytes(db.NewMapDB(), nil)
+	keys, values, proof, err := GetRangeProof(tr, nil, 10)
+	assert.NoError(t, err)
+	assert.Empty(t, keys)
+	more, err := VerifyRangeProof(tr.Hash(), nil, keys, values, proof)
+	assert.NoError(t, err)
+	assert.False(t, more)
+
+	_, _, _, err = GetRangeProof(tr, nil, 0)
+	assert.Error(t, err)
+}
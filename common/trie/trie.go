@@ -2,6 +2,7 @@ package trie
 
 import (
 	"reflect"
+	"strconv"
 
 	"github.com/icon-project/goloop/common/db"
 	"github.com/icon-project/goloop/common/merkle"
@@ -36,6 +37,14 @@ type (
 		Get() (value []byte, key []byte, err error)
 	}
 
+	// DiffIterator iterates changed keys between two trees in key order.
+	// Old is nil for added keys, and new is nil for removed keys.
+	DiffIterator interface {
+		Next() error
+		Has() bool
+		Get() (op DiffOp, key []byte, old, new []byte, err error)
+	}
+
 	Mutable interface {
 		Get(k []byte) ([]byte, error)
 		Set(k, v []byte) ([]byte, error)
@@ -61,6 +70,12 @@ type (
 		Get() (Object, []byte, error)
 	}
 
+	DiffIteratorForObject interface {
+		Next() error
+		Has() bool
+		Get() (op DiffOp, key []byte, old, new Object, err error)
+	}
+
 	ImmutableForObject interface {
 		Empty() bool
 		Get(k []byte) (Object, error)
@@ -97,3 +112,24 @@ type (
 		NewMutableForObject(h []byte, t reflect.Type) MutableForObject
 	}
 )
+
+type DiffOp int
+
+const (
+	DiffAdded DiffOp = iota
+	DiffRemoved
+	DiffModified
+)
+
+func (op DiffOp) String() string {
+	switch op {
+	case DiffAdded:
+		return "added"
+	case DiffRemoved:
+		return "removed"
+	case DiffModified:
+		return "modified"
+	default:
+		return strconv.Itoa(int(op))
+	}
+}
@@ -40,3 +40,23 @@ func SetCacheOfMutable(mutable trie.Mutable, cache *cache.NodeCache) {
 func SetCacheOfMutableForObject(mutable trie.MutableForObject, cache *cache.NodeCache) {
 	ompt.SetCacheOfMutableForObject(mutable, cache)
 }
+
+func NewDiffIterator(from, to trie.Immutable) (trie.DiffIterator, error) {
+	return ompt.NewDiffIterator(from, to)
+}
+
+func NewDiffIteratorForObject(from, to trie.ImmutableForObject) (trie.DiffIteratorForObject, error) {
+	return ompt.NewDiffIteratorForObject(from, to)
+}
+
+func GetRangeProof(immutable trie.Immutable, start []byte, limit int) (keys, values, proof [][]byte, err error) {
+	return ompt.GetRangeProof(immutable, start, limit)
+}
+
+func GetRangeProofForObject(immutable trie.ImmutableForObject, start []byte, limit int) (keys, values, proof [][]byte, err error) {
+	return ompt.GetRangeProofForObject(immutable, start, limit)
+}
+
+func VerifyRangeProof(root []byte, start []byte, keys, values, proof [][]byte) (bool, error) {
+	return ompt.VerifyRangeProof(root, start, keys, values, proof)
+}
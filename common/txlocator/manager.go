@@ -113,6 +113,7 @@ type txListCache struct {
 
 type manager struct {
 	lock     sync.Mutex
+	dbase    db.Database
 	lbk      db.Bucket
 	log      log.Logger
 
@@ -276,18 +277,19 @@ func (m *manager) addListAndClearOldInLock(list *txList) {
 	c.lastP = &list.next
 }
 
+// flushList writes locators of the list in a batch, so locators of
+// a transaction list are written all or nothing.
 func (m *manager) flushList(l *txList) error {
+	batch := db.NewBatch(m.dbase)
 	for ptr := l.head ; ptr != nil ; ptr = ptr.next {
 		bs := codec.BC.MustMarshalToBytes(module.TransactionLocator{
 			BlockHeight:      ptr.list.height,
 			IndexInGroup:     ptr.offset,
 			TransactionGroup: ptr.list.group,
 		})
-		if err := m.lbk.Set([]byte(ptr.id), bs); err != nil {
-			return err
-		}
+		batch.Set(db.TransactionLocatorByHash, []byte(ptr.id), bs)
 	}
-	return nil
+	return batch.Write()
 }
 
 func (m *manager) pushFlushJobInLock(l *txList) *locatorFlushJob {
@@ -374,6 +376,7 @@ func NewManager(dbase db.Database, logger log.Logger) (module.LocatorManager, er
 		return nil, err
 	}
 	mgr := &manager{
+		dbase:    dbase,
 		lbk:      lbk,
 		log:      logger,
 		locators: make(map[string]*locator),
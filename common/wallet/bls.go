@@ -0,0 +1,92 @@
+package wallet
+
+import (
+	"bytes"
+	"encoding/json"
+
+	"github.com/gofrs/uuid"
+
+	"github.com/icon-project/goloop/common"
+	"github.com/icon-project/goloop/common/crypto"
+	"github.com/icon-project/goloop/common/errors"
+	"github.com/icon-project/goloop/module"
+)
+
+type blsWallet struct {
+	skey *crypto.BLSPrivateKey
+	pkey *crypto.BLSPublicKey
+}
+
+func (w *blsWallet) Sign(data []byte) ([]byte, error) {
+	sig, err := w.skey.Sign(data)
+	if err != nil {
+		return nil, err
+	}
+	return sig.Bytes(), nil
+}
+
+func (w *blsWallet) PublicKey() []byte {
+	return w.pkey.Bytes()
+}
+
+func NewBLSFromPrivateKey(sk *crypto.BLSPrivateKey) module.BaseWallet {
+	return &blsWallet{
+		skey: sk,
+		pkey: sk.PublicKey(),
+	}
+}
+
+type BLSKeyStoreData struct {
+	PublicKey common.RawHexBytes `json:"publicKey"`
+	ID        string             `json:"id"`
+	Version   int                `json:"version"`
+	CoinType  string             `json:"coinType"`
+	Crypto    CryptoData         `json:"crypto"`
+}
+
+func EncryptBLSKeyAsKeyStore(sk *crypto.BLSPrivateKey, pw []byte) ([]byte, error) {
+	var ks BLSKeyStoreData
+
+	cd, err := encryptSecret(sk.Bytes(), pw)
+	if err != nil {
+		return nil, err
+	}
+	ks.Crypto = *cd
+	ks.Version = 3
+	ks.CoinType = coinTypeBLS
+	ks.ID = uuid.Must(uuid.NewV4()).String()
+	ks.PublicKey = sk.PublicKey().Bytes()
+	return json.Marshal(&ks)
+}
+
+func DecryptBLSKeyStore(data, pw []byte) (*crypto.BLSPrivateKey, error) {
+	var ksData BLSKeyStoreData
+	if err := json.Unmarshal(data, &ksData); err != nil {
+		return nil, err
+	}
+	if ksData.CoinType != coinTypeBLS {
+		return nil, errors.Errorf("InvalidCoinType(coin=%s)", ksData.CoinType)
+	}
+
+	secretBytes, err := decryptSecret(&ksData.Crypto, pw)
+	if err != nil {
+		return nil, err
+	}
+	sk, err := crypto.ParseBLSPrivateKey(secretBytes)
+	if err != nil {
+		return nil, err
+	}
+	if !bytes.Equal(sk.PublicKey().Bytes(), ksData.PublicKey.Bytes()) {
+		return nil, errors.Errorf("InvalidPublicKey(key=%x)",
+			ksData.PublicKey.Bytes())
+	}
+	return sk, nil
+}
+
+func NewBLSFromKeyStore(data, pw []byte) (module.BaseWallet, error) {
+	sk, err := DecryptBLSKeyStore(data, pw)
+	if err != nil {
+		return nil, err
+	}
+	return NewBLSFromPrivateKey(sk), nil
+}
@@ -0,0 +1,43 @@
+package wallet
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/icon-project/goloop/common/crypto"
+)
+
+func TestBLSKeyStore(t *testing.T) {
+	sk, pk := crypto.GenerateBLSKeyPair()
+	pw := []byte("password")
+
+	ks, err := EncryptBLSKeyAsKeyStore(sk, pw)
+	assert.NoError(t, err)
+
+	sk2, err := DecryptBLSKeyStore(ks, pw)
+	assert.NoError(t, err)
+	assert.Equal(t, sk.Bytes(), sk2.Bytes())
+
+	w, err := NewBLSFromKeyStore(ks, pw)
+	assert.NoError(t, err)
+	assert.Equal(t, pk.Bytes(), w.PublicKey())
+
+	sig, err := w.Sign([]byte("message"))
+	assert.NoError(t, err)
+	s, err := crypto.ParseBLSSignature(sig)
+	assert.NoError(t, err)
+	assert.True(t, pk.Verify([]byte("message"), s))
+
+	_, err = DecryptBLSKeyStore(ks, []byte("invalid"))
+	assert.Error(t, err)
+
+	// secp256k1 key store is not a BLS key store
+	priv, _ := crypto.GenerateKeyPair()
+	ks2, err := EncryptKeyAsKeyStore(priv, pw)
+	assert.NoError(t, err)
+	_, err = DecryptBLSKeyStore(ks2, pw)
+	assert.Error(t, err)
+	_, err = DecryptKeyStore(ks, pw)
+	assert.Error(t, err)
+}
@@ -0,0 +1,127 @@
+package wallet
+
+import (
+	"math/big"
+	"sync"
+
+	"github.com/decred/dcrd/dcrec/secp256k1/v4"
+
+	"github.com/icon-project/goloop/common"
+	"github.com/icon-project/goloop/common/crypto"
+	"github.com/icon-project/goloop/common/errors"
+	"github.com/icon-project/goloop/module"
+)
+
+// PKCS11Config is the configuration of the secp256k1 key in a PKCS#11
+// token (e.g. HSM). The key is selected by Label or ID (hex), and the token
+// is selected by Token (label) or the first slot with a token.
+type PKCS11Config struct {
+	Library string
+	Token   string
+	Label   string
+	ID      string
+	PIN     string
+}
+
+// ecdsaSigner signs the hash and returns the signature in R|S format.
+type ecdsaSigner interface {
+	SignHash(hash []byte) ([]byte, error)
+	Close() error
+}
+
+type pkcs11Wallet struct {
+	lock   sync.Mutex
+	signer ecdsaSigner
+	pkey   *crypto.PublicKey
+}
+
+func (w *pkcs11Wallet) Address() module.Address {
+	return common.NewAccountAddressFromPublicKey(w.pkey)
+}
+
+func (w *pkcs11Wallet) Sign(data []byte) ([]byte, error) {
+	if len(data) != crypto.HashLen {
+		return nil, errors.IllegalArgumentError.Errorf(
+			"InvalidHashLength(len=%d)", len(data))
+	}
+	w.lock.Lock()
+	rs, err := w.signer.SignHash(data)
+	w.lock.Unlock()
+	if err != nil {
+		return nil, err
+	}
+	return recoverableSignature(rs, data, w.pkey)
+}
+
+func (w *pkcs11Wallet) PublicKey() []byte {
+	return w.pkey.SerializeCompressed()
+}
+
+func (w *pkcs11Wallet) Close() error {
+	w.lock.Lock()
+	defer w.lock.Unlock()
+	return w.signer.Close()
+}
+
+// publicKeyFromECPoint parses CKA_EC_POINT. Tokens return the point as
+// the DER encoded OCTET STRING or the raw point.
+func publicKeyFromECPoint(point []byte) (*crypto.PublicKey, error) {
+	if len(point) > 2 && point[0] == 0x04 && int(point[1]) == len(point)-2 {
+		if pk, err := crypto.ParsePublicKey(point[2:]); err == nil {
+			return pk, nil
+		}
+	}
+	return crypto.ParsePublicKey(point)
+}
+
+// recoverableSignature returns the signature in R|S|V format with low S,
+// so it can be used for recovery of the public key.
+func recoverableSignature(rs []byte, hash []byte, pk *crypto.PublicKey) ([]byte, error) {
+	if len(rs) != crypto.SignatureLenRaw {
+		return nil, errors.InvalidStateError.Errorf(
+			"InvalidSignatureLength(len=%d)", len(rs))
+	}
+	n := secp256k1.S256().N
+	s := new(big.Int).SetBytes(rs[32:])
+	if s.Cmp(new(big.Int).Rsh(n, 1)) > 0 {
+		s.Sub(n, s)
+	}
+	sig := make([]byte, crypto.SignatureLenRawWithV)
+	copy(sig, rs[:32])
+	s.FillBytes(sig[32:64])
+	for v := byte(0); v < 2; v++ {
+		sig[64] = v
+		parsed, err := crypto.ParseSignature(sig)
+		if err != nil {
+			return nil, err
+		}
+		if rpk, err := parsed.RecoverPublicKey(hash); err == nil && rpk.Equal(pk) {
+			return sig, nil
+		}
+	}
+	return nil, errors.InvalidStateError.New("SignatureNotForPublicKey")
+}
+
+// OpenPKCS11 opens the wallet with the key in a PKCS#11 token. It's
+// available only for the binary built with pkcs11 tag.
+func OpenPKCS11(cfg *PKCS11Config) (module.Wallet, error) {
+	if cfg.Library == "" {
+		return nil, errors.IllegalArgumentError.New("NoPKCS11Library")
+	}
+	if cfg.Label == "" && cfg.ID == "" {
+		return nil, errors.IllegalArgumentError.New("NoPKCS11KeyLabelOrID")
+	}
+	signer, point, err := openPKCS11Signer(cfg)
+	if err != nil {
+		return nil, err
+	}
+	pk, err := publicKeyFromECPoint(point)
+	if err != nil {
+		signer.Close()
+		return nil, errors.Wrap(err, "InvalidPKCS11PublicKey")
+	}
+	return &pkcs11Wallet{
+		signer: signer,
+		pkey:   pk,
+	}, nil
+}
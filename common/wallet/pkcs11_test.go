@@ -0,0 +1,92 @@
+package wallet
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/decred/dcrd/dcrec/secp256k1/v4"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/icon-project/goloop/common/crypto"
+)
+
+// softSigner signs like a token. It returns high S signatures if highS is
+// set.
+type softSigner struct {
+	key    *crypto.PrivateKey
+	highS  bool
+	closed bool
+}
+
+func (s *softSigner) SignHash(hash []byte) ([]byte, error) {
+	sig, err := crypto.NewSignature(hash, s.key)
+	if err != nil {
+		return nil, err
+	}
+	rsv, err := sig.SerializeRSV()
+	if err != nil {
+		return nil, err
+	}
+	rs := rsv[:crypto.SignatureLenRaw]
+	if s.highS {
+		n := secp256k1.S256().N
+		sv := new(big.Int).SetBytes(rs[32:])
+		sv.Sub(n, sv).FillBytes(rs[32:])
+	}
+	return rs, nil
+}
+
+func (s *softSigner) Close() error {
+	s.closed = true
+	return nil
+}
+
+func TestPKCS11Wallet_Sign(t *testing.T) {
+	for _, highS := range []bool{false, true} {
+		sk, pk := crypto.GenerateKeyPair()
+		signer := &softSigner{key: sk, highS: highS}
+		w := &pkcs11Wallet{signer: signer, pkey: pk}
+		assert.Equal(t, pk.SerializeCompressed(), w.PublicKey())
+
+		hash := crypto.SHA3Sum256([]byte("test"))
+		sigBytes, err := w.Sign(hash)
+		assert.NoError(t, err)
+		sig, err := crypto.ParseSignature(sigBytes)
+		assert.NoError(t, err)
+		rpk, err := sig.RecoverPublicKey(hash)
+		assert.NoError(t, err)
+		assert.True(t, rpk.Equal(pk))
+		sw, err := NewFromPrivateKey(sk)
+		assert.NoError(t, err)
+		assert.True(t, w.Address().Equal(sw.Address()))
+
+		_, err = w.Sign([]byte("short"))
+		assert.Error(t, err)
+
+		// signature by other key
+		sk2, _ := crypto.GenerateKeyPair()
+		signer.key = sk2
+		_, err = w.Sign(hash)
+		assert.Error(t, err)
+
+		assert.NoError(t, w.Close())
+		assert.True(t, signer.closed)
+	}
+}
+
+func TestPublicKeyFromECPoint(t *testing.T) {
+	_, pk := crypto.GenerateKeyPair()
+	raw := pk.SerializeUncompressed()
+
+	rpk, err := publicKeyFromECPoint(raw)
+	assert.NoError(t, err)
+	assert.True(t, rpk.Equal(pk))
+
+	der := append([]byte{0x04, byte(len(raw))}, raw...)
+	rpk, err = publicKeyFromECPoint(der)
+	assert.NoError(t, err)
+	assert.True(t, rpk.Equal(pk))
+
+	_, err = publicKeyFromECPoint([]byte{0x04, 0x01, 0x02})
+	assert.Error(t, err)
+}
@@ -0,0 +1,160 @@
+//go:build pkcs11
+// +build pkcs11
+
+package wallet
+
+import (
+	"encoding/hex"
+
+	"github.com/miekg/pkcs11"
+
+	"github.com/icon-project/goloop/common/errors"
+)
+
+type pkcs11Signer struct {
+	ctx     *pkcs11.Ctx
+	session pkcs11.SessionHandle
+	key     pkcs11.ObjectHandle
+}
+
+func (s *pkcs11Signer) SignHash(hash []byte) ([]byte, error) {
+	mech := []*pkcs11.Mechanism{pkcs11.NewMechanism(pkcs11.CKM_ECDSA, nil)}
+	if err := s.ctx.SignInit(s.session, mech, s.key); err != nil {
+		return nil, errors.Wrap(err, "PKCS11SignInitFailure")
+	}
+	sig, err := s.ctx.Sign(s.session, hash)
+	if err != nil {
+		return nil, errors.Wrap(err, "PKCS11SignFailure")
+	}
+	return sig, nil
+}
+
+func (s *pkcs11Signer) Close() error {
+	s.ctx.Logout(s.session)
+	err := s.ctx.CloseSession(s.session)
+	s.ctx.Finalize()
+	s.ctx.Destroy()
+	return err
+}
+
+func findPKCS11Slot(ctx *pkcs11.Ctx, token string) (uint, error) {
+	slots, err := ctx.GetSlotList(true)
+	if err != nil {
+		return 0, errors.Wrap(err, "PKCS11GetSlotListFailure")
+	}
+	for _, slot := range slots {
+		if token == "" {
+			return slot, nil
+		}
+		info, err := ctx.GetTokenInfo(slot)
+		if err != nil {
+			return 0, errors.Wrap(err, "PKCS11GetTokenInfoFailure")
+		}
+		if info.Label == token {
+			return slot, nil
+		}
+	}
+	return 0, errors.NotFoundError.Errorf("PKCS11TokenNotFound(token=%q)", token)
+}
+
+func findPKCS11Object(ctx *pkcs11.Ctx, session pkcs11.SessionHandle, class uint, cfg *PKCS11Config) (pkcs11.ObjectHandle, error) {
+	tmpl := []*pkcs11.Attribute{
+		pkcs11.NewAttribute(pkcs11.CKA_CLASS, class),
+		pkcs11.NewAttribute(pkcs11.CKA_KEY_TYPE, pkcs11.CKK_EC),
+	}
+	if cfg.Label != "" {
+		tmpl = append(tmpl, pkcs11.NewAttribute(pkcs11.CKA_LABEL, cfg.Label))
+	}
+	if cfg.ID != "" {
+		id, err := hex.DecodeString(cfg.ID)
+		if err != nil {
+			return 0, errors.IllegalArgumentError.Wrapf(err, "InvalidPKCS11KeyID(id=%s)", cfg.ID)
+		}
+		tmpl = append(tmpl, pkcs11.NewAttribute(pkcs11.CKA_ID, id))
+	}
+	if err := ctx.FindObjectsInit(session, tmpl); err != nil {
+		return 0, errors.Wrap(err, "PKCS11FindObjectsFailure")
+	}
+	objs, _, err := ctx.FindObjects(session, 2)
+	ctx.FindObjectsFinal(session)
+	if err != nil {
+		return 0, errors.Wrap(err, "PKCS11FindObjectsFailure")
+	}
+	switch len(objs) {
+	case 0:
+		return 0, errors.NotFoundError.Errorf(
+			"PKCS11KeyNotFound(class=%d,label=%q,id=%s)", class, cfg.Label, cfg.ID)
+	case 1:
+		return objs[0], nil
+	default:
+		return 0, errors.IllegalArgumentError.Errorf(
+			"PKCS11KeyNotUnique(class=%d,label=%q,id=%s)", class, cfg.Label, cfg.ID)
+	}
+}
+
+func openPKCS11Signer(cfg *PKCS11Config) (ecdsaSigner, []byte, error) {
+	ctx := pkcs11.New(cfg.Library)
+	if ctx == nil {
+		return nil, nil, errors.IllegalArgumentError.Errorf(
+			"FailToLoadPKCS11Library(lib=%s)", cfg.Library)
+	}
+	if err := ctx.Initialize(); err != nil {
+		if e, ok := err.(pkcs11.Error); !ok || e != pkcs11.CKR_CRYPTOKI_ALREADY_INITIALIZED {
+			ctx.Destroy()
+			return nil, nil, errors.Wrap(err, "PKCS11InitializeFailure")
+		}
+	}
+	s := &pkcs11Signer{ctx: ctx}
+	point, err := s.open(cfg)
+	if err != nil {
+		ctx.Finalize()
+		ctx.Destroy()
+		return nil, nil, err
+	}
+	return s, point, nil
+}
+
+// open opens the session and finds the key. It returns CKA_EC_POINT of
+// the public key.
+func (s *pkcs11Signer) open(cfg *PKCS11Config) ([]byte, error) {
+	slot, err := findPKCS11Slot(s.ctx, cfg.Token)
+	if err != nil {
+		return nil, err
+	}
+	s.session, err = s.ctx.OpenSession(slot, pkcs11.CKF_SERIAL_SESSION)
+	if err != nil {
+		return nil, errors.Wrap(err, "PKCS11OpenSessionFailure")
+	}
+	if err := s.ctx.Login(s.session, pkcs11.CKU_USER, cfg.PIN); err != nil {
+		if e, ok := err.(pkcs11.Error); !ok || e != pkcs11.CKR_USER_ALREADY_LOGGED_IN {
+			s.ctx.CloseSession(s.session)
+			return nil, errors.Wrap(err, "PKCS11LoginFailure")
+		}
+	}
+	point, err := s.find(cfg)
+	if err != nil {
+		s.ctx.Logout(s.session)
+		s.ctx.CloseSession(s.session)
+		return nil, err
+	}
+	return point, nil
+}
+
+func (s *pkcs11Signer) find(cfg *PKCS11Config) ([]byte, error) {
+	var err error
+	s.key, err = findPKCS11Object(s.ctx, s.session, pkcs11.CKO_PRIVATE_KEY, cfg)
+	if err != nil {
+		return nil, err
+	}
+	pub, err := findPKCS11Object(s.ctx, s.session, pkcs11.CKO_PUBLIC_KEY, cfg)
+	if err != nil {
+		return nil, err
+	}
+	attrs, err := s.ctx.GetAttributeValue(s.session, pub, []*pkcs11.Attribute{
+		pkcs11.NewAttribute(pkcs11.CKA_EC_POINT, nil),
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, "PKCS11GetAttributeFailure")
+	}
+	return attrs[0].Value, nil
+}
@@ -0,0 +1,105 @@
+//go:build pkcs11
+// +build pkcs11
+
+package wallet
+
+import (
+	"os"
+	"testing"
+
+	"github.com/miekg/pkcs11"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/icon-project/goloop/common/crypto"
+)
+
+// secp256k1OID is the DER encoded OID of secp256k1 (1.3.132.0.10).
+var secp256k1OID = []byte{0x06, 0x05, 0x2b, 0x81, 0x04, 0x00, 0x0a}
+
+// TestOpenPKCS11 runs against the initialized token like SoftHSM.
+//
+//	softhsm2-util --init-token --free --label test --pin 1234 --so-pin 1234
+//	PKCS11_TEST_LIB=/usr/lib/softhsm/libsofthsm2.so \
+//	PKCS11_TEST_TOKEN=test PKCS11_TEST_PIN=1234 \
+//	go test -tags pkcs11 -run TestOpenPKCS11 ./common/wallet/
+func TestOpenPKCS11(t *testing.T) {
+	lib := os.Getenv("PKCS11_TEST_LIB")
+	if lib == "" {
+		t.Skip("PKCS11_TEST_LIB is not set")
+	}
+	cfg := &PKCS11Config{
+		Library: lib,
+		Token:   os.Getenv("PKCS11_TEST_TOKEN"),
+		Label:   "goloop-test",
+		PIN:     os.Getenv("PKCS11_TEST_PIN"),
+	}
+	generatePKCS11TestKey(t, cfg)
+
+	w, err := OpenPKCS11(cfg)
+	if !assert.NoError(t, err) {
+		return
+	}
+	defer w.(*pkcs11Wallet).Close()
+
+	for i := 0; i < 10; i++ {
+		hash := crypto.SHA3Sum256([]byte{byte(i)})
+		sigBytes, err := w.Sign(hash)
+		assert.NoError(t, err)
+		sig, err := crypto.ParseSignature(sigBytes)
+		assert.NoError(t, err)
+		pk, err := sig.RecoverPublicKey(hash)
+		assert.NoError(t, err)
+		assert.Equal(t, w.PublicKey(), pk.SerializeCompressed())
+	}
+
+	cfg.Label = "goloop-unknown"
+	_, err = OpenPKCS11(cfg)
+	assert.Error(t, err)
+}
+
+func generatePKCS11TestKey(t *testing.T, cfg *PKCS11Config) {
+	ctx := pkcs11.New(cfg.Library)
+	if !assert.NotNil(t, ctx) {
+		t.FailNow()
+	}
+	defer ctx.Destroy()
+	assert.NoError(t, ctx.Initialize())
+	defer ctx.Finalize()
+
+	slot, err := findPKCS11Slot(ctx, cfg.Token)
+	assert.NoError(t, err)
+	session, err := ctx.OpenSession(slot, pkcs11.CKF_SERIAL_SESSION|pkcs11.CKF_RW_SESSION)
+	assert.NoError(t, err)
+	defer ctx.CloseSession(session)
+	assert.NoError(t, ctx.Login(session, pkcs11.CKU_USER, cfg.PIN))
+	defer ctx.Logout(session)
+
+	// remove keys of previous runs
+	for _, class := range []uint{pkcs11.CKO_PRIVATE_KEY, pkcs11.CKO_PUBLIC_KEY} {
+		for {
+			obj, err := findPKCS11Object(ctx, session, class, cfg)
+			if err != nil {
+				break
+			}
+			assert.NoError(t, ctx.DestroyObject(session, obj))
+		}
+	}
+
+	_, _, err = ctx.GenerateKeyPair(session,
+		[]*pkcs11.Mechanism{pkcs11.NewMechanism(pkcs11.CKM_EC_KEY_PAIR_GEN, nil)},
+		[]*pkcs11.Attribute{
+			pkcs11.NewAttribute(pkcs11.CKA_TOKEN, true),
+			pkcs11.NewAttribute(pkcs11.CKA_LABEL, cfg.Label),
+			pkcs11.NewAttribute(pkcs11.CKA_EC_PARAMS, secp256k1OID),
+			pkcs11.NewAttribute(pkcs11.CKA_VERIFY, true),
+		},
+		[]*pkcs11.Attribute{
+			pkcs11.NewAttribute(pkcs11.CKA_TOKEN, true),
+			pkcs11.NewAttribute(pkcs11.CKA_LABEL, cfg.Label),
+			pkcs11.NewAttribute(pkcs11.CKA_PRIVATE, true),
+			pkcs11.NewAttribute(pkcs11.CKA_SENSITIVE, true),
+			pkcs11.NewAttribute(pkcs11.CKA_SIGN, true),
+		},
+	)
+	assert.NoError(t, err)
+}
@@ -0,0 +1,13 @@
+//go:build !pkcs11
+// +build !pkcs11
+
+package wallet
+
+import (
+	"github.com/icon-project/goloop/common/errors"
+)
+
+func openPKCS11Signer(cfg *PKCS11Config) (ecdsaSigner, []byte, error) {
+	return nil, nil, errors.UnsupportedError.New(
+		"PKCS11NotSupported(build with pkcs11 tag)")
+}
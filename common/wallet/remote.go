@@ -0,0 +1,354 @@
+package wallet
+
+import (
+	"bytes"
+	"crypto/rand"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/icon-project/goloop/btp/ntm"
+	"github.com/icon-project/goloop/common"
+	"github.com/icon-project/goloop/common/codec"
+	"github.com/icon-project/goloop/common/crypto"
+	"github.com/icon-project/goloop/common/errors"
+	"github.com/icon-project/goloop/common/ipc"
+	"github.com/icon-project/goloop/module"
+)
+
+// Remote signer protocol
+//
+// The node connects to the signer, and they exchange random nonces with
+// remoteMsgHello. The signer proves that it has the key by signing the nonces.
+// Then the node sends remoteMsgSign requests signed by its own key with the
+// nonces and the sequence number, so the signer accepts requests only from
+// the allowed nodes.
+
+const (
+	remoteVersion    = 2
+	remoteNonceLen   = 32
+	remoteRoleSigner = "signer"
+	remoteRoleClient = "client"
+)
+
+const (
+	remoteMsgHello uint = iota
+	remoteMsgSign
+)
+
+type remoteHello struct {
+	Version int
+	Nonce   []byte
+}
+
+type remoteHelloResponse struct {
+	Code      errors.Code
+	Message   string
+	Nonce     []byte
+	PublicKey []byte
+	Signature []byte
+}
+
+type remoteSign struct {
+	Kind      module.SignKind
+	Data      []byte
+	PublicKey []byte
+	Signature []byte
+}
+
+type remoteSignResponse struct {
+	Code      errors.Code
+	Message   string
+	Signature []byte
+}
+
+type remoteAuthData struct {
+	Role        string
+	ClientNonce []byte
+	SignerNonce []byte
+	Sequence    int64
+	Kind        module.SignKind
+	Data        []byte
+}
+
+func (d *remoteAuthData) Hash() []byte {
+	return crypto.SHA3Sum256(codec.BC.MustMarshalToBytes(d))
+}
+
+// hashOfMessage returns the hash to be signed for the message.
+func hashOfMessage(kind module.SignKind, data []byte) ([]byte, error) {
+	switch kind {
+	case module.SignHash:
+		return data, nil
+	case module.SignNTSDecision:
+		_, _, _, _, hash, err := ntm.ParseDecisionMessage(data)
+		return hash, err
+	default:
+		return crypto.SHA3Sum256(data), nil
+	}
+}
+
+func remoteError(code errors.Code, msg string) error {
+	if code == errors.Success {
+		return nil
+	}
+	return errors.NewBase(code, msg)
+}
+
+func remoteErrorOf(err error) (errors.Code, string) {
+	if err == nil {
+		return errors.Success, ""
+	}
+	code := errors.CodeOf(err)
+	if code == errors.Success {
+		code = errors.UnknownError
+	}
+	return code, err.Error()
+}
+
+func recoverPublicKey(hash, sig []byte) (*crypto.PublicKey, error) {
+	s, err := crypto.ParseSignature(sig)
+	if err != nil {
+		return nil, err
+	}
+	return s.RecoverPublicKey(hash)
+}
+
+func newRemoteNonce() []byte {
+	nonce := make([]byte, remoteNonceLen)
+	if _, err := rand.Read(nonce); err != nil {
+		panic(err)
+	}
+	return nonce
+}
+
+// ParseRemoteAddress parses the address of the signer in unix://<path> or
+// tcp://<host>:<port> format, and returns network and address for it.
+func ParseRemoteAddress(s string) (string, string, error) {
+	for _, network := range []string{"unix", "tcp"} {
+		prefix := network + "://"
+		if strings.HasPrefix(s, prefix) && len(s) > len(prefix) {
+			return network, s[len(prefix):], nil
+		}
+	}
+	return "", "", errors.IllegalArgumentError.Errorf(
+		"InvalidRemoteSignerAddress(addr=%s)", s)
+}
+
+// RemoteConfig is the configuration for the wallet with the key in the
+// remote signer.
+type RemoteConfig struct {
+	// Address of the signer in unix://<path> or tcp://<host>:<port> format.
+	Address string
+
+	// Signer is the address of the key in the signer.
+	Signer module.Address
+
+	// Client is used to authenticate requests to the signer.
+	Client module.Wallet
+
+	// Timeout for each request. DefaultRemoteTimeout is used if it's zero.
+	Timeout time.Duration
+}
+
+const DefaultRemoteTimeout = 5 * time.Second
+
+type remoteWallet struct {
+	lock    sync.Mutex
+	network string
+	address string
+	signer  module.Address
+	client  module.Wallet
+	timeout time.Duration
+
+	pkey *crypto.PublicKey
+
+	conn        ipc.Connection
+	clientNonce []byte
+	signerNonce []byte
+	sequence    int64
+}
+
+func (w *remoteWallet) Address() module.Address {
+	return w.signer
+}
+
+func (w *remoteWallet) PublicKey() []byte {
+	return w.pkey.SerializeCompressed()
+}
+
+func (w *remoteWallet) Sign(data []byte) ([]byte, error) {
+	if len(data) != crypto.HashLen {
+		return nil, errors.IllegalArgumentError.Errorf(
+			"InvalidHashLength(len=%d)", len(data))
+	}
+	return w.SignMessage(module.SignHash, data)
+}
+
+func (w *remoteWallet) SignMessage(kind module.SignKind, data []byte) ([]byte, error) {
+	hash, err := hashOfMessage(kind, data)
+	if err != nil {
+		return nil, err
+	}
+
+	w.lock.Lock()
+	defer w.lock.Unlock()
+
+	if err := w.connect(); err != nil {
+		return nil, err
+	}
+	w.sequence += 1
+	auth := &remoteAuthData{
+		Role:        remoteRoleClient,
+		ClientNonce: w.clientNonce,
+		SignerNonce: w.signerNonce,
+		Sequence:    w.sequence,
+		Kind:        kind,
+		Data:        data,
+	}
+	sig, err := w.client.Sign(auth.Hash())
+	if err != nil {
+		return nil, err
+	}
+	resp := new(remoteSignResponse)
+	if err := w.call(remoteMsgSign, &remoteSign{
+		Kind:      kind,
+		Data:      data,
+		PublicKey: w.client.PublicKey(),
+		Signature: sig,
+	}, resp); err != nil {
+		return nil, err
+	}
+	if err := remoteError(resp.Code, resp.Message); err != nil {
+		return nil, err
+	}
+	if pk, err := recoverPublicKey(hash, resp.Signature); err != nil || !pk.Equal(w.pkey) {
+		return nil, errors.InvalidStateError.New("InvalidSignatureFromSigner")
+	}
+	return resp.Signature, nil
+}
+
+// call sends the request and receives the response. It closes the
+// connection on failure, so it would be connected again on the next call.
+func (w *remoteWallet) call(msg uint, req, resp interface{}) error {
+	conn := w.conn
+	done := make(chan error, 1)
+	go func() {
+		done <- conn.SendAndReceive(msg, req, resp)
+	}()
+	var err error
+	select {
+	case err = <-done:
+	case <-time.After(w.timeout):
+		err = errors.TimeoutError.New("RemoteSignerTimeout")
+	}
+	if err != nil {
+		conn.Close()
+		w.conn = nil
+	}
+	return err
+}
+
+func (w *remoteWallet) connect() error {
+	if w.conn != nil {
+		return nil
+	}
+	conn, err := ipc.Dial(w.network, w.address)
+	if err != nil {
+		return errors.InvalidNetworkError.Wrapf(err,
+			"FailToConnectSigner(addr=%s)", w.address)
+	}
+	w.conn = conn
+	nonce := newRemoteNonce()
+	resp := new(remoteHelloResponse)
+	if err := w.call(remoteMsgHello, &remoteHello{
+		Version: remoteVersion,
+		Nonce:   nonce,
+	}, resp); err != nil {
+		return err
+	}
+	if err := w.verifyHello(nonce, resp); err != nil {
+		w.conn.Close()
+		w.conn = nil
+		return err
+	}
+	w.clientNonce = nonce
+	w.signerNonce = resp.Nonce
+	w.sequence = 0
+	return nil
+}
+
+func (w *remoteWallet) verifyHello(nonce []byte, resp *remoteHelloResponse) error {
+	if err := remoteError(resp.Code, resp.Message); err != nil {
+		return err
+	}
+	if len(resp.Nonce) != remoteNonceLen {
+		return errors.InvalidStateError.Errorf(
+			"InvalidSignerNonce(len=%d)", len(resp.Nonce))
+	}
+	auth := &remoteAuthData{
+		Role:        remoteRoleSigner,
+		ClientNonce: nonce,
+		SignerNonce: resp.Nonce,
+	}
+	pk, err := recoverPublicKey(auth.Hash(), resp.Signature)
+	if err != nil {
+		return errors.InvalidStateError.Wrap(err, "InvalidSignerSignature")
+	}
+	if !bytes.Equal(pk.SerializeCompressed(), resp.PublicKey) {
+		return errors.InvalidStateError.New("SignerPublicKeyMismatch")
+	}
+	if addr := common.NewAccountAddressFromPublicKey(pk); !addr.Equal(w.signer) {
+		return errors.InvalidStateError.Errorf(
+			"UnexpectedSigner(exp=%s,real=%s)", w.signer, addr)
+	}
+	if w.pkey == nil {
+		w.pkey = pk
+	}
+	return nil
+}
+
+// Close closes the connection to the signer.
+func (w *remoteWallet) Close() error {
+	w.lock.Lock()
+	defer w.lock.Unlock()
+	if w.conn == nil {
+		return nil
+	}
+	err := w.conn.Close()
+	w.conn = nil
+	return err
+}
+
+// OpenRemote returns the wallet forwarding requests to the remote signer.
+// It connects to the signer to get the public key of the signer. Requests
+// for consensus messages are checked by the signer to prevent double
+// signing.
+func OpenRemote(cfg *RemoteConfig) (module.Wallet, error) {
+	network, address, err := ParseRemoteAddress(cfg.Address)
+	if err != nil {
+		return nil, err
+	}
+	if cfg.Signer == nil {
+		return nil, errors.IllegalArgumentError.New("NoRemoteSignerAddress")
+	}
+	if cfg.Client == nil {
+		return nil, errors.IllegalArgumentError.New("NoRemoteClientWallet")
+	}
+	w := &remoteWallet{
+		network: network,
+		address: address,
+		signer:  cfg.Signer,
+		client:  cfg.Client,
+		timeout: cfg.Timeout,
+	}
+	if w.timeout == 0 {
+		w.timeout = DefaultRemoteTimeout
+	}
+	w.lock.Lock()
+	defer w.lock.Unlock()
+	if err := w.connect(); err != nil {
+		return nil, err
+	}
+	return w, nil
+}
@@ -0,0 +1,550 @@
+package wallet
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sync"
+
+	"github.com/icon-project/goloop/btp/ntm"
+	"github.com/icon-project/goloop/common"
+	"github.com/icon-project/goloop/common/codec"
+	"github.com/icon-project/goloop/common/crypto"
+	"github.com/icon-project/goloop/common/errors"
+	"github.com/icon-project/goloop/common/ipc"
+	"github.com/icon-project/goloop/common/log"
+	"github.com/icon-project/goloop/module"
+)
+
+const (
+	stepProposal  = "proposal"
+	stepPrevote   = "prevote"
+	stepPrecommit = "precommit"
+	stepNTSPrefix = "nts"
+)
+
+const (
+	// peerAuthMaxLen is the max length of the content for the peer
+	// authentication, which is a random secret of the secure channel.
+	peerAuthMaxLen = 32
+	// txHashPrefix is the prefix of the bytes for the transaction hash.
+	txHashPrefix = "icx_sendTransaction."
+)
+
+// validatorTxTypes are the data types of the transactions that the node
+// signs with the validator key. They are sent to the system address.
+var validatorTxTypes = map[string]bool{
+	"base":  true,
+	"patch": true,
+}
+
+var systemAddress = common.MustNewAddressFromString("cx0000000000000000000000000000000000000000")
+
+type partSetIDAndAppData struct {
+	CountWord uint64
+	Hash      []byte
+}
+
+type consensusMessageHeader struct {
+	kind     module.SignKind
+	Height   int64
+	Round    int32
+	VoteType int
+	NID      uint32
+}
+
+func (h *consensusMessageHeader) RLPDecodeSelf(d codec.Decoder) error {
+	d2, err := d.DecodeList()
+	if err != nil {
+		return err
+	}
+	if h.kind == module.SignVote {
+		var blockID []byte
+		var bpsID *partSetIDAndAppData
+		if _, err = d2.DecodeMulti(&h.Height, &h.Round, &h.VoteType, &blockID, &bpsID); err != nil {
+			return err
+		}
+		// NID is in the block ID of nil votes, and in the application data
+		// of the others, which is zero before UseNIDInConsensusMessage.
+		if bpsID == nil {
+			var nid int32
+			if _, err = codec.BC.UnmarshalFromBytes(blockID, &nid); err != nil {
+				return err
+			}
+			h.NID = uint32(nid)
+		} else {
+			h.NID = uint32(bpsID.CountWord >> 32)
+		}
+		return nil
+	}
+	var bpsID *struct {
+		Count uint16
+		Hash  []byte
+	}
+	var polRound int32
+	cnt, err := d2.DecodeMulti(&h.Height, &h.Round, &bpsID, &polRound, &h.NID)
+	if cnt == 4 && err == io.EOF {
+		h.NID = 0
+		return nil
+	}
+	return err
+}
+
+// parseConsensusMessage returns step, chain, height and round of the
+// consensus message. Proposals are encoded as [Height, Round, BlockPartSetID,
+// POLRound, (NID)], and votes are encoded as [Height, Round, Type, BlockID,
+// BlockPartSetIDAndAppData, ...]. The chain is empty if the message doesn't
+// have NID.
+func parseConsensusMessage(kind module.SignKind, data []byte) (string, string, int64, int32, error) {
+	if kind != module.SignProposal && kind != module.SignVote {
+		return "", "", 0, 0, errors.IllegalArgumentError.Errorf(
+			"InvalidSignKind(kind=%d)", kind)
+	}
+	h := &consensusMessageHeader{kind: kind}
+	remain, err := codec.BC.UnmarshalFromBytes(data, h)
+	if err != nil {
+		return "", "", 0, 0, errors.IllegalArgumentError.Wrap(err, "InvalidMessage")
+	}
+	if len(remain) != 0 {
+		return "", "", 0, 0, errors.IllegalArgumentError.Errorf(
+			"InvalidMessage(remain=%d)", len(remain))
+	}
+	step := stepProposal
+	if kind == module.SignVote {
+		switch h.VoteType {
+		case 0:
+			step = stepPrevote
+		case 1:
+			step = stepPrecommit
+		default:
+			return "", "", 0, 0, errors.IllegalArgumentError.Errorf(
+				"InvalidVoteType(type=%d)", h.VoteType)
+		}
+	}
+	if h.Height <= 0 || h.Round < 0 {
+		return "", "", 0, 0, errors.IllegalArgumentError.Errorf(
+			"InvalidHeightRound(height=%d,round=%d)", h.Height, h.Round)
+	}
+	var chain string
+	if h.NID != 0 {
+		chain = string(module.SourceNetworkUID(int(h.NID)))
+	}
+	return step, chain, h.Height, h.Round, nil
+}
+
+// mayBeConsensusMessage returns whether the data can be a consensus message.
+// The hash of such data is signed only as a consensus message.
+func mayBeConsensusMessage(data []byte) bool {
+	for _, kind := range []module.SignKind{module.SignProposal, module.SignVote} {
+		if _, _, _, _, err := parseConsensusMessage(kind, data); err == nil {
+			return true
+		}
+	}
+	return false
+}
+
+// parseTransaction returns fields of the transaction in the bytes for the
+// transaction hash, which are "icx_sendTransaction" followed by names and
+// values of fields separated by dots. Dots in values are escaped or in
+// brackets.
+func parseTransaction(data []byte) (map[string]string, error) {
+	if !bytes.HasPrefix(data, []byte(txHashPrefix)) {
+		return nil, errors.IllegalArgumentError.New("InvalidTransaction")
+	}
+	var tokens []string
+	start, depth, escaped := len(txHashPrefix), 0, false
+	for i := start; i < len(data) && depth >= 0; i++ {
+		if escaped {
+			escaped = false
+			continue
+		}
+		switch data[i] {
+		case '\\':
+			escaped = true
+		case '{', '[':
+			depth += 1
+		case '}', ']':
+			depth -= 1
+		case '.':
+			if depth == 0 {
+				tokens = append(tokens, string(data[start:i]))
+				start = i + 1
+			}
+		}
+	}
+	tokens = append(tokens, string(data[start:]))
+	if escaped || depth != 0 || len(tokens)%2 != 0 {
+		return nil, errors.IllegalArgumentError.New("InvalidTransaction")
+	}
+	fields := make(map[string]string, len(tokens)/2)
+	for i := 0; i < len(tokens); i += 2 {
+		if _, ok := fields[tokens[i]]; ok {
+			return nil, errors.IllegalArgumentError.Errorf(
+				"InvalidTransaction(dup=%s)", tokens[i])
+		}
+		fields[tokens[i]] = tokens[i+1]
+	}
+	return fields, nil
+}
+
+type signedState struct {
+	Step      string          `json:"step"`
+	Chain     string          `json:"chain,omitempty"`
+	Height    int64           `json:"height"`
+	Round     int32           `json:"round"`
+	Hash      common.HexBytes `json:"hash"`
+	Signature common.HexBytes `json:"signature"`
+}
+
+func stateKeyOf(step, chain string) string {
+	if chain == "" {
+		return step
+	}
+	return step + "@" + chain
+}
+
+// RemoteSigner serves requests from the wallets opened by OpenRemote. It
+// keeps the last signed height and round for each step (proposal, prevote,
+// precommit and decision of each network type) of each chain in the state
+// file, and refuses to sign the message for the same or the previous height
+// and round unless it's the same message. The chain of the message is
+// identified by NID in it. Messages without NID are valid for any chain, so
+// they're checked against the steps of all chains. It signs only the
+// messages it can inspect, so raw hashes are refused, and the transactions
+// are limited to the ones the validator sends.
+type RemoteSigner struct {
+	lock    sync.Mutex
+	wallet  module.Wallet
+	clients map[string]bool
+	path    string
+	state   map[string]*signedState
+	server  ipc.Server
+	log     log.Logger
+}
+
+func (s *RemoteSigner) loadState() error {
+	bs, err := os.ReadFile(s.path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil
+		}
+		return errors.CriticalIOError.Wrapf(err, "FailToReadState(path=%s)", s.path)
+	}
+	if err := json.Unmarshal(bs, &s.state); err != nil {
+		return errors.CriticalFormatError.Wrapf(err, "InvalidState(path=%s)", s.path)
+	}
+	if s.state == nil {
+		s.state = make(map[string]*signedState)
+	}
+	for key, st := range s.state {
+		if st == nil || key != stateKeyOf(st.Step, st.Chain) {
+			return errors.CriticalFormatError.Errorf(
+				"InvalidState(path=%s,key=%s)", s.path, key)
+		}
+	}
+	return nil
+}
+
+func (s *RemoteSigner) saveState() error {
+	bs, err := json.Marshal(s.state)
+	if err != nil {
+		return err
+	}
+	tmp := s.path + ".tmp"
+	f, err := os.OpenFile(tmp, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0600)
+	if err != nil {
+		return errors.CriticalIOError.Wrap(err, "FailToWriteState")
+	}
+	_, err = f.Write(bs)
+	if err == nil {
+		err = f.Sync()
+	}
+	if err2 := f.Close(); err == nil {
+		err = err2
+	}
+	if err == nil {
+		err = os.Rename(tmp, s.path)
+	}
+	if err != nil {
+		return errors.CriticalIOError.Wrap(err, "FailToWriteState")
+	}
+	return nil
+}
+
+// checkTransaction returns an error if the transaction isn't the one the
+// validator sends, so the host of the node can't make the signer transfer
+// its coins.
+func (s *RemoteSigner) checkTransaction(data []byte) error {
+	fields, err := parseTransaction(data)
+	if err != nil {
+		return err
+	}
+	if fields["from"] != s.wallet.Address().String() {
+		return errors.IllegalArgumentError.Errorf(
+			"InvalidSender(from=%s)", fields["from"])
+	}
+	if _, ok := fields["value"]; ok ||
+		!validatorTxTypes[fields["dataType"]] ||
+		fields["to"] != systemAddress.String() {
+		return errors.IllegalArgumentError.Errorf(
+			"UnsupportedTransaction(dataType=%s,to=%s,value=%s)",
+			fields["dataType"], fields["to"], fields["value"])
+	}
+	return nil
+}
+
+// checkState returns the signature of the message if it's signed already.
+// It returns an error if the message conflicts with the one signed for the
+// same or the later height and round of the step for the chain.
+func (s *RemoteSigner) checkState(step, chain string, height int64, round int32, hash []byte) ([]byte, error) {
+	for _, last := range s.state {
+		if last.Step != step || (chain != "" && last.Chain != "" && last.Chain != chain) {
+			continue
+		}
+		if height < last.Height || (height == last.Height && round < last.Round) {
+			return nil, errors.InvalidStateError.Errorf(
+				"AlreadySigned(step=%s,chain=%s,height=%d,round=%d,last=%s/%d/%d)",
+				step, chain, height, round, last.Chain, last.Height, last.Round)
+		}
+		if height == last.Height && round == last.Round {
+			if bytes.Equal(hash, last.Hash) {
+				return last.Signature, nil
+			}
+			return nil, errors.InvalidStateError.Errorf(
+				"DoubleSign(step=%s,chain=%s,height=%d,round=%d)",
+				step, chain, height, round)
+		}
+	}
+	return nil, nil
+}
+
+func (s *RemoteSigner) sign(kind module.SignKind, data []byte) ([]byte, error) {
+	var step, chain string
+	var height int64
+	var round int32
+	var hash []byte
+	var err error
+	switch kind {
+	case module.SignPeerAuth:
+		// It may refuse a random content which can be decoded as a
+		// consensus message, then the peer connects again with another.
+		if len(data) == 0 || len(data) > peerAuthMaxLen || mayBeConsensusMessage(data) {
+			return nil, errors.IllegalArgumentError.Errorf(
+				"InvalidPeerAuth(len=%d)", len(data))
+		}
+		return s.wallet.Sign(crypto.SHA3Sum256(data))
+	case module.SignTransaction:
+		if err := s.checkTransaction(data); err != nil {
+			return nil, err
+		}
+		return s.wallet.Sign(crypto.SHA3Sum256(data))
+	case module.SignProposal, module.SignVote:
+		step, chain, height, round, err = parseConsensusMessage(kind, data)
+		hash = crypto.SHA3Sum256(data)
+	case module.SignNTSDecision:
+		var src []byte
+		var ntid int64
+		src, ntid, height, round, hash, err = ntm.ParseDecisionMessage(data)
+		step = fmt.Sprintf("%s%d", stepNTSPrefix, ntid)
+		chain = string(src)
+	default:
+		return nil, errors.IllegalArgumentError.Errorf(
+			"UnsupportedSignKind(kind=%d)", kind)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	if sig, err := s.checkState(step, chain, height, round, hash); sig != nil || err != nil {
+		return sig, err
+	}
+	sig, err := s.wallet.Sign(hash)
+	if err != nil {
+		return nil, err
+	}
+	key := stateKeyOf(step, chain)
+	last := s.state[key]
+	s.state[key] = &signedState{
+		Step:      step,
+		Chain:     chain,
+		Height:    height,
+		Round:     round,
+		Hash:      hash,
+		Signature: sig,
+	}
+	if err := s.saveState(); err != nil {
+		if last != nil {
+			s.state[key] = last
+		} else {
+			delete(s.state, key)
+		}
+		return nil, err
+	}
+	s.log.Infof("Signed %s chain=%s height=%d round=%d", step, chain, height, round)
+	return sig, nil
+}
+
+type remoteSession struct {
+	signer      *RemoteSigner
+	clientNonce []byte
+	signerNonce []byte
+	sequence    int64
+}
+
+func (s *remoteSession) handleHello(req *remoteHello) *remoteHelloResponse {
+	if req.Version != remoteVersion {
+		return &remoteHelloResponse{
+			Code:    errors.UnsupportedError,
+			Message: "UnsupportedVersion",
+		}
+	}
+	if len(req.Nonce) != remoteNonceLen {
+		return &remoteHelloResponse{
+			Code:    errors.IllegalArgumentError,
+			Message: "InvalidNonce",
+		}
+	}
+	nonce := newRemoteNonce()
+	auth := &remoteAuthData{
+		Role:        remoteRoleSigner,
+		ClientNonce: req.Nonce,
+		SignerNonce: nonce,
+	}
+	sig, err := s.signer.wallet.Sign(auth.Hash())
+	if err != nil {
+		code, msg := remoteErrorOf(err)
+		return &remoteHelloResponse{Code: code, Message: msg}
+	}
+	s.clientNonce = req.Nonce
+	s.signerNonce = nonce
+	s.sequence = 0
+	return &remoteHelloResponse{
+		Nonce:     nonce,
+		PublicKey: s.signer.wallet.PublicKey(),
+		Signature: sig,
+	}
+}
+
+func (s *remoteSession) authenticate(req *remoteSign) error {
+	if s.signerNonce == nil {
+		return errors.InvalidStateError.New("NoHello")
+	}
+	auth := &remoteAuthData{
+		Role:        remoteRoleClient,
+		ClientNonce: s.clientNonce,
+		SignerNonce: s.signerNonce,
+		Sequence:    s.sequence + 1,
+		Kind:        req.Kind,
+		Data:        req.Data,
+	}
+	pk, err := recoverPublicKey(auth.Hash(), req.Signature)
+	if err != nil || !bytes.Equal(pk.SerializeCompressed(), req.PublicKey) {
+		return errors.IllegalArgumentError.New("InvalidClientSignature")
+	}
+	addr := common.NewAccountAddressFromPublicKey(pk)
+	if !s.signer.clients[string(addr.Bytes())] {
+		return errors.IllegalArgumentError.Errorf("UnknownClient(addr=%s)", addr)
+	}
+	s.sequence += 1
+	return nil
+}
+
+func (s *remoteSession) handleSign(req *remoteSign) *remoteSignResponse {
+	var sig []byte
+	err := s.authenticate(req)
+	if err == nil {
+		sig, err = s.signer.sign(req.Kind, req.Data)
+	}
+	if err != nil {
+		s.signer.log.Warnf("Fail to sign kind=%d err=%v", req.Kind, err)
+		code, msg := remoteErrorOf(err)
+		return &remoteSignResponse{Code: code, Message: msg}
+	}
+	return &remoteSignResponse{Signature: sig}
+}
+
+func (s *remoteSession) HandleMessage(c ipc.Connection, msg uint, data []byte) error {
+	switch msg {
+	case remoteMsgHello:
+		var req remoteHello
+		if _, err := codec.MP.UnmarshalFromBytes(data, &req); err != nil {
+			return err
+		}
+		return c.Send(msg, s.handleHello(&req))
+	case remoteMsgSign:
+		var req remoteSign
+		if _, err := codec.MP.UnmarshalFromBytes(data, &req); err != nil {
+			return err
+		}
+		return c.Send(msg, s.handleSign(&req))
+	default:
+		return errors.UnsupportedError.Errorf("UnknownMessage(msg=%d)", msg)
+	}
+}
+
+func (s *RemoteSigner) OnConnect(c ipc.Connection) error {
+	session := &remoteSession{signer: s}
+	c.SetHandler(remoteMsgHello, session)
+	c.SetHandler(remoteMsgSign, session)
+	return nil
+}
+
+func (s *RemoteSigner) OnClose(c ipc.Connection) {
+	// do nothing
+}
+
+// Listen listens the address in unix://<path> or tcp://<host>:<port>
+// format.
+func (s *RemoteSigner) Listen(addr string) error {
+	network, address, err := ParseRemoteAddress(addr)
+	if err != nil {
+		return err
+	}
+	return s.server.Listen(network, address)
+}
+
+// Serve handles connections until it's closed.
+func (s *RemoteSigner) Serve() error {
+	return s.server.Loop()
+}
+
+func (s *RemoteSigner) Close() error {
+	return s.server.Close()
+}
+
+// NewRemoteSigner returns the signer with the wallet. It accepts requests
+// only from the clients, and it keeps the state in the file at path.
+func NewRemoteSigner(
+	w module.Wallet, clients []module.Address, path string, l log.Logger,
+) (*RemoteSigner, error) {
+	if len(clients) == 0 {
+		return nil, errors.IllegalArgumentError.New("NoClients")
+	}
+	s := &RemoteSigner{
+		wallet:  w,
+		clients: make(map[string]bool, len(clients)),
+		path:    path,
+		state:   make(map[string]*signedState),
+		server:  ipc.NewServer(),
+		log:     l,
+	}
+	for _, addr := range clients {
+		s.clients[string(addr.Bytes())] = true
+	}
+	if dir := filepath.Dir(path); dir != "" {
+		if err := os.MkdirAll(dir, 0700); err != nil {
+			return nil, errors.CriticalIOError.Wrap(err, "FailToMakeStateDir")
+		}
+	}
+	if err := s.loadState(); err != nil {
+		return nil, err
+	}
+	s.server.SetHandler(s)
+	return s, nil
+}
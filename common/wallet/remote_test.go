@@ -0,0 +1,227 @@
+package wallet
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/icon-project/goloop/btp/ntm"
+	"github.com/icon-project/goloop/common/codec"
+	"github.com/icon-project/goloop/common/crypto"
+	"github.com/icon-project/goloop/common/log"
+	"github.com/icon-project/goloop/module"
+)
+
+func startTestSigner(t *testing.T, w module.Wallet, client module.Wallet, dir string) (*RemoteSigner, string) {
+	s, err := NewRemoteSigner(w, []module.Address{client.Address()},
+		filepath.Join(dir, "state.json"), log.New())
+	assert.NoError(t, err)
+	addr := "unix://" + filepath.Join(dir, "signer.sock")
+	assert.NoError(t, s.Listen(addr))
+	go s.Serve()
+	return s, addr
+}
+
+func proposalBytes(height int64, round int32, id string, nid uint32) []byte {
+	msg := []interface{}{height, round, []interface{}{uint16(1), []byte(id)}, int32(-1)}
+	if nid != 0 {
+		msg = append(msg, nid)
+	}
+	return codec.BC.MustMarshalToBytes(msg)
+}
+
+// voteBytes returns the bytes of the vote for the block of id, or the nil
+// vote if id is empty.
+func voteBytes(height int64, round int32, voteType int, id string, nid uint32) []byte {
+	if id == "" {
+		return codec.BC.MustMarshalToBytes([]interface{}{
+			height, round, voteType, codec.BC.MustMarshalToBytes(int(nid)), nil, int64(1),
+		})
+	}
+	return codec.BC.MustMarshalToBytes([]interface{}{
+		height, round, voteType, []byte(id),
+		[]interface{}{uint64(nid)<<32 | 1, []byte(id)}, int64(1),
+	})
+}
+
+func txBytes(from module.Address, dataType, to string, value string) []byte {
+	tx := "icx_sendTransaction.data.{type.0x0.data.0x12\\.34}.dataType." + dataType +
+		".from." + from.String() + ".nid.0x1.stepLimit.0x0.timestamp.0x1.to." + to
+	if value != "" {
+		tx += ".value." + value
+	}
+	return []byte(tx + ".version.0x3")
+}
+
+func TestRemoteWallet_Sign(t *testing.T) {
+	dir := t.TempDir()
+	w := New()
+	client := New()
+	s, addr := startTestSigner(t, w, client, dir)
+
+	rw, err := OpenRemote(&RemoteConfig{
+		Address: addr,
+		Signer:  w.Address(),
+		Client:  client,
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, w.PublicKey(), rw.PublicKey())
+	assert.True(t, w.Address().Equal(rw.Address()))
+
+	// raw hashes are refused
+	_, err = rw.Sign(crypto.SHA3Sum256([]byte("test")))
+	assert.Error(t, err)
+	_, err = rw.Sign([]byte("short"))
+	assert.Error(t, err)
+
+	ms := rw.(module.MessageSigner)
+	auth := crypto.SHA3Sum256([]byte("auth"))
+	sig, err := ms.SignMessage(module.SignPeerAuth, auth)
+	assert.NoError(t, err)
+	pk, err := recoverPublicKey(crypto.SHA3Sum256(auth), sig)
+	assert.NoError(t, err)
+	assert.Equal(t, w.PublicKey(), pk.SerializeCompressed())
+	_, err = ms.SignMessage(module.SignPeerAuth, proposalBytes(10, 0, "block1", 0))
+	assert.Error(t, err)
+
+	// only the transactions of the validator are signed
+	gov := "cx0000000000000000000000000000000000000000"
+	_, err = ms.SignMessage(module.SignTransaction, txBytes(w.Address(), "patch", gov, ""))
+	assert.NoError(t, err)
+	_, err = ms.SignMessage(module.SignTransaction, txBytes(w.Address(), "base", gov, ""))
+	assert.NoError(t, err)
+	_, err = ms.SignMessage(module.SignTransaction, txBytes(w.Address(), "patch", gov, "0x1"))
+	assert.Error(t, err)
+	_, err = ms.SignMessage(module.SignTransaction, txBytes(w.Address(), "call", gov, ""))
+	assert.Error(t, err)
+	_, err = ms.SignMessage(module.SignTransaction, txBytes(w.Address(), "patch", client.Address().String(), ""))
+	assert.Error(t, err)
+	_, err = ms.SignMessage(module.SignTransaction, txBytes(client.Address(), "patch", gov, ""))
+	assert.Error(t, err)
+	_, err = ms.SignMessage(module.SignTransaction, []byte("icx_sendTransaction.from.hx1"))
+	assert.Error(t, err)
+	_, err = ms.SignMessage(module.SignTransaction, proposalBytes(10, 0, "block1", 0))
+	assert.Error(t, err)
+
+	p1 := proposalBytes(10, 0, "block1", 0)
+	sig1, err := ms.SignMessage(module.SignProposal, p1)
+	assert.NoError(t, err)
+	pk, err = recoverPublicKey(crypto.SHA3Sum256(p1), sig1)
+	assert.NoError(t, err)
+	assert.Equal(t, w.PublicKey(), pk.SerializeCompressed())
+
+	// same message is signed again
+	sig2, err := ms.SignMessage(module.SignProposal, p1)
+	assert.NoError(t, err)
+	assert.Equal(t, sig1, sig2)
+
+	// other message for the same height and round
+	_, err = ms.SignMessage(module.SignProposal, proposalBytes(10, 0, "block2", 0))
+	assert.Error(t, err)
+	// message for the previous height
+	_, err = ms.SignMessage(module.SignProposal, proposalBytes(9, 3, "block2", 0))
+	assert.Error(t, err)
+	// message for the next round
+	_, err = ms.SignMessage(module.SignProposal, proposalBytes(10, 1, "block2", 0))
+	assert.NoError(t, err)
+
+	// votes are tracked for each type
+	_, err = ms.SignMessage(module.SignVote, voteBytes(10, 1, 0, "block2", 1))
+	assert.NoError(t, err)
+	_, err = ms.SignMessage(module.SignVote, voteBytes(10, 1, 1, "block2", 1))
+	assert.NoError(t, err)
+	_, err = ms.SignMessage(module.SignVote, voteBytes(10, 1, 0, "", 1))
+	assert.Error(t, err)
+	_, err = ms.SignMessage(module.SignVote, voteBytes(10, 1, 2, "", 1))
+	assert.Error(t, err)
+	_, err = ms.SignMessage(module.SignVote, []byte{0x01})
+	assert.Error(t, err)
+
+	// decisions are tracked for each network type
+	pc, err := ntm.ForUID("eth").NewProofContext(nil)
+	assert.NoError(t, err)
+	d1 := pc.NewDecision([]byte("src"), 1, 10, 1, []byte("nts1"))
+	sig, err = ms.SignMessage(module.SignNTSDecision, ntm.NewDecisionMessage("eth", d1.Bytes()))
+	assert.NoError(t, err)
+	pk, err = recoverPublicKey(d1.Hash(), sig)
+	assert.NoError(t, err)
+	assert.Equal(t, w.PublicKey(), pk.SerializeCompressed())
+	d2 := pc.NewDecision([]byte("src"), 1, 10, 1, []byte("nts2"))
+	_, err = ms.SignMessage(module.SignNTSDecision, ntm.NewDecisionMessage("eth", d2.Bytes()))
+	assert.Error(t, err)
+	d3 := pc.NewDecision([]byte("src"), 2, 10, 1, []byte("nts2"))
+	_, err = ms.SignMessage(module.SignNTSDecision, ntm.NewDecisionMessage("eth", d3.Bytes()))
+	assert.NoError(t, err)
+	_, err = ms.SignMessage(module.SignNTSDecision, d3.Bytes())
+	assert.Error(t, err)
+
+	// decisions are tracked for each source network
+	d4 := pc.NewDecision([]byte("src2"), 1, 5, 1, []byte("nts4"))
+	_, err = ms.SignMessage(module.SignNTSDecision, ntm.NewDecisionMessage("eth", d4.Bytes()))
+	assert.NoError(t, err)
+
+	// restarted signer keeps the state
+	assert.NoError(t, s.Close())
+	s, _ = startTestSigner(t, w, client, dir)
+	defer s.Close()
+	_, err = ms.SignMessage(module.SignVote, voteBytes(10, 1, 1, "", 1))
+	assert.Error(t, err)
+	_, err = ms.SignMessage(module.SignVote, voteBytes(11, 0, 1, "", 1))
+	assert.NoError(t, err)
+
+	// messages are tracked for each chain
+	_, err = ms.SignMessage(module.SignVote, voteBytes(5, 0, 1, "block3", 2))
+	assert.NoError(t, err)
+	_, err = ms.SignMessage(module.SignVote, voteBytes(5, 0, 1, "", 2))
+	assert.Error(t, err)
+	_, err = ms.SignMessage(module.SignProposal, proposalBytes(11, 0, "block3", 2))
+	assert.NoError(t, err)
+	// messages without NID are valid for any chain
+	_, err = ms.SignMessage(module.SignVote, voteBytes(6, 0, 1, "block4", 0))
+	assert.Error(t, err)
+	_, err = ms.SignMessage(module.SignVote, voteBytes(12, 0, 1, "block4", 0))
+	assert.NoError(t, err)
+	_, err = ms.SignMessage(module.SignVote, voteBytes(6, 0, 1, "block4", 2))
+	assert.Error(t, err)
+	_, err = ms.SignMessage(module.SignVote, voteBytes(12, 0, 1, "block5", 1))
+	assert.Error(t, err)
+	_, err = ms.SignMessage(module.SignVote, voteBytes(13, 0, 1, "block5", 2))
+	assert.NoError(t, err)
+
+	assert.NoError(t, rw.(*remoteWallet).Close())
+}
+
+func TestRemoteWallet_Authentication(t *testing.T) {
+	dir := t.TempDir()
+	w := New()
+	client := New()
+	s, addr := startTestSigner(t, w, client, dir)
+	defer s.Close()
+
+	// unexpected signer
+	_, err := OpenRemote(&RemoteConfig{
+		Address: addr,
+		Signer:  New().Address(),
+		Client:  client,
+	})
+	assert.Error(t, err)
+
+	// unknown client
+	rw, err := OpenRemote(&RemoteConfig{
+		Address: addr,
+		Signer:  w.Address(),
+		Client:  New(),
+	})
+	assert.NoError(t, err)
+	_, err = rw.(module.MessageSigner).SignMessage(module.SignProposal,
+		proposalBytes(10, 0, "block1", 0))
+	assert.Error(t, err)
+
+	_, err = OpenRemote(&RemoteConfig{
+		Address: "signer.sock",
+		Signer:  w.Address(),
+		Client:  client,
+	})
+	assert.Error(t, err)
+}
@@ -0,0 +1,740 @@
+/*
+ * Copyright 2026 ICON Foundation
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package wasm
+
+// instr is a compiled instruction. Meaning of the operands depends on op.
+//   - br, br_if : x for target pc, y for arity and z for height of the label
+//   - br_table : x for the index of the table in the function
+//   - if : x for pc of else branch
+//   - call, call_indirect : x for function or type index
+//   - local.*, global.* : x for the index
+//   - load, store : x for the offset
+//   - const : v for the value
+//   - memory.init, data.drop : x for the data index
+type instr struct {
+	op byte
+	x  uint32
+	y  uint32
+	z  uint32
+	v  uint64
+}
+
+type brTarget struct {
+	pc     uint32
+	arity  uint32
+	height uint32
+}
+
+type function struct {
+	typeIdx   uint32
+	numLocals uint32
+	// maxHeight is the maximum stack height including locals.
+	maxHeight uint32
+	code      []instr
+	tables    [][]brTarget
+}
+
+type ctrlFrame struct {
+	op          byte
+	params      []ValueType
+	results     []ValueType
+	height      int
+	unreachable bool
+	pc          uint32
+	ifPC        int
+	fixups      []func(pc uint32)
+}
+
+func (f *ctrlFrame) labelTypes() []ValueType {
+	if f.op == opLoop {
+		return f.params
+	}
+	return f.results
+}
+
+type compiler struct {
+	m      *Module
+	f      *function
+	locals []ValueType
+	vals   []ValueType
+	ctrls  []*ctrlFrame
+	max    int
+}
+
+func (c *compiler) push(t ValueType) {
+	c.vals = append(c.vals, t)
+	if len(c.vals) > c.max {
+		c.max = len(c.vals)
+	}
+}
+
+func (c *compiler) pushN(ts []ValueType) {
+	for _, t := range ts {
+		c.push(t)
+	}
+}
+
+func (c *compiler) pop() (ValueType, error) {
+	top := c.ctrls[len(c.ctrls)-1]
+	if len(c.vals) == top.height {
+		if top.unreachable {
+			return typeUnknown, nil
+		}
+		return 0, formatError("StackUnderflow")
+	}
+	t := c.vals[len(c.vals)-1]
+	c.vals = c.vals[:len(c.vals)-1]
+	return t, nil
+}
+
+func (c *compiler) popExpect(expect ValueType) (ValueType, error) {
+	t, err := c.pop()
+	if err != nil {
+		return 0, err
+	}
+	if t != expect && t != typeUnknown && expect != typeUnknown {
+		return 0, formatError("TypeMismatch(expect=%s,actual=%s)", expect, t)
+	}
+	if t == typeUnknown {
+		return expect, nil
+	}
+	return t, nil
+}
+
+func (c *compiler) popN(ts []ValueType) error {
+	for i := len(ts) - 1; i >= 0; i-- {
+		if _, err := c.popExpect(ts[i]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (c *compiler) pushCtrl(op byte, params, results []ValueType) *ctrlFrame {
+	f := &ctrlFrame{
+		op:      op,
+		params:  params,
+		results: results,
+		height:  len(c.vals),
+		pc:      uint32(len(c.f.code)),
+		ifPC:    -1,
+	}
+	c.ctrls = append(c.ctrls, f)
+	c.pushN(params)
+	return f
+}
+
+func (c *compiler) popCtrl() (*ctrlFrame, error) {
+	if len(c.ctrls) == 0 {
+		return nil, formatError("ControlStackUnderflow")
+	}
+	f := c.ctrls[len(c.ctrls)-1]
+	if err := c.popN(f.results); err != nil {
+		return nil, err
+	}
+	if len(c.vals) != f.height {
+		return nil, formatError("StackHeightMismatch")
+	}
+	c.ctrls = c.ctrls[:len(c.ctrls)-1]
+	return f, nil
+}
+
+func (c *compiler) setUnreachable() {
+	top := c.ctrls[len(c.ctrls)-1]
+	c.vals = c.vals[:top.height]
+	top.unreachable = true
+}
+
+func (c *compiler) emit(ins instr) int {
+	c.f.code = append(c.f.code, ins)
+	return len(c.f.code) - 1
+}
+
+func (c *compiler) pc() uint32 {
+	return uint32(len(c.f.code))
+}
+
+// label returns the control frame of the label.
+func (c *compiler) label(depth uint32) (*ctrlFrame, error) {
+	if depth >= uint32(len(c.ctrls)) {
+		return nil, formatError("InvalidLabel(depth=%d)", depth)
+	}
+	return c.ctrls[len(c.ctrls)-1-int(depth)], nil
+}
+
+// target calls set with the branch target for the label. It's called at
+// the end of the label if the label is a forward one.
+func (c *compiler) target(f *ctrlFrame, set func(t brTarget)) {
+	t := brTarget{
+		arity:  uint32(len(f.labelTypes())),
+		height: c.f.numLocals + uint32(f.height),
+	}
+	if f.op == opLoop {
+		t.pc = f.pc
+		set(t)
+		return
+	}
+	f.fixups = append(f.fixups, func(pc uint32) {
+		t.pc = pc
+		set(t)
+	})
+}
+
+// branch emits the branch instruction to the label.
+func (c *compiler) branch(op byte, f *ctrlFrame) {
+	idx := c.emit(instr{op: op})
+	c.target(f, func(t brTarget) {
+		c.f.code[idx].x = t.pc
+		c.f.code[idx].y = t.arity
+		c.f.code[idx].z = t.height
+	})
+}
+
+func (c *compiler) blockType(r *reader) ([]ValueType, []ValueType, error) {
+	if r.eof() {
+		return nil, nil, formatError("UnexpectedEnd")
+	}
+	switch b := r.buf[r.pos]; ValueType(b) {
+	case 0x40:
+		r.pos += 1
+		return nil, nil, nil
+	case I32, I64:
+		r.pos += 1
+		return nil, []ValueType{ValueType(b)}, nil
+	}
+	idx, err := r.s33()
+	if err != nil {
+		return nil, nil, err
+	}
+	if idx < 0 {
+		return nil, nil, unsupportedError("UnsupportedBlockType(%d)", idx)
+	}
+	ft, err := c.m.typeOf(uint32(idx))
+	if err != nil {
+		return nil, nil, err
+	}
+	return ft.Params, ft.Results, nil
+}
+
+func (c *compiler) local(idx uint32) (ValueType, error) {
+	if idx >= uint32(len(c.locals)) {
+		return 0, formatError("InvalidLocalIndex(idx=%d)", idx)
+	}
+	return c.locals[idx], nil
+}
+
+func (c *compiler) global(idx uint32) (*global, error) {
+	if idx >= uint32(len(c.m.globals)) {
+		return nil, formatError("InvalidGlobalIndex(idx=%d)", idx)
+	}
+	return &c.m.globals[idx], nil
+}
+
+func (c *compiler) checkMemory() error {
+	if c.m.memory == nil {
+		return formatError("NoMemory")
+	}
+	return nil
+}
+
+func (c *compiler) checkData(idx uint32) error {
+	if c.m.dataCount == nil {
+		return formatError("NoDataCount")
+	}
+	if idx >= *c.m.dataCount {
+		return formatError("InvalidDataIndex(idx=%d)", idx)
+	}
+	return nil
+}
+
+// zeroByte reads reserved byte for memory or table index.
+func zeroByte(r *reader) error {
+	b, err := r.byte()
+	if err != nil {
+		return err
+	}
+	if b != 0 {
+		return formatError("InvalidReservedByte(%#x)", b)
+	}
+	return nil
+}
+
+func (m *Module) compile(f *function, body []byte) error {
+	ft := &m.types[f.typeIdx]
+	r := newReader(body)
+	c := &compiler{m: m, f: f}
+	c.locals = append(c.locals, ft.Params...)
+
+	n, err := r.u32()
+	if err != nil {
+		return err
+	}
+	for i := uint32(0); i < n; i++ {
+		cnt, err := r.u32()
+		if err != nil {
+			return err
+		}
+		t, err := r.valueType()
+		if err != nil {
+			return err
+		}
+		if uint64(len(c.locals))+uint64(cnt) > maxLocals {
+			return unsupportedError("TooManyLocals")
+		}
+		for j := uint32(0); j < cnt; j++ {
+			c.locals = append(c.locals, t)
+		}
+	}
+	f.numLocals = uint32(len(c.locals))
+
+	c.pushCtrl(opBlock, nil, ft.Results)
+	for len(c.ctrls) > 0 {
+		op, err := r.byte()
+		if err != nil {
+			return err
+		}
+		if err := c.compileOp(r, op); err != nil {
+			return err
+		}
+	}
+	if !r.eof() {
+		return formatError("CodeAfterEnd")
+	}
+	f.maxHeight = f.numLocals + uint32(c.max)
+	return nil
+}
+
+func (c *compiler) compileOp(r *reader, op byte) error {
+	switch op {
+	case opUnreachable:
+		c.emit(instr{op: op})
+		c.setUnreachable()
+
+	case opNop:
+
+	case opBlock, opLoop:
+		params, results, err := c.blockType(r)
+		if err != nil {
+			return err
+		}
+		if err := c.popN(params); err != nil {
+			return err
+		}
+		c.pushCtrl(op, params, results)
+
+	case opIf:
+		params, results, err := c.blockType(r)
+		if err != nil {
+			return err
+		}
+		if _, err := c.popExpect(I32); err != nil {
+			return err
+		}
+		if err := c.popN(params); err != nil {
+			return err
+		}
+		f := c.pushCtrl(op, params, results)
+		f.ifPC = c.emit(instr{op: opIf})
+
+	case opElse:
+		top := c.ctrls[len(c.ctrls)-1]
+		if top.op != opIf {
+			return formatError("ElseWithoutIf")
+		}
+		f, err := c.popCtrl()
+		if err != nil {
+			return err
+		}
+		// jump to the end at the end of then branch
+		c.branch(opBr, f)
+		c.f.code[f.ifPC].x = c.pc()
+		nf := c.pushCtrl(opElse, f.params, f.results)
+		nf.fixups = f.fixups
+
+	case opEnd:
+		f, err := c.popCtrl()
+		if err != nil {
+			return err
+		}
+		if f.op == opIf {
+			if !equalTypes(f.params, f.results) {
+				return formatError("IfWithoutElse")
+			}
+			c.f.code[f.ifPC].x = c.pc()
+		}
+		if len(c.ctrls) == 0 {
+			// end of the function
+			for _, fix := range f.fixups {
+				fix(c.pc())
+			}
+			c.emit(instr{op: opReturn})
+			return nil
+		}
+		for _, fix := range f.fixups {
+			fix(c.pc())
+		}
+		c.pushN(f.results)
+
+	case opBr, opBrIf:
+		depth, err := r.u32()
+		if err != nil {
+			return err
+		}
+		f, err := c.label(depth)
+		if err != nil {
+			return err
+		}
+		if op == opBrIf {
+			if _, err := c.popExpect(I32); err != nil {
+				return err
+			}
+		}
+		if err := c.popN(f.labelTypes()); err != nil {
+			return err
+		}
+		c.branch(op, f)
+		if op == opBr {
+			c.setUnreachable()
+		} else {
+			c.pushN(f.labelTypes())
+		}
+
+	case opBrTable:
+		n, err := r.u32()
+		if err != nil {
+			return err
+		}
+		if n >= maxBranchTargets {
+			return unsupportedError("TooManyBranchTargets(n=%d)", n)
+		}
+		table := make([]brTarget, n+1)
+		tidx := len(c.f.tables)
+		c.f.tables = append(c.f.tables, table)
+		if _, err := c.popExpect(I32); err != nil {
+			return err
+		}
+		var arity = -1
+		for i := range table {
+			depth, err := r.u32()
+			if err != nil {
+				return err
+			}
+			f, err := c.label(depth)
+			if err != nil {
+				return err
+			}
+			types := f.labelTypes()
+			if arity >= 0 && arity != len(types) {
+				return formatError("BranchArityMismatch")
+			}
+			arity = len(types)
+			// check types without consuming the values
+			vals := append([]ValueType(nil), c.vals...)
+			if err := c.popN(types); err != nil {
+				return err
+			}
+			c.vals = vals
+			i := i
+			c.target(f, func(t brTarget) {
+				c.f.tables[tidx][i] = t
+			})
+		}
+		c.emit(instr{op: op, x: uint32(tidx)})
+		c.setUnreachable()
+
+	case opReturn:
+		if err := c.popN(c.ctrls[0].results); err != nil {
+			return err
+		}
+		c.emit(instr{op: op})
+		c.setUnreachable()
+
+	case opCall:
+		idx, err := r.u32()
+		if err != nil {
+			return err
+		}
+		if idx >= c.m.numFuncs() {
+			return formatError("InvalidFuncIndex(idx=%d)", idx)
+		}
+		ft := c.m.funcType(idx)
+		if err := c.popN(ft.Params); err != nil {
+			return err
+		}
+		c.emit(instr{op: op, x: idx})
+		c.pushN(ft.Results)
+
+	case opCallIndirect:
+		idx, err := r.u32()
+		if err != nil {
+			return err
+		}
+		if err := zeroByte(r); err != nil {
+			return err
+		}
+		if c.m.table == nil {
+			return formatError("NoTable")
+		}
+		ft, err := c.m.typeOf(idx)
+		if err != nil {
+			return err
+		}
+		if _, err := c.popExpect(I32); err != nil {
+			return err
+		}
+		if err := c.popN(ft.Params); err != nil {
+			return err
+		}
+		c.emit(instr{op: op, x: idx})
+		c.pushN(ft.Results)
+
+	case opDrop:
+		if _, err := c.pop(); err != nil {
+			return err
+		}
+		c.emit(instr{op: op})
+
+	case opSelect, opSelectTyped:
+		var expect = typeUnknown
+		if op == opSelectTyped {
+			n, err := r.u32()
+			if err != nil {
+				return err
+			}
+			if n != 1 {
+				return formatError("InvalidSelectType")
+			}
+			if expect, err = r.valueType(); err != nil {
+				return err
+			}
+		}
+		if _, err := c.popExpect(I32); err != nil {
+			return err
+		}
+		t1, err := c.popExpect(expect)
+		if err != nil {
+			return err
+		}
+		t2, err := c.popExpect(t1)
+		if err != nil {
+			return err
+		}
+		c.emit(instr{op: opSelect})
+		c.push(t2)
+
+	case opLocalGet, opLocalSet, opLocalTee:
+		idx, err := r.u32()
+		if err != nil {
+			return err
+		}
+		t, err := c.local(idx)
+		if err != nil {
+			return err
+		}
+		if op != opLocalGet {
+			if _, err := c.popExpect(t); err != nil {
+				return err
+			}
+		}
+		if op != opLocalSet {
+			c.push(t)
+		}
+		c.emit(instr{op: op, x: idx})
+
+	case opGlobalGet, opGlobalSet:
+		idx, err := r.u32()
+		if err != nil {
+			return err
+		}
+		g, err := c.global(idx)
+		if err != nil {
+			return err
+		}
+		if op == opGlobalGet {
+			c.push(g.typ)
+		} else {
+			if !g.mutable {
+				return formatError("ImmutableGlobal(idx=%d)", idx)
+			}
+			if _, err := c.popExpect(g.typ); err != nil {
+				return err
+			}
+		}
+		c.emit(instr{op: op, x: idx})
+
+	case opMemorySize, opMemoryGrow:
+		if err := zeroByte(r); err != nil {
+			return err
+		}
+		if err := c.checkMemory(); err != nil {
+			return err
+		}
+		if op == opMemoryGrow {
+			if _, err := c.popExpect(I32); err != nil {
+				return err
+			}
+		}
+		c.push(I32)
+		c.emit(instr{op: op})
+
+	case opI32Const:
+		v, err := r.s32()
+		if err != nil {
+			return err
+		}
+		c.push(I32)
+		c.emit(instr{op: op, v: uint64(uint32(v))})
+
+	case opI64Const:
+		v, err := r.s64()
+		if err != nil {
+			return err
+		}
+		c.push(I64)
+		c.emit(instr{op: op, v: uint64(v)})
+
+	case opPrefixMisc:
+		return c.compileMisc(r)
+
+	default:
+		if mop, ok := memoryOps[op]; ok {
+			return c.compileMemoryOp(r, op, mop)
+		}
+		if nop, ok := numericOps[op]; ok {
+			if err := c.popN(nop.params); err != nil {
+				return err
+			}
+			c.push(nop.result)
+			c.emit(instr{op: op})
+			return nil
+		}
+		return unsupportedError("UnsupportedOperator(op=%#x)", op)
+	}
+	return nil
+}
+
+func (c *compiler) compileMemoryOp(r *reader, op byte, mop memoryOp) error {
+	align, err := r.u32()
+	if err != nil {
+		return err
+	}
+	offset, err := r.u32()
+	if err != nil {
+		return err
+	}
+	if align > 3 || 1<<align > mop.size {
+		return formatError("InvalidAlignment(align=%d)", align)
+	}
+	if err := c.checkMemory(); err != nil {
+		return err
+	}
+	if mop.store {
+		if _, err := c.popExpect(mop.typ); err != nil {
+			return err
+		}
+		if _, err := c.popExpect(I32); err != nil {
+			return err
+		}
+	} else {
+		if _, err := c.popExpect(I32); err != nil {
+			return err
+		}
+		c.push(mop.typ)
+	}
+	c.emit(instr{op: op, x: offset})
+	return nil
+}
+
+func (c *compiler) compileMisc(r *reader) error {
+	sub, err := r.u32()
+	if err != nil {
+		return err
+	}
+	switch sub {
+	case miscMemoryInit:
+		idx, err := r.u32()
+		if err != nil {
+			return err
+		}
+		if err := zeroByte(r); err != nil {
+			return err
+		}
+		if err := c.checkMemory(); err != nil {
+			return err
+		}
+		if err := c.checkData(idx); err != nil {
+			return err
+		}
+		if err := c.popN(binaryI32); err != nil {
+			return err
+		}
+		if _, err := c.popExpect(I32); err != nil {
+			return err
+		}
+		c.emit(instr{op: opMemoryInit, x: idx})
+
+	case miscDataDrop:
+		idx, err := r.u32()
+		if err != nil {
+			return err
+		}
+		if err := c.checkData(idx); err != nil {
+			return err
+		}
+		c.emit(instr{op: opDataDrop, x: idx})
+
+	case miscMemoryCopy, miscMemoryFill:
+		if err := zeroByte(r); err != nil {
+			return err
+		}
+		if sub == miscMemoryCopy {
+			if err := zeroByte(r); err != nil {
+				return err
+			}
+		}
+		if err := c.checkMemory(); err != nil {
+			return err
+		}
+		if err := c.popN(binaryI32); err != nil {
+			return err
+		}
+		if _, err := c.popExpect(I32); err != nil {
+			return err
+		}
+		if sub == miscMemoryCopy {
+			c.emit(instr{op: opMemoryCopy})
+		} else {
+			c.emit(instr{op: opMemoryFill})
+		}
+
+	default:
+		return unsupportedError("UnsupportedOperator(op=0xfc %d)", sub)
+	}
+	return nil
+}
+
+func equalTypes(t1, t2 []ValueType) bool {
+	if len(t1) != len(t2) {
+		return false
+	}
+	for i := range t1 {
+		if t1[i] != t2[i] {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,423 @@
+/*
+ * Copyright 2026 ICON Foundation
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package wasm
+
+import (
+	"encoding/binary"
+	"math"
+	"math/bits"
+)
+
+func b2i(b bool) uint64 {
+	if b {
+		return 1
+	}
+	return 0
+}
+
+// execute runs the function with the frame at fp. Locals are already
+// initialized, and the results are placed at fp on return.
+func (in *Instance) execute(f *function, fp int) {
+	stack := in.stack
+	code := f.code
+	sp := fp + int(f.numLocals)
+	for pc := 0; ; pc++ {
+		ins := &code[pc]
+		if in.steps <= 0 {
+			throw(ErrOutOfStep)
+		}
+		in.steps -= 1
+		switch ins.op {
+		case opUnreachable:
+			throw(ErrUnreachable)
+
+		case opIf:
+			sp -= 1
+			if uint32(stack[sp]) == 0 {
+				pc = int(ins.x) - 1
+			}
+
+		case opBr:
+			in.checkAborted()
+			sp = branch(stack, fp, sp, ins.y, ins.z)
+			pc = int(ins.x) - 1
+
+		case opBrIf:
+			sp -= 1
+			if uint32(stack[sp]) != 0 {
+				in.checkAborted()
+				sp = branch(stack, fp, sp, ins.y, ins.z)
+				pc = int(ins.x) - 1
+			}
+
+		case opBrTable:
+			in.checkAborted()
+			sp -= 1
+			table := f.tables[ins.x]
+			idx := uint32(stack[sp])
+			if idx >= uint32(len(table)) {
+				idx = uint32(len(table) - 1)
+			}
+			t := &table[idx]
+			sp = branch(stack, fp, sp, t.arity, t.height)
+			pc = int(t.pc) - 1
+
+		case opReturn:
+			n := len(in.module.types[f.typeIdx].Results)
+			copy(stack[fp:], stack[sp-n:sp])
+			return
+
+		case opCall:
+			sp = in.call(ins.x, sp)
+
+		case opCallIndirect:
+			sp -= 1
+			idx := uint32(stack[sp])
+			if idx >= uint32(len(in.table)) || in.table[idx] < 0 {
+				throw(ErrInvalidIndirect)
+			}
+			fidx := uint32(in.table[idx])
+			if !in.module.funcType(fidx).Equal(&in.module.types[ins.x]) {
+				throw(ErrInvalidIndirect)
+			}
+			sp = in.call(fidx, sp)
+
+		case opDrop:
+			sp -= 1
+
+		case opSelect:
+			sp -= 2
+			if uint32(stack[sp+1]) == 0 {
+				stack[sp-1] = stack[sp]
+			}
+
+		case opLocalGet:
+			stack[sp] = stack[fp+int(ins.x)]
+			sp += 1
+		case opLocalSet:
+			sp -= 1
+			stack[fp+int(ins.x)] = stack[sp]
+		case opLocalTee:
+			stack[fp+int(ins.x)] = stack[sp-1]
+		case opGlobalGet:
+			stack[sp] = in.globals[ins.x]
+			sp += 1
+		case opGlobalSet:
+			sp -= 1
+			in.globals[ins.x] = stack[sp]
+
+		case opI32Load:
+			a := in.address(stack[sp-1], ins.x, 4)
+			stack[sp-1] = uint64(binary.LittleEndian.Uint32(in.memory[a:]))
+		case opI64Load:
+			a := in.address(stack[sp-1], ins.x, 8)
+			stack[sp-1] = binary.LittleEndian.Uint64(in.memory[a:])
+		case opI32Load8S:
+			a := in.address(stack[sp-1], ins.x, 1)
+			stack[sp-1] = uint64(uint32(int8(in.memory[a])))
+		case opI32Load8U, opI64Load8U:
+			a := in.address(stack[sp-1], ins.x, 1)
+			stack[sp-1] = uint64(in.memory[a])
+		case opI32Load16S:
+			a := in.address(stack[sp-1], ins.x, 2)
+			stack[sp-1] = uint64(uint32(int16(binary.LittleEndian.Uint16(in.memory[a:]))))
+		case opI32Load16U, opI64Load16U:
+			a := in.address(stack[sp-1], ins.x, 2)
+			stack[sp-1] = uint64(binary.LittleEndian.Uint16(in.memory[a:]))
+		case opI64Load8S:
+			a := in.address(stack[sp-1], ins.x, 1)
+			stack[sp-1] = uint64(int8(in.memory[a]))
+		case opI64Load16S:
+			a := in.address(stack[sp-1], ins.x, 2)
+			stack[sp-1] = uint64(int16(binary.LittleEndian.Uint16(in.memory[a:])))
+		case opI64Load32S:
+			a := in.address(stack[sp-1], ins.x, 4)
+			stack[sp-1] = uint64(int32(binary.LittleEndian.Uint32(in.memory[a:])))
+		case opI64Load32U:
+			a := in.address(stack[sp-1], ins.x, 4)
+			stack[sp-1] = uint64(binary.LittleEndian.Uint32(in.memory[a:]))
+
+		case opI32Store, opI64Store32:
+			sp -= 2
+			a := in.address(stack[sp], ins.x, 4)
+			binary.LittleEndian.PutUint32(in.memory[a:], uint32(stack[sp+1]))
+		case opI64Store:
+			sp -= 2
+			a := in.address(stack[sp], ins.x, 8)
+			binary.LittleEndian.PutUint64(in.memory[a:], stack[sp+1])
+		case opI32Store8, opI64Store8:
+			sp -= 2
+			a := in.address(stack[sp], ins.x, 1)
+			in.memory[a] = byte(stack[sp+1])
+		case opI32Store16, opI64Store16:
+			sp -= 2
+			a := in.address(stack[sp], ins.x, 2)
+			binary.LittleEndian.PutUint16(in.memory[a:], uint16(stack[sp+1]))
+
+		case opMemorySize:
+			stack[sp] = uint64(len(in.memory) / PageSize)
+			sp += 1
+		case opMemoryGrow:
+			stack[sp-1] = uint64(in.grow(uint32(stack[sp-1])))
+
+		case opMemoryInit:
+			sp -= 3
+			d, s, n := uint64(uint32(stack[sp])), uint64(uint32(stack[sp+1])), uint32(stack[sp+2])
+			in.charge(bulkSteps(n))
+			var data []byte
+			if !in.dropped[ins.x] {
+				data = in.module.data[ins.x].data
+			}
+			in.checkRange(s, uint64(n), len(data))
+			in.checkRange(d, uint64(n), len(in.memory))
+			copy(in.memory[d:], data[s:s+uint64(n)])
+		case opDataDrop:
+			in.dropped[ins.x] = true
+		case opMemoryCopy:
+			sp -= 3
+			d, s, n := uint64(uint32(stack[sp])), uint64(uint32(stack[sp+1])), uint32(stack[sp+2])
+			in.charge(bulkSteps(n))
+			in.checkRange(s, uint64(n), len(in.memory))
+			in.checkRange(d, uint64(n), len(in.memory))
+			copy(in.memory[d:], in.memory[s:s+uint64(n)])
+		case opMemoryFill:
+			sp -= 3
+			d, v, n := uint64(uint32(stack[sp])), byte(stack[sp+1]), uint32(stack[sp+2])
+			in.charge(bulkSteps(n))
+			in.checkRange(d, uint64(n), len(in.memory))
+			mem := in.memory[d : d+uint64(n)]
+			for i := range mem {
+				mem[i] = v
+			}
+
+		case opI32Const, opI64Const:
+			stack[sp] = ins.v
+			sp += 1
+
+		default:
+			sp = execNumeric(ins.op, stack, sp)
+		}
+	}
+}
+
+// branch moves arity values on top of the stack to the height of the label,
+// and returns the new stack pointer.
+func branch(stack []uint64, fp, sp int, arity, height uint32) int {
+	dst := fp + int(height)
+	copy(stack[dst:], stack[sp-int(arity):sp])
+	return dst + int(arity)
+}
+
+func execNumeric(op byte, stack []uint64, sp int) int {
+	if op == opI32Eqz || op == opI64Eqz || (op >= opI32Clz && op <= opI32Popcnt) ||
+		(op >= opI64Clz && op <= opI64Popcnt) || op >= opI32WrapI64 {
+		stack[sp-1] = execUnary(op, stack[sp-1])
+		return sp
+	}
+	sp -= 1
+	if op <= opI32GeU || (op >= opI32Add && op <= opI32Rotr) {
+		stack[sp-1] = uint64(execBinaryI32(op, uint32(stack[sp-1]), uint32(stack[sp])))
+	} else {
+		stack[sp-1] = execBinaryI64(op, stack[sp-1], stack[sp])
+	}
+	return sp
+}
+
+func execUnary(op byte, v uint64) uint64 {
+	switch op {
+	case opI32Eqz:
+		return b2i(uint32(v) == 0)
+	case opI64Eqz:
+		return b2i(v == 0)
+	case opI32Clz:
+		return uint64(bits.LeadingZeros32(uint32(v)))
+	case opI32Ctz:
+		return uint64(bits.TrailingZeros32(uint32(v)))
+	case opI32Popcnt:
+		return uint64(bits.OnesCount32(uint32(v)))
+	case opI64Clz:
+		return uint64(bits.LeadingZeros64(v))
+	case opI64Ctz:
+		return uint64(bits.TrailingZeros64(v))
+	case opI64Popcnt:
+		return uint64(bits.OnesCount64(v))
+	case opI32WrapI64, opI64ExtendI32U:
+		return uint64(uint32(v))
+	case opI64ExtendI32S, opI64Extend32S:
+		return uint64(int32(v))
+	case opI32Extend8S:
+		return uint64(uint32(int8(v)))
+	case opI32Extend16S:
+		return uint64(uint32(int16(v)))
+	case opI64Extend8S:
+		return uint64(int8(v))
+	case opI64Extend16S:
+		return uint64(int16(v))
+	default:
+		panic("unknown unary operator")
+	}
+}
+
+func execBinaryI32(op byte, a, b uint32) uint32 {
+	switch op {
+	case opI32Eq:
+		return uint32(b2i(a == b))
+	case opI32Ne:
+		return uint32(b2i(a != b))
+	case opI32LtS:
+		return uint32(b2i(int32(a) < int32(b)))
+	case opI32LtU:
+		return uint32(b2i(a < b))
+	case opI32GtS:
+		return uint32(b2i(int32(a) > int32(b)))
+	case opI32GtU:
+		return uint32(b2i(a > b))
+	case opI32LeS:
+		return uint32(b2i(int32(a) <= int32(b)))
+	case opI32LeU:
+		return uint32(b2i(a <= b))
+	case opI32GeS:
+		return uint32(b2i(int32(a) >= int32(b)))
+	case opI32GeU:
+		return uint32(b2i(a >= b))
+	case opI32Add:
+		return a + b
+	case opI32Sub:
+		return a - b
+	case opI32Mul:
+		return a * b
+	case opI32DivS:
+		if b == 0 {
+			throw(ErrDivideByZero)
+		}
+		if int32(a) == math.MinInt32 && int32(b) == -1 {
+			throw(ErrIntegerOverflow)
+		}
+		return uint32(int32(a) / int32(b))
+	case opI32DivU:
+		if b == 0 {
+			throw(ErrDivideByZero)
+		}
+		return a / b
+	case opI32RemS:
+		if b == 0 {
+			throw(ErrDivideByZero)
+		}
+		if int32(b) == -1 {
+			return 0
+		}
+		return uint32(int32(a) % int32(b))
+	case opI32RemU:
+		if b == 0 {
+			throw(ErrDivideByZero)
+		}
+		return a % b
+	case opI32And:
+		return a & b
+	case opI32Or:
+		return a | b
+	case opI32Xor:
+		return a ^ b
+	case opI32Shl:
+		return a << (b & 31)
+	case opI32ShrS:
+		return uint32(int32(a) >> (b & 31))
+	case opI32ShrU:
+		return a >> (b & 31)
+	case opI32Rotl:
+		return bits.RotateLeft32(a, int(b&31))
+	case opI32Rotr:
+		return bits.RotateLeft32(a, -int(b&31))
+	default:
+		panic("unknown binary operator")
+	}
+}
+
+func execBinaryI64(op byte, a, b uint64) uint64 {
+	switch op {
+	case opI64Eq:
+		return b2i(a == b)
+	case opI64Ne:
+		return b2i(a != b)
+	case opI64LtS:
+		return b2i(int64(a) < int64(b))
+	case opI64LtU:
+		return b2i(a < b)
+	case opI64GtS:
+		return b2i(int64(a) > int64(b))
+	case opI64GtU:
+		return b2i(a > b)
+	case opI64LeS:
+		return b2i(int64(a) <= int64(b))
+	case opI64LeU:
+		return b2i(a <= b)
+	case opI64GeS:
+		return b2i(int64(a) >= int64(b))
+	case opI64GeU:
+		return b2i(a >= b)
+	case opI64Add:
+		return a + b
+	case opI64Sub:
+		return a - b
+	case opI64Mul:
+		return a * b
+	case opI64DivS:
+		if b == 0 {
+			throw(ErrDivideByZero)
+		}
+		if int64(a) == math.MinInt64 && int64(b) == -1 {
+			throw(ErrIntegerOverflow)
+		}
+		return uint64(int64(a) / int64(b))
+	case opI64DivU:
+		if b == 0 {
+			throw(ErrDivideByZero)
+		}
+		return a / b
+	case opI64RemS:
+		if b == 0 {
+			throw(ErrDivideByZero)
+		}
+		if int64(b) == -1 {
+			return 0
+		}
+		return uint64(int64(a) % int64(b))
+	case opI64RemU:
+		if b == 0 {
+			throw(ErrDivideByZero)
+		}
+		return a % b
+	case opI64And:
+		return a & b
+	case opI64Or:
+		return a | b
+	case opI64Xor:
+		return a ^ b
+	case opI64Shl:
+		return a << (b & 63)
+	case opI64ShrS:
+		return uint64(int64(a) >> (b & 63))
+	case opI64ShrU:
+		return a >> (b & 63)
+	case opI64Rotl:
+		return bits.RotateLeft64(a, int(b&63))
+	case opI64Rotr:
+		return bits.RotateLeft64(a, -int(b&63))
+	default:
+		panic("unknown binary operator")
+	}
+}
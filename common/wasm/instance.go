@@ -0,0 +1,307 @@
+/*
+ * Copyright 2026 ICON Foundation
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package wasm
+
+import (
+	"sync/atomic"
+
+	"github.com/icon-project/goloop/common/errors"
+)
+
+var (
+	ErrOutOfStep        = errors.New("OutOfStep")
+	ErrStackOverflow    = errors.New("StackOverflow")
+	ErrAborted          = errors.New("Aborted")
+	ErrUnreachable      = errors.New("Unreachable")
+	ErrOutOfBounds      = errors.New("OutOfBounds")
+	ErrDivideByZero     = errors.New("DivideByZero")
+	ErrIntegerOverflow  = errors.New("IntegerOverflow")
+	ErrInvalidIndirect  = errors.New("InvalidIndirectCall")
+	ErrFunctionNotFound = errors.New("FunctionNotFound")
+)
+
+const (
+	// StackSize is the number of values in the stack of an instance.
+	StackSize = 1 << 16
+	// MaxCallDepth is the maximum depth of calls among functions.
+	MaxCallDepth = 1024
+
+	// stepsPerPage is the steps for a page grown by memory.grow.
+	stepsPerPage = 1024
+	// bytesPerStep is the bytes handled by bulk memory operators for a step.
+	bytesPerStep = 64
+)
+
+// HostFunction is a function provided by the host. Results should match
+// to the type. If Call returns an error, the execution stops with it.
+type HostFunction struct {
+	Type FuncType
+	Call func(in *Instance, args []uint64) ([]uint64, error)
+}
+
+// Imports is host functions for the instance keyed by module and name.
+type Imports map[string]map[string]*HostFunction
+
+// Instance is an instantiated module. It executes one function at a time.
+type Instance struct {
+	module   *Module
+	hosts    []*HostFunction
+	memory   []byte
+	maxPages uint32
+	globals  []uint64
+	table    []int64
+	dropped  []bool
+	stack    []uint64
+	depth    int
+	limit    int64
+	steps    int64
+	aborted  int32
+}
+
+type trap struct {
+	err error
+}
+
+func throw(err error) {
+	panic(&trap{err})
+}
+
+// NewInstance instantiates the module with the imports. Executions of the
+// instance can use limit steps in total.
+func NewInstance(m *Module, imports Imports, limit int64) (*Instance, error) {
+	in := &Instance{
+		module: m,
+		hosts:  make([]*HostFunction, len(m.imports)),
+		limit:  limit,
+		steps:  limit,
+		stack:  make([]uint64, StackSize),
+	}
+	for i := range m.imports {
+		im := &m.imports[i]
+		h, ok := imports[im.Module][im.Name]
+		if !ok {
+			return nil, errors.NotFoundError.Errorf(
+				"ImportNotFound(module=%s,name=%s)", im.Module, im.Name)
+		}
+		if !h.Type.Equal(&im.Type) {
+			return nil, errors.IllegalArgumentError.Errorf(
+				"ImportTypeMismatch(module=%s,name=%s)", im.Module, im.Name)
+		}
+		in.hosts[i] = h
+	}
+	if m.memory != nil {
+		in.memory = make([]byte, int(m.memory.min)*PageSize)
+		in.maxPages = MaxPages
+		if m.memory.hasMax && m.memory.max < MaxPages {
+			in.maxPages = m.memory.max
+		}
+	}
+	in.globals = make([]uint64, len(m.globals))
+	for i, g := range m.globals {
+		in.globals[i] = g.init
+	}
+	if m.table != nil {
+		in.table = make([]int64, m.table.min)
+		for i := range in.table {
+			in.table[i] = -1
+		}
+		for _, e := range m.elements {
+			if uint64(e.offset)+uint64(len(e.funcs)) > uint64(len(in.table)) {
+				return nil, errors.Wrap(ErrOutOfBounds, "InvalidElementSegment")
+			}
+			for j, idx := range e.funcs {
+				in.table[int(e.offset)+j] = int64(idx)
+			}
+		}
+	}
+	in.dropped = make([]bool, len(m.data))
+	for i, d := range m.data {
+		if !d.active {
+			continue
+		}
+		if uint64(d.offset)+uint64(len(d.data)) > uint64(len(in.memory)) {
+			return nil, errors.Wrap(ErrOutOfBounds, "InvalidDataSegment")
+		}
+		copy(in.memory[d.offset:], d.data)
+		in.dropped[i] = true
+	}
+	return in, nil
+}
+
+// Module returns the module of the instance.
+func (in *Instance) Module() *Module {
+	return in.module
+}
+
+// Call calls the exported function of the name with the arguments, and
+// returns its results. Values of I32 are passed in lower 32 bits.
+func (in *Instance) Call(name string, args ...uint64) (ret []uint64, err error) {
+	idx, ok := in.module.exports[name]
+	if !ok {
+		return nil, errors.Wrapf(ErrFunctionNotFound, "FunctionNotFound(name=%s)", name)
+	}
+	ft := in.module.funcType(idx)
+	if len(args) != len(ft.Params) {
+		return nil, errors.IllegalArgumentError.Errorf(
+			"InvalidArguments(name=%s,exp=%d,given=%d)", name, len(ft.Params), len(args))
+	}
+	defer func() {
+		if r := recover(); r != nil {
+			t, ok := r.(*trap)
+			if !ok {
+				panic(r)
+			}
+			ret, err = nil, t.err
+		}
+		in.depth = 0
+	}()
+	for i, v := range args {
+		if ft.Params[i] == I32 {
+			v = uint64(uint32(v))
+		}
+		in.stack[i] = v
+	}
+	sp := in.call(idx, len(args))
+	return append([]uint64(nil), in.stack[sp-len(ft.Results):sp]...), nil
+}
+
+// Abort makes the current execution stop with ErrAborted. It can be called
+// by other goroutines.
+func (in *Instance) Abort() {
+	atomic.StoreInt32(&in.aborted, 1)
+}
+
+func (in *Instance) checkAborted() {
+	if atomic.LoadInt32(&in.aborted) != 0 {
+		throw(ErrAborted)
+	}
+}
+
+// Charge uses the steps. It returns ErrOutOfStep if there are not enough
+// steps, and all the remaining steps are used.
+func (in *Instance) Charge(steps int64) error {
+	if steps < 0 || steps > in.steps {
+		in.steps = 0
+		return ErrOutOfStep
+	}
+	in.steps -= steps
+	return nil
+}
+
+func (in *Instance) charge(steps int64) {
+	if err := in.Charge(steps); err != nil {
+		throw(err)
+	}
+}
+
+// StepUsed returns the steps used by executions of the instance.
+func (in *Instance) StepUsed() int64 {
+	return in.limit - in.steps
+}
+
+// Read returns a copy of the memory of the range.
+func (in *Instance) Read(ptr, size uint32) ([]byte, error) {
+	if uint64(ptr)+uint64(size) > uint64(len(in.memory)) {
+		return nil, ErrOutOfBounds
+	}
+	return append([]byte(nil), in.memory[ptr:ptr+size]...), nil
+}
+
+// Write writes the data to the memory at ptr.
+func (in *Instance) Write(ptr uint32, data []byte) error {
+	if uint64(ptr)+uint64(len(data)) > uint64(len(in.memory)) {
+		return ErrOutOfBounds
+	}
+	copy(in.memory[ptr:], data)
+	return nil
+}
+
+// call calls the function with the arguments on top of the stack, and
+// returns the stack pointer after pushing the results.
+func (in *Instance) call(idx uint32, sp int) int {
+	in.checkAborted()
+	if in.depth >= MaxCallDepth {
+		throw(ErrStackOverflow)
+	}
+	in.depth += 1
+	if idx < uint32(len(in.hosts)) {
+		h := in.hosts[idx]
+		n := len(h.Type.Params)
+		args := append([]uint64(nil), in.stack[sp-n:sp]...)
+		results, err := h.Call(in, args)
+		if err != nil {
+			throw(err)
+		}
+		if len(results) != len(h.Type.Results) {
+			throw(errors.InvalidStateError.Errorf("InvalidHostResults(exp=%d,given=%d)",
+				len(h.Type.Results), len(results)))
+		}
+		sp -= n
+		for i, v := range results {
+			if h.Type.Results[i] == I32 {
+				v = uint64(uint32(v))
+			}
+			in.stack[sp] = v
+			sp += 1
+		}
+	} else {
+		f := in.module.funcs[idx-uint32(len(in.hosts))]
+		ft := &in.module.types[f.typeIdx]
+		fp := sp - len(ft.Params)
+		if fp+int(f.maxHeight) > len(in.stack) {
+			throw(ErrStackOverflow)
+		}
+		locals := in.stack[sp : fp+int(f.numLocals)]
+		for i := range locals {
+			locals[i] = 0
+		}
+		in.execute(f, fp)
+		sp = fp + len(ft.Results)
+	}
+	in.depth -= 1
+	return sp
+}
+
+// address returns the effective address of the memory access after checking
+// bounds.
+func (in *Instance) address(base uint64, offset uint32, size uint32) uint32 {
+	ea := uint64(uint32(base)) + uint64(offset)
+	if ea+uint64(size) > uint64(len(in.memory)) {
+		throw(ErrOutOfBounds)
+	}
+	return uint32(ea)
+}
+
+func (in *Instance) checkRange(ptr, size uint64, limit int) {
+	if ptr+size > uint64(limit) {
+		throw(ErrOutOfBounds)
+	}
+}
+
+func (in *Instance) grow(delta uint32) uint32 {
+	pages := uint32(len(in.memory) / PageSize)
+	if uint64(pages)+uint64(delta) > uint64(in.maxPages) {
+		return 0xffffffff
+	}
+	in.charge(int64(delta) * stepsPerPage)
+	in.memory = append(in.memory, make([]byte, int(delta)*PageSize)...)
+	return pages
+}
+
+func bulkSteps(n uint32) int64 {
+	return (int64(n) + bytesPerStep - 1) / bytesPerStep
+}
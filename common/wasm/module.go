@@ -0,0 +1,877 @@
+/*
+ * Copyright 2026 ICON Foundation
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+// Package wasm implements a deterministic interpreter of WebAssembly modules.
+//
+// It supports integer instructions of WebAssembly 1.0 with sign extension
+// operators, bulk memory operators and multi-value blocks. Floating point
+// types and instructions are rejected on decoding, so the result of the
+// execution doesn't depend on the platform. Every executed instruction is
+// metered, and the execution stops when it uses up the given steps.
+package wasm
+
+import (
+	"bytes"
+	"unicode/utf8"
+
+	"github.com/icon-project/goloop/common/errors"
+)
+
+type ValueType byte
+
+const (
+	I32 ValueType = 0x7f
+	I64 ValueType = 0x7e
+
+	typeF32     ValueType = 0x7d
+	typeF64     ValueType = 0x7c
+	typeFuncRef ValueType = 0x70
+	// typeUnknown is used for the operand of unreachable code in validation.
+	typeUnknown ValueType = 0
+)
+
+func (t ValueType) String() string {
+	switch t {
+	case I32:
+		return "i32"
+	case I64:
+		return "i64"
+	case typeF32:
+		return "f32"
+	case typeF64:
+		return "f64"
+	default:
+		return "unknown"
+	}
+}
+
+type FuncType struct {
+	Params  []ValueType
+	Results []ValueType
+}
+
+func (t *FuncType) Equal(t2 *FuncType) bool {
+	return bytes.Equal(valueTypesToBytes(t.Params), valueTypesToBytes(t2.Params)) &&
+		bytes.Equal(valueTypesToBytes(t.Results), valueTypesToBytes(t2.Results))
+}
+
+func valueTypesToBytes(ts []ValueType) []byte {
+	bs := make([]byte, len(ts))
+	for i, t := range ts {
+		bs[i] = byte(t)
+	}
+	return bs
+}
+
+type Import struct {
+	Module string
+	Name   string
+	Type   FuncType
+}
+
+type limits struct {
+	min    uint32
+	max    uint32
+	hasMax bool
+}
+
+type global struct {
+	typ     ValueType
+	mutable bool
+	init    uint64
+}
+
+type elementSegment struct {
+	offset uint32
+	funcs  []uint32
+}
+
+type dataSegment struct {
+	active bool
+	offset uint32
+	data   []byte
+}
+
+type customSection struct {
+	name string
+	data []byte
+}
+
+type Module struct {
+	types     []FuncType
+	imports   []Import
+	funcs     []*function
+	table     *limits
+	memory    *limits
+	globals   []global
+	exports   map[string]uint32
+	elements  []elementSegment
+	data      []dataSegment
+	dataCount *uint32
+	customs   []customSection
+}
+
+const (
+	sectionCustom = iota
+	sectionType
+	sectionImport
+	sectionFunction
+	sectionTable
+	sectionMemory
+	sectionGlobal
+	sectionExport
+	sectionStart
+	sectionElement
+	sectionCode
+	sectionData
+	sectionDataCount
+)
+
+const (
+	externalFunc   = 0x00
+	externalTable  = 0x01
+	externalMemory = 0x02
+	externalGlobal = 0x03
+)
+
+const (
+	// MaxPages is the maximum number of memory pages of an instance.
+	MaxPages = 64
+	// PageSize is the size of a memory page.
+	PageSize = 65536
+
+	maxTableSize     = 1 << 16
+	maxFunctions     = 1 << 16
+	maxLocals        = 1 << 12
+	maxParams        = 1 << 8
+	maxBranchTargets = 1 << 16
+)
+
+var magic = []byte{0x00, 0x61, 0x73, 0x6d, 0x01, 0x00, 0x00, 0x00}
+
+// sectionOrder returns the order of the section. Data count section comes
+// before code section.
+func sectionOrder(id byte) int {
+	switch id {
+	case sectionDataCount:
+		return sectionCode
+	case sectionCode, sectionData:
+		return int(id) + 1
+	default:
+		return int(id)
+	}
+}
+
+// Imports returns imported functions of the module.
+func (m *Module) Imports() []Import {
+	return m.imports
+}
+
+// CustomSection returns the content of the first custom section of the name.
+func (m *Module) CustomSection(name string) ([]byte, bool) {
+	for _, c := range m.customs {
+		if c.name == name {
+			return c.data, true
+		}
+	}
+	return nil, false
+}
+
+// ExportedFunction returns the type of the exported function of the name.
+func (m *Module) ExportedFunction(name string) (*FuncType, bool) {
+	idx, ok := m.exports[name]
+	if !ok || idx >= uint32(len(m.imports)+len(m.funcs)) {
+		return nil, false
+	}
+	return m.funcType(idx), true
+}
+
+func (m *Module) funcType(idx uint32) *FuncType {
+	if idx < uint32(len(m.imports)) {
+		return &m.imports[idx].Type
+	}
+	return &m.types[m.funcs[idx-uint32(len(m.imports))].typeIdx]
+}
+
+func (m *Module) numFuncs() uint32 {
+	return uint32(len(m.imports) + len(m.funcs))
+}
+
+func formatError(f string, args ...interface{}) error {
+	return errors.IllegalArgumentError.Errorf(f, args...)
+}
+
+func unsupportedError(f string, args ...interface{}) error {
+	return errors.UnsupportedError.Errorf(f, args...)
+}
+
+// exportKey returns the key of the export in exports. Only functions are
+// kept with their names, and others are kept for checking duplication.
+func exportKey(kind byte, name string) string {
+	if kind == externalFunc {
+		return name
+	}
+	return string([]byte{0}) + name
+}
+
+// Decode decodes and validates the module, and compiles functions of the
+// module.
+func Decode(code []byte) (*Module, error) {
+	if !bytes.HasPrefix(code, magic) {
+		return nil, formatError("InvalidHeader")
+	}
+	m := &Module{
+		exports: make(map[string]uint32),
+	}
+	r := newReader(code[len(magic):])
+	hasCode := false
+	last := 0
+	for !r.eof() {
+		id, err := r.byte()
+		if err != nil {
+			return nil, err
+		}
+		size, err := r.u32()
+		if err != nil {
+			return nil, err
+		}
+		payload, err := r.bytes(size)
+		if err != nil {
+			return nil, formatError("TruncatedSection(id=%d)", id)
+		}
+		if id > sectionDataCount {
+			return nil, formatError("UnknownSection(id=%d)", id)
+		}
+		if id != sectionCustom {
+			if order := sectionOrder(id); order <= last {
+				return nil, formatError("InvalidSectionOrder(id=%d)", id)
+			} else {
+				last = order
+			}
+		}
+		sr := newReader(payload)
+		switch id {
+		case sectionCustom:
+			err = m.decodeCustom(sr)
+		case sectionType:
+			err = m.decodeTypes(sr)
+		case sectionImport:
+			err = m.decodeImports(sr)
+		case sectionFunction:
+			err = m.decodeFunctions(sr)
+		case sectionTable:
+			err = m.decodeTable(sr)
+		case sectionMemory:
+			err = m.decodeMemory(sr)
+		case sectionGlobal:
+			err = m.decodeGlobals(sr)
+		case sectionExport:
+			err = m.decodeExports(sr)
+		case sectionStart:
+			err = unsupportedError("StartFunctionNotAllowed")
+		case sectionElement:
+			err = m.decodeElements(sr)
+		case sectionCode:
+			err = m.decodeCodes(sr)
+			hasCode = true
+		case sectionData:
+			err = m.decodeData(sr)
+		case sectionDataCount:
+			err = m.decodeDataCount(sr)
+		}
+		if err != nil {
+			return nil, err
+		}
+		if id != sectionCustom && !sr.eof() {
+			return nil, formatError("SectionSizeMismatch(id=%d)", id)
+		}
+	}
+	if len(m.funcs) > 0 && !hasCode {
+		return nil, formatError("NoCodeForFunctions")
+	}
+	if m.dataCount != nil && int(*m.dataCount) != len(m.data) {
+		return nil, formatError("DataCountMismatch")
+	}
+	return m, nil
+}
+
+func (m *Module) decodeCustom(r *reader) error {
+	name, err := r.name()
+	if err != nil {
+		return err
+	}
+	m.customs = append(m.customs, customSection{name, r.rest()})
+	return nil
+}
+
+func (r *reader) valueType() (ValueType, error) {
+	b, err := r.byte()
+	if err != nil {
+		return 0, err
+	}
+	switch t := ValueType(b); t {
+	case I32, I64:
+		return t, nil
+	case typeF32, typeF64:
+		return 0, unsupportedError("FloatNotAllowed")
+	default:
+		return 0, unsupportedError("UnsupportedValueType(type=%#x)", b)
+	}
+}
+
+func (r *reader) valueTypes(limit int) ([]ValueType, error) {
+	n, err := r.u32()
+	if err != nil {
+		return nil, err
+	}
+	if n > uint32(limit) {
+		return nil, unsupportedError("TooManyValues(n=%d)", n)
+	}
+	ts := make([]ValueType, n)
+	for i := range ts {
+		if ts[i], err = r.valueType(); err != nil {
+			return nil, err
+		}
+	}
+	return ts, nil
+}
+
+func (r *reader) funcType() (FuncType, error) {
+	var ft FuncType
+	if b, err := r.byte(); err != nil {
+		return ft, err
+	} else if b != 0x60 {
+		return ft, formatError("InvalidFuncType(tag=%#x)", b)
+	}
+	var err error
+	if ft.Params, err = r.valueTypes(maxParams); err != nil {
+		return ft, err
+	}
+	if ft.Results, err = r.valueTypes(maxParams); err != nil {
+		return ft, err
+	}
+	return ft, nil
+}
+
+func (m *Module) decodeTypes(r *reader) error {
+	n, err := r.u32()
+	if err != nil {
+		return err
+	}
+	if n > maxFunctions {
+		return unsupportedError("TooManyTypes(n=%d)", n)
+	}
+	m.types = make([]FuncType, n)
+	for i := range m.types {
+		if m.types[i], err = r.funcType(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (m *Module) typeOf(idx uint32) (FuncType, error) {
+	if idx >= uint32(len(m.types)) {
+		return FuncType{}, formatError("InvalidTypeIndex(idx=%d)", idx)
+	}
+	return m.types[idx], nil
+}
+
+func (m *Module) decodeImports(r *reader) error {
+	n, err := r.u32()
+	if err != nil {
+		return err
+	}
+	if n > maxFunctions {
+		return unsupportedError("TooManyImports(n=%d)", n)
+	}
+	for i := uint32(0); i < n; i++ {
+		var im Import
+		if im.Module, err = r.name(); err != nil {
+			return err
+		}
+		if im.Name, err = r.name(); err != nil {
+			return err
+		}
+		kind, err := r.byte()
+		if err != nil {
+			return err
+		}
+		if kind != externalFunc {
+			return unsupportedError("UnsupportedImport(module=%s,name=%s,kind=%d)",
+				im.Module, im.Name, kind)
+		}
+		idx, err := r.u32()
+		if err != nil {
+			return err
+		}
+		if im.Type, err = m.typeOf(idx); err != nil {
+			return err
+		}
+		m.imports = append(m.imports, im)
+	}
+	return nil
+}
+
+// decodeFunctions decodes types of the functions. Codes of them are compiled
+// with code section.
+func (m *Module) decodeFunctions(r *reader) error {
+	n, err := r.u32()
+	if err != nil {
+		return err
+	}
+	if uint64(n)+uint64(len(m.imports)) > maxFunctions {
+		return unsupportedError("TooManyFunctions(n=%d)", n)
+	}
+	m.funcs = make([]*function, n)
+	for i := range m.funcs {
+		idx, err := r.u32()
+		if err != nil {
+			return err
+		}
+		if _, err := m.typeOf(idx); err != nil {
+			return err
+		}
+		m.funcs[i] = &function{typeIdx: idx}
+	}
+	return nil
+}
+
+func (r *reader) limits(limit uint32) (*limits, error) {
+	flag, err := r.byte()
+	if err != nil {
+		return nil, err
+	}
+	l := new(limits)
+	switch flag {
+	case 0x00:
+		l.min, err = r.u32()
+	case 0x01:
+		if l.min, err = r.u32(); err == nil {
+			l.max, err = r.u32()
+			l.hasMax = true
+		}
+	default:
+		return nil, unsupportedError("UnsupportedLimits(flag=%#x)", flag)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if l.hasMax && l.max < l.min {
+		return nil, formatError("InvalidLimits(min=%d,max=%d)", l.min, l.max)
+	}
+	if l.min > limit {
+		return nil, unsupportedError("LimitsTooLarge(min=%d,limit=%d)", l.min, limit)
+	}
+	return l, nil
+}
+
+func (m *Module) decodeTable(r *reader) error {
+	n, err := r.u32()
+	if err != nil {
+		return err
+	}
+	if n > 1 {
+		return unsupportedError("MultipleTables")
+	}
+	if n == 1 {
+		if t, err := r.byte(); err != nil {
+			return err
+		} else if ValueType(t) != typeFuncRef {
+			return unsupportedError("UnsupportedTableType(type=%#x)", t)
+		}
+		if m.table, err = r.limits(maxTableSize); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (m *Module) decodeMemory(r *reader) error {
+	n, err := r.u32()
+	if err != nil {
+		return err
+	}
+	if n > 1 {
+		return unsupportedError("MultipleMemories")
+	}
+	if n == 1 {
+		if m.memory, err = r.limits(MaxPages); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// constExpr reads the constant expression of the type. Only constant
+// instructions are allowed since the module can't import globals.
+func (r *reader) constExpr(t ValueType) (uint64, error) {
+	op, err := r.byte()
+	if err != nil {
+		return 0, err
+	}
+	var value uint64
+	switch {
+	case op == opI32Const && t == I32:
+		var v int32
+		v, err = r.s32()
+		value = uint64(uint32(v))
+	case op == opI64Const && t == I64:
+		var v int64
+		v, err = r.s64()
+		value = uint64(v)
+	default:
+		return 0, unsupportedError("UnsupportedConstExpr(op=%#x)", op)
+	}
+	if err != nil {
+		return 0, err
+	}
+	if end, err := r.byte(); err != nil {
+		return 0, err
+	} else if end != opEnd {
+		return 0, formatError("InvalidConstExpr")
+	}
+	return value, nil
+}
+
+func (m *Module) decodeGlobals(r *reader) error {
+	n, err := r.u32()
+	if err != nil {
+		return err
+	}
+	if n > maxFunctions {
+		return unsupportedError("TooManyGlobals(n=%d)", n)
+	}
+	m.globals = make([]global, n)
+	for i := range m.globals {
+		g := &m.globals[i]
+		if g.typ, err = r.valueType(); err != nil {
+			return err
+		}
+		mut, err := r.byte()
+		if err != nil {
+			return err
+		}
+		if mut > 1 {
+			return formatError("InvalidMutability(%d)", mut)
+		}
+		g.mutable = mut == 1
+		if g.init, err = r.constExpr(g.typ); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (m *Module) decodeExports(r *reader) error {
+	n, err := r.u32()
+	if err != nil {
+		return err
+	}
+	if n > maxFunctions {
+		return unsupportedError("TooManyExports(n=%d)", n)
+	}
+	names := make(map[string]bool)
+	for i := uint32(0); i < n; i++ {
+		name, err := r.name()
+		if err != nil {
+			return err
+		}
+		if names[name] {
+			return formatError("DuplicateExport(name=%s)", name)
+		}
+		names[name] = true
+		kind, err := r.byte()
+		if err != nil {
+			return err
+		}
+		idx, err := r.u32()
+		if err != nil {
+			return err
+		}
+		switch kind {
+		case externalFunc:
+			if idx >= m.numFuncs() {
+				return formatError("InvalidFuncIndex(idx=%d)", idx)
+			}
+			m.exports[name] = idx
+		case externalTable:
+			if m.table == nil || idx != 0 {
+				return formatError("InvalidTableIndex(idx=%d)", idx)
+			}
+		case externalMemory:
+			if m.memory == nil || idx != 0 {
+				return formatError("InvalidMemoryIndex(idx=%d)", idx)
+			}
+		case externalGlobal:
+			if idx >= uint32(len(m.globals)) {
+				return formatError("InvalidGlobalIndex(idx=%d)", idx)
+			}
+		default:
+			return formatError("InvalidExportKind(kind=%d)", kind)
+		}
+	}
+	return nil
+}
+
+func (m *Module) decodeElements(r *reader) error {
+	n, err := r.u32()
+	if err != nil {
+		return err
+	}
+	if n > maxFunctions {
+		return unsupportedError("TooManyElements(n=%d)", n)
+	}
+	for i := uint32(0); i < n; i++ {
+		flag, err := r.u32()
+		if err != nil {
+			return err
+		}
+		if flag != 0 {
+			return unsupportedError("UnsupportedElementSegment(flag=%d)", flag)
+		}
+		if m.table == nil {
+			return formatError("NoTableForElements")
+		}
+		offset, err := r.constExpr(I32)
+		if err != nil {
+			return err
+		}
+		cnt, err := r.u32()
+		if err != nil {
+			return err
+		}
+		if cnt > maxTableSize {
+			return unsupportedError("TooManyElements(n=%d)", cnt)
+		}
+		seg := elementSegment{
+			offset: uint32(offset),
+			funcs:  make([]uint32, cnt),
+		}
+		for j := range seg.funcs {
+			if seg.funcs[j], err = r.u32(); err != nil {
+				return err
+			}
+			if seg.funcs[j] >= m.numFuncs() {
+				return formatError("InvalidFuncIndex(idx=%d)", seg.funcs[j])
+			}
+		}
+		m.elements = append(m.elements, seg)
+	}
+	return nil
+}
+
+func (m *Module) decodeCodes(r *reader) error {
+	n, err := r.u32()
+	if err != nil {
+		return err
+	}
+	if n != uint32(len(m.funcs)) {
+		return formatError("FunctionCountMismatch(funcs=%d,codes=%d)", len(m.funcs), n)
+	}
+	bodies := make([][]byte, n)
+	for i := range bodies {
+		size, err := r.u32()
+		if err != nil {
+			return err
+		}
+		if bodies[i], err = r.bytes(size); err != nil {
+			return err
+		}
+	}
+	for i, f := range m.funcs {
+		if err := m.compile(f, bodies[i]); err != nil {
+			return errors.Wrapf(err, "InvalidFunction(idx=%d)", len(m.imports)+i)
+		}
+	}
+	return nil
+}
+
+func (m *Module) decodeData(r *reader) error {
+	n, err := r.u32()
+	if err != nil {
+		return err
+	}
+	if n > maxFunctions {
+		return unsupportedError("TooManyDataSegments(n=%d)", n)
+	}
+	for i := uint32(0); i < n; i++ {
+		flag, err := r.u32()
+		if err != nil {
+			return err
+		}
+		var seg dataSegment
+		switch flag {
+		case 0, 2:
+			if flag == 2 {
+				if idx, err := r.u32(); err != nil {
+					return err
+				} else if idx != 0 {
+					return formatError("InvalidMemoryIndex(idx=%d)", idx)
+				}
+			}
+			if m.memory == nil {
+				return formatError("NoMemoryForData")
+			}
+			offset, err := r.constExpr(I32)
+			if err != nil {
+				return err
+			}
+			seg.active = true
+			seg.offset = uint32(offset)
+		case 1:
+		default:
+			return formatError("InvalidDataSegment(flag=%d)", flag)
+		}
+		size, err := r.u32()
+		if err != nil {
+			return err
+		}
+		if seg.data, err = r.bytes(size); err != nil {
+			return err
+		}
+		m.data = append(m.data, seg)
+	}
+	return nil
+}
+
+func (m *Module) decodeDataCount(r *reader) error {
+	n, err := r.u32()
+	if err != nil {
+		return err
+	}
+	m.dataCount = &n
+	return nil
+}
+
+type reader struct {
+	buf []byte
+	pos int
+}
+
+func newReader(bs []byte) *reader {
+	return &reader{buf: bs}
+}
+
+func (r *reader) eof() bool {
+	return r.pos >= len(r.buf)
+}
+
+func (r *reader) rest() []byte {
+	bs := r.buf[r.pos:]
+	r.pos = len(r.buf)
+	return bs
+}
+
+func (r *reader) byte() (byte, error) {
+	if r.pos >= len(r.buf) {
+		return 0, formatError("UnexpectedEnd")
+	}
+	b := r.buf[r.pos]
+	r.pos += 1
+	return b, nil
+}
+
+func (r *reader) bytes(n uint32) ([]byte, error) {
+	if uint64(n) > uint64(len(r.buf)-r.pos) {
+		return nil, formatError("UnexpectedEnd")
+	}
+	bs := r.buf[r.pos : r.pos+int(n)]
+	r.pos += int(n)
+	return bs, nil
+}
+
+func (r *reader) name() (string, error) {
+	n, err := r.u32()
+	if err != nil {
+		return "", err
+	}
+	bs, err := r.bytes(n)
+	if err != nil {
+		return "", err
+	}
+	if !utf8.Valid(bs) {
+		return "", formatError("InvalidName")
+	}
+	return string(bs), nil
+}
+
+// uleb reads unsigned LEB128 value of the bits.
+func (r *reader) uleb(bits uint) (uint64, error) {
+	var value uint64
+	for shift := uint(0); ; shift += 7 {
+		b, err := r.byte()
+		if err != nil {
+			return 0, err
+		}
+		value |= uint64(b&0x7f) << shift
+		if shift+7 >= bits {
+			// last byte can't have continuation and unused bits.
+			if b&0x80 != 0 || b>>(bits-shift) != 0 {
+				return 0, formatError("InvalidInteger")
+			}
+			return value, nil
+		}
+		if b&0x80 == 0 {
+			return value, nil
+		}
+	}
+}
+
+// sleb reads signed LEB128 value of the bits.
+func (r *reader) sleb(bits uint) (int64, error) {
+	var value int64
+	for shift := uint(0); ; shift += 7 {
+		b, err := r.byte()
+		if err != nil {
+			return 0, err
+		}
+		value |= int64(b&0x7f) << shift
+		last := shift+7 >= bits
+		if last {
+			// last byte can't have continuation, and unused bits should
+			// be same as the sign bit.
+			mask := byte(0x7f) >> (bits - shift - 1) << (bits - shift - 1)
+			if b&0x80 != 0 || (b&mask != 0 && b&mask != mask) {
+				return 0, formatError("InvalidInteger")
+			}
+		}
+		if last || b&0x80 == 0 {
+			if shift+7 < 64 && b&0x40 != 0 {
+				value |= -1 << (shift + 7)
+			}
+			return value, nil
+		}
+	}
+}
+
+func (r *reader) u32() (uint32, error) {
+	v, err := r.uleb(32)
+	return uint32(v), err
+}
+
+func (r *reader) s32() (int32, error) {
+	v, err := r.sleb(32)
+	return int32(v), err
+}
+
+func (r *reader) s33() (int64, error) {
+	return r.sleb(33)
+}
+
+func (r *reader) s64() (int64, error) {
+	return r.sleb(64)
+}
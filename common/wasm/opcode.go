@@ -0,0 +1,215 @@
+/*
+ * Copyright 2026 ICON Foundation
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package wasm
+
+const (
+	opUnreachable  = 0x00
+	opNop          = 0x01
+	opBlock        = 0x02
+	opLoop         = 0x03
+	opIf           = 0x04
+	opElse         = 0x05
+	opEnd          = 0x0b
+	opBr           = 0x0c
+	opBrIf         = 0x0d
+	opBrTable      = 0x0e
+	opReturn       = 0x0f
+	opCall         = 0x10
+	opCallIndirect = 0x11
+
+	opDrop        = 0x1a
+	opSelect      = 0x1b
+	opSelectTyped = 0x1c
+	opLocalGet    = 0x20
+	opLocalSet    = 0x21
+	opLocalTee    = 0x22
+	opGlobalGet   = 0x23
+	opGlobalSet   = 0x24
+
+	opI32Load    = 0x28
+	opI64Load    = 0x29
+	opI32Load8S  = 0x2c
+	opI32Load8U  = 0x2d
+	opI32Load16S = 0x2e
+	opI32Load16U = 0x2f
+	opI64Load8S  = 0x30
+	opI64Load8U  = 0x31
+	opI64Load16S = 0x32
+	opI64Load16U = 0x33
+	opI64Load32S = 0x34
+	opI64Load32U = 0x35
+	opI32Store   = 0x36
+	opI64Store   = 0x37
+	opI32Store8  = 0x3a
+	opI32Store16 = 0x3b
+	opI64Store8  = 0x3c
+	opI64Store16 = 0x3d
+	opI64Store32 = 0x3e
+	opMemorySize = 0x3f
+	opMemoryGrow = 0x40
+
+	opI32Const = 0x41
+	opI64Const = 0x42
+
+	opI32Eqz = 0x45
+	opI32Eq  = 0x46
+	opI32Ne  = 0x47
+	opI32LtS = 0x48
+	opI32LtU = 0x49
+	opI32GtS = 0x4a
+	opI32GtU = 0x4b
+	opI32LeS = 0x4c
+	opI32LeU = 0x4d
+	opI32GeS = 0x4e
+	opI32GeU = 0x4f
+
+	opI64Eqz = 0x50
+	opI64Eq  = 0x51
+	opI64Ne  = 0x52
+	opI64LtS = 0x53
+	opI64LtU = 0x54
+	opI64GtS = 0x55
+	opI64GtU = 0x56
+	opI64LeS = 0x57
+	opI64LeU = 0x58
+	opI64GeS = 0x59
+	opI64GeU = 0x5a
+
+	opI32Clz    = 0x67
+	opI32Ctz    = 0x68
+	opI32Popcnt = 0x69
+	opI32Add    = 0x6a
+	opI32Sub    = 0x6b
+	opI32Mul    = 0x6c
+	opI32DivS   = 0x6d
+	opI32DivU   = 0x6e
+	opI32RemS   = 0x6f
+	opI32RemU   = 0x70
+	opI32And    = 0x71
+	opI32Or     = 0x72
+	opI32Xor    = 0x73
+	opI32Shl    = 0x74
+	opI32ShrS   = 0x75
+	opI32ShrU   = 0x76
+	opI32Rotl   = 0x77
+	opI32Rotr   = 0x78
+
+	opI64Clz    = 0x79
+	opI64Ctz    = 0x7a
+	opI64Popcnt = 0x7b
+	opI64Add    = 0x7c
+	opI64Sub    = 0x7d
+	opI64Mul    = 0x7e
+	opI64DivS   = 0x7f
+	opI64DivU   = 0x80
+	opI64RemS   = 0x81
+	opI64RemU   = 0x82
+	opI64And    = 0x83
+	opI64Or     = 0x84
+	opI64Xor    = 0x85
+	opI64Shl    = 0x86
+	opI64ShrS   = 0x87
+	opI64ShrU   = 0x88
+	opI64Rotl   = 0x89
+	opI64Rotr   = 0x8a
+
+	opI32WrapI64    = 0xa7
+	opI64ExtendI32S = 0xac
+	opI64ExtendI32U = 0xad
+	opI32Extend8S   = 0xc0
+	opI32Extend16S  = 0xc1
+	opI64Extend8S   = 0xc2
+	opI64Extend16S  = 0xc3
+	opI64Extend32S  = 0xc4
+
+	opPrefixMisc = 0xfc
+)
+
+// Operators with opPrefixMisc. They are compiled into the operators below,
+// which are not used by other operators.
+const (
+	miscMemoryInit = 0x08
+	miscDataDrop   = 0x09
+	miscMemoryCopy = 0x0a
+	miscMemoryFill = 0x0b
+
+	opMemoryInit = 0xe0
+	opDataDrop   = 0xe1
+	opMemoryCopy = 0xe2
+	opMemoryFill = 0xe3
+)
+
+type memoryOp struct {
+	size  uint32
+	typ   ValueType
+	store bool
+}
+
+var memoryOps = map[byte]memoryOp{
+	opI32Load:    {4, I32, false},
+	opI64Load:    {8, I64, false},
+	opI32Load8S:  {1, I32, false},
+	opI32Load8U:  {1, I32, false},
+	opI32Load16S: {2, I32, false},
+	opI32Load16U: {2, I32, false},
+	opI64Load8S:  {1, I64, false},
+	opI64Load8U:  {1, I64, false},
+	opI64Load16S: {2, I64, false},
+	opI64Load16U: {2, I64, false},
+	opI64Load32S: {4, I64, false},
+	opI64Load32U: {4, I64, false},
+	opI32Store:   {4, I32, true},
+	opI64Store:   {8, I64, true},
+	opI32Store8:  {1, I32, true},
+	opI32Store16: {2, I32, true},
+	opI64Store8:  {1, I64, true},
+	opI64Store16: {2, I64, true},
+	opI64Store32: {4, I64, true},
+}
+
+type numericOp struct {
+	params []ValueType
+	result ValueType
+}
+
+var (
+	unaryI32   = []ValueType{I32}
+	unaryI64   = []ValueType{I64}
+	binaryI32  = []ValueType{I32, I32}
+	binaryI64  = []ValueType{I64, I64}
+	numericOps = make(map[byte]numericOp)
+)
+
+func init() {
+	add := func(from, to byte, params []ValueType, result ValueType) {
+		for op := int(from); op <= int(to); op++ {
+			numericOps[byte(op)] = numericOp{params, result}
+		}
+	}
+	add(opI32Eqz, opI32Eqz, unaryI32, I32)
+	add(opI32Eq, opI32GeU, binaryI32, I32)
+	add(opI64Eqz, opI64Eqz, unaryI64, I32)
+	add(opI64Eq, opI64GeU, binaryI64, I32)
+	add(opI32Clz, opI32Popcnt, unaryI32, I32)
+	add(opI32Add, opI32Rotr, binaryI32, I32)
+	add(opI64Clz, opI64Popcnt, unaryI64, I64)
+	add(opI64Add, opI64Rotr, binaryI64, I64)
+	add(opI32WrapI64, opI32WrapI64, unaryI64, I32)
+	add(opI64ExtendI32S, opI64ExtendI32U, unaryI32, I64)
+	add(opI32Extend8S, opI32Extend16S, unaryI32, I32)
+	add(opI64Extend8S, opI64Extend32S, unaryI64, I64)
+}
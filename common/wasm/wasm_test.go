@@ -0,0 +1,305 @@
+package wasm
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/icon-project/goloop/common/errors"
+)
+
+func uleb(v uint64) []byte {
+	var bs []byte
+	for {
+		b := byte(v & 0x7f)
+		v >>= 7
+		if v == 0 {
+			return append(bs, b)
+		}
+		bs = append(bs, b|0x80)
+	}
+}
+
+func vec(items ...[]byte) []byte {
+	return append(uleb(uint64(len(items))), bytes.Join(items, nil)...)
+}
+
+func str(s string) []byte {
+	return append(uleb(uint64(len(s))), s...)
+}
+
+func section(id byte, items ...[]byte) []byte {
+	content := vec(items...)
+	return append(append([]byte{id}, uleb(uint64(len(content)))...), content...)
+}
+
+func funcType(params, results []byte) []byte {
+	return append(append([]byte{0x60}, vec(splitBytes(params)...)...),
+		vec(splitBytes(results)...)...)
+}
+
+func splitBytes(bs []byte) [][]byte {
+	var items [][]byte
+	for _, b := range bs {
+		items = append(items, []byte{b})
+	}
+	return items
+}
+
+func body(locals [][]byte, code ...byte) []byte {
+	bs := append(vec(locals...), code...)
+	return append(uleb(uint64(len(bs))), bs...)
+}
+
+func export(name string, idx byte) []byte {
+	return append(str(name), externalFunc, idx)
+}
+
+func module(sections ...[]byte) []byte {
+	return append(append([]byte(nil), magic...), bytes.Join(sections, nil)...)
+}
+
+// funcModule returns a module of the functions of the type exported as
+// f0, f1, ... in order.
+func funcModule(ft []byte, bodies ...[]byte) []byte {
+	var funcs, exports [][]byte
+	for i := range bodies {
+		funcs = append(funcs, []byte{0})
+		exports = append(exports, export("f"+string(rune('0'+i)), byte(i)))
+	}
+	return module(
+		section(sectionType, ft),
+		section(sectionFunction, funcs...),
+		section(sectionMemory, []byte{0x01, 0x01, 0x02}),
+		section(sectionExport, exports...),
+		section(sectionCode, bodies...),
+	)
+}
+
+func instantiate(t *testing.T, code []byte, imports Imports, limit int64) *Instance {
+	m, err := Decode(code)
+	if !assert.NoError(t, err) {
+		t.FailNow()
+	}
+	in, err := NewInstance(m, imports, limit)
+	if !assert.NoError(t, err) {
+		t.FailNow()
+	}
+	return in
+}
+
+func TestInstance_Call(t *testing.T) {
+	// factorial(n i64) i64
+	factorial := body(nil,
+		0x20, 0, 0x50, 0x04, 0x7e, 0x42, 1, 0x05,
+		0x20, 0, 0x20, 0, 0x42, 1, 0x7d, 0x10, 0, 0x7e,
+		0x0b, 0x0b)
+	in := instantiate(t, funcModule(funcType([]byte{0x7e}, []byte{0x7e}), factorial), nil, 1000)
+	ret, err := in.Call("f0", 10)
+	assert.NoError(t, err)
+	assert.Equal(t, []uint64{3628800}, ret)
+	used := in.StepUsed()
+	assert.True(t, used > 0)
+
+	// the same call uses the same steps
+	ret, err = in.Call("f0", 10)
+	assert.NoError(t, err)
+	assert.Equal(t, []uint64{3628800}, ret)
+	assert.Equal(t, used*2, in.StepUsed())
+
+	// sum(n i32) i32 with loop
+	sum := body([][]byte{{1, 0x7f}},
+		0x02, 0x40, 0x03, 0x40,
+		0x20, 0, 0x45, 0x0d, 1,
+		0x20, 1, 0x20, 0, 0x6a, 0x21, 1,
+		0x20, 0, 0x41, 1, 0x6b, 0x21, 0,
+		0x0c, 0,
+		0x0b, 0x0b, 0x20, 1, 0x0b)
+	// switch(n i32) i32 with br_table
+	switchTo := body(nil,
+		0x02, 0x40, 0x02, 0x40, 0x02, 0x40,
+		0x20, 0, 0x0e, 2, 0, 1, 2, 0x0b,
+		0x41, 10, 0x0f, 0x0b,
+		0x41, 20, 0x0f, 0x0b,
+		0x41, 30, 0x0b)
+	// div(n i32) i32 : 100 / n with a value kept by br_if
+	div := body(nil,
+		0x02, 0x7f, 0x41, 0xe4, 0x00, 0x20, 0, 0x6d,
+		0x41, 1, 0x0d, 0, 0x1a, 0x41, 9, 0x0b, 0x0b)
+	in = instantiate(t, funcModule(funcType([]byte{0x7f}, []byte{0x7f}), sum, switchTo, div), nil, 10000)
+
+	ret, err = in.Call("f0", 100)
+	assert.NoError(t, err)
+	assert.Equal(t, []uint64{5050}, ret)
+
+	for n, exp := range map[uint64]uint64{0: 10, 1: 20, 2: 30, 100: 30} {
+		ret, err = in.Call("f1", n)
+		assert.NoError(t, err)
+		assert.Equal(t, []uint64{exp}, ret)
+	}
+
+	ret, err = in.Call("f2", 0xffffffff)
+	assert.NoError(t, err)
+	assert.Equal(t, []uint64{uint64(uint32(0xffffff9c))}, ret)
+
+	_, err = in.Call("f2", 0)
+	assert.True(t, errors.Is(err, ErrDivideByZero))
+
+	_, err = in.Call("f3", 0)
+	assert.True(t, errors.Is(err, ErrFunctionNotFound))
+}
+
+func TestInstance_Limits(t *testing.T) {
+	loop := body(nil, 0x03, 0x40, 0x0c, 0, 0x0b, 0x0b)
+	recursive := body(nil, 0x10, 1, 0x0b)
+	grow := body(nil, 0x41, 1, 0x40, 0, 0x1a, 0x41, 1, 0x40, 0, 0x1a, 0x0b)
+	load := body(nil, 0x41, 0x80, 0x80, 0x04, 0x28, 2, 0, 0x1a, 0x0b)
+	code := funcModule(funcType(nil, nil), loop, recursive, grow, load)
+
+	in := instantiate(t, code, nil, 1000)
+	_, err := in.Call("f0")
+	assert.True(t, errors.Is(err, ErrOutOfStep))
+	assert.EqualValues(t, 1000, in.StepUsed())
+
+	in = instantiate(t, code, nil, 1000000)
+	_, err = in.Call("f1")
+	assert.True(t, errors.Is(err, ErrStackOverflow))
+
+	// growing memory charges steps for the pages
+	in = instantiate(t, code, nil, 1000000)
+	_, err = in.Call("f2")
+	assert.NoError(t, err)
+	assert.True(t, in.StepUsed() > stepsPerPage)
+	assert.Equal(t, 2*PageSize, len(in.memory))
+
+	_, err = in.Call("f3")
+	assert.NoError(t, err)
+
+	in = instantiate(t, code, nil, 1000000)
+	_, err = in.Call("f3")
+	assert.True(t, errors.Is(err, ErrOutOfBounds))
+
+	in = instantiate(t, code, nil, 1000000)
+	in.Abort()
+	_, err = in.Call("f0")
+	assert.True(t, errors.Is(err, ErrAborted))
+}
+
+func TestInstance_Host(t *testing.T) {
+	code := module(
+		section(sectionType,
+			funcType([]byte{0x7f, 0x7f}, []byte{0x7f}),
+			funcType(nil, nil)),
+		section(sectionImport, append(append(str("env"), str("add")...), externalFunc, 0)),
+		section(sectionFunction, []byte{1}),
+		section(sectionMemory, []byte{0x00, 0x01}),
+		section(sectionExport, export("run", 1)),
+		section(sectionCode, body(nil,
+			0x41, 0, 0x41, 2, 0x41, 3, 0x10, 0, 0x36, 2, 0, 0x0b)),
+		section(sectionData, append([]byte{0, 0x41, 16, 0x0b}, str("hello")...)),
+	)
+	m, err := Decode(code)
+	assert.NoError(t, err)
+	assert.Equal(t, []Import{{
+		Module: "env", Name: "add",
+		Type: FuncType{[]ValueType{I32, I32}, []ValueType{I32}},
+	}}, m.Imports())
+
+	_, err = NewInstance(m, nil, 1000)
+	assert.Error(t, err)
+
+	add := &HostFunction{
+		Type: FuncType{[]ValueType{I32, I32}, []ValueType{I32}},
+		Call: func(in *Instance, args []uint64) ([]uint64, error) {
+			if err := in.Charge(100); err != nil {
+				return nil, err
+			}
+			return []uint64{args[0] + args[1]}, nil
+		},
+	}
+	in, err := NewInstance(m, Imports{"env": {"add": add}}, 1000)
+	assert.NoError(t, err)
+	_, err = in.Call("run")
+	assert.NoError(t, err)
+	bs, err := in.Read(0, 4)
+	assert.NoError(t, err)
+	assert.Equal(t, []byte{5, 0, 0, 0}, bs)
+	bs, err = in.Read(16, 5)
+	assert.NoError(t, err)
+	assert.Equal(t, []byte("hello"), bs)
+	assert.True(t, in.StepUsed() > 100)
+
+	_, err = in.Read(PageSize-1, 2)
+	assert.Error(t, err)
+
+	errHost := errors.New("HostError")
+	add.Call = func(in *Instance, args []uint64) ([]uint64, error) {
+		return nil, errHost
+	}
+	in, err = NewInstance(m, Imports{"env": {"add": add}}, 1000)
+	assert.NoError(t, err)
+	_, err = in.Call("run")
+	assert.Equal(t, errHost, err)
+}
+
+func TestDecode_Invalid(t *testing.T) {
+	ft := funcType(nil, []byte{0x7f})
+	cases := map[string][]byte{
+		"header": []byte("\x00asm\x02\x00\x00\x00"),
+		"float":  module(section(sectionType, funcType([]byte{0x7d}, nil))),
+		"start": module(
+			section(sectionType, funcType(nil, nil)),
+			section(sectionFunction, []byte{0}),
+			[]byte{sectionStart, 1, 0},
+			section(sectionCode, body(nil, 0x0b)),
+		),
+		"importMemory": module(
+			section(sectionImport, append(append(str("env"), str("memory")...), externalMemory, 0, 1)),
+		),
+		"order": module(
+			section(sectionFunction),
+			section(sectionType),
+		),
+		"floatOp":      funcModule(ft, body(nil, 0x41, 1, 0x41, 1, 0x92, 0x0b)),
+		"typeMismatch": funcModule(ft, body(nil, 0x41, 1, 0x42, 1, 0x6a, 0x0b)),
+		"underflow":    funcModule(ft, body(nil, 0x6a, 0x0b)),
+		"noResult":     funcModule(ft, body(nil, 0x0b)),
+		"label":        funcModule(ft, body(nil, 0x0c, 1, 0x0b)),
+		"local":        funcModule(ft, body(nil, 0x20, 0, 0x0b)),
+		"noEnd":        funcModule(ft, body(nil, 0x41, 1)),
+		"noCode": module(
+			section(sectionType, ft),
+			section(sectionFunction, []byte{0}),
+		),
+	}
+	for name, code := range cases {
+		t.Run(name, func(t *testing.T) {
+			_, err := Decode(code)
+			assert.Error(t, err)
+		})
+	}
+}
+
+func TestDecode_CustomSection(t *testing.T) {
+	code := module(
+		append([]byte{sectionCustom, 8}, append(str("api"), "data"...)...),
+		section(sectionType, funcType(nil, nil)),
+		section(sectionFunction, []byte{0}),
+		section(sectionExport, export("run", 0)),
+		section(sectionCode, body(nil, 0x0b)),
+	)
+	m, err := Decode(code)
+	assert.NoError(t, err)
+	data, ok := m.CustomSection("api")
+	assert.True(t, ok)
+	assert.Equal(t, []byte("data"), data)
+	_, ok = m.CustomSection("none")
+	assert.False(t, ok)
+
+	ft, ok := m.ExportedFunction("run")
+	assert.True(t, ok)
+	assert.Equal(t, 0, len(ft.Params))
+	_, ok = m.ExportedFunction("none")
+	assert.False(t, ok)
+}
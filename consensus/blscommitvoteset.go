@@ -0,0 +1,333 @@
+/*
+ * Copyright 2026 ICON Foundation
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package consensus
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"sort"
+
+	"github.com/icon-project/goloop/btp/ntm"
+	"github.com/icon-project/goloop/common"
+	"github.com/icon-project/goloop/common/codec"
+	"github.com/icon-project/goloop/common/crypto"
+	"github.com/icon-project/goloop/common/db"
+	"github.com/icon-project/goloop/common/errors"
+	"github.com/icon-project/goloop/module"
+	"github.com/icon-project/goloop/service"
+)
+
+// blsCommitVoteSetPrefix is the first byte of serialized BLSCommitVoteSet.
+// Serialized CommitVoteList is an RLP list, so it never starts with it.
+const blsCommitVoteSetPrefix byte = 0x01
+
+// BLSKeyContext provides revisions and BLS public keys of validators for
+// states of block results.
+type BLSKeyContext interface {
+	GetRevision(result []byte) module.Revision
+	GetBLSPublicKey(result []byte, addr module.Address) ([]byte, error)
+}
+
+type blsChain interface {
+	Database() db.Database
+	ServiceManager() module.ServiceManager
+}
+
+type chainBLSKeyContext struct {
+	c blsChain
+}
+
+func (ctx *chainBLSKeyContext) GetRevision(result []byte) module.Revision {
+	sm := ctx.c.ServiceManager()
+	if sm == nil {
+		return module.NoRevision
+	}
+	return sm.GetRevision(result)
+}
+
+func (ctx *chainBLSKeyContext) GetBLSPublicKey(result []byte, addr module.Address) ([]byte, error) {
+	bc, err := service.NewBTPContext(ctx.c.Database(), result)
+	if err != nil {
+		return nil, err
+	}
+	return bc.GetPublicKey(addr, ntm.BLSDSA), nil
+}
+
+// NewBLSKeyContext returns BLSKeyContext reading BLS public keys registered
+// in BTP states of the chain.
+func NewBLSKeyContext(c blsChain) BLSKeyContext {
+	return &chainBLSKeyContext{c}
+}
+
+type blsBlockCommitVoteSet struct {
+	Round                    int32
+	BlockPartSetIDAndAppData *PartSetIDAndAppData
+	Signers                  *BitArray
+	Timestamps               []int64
+	Signature                []byte
+}
+
+// BLSCommitVoteSet is a commit vote set with an aggregated BLS signature.
+// Signers has a bit for each validator, and Timestamps has a timestamp for
+// each signer in the order of validators.
+type BLSCommitVoteSet struct {
+	blsBlockCommitVoteSet
+	NTSDProves [][]byte
+
+	ctx        BLSKeyContext
+	bytes      []byte
+	blockBytes []byte
+}
+
+func (vs *BLSCommitVoteSet) RLPEncodeSelf(e codec.Encoder) error {
+	if len(vs.NTSDProves) == 0 {
+		return e.EncodeListOf(&vs.blsBlockCommitVoteSet)
+	}
+	return e.EncodeListOf(&vs.blsBlockCommitVoteSet, vs.NTSDProves)
+}
+
+func (vs *BLSCommitVoteSet) RLPDecodeSelf(d codec.Decoder) error {
+	d2, err := d.DecodeList()
+	if err != nil {
+		return err
+	}
+	cnt, err := d2.DecodeMulti(&vs.blsBlockCommitVoteSet, &vs.NTSDProves)
+	if cnt == 1 && err == io.EOF {
+		vs.NTSDProves = nil
+		return nil
+	}
+	return err
+}
+
+func blsCommitVoteSetBytes(v interface{}) []byte {
+	return append([]byte{blsCommitVoteSetPrefix}, vlCodec.MustMarshalToBytes(v)...)
+}
+
+func (vs *BLSCommitVoteSet) Bytes() []byte {
+	if vs.bytes == nil {
+		vs.bytes = blsCommitVoteSetBytes(vs)
+	}
+	return vs.bytes
+}
+
+func (vs *BLSCommitVoteSet) BlockVoteSetBytes() []byte {
+	if vs.blockBytes == nil {
+		vs.blockBytes = blsCommitVoteSetBytes(&vs.blsBlockCommitVoteSet)
+	}
+	return vs.blockBytes
+}
+
+func (vs *BLSCommitVoteSet) Hash() []byte {
+	return crypto.SHA3Sum256(vs.Bytes())
+}
+
+func (vs *BLSCommitVoteSet) Timestamp() int64 {
+	return medianTimestamp(append([]int64(nil), vs.Timestamps...))
+}
+
+func (vs *BLSCommitVoteSet) VoteRound() int32 {
+	return vs.Round
+}
+
+func (vs *BLSCommitVoteSet) NTSDProofCount() int {
+	return len(vs.NTSDProves)
+}
+
+func (vs *BLSCommitVoteSet) NTSDProofAt(i int) []byte {
+	return vs.NTSDProves[i]
+}
+
+// CommitVoteSet returns itself. Signatures of voters can't be taken from
+// the aggregated signature, so it's used as VoteSet as it is.
+func (vs *BLSCommitVoteSet) CommitVoteSet(pcm module.BTPProofContextMap) (module.CommitVoteSet, error) {
+	return vs, nil
+}
+
+func (vs *BLSCommitVoteSet) Add(idx int, vote interface{}) bool {
+	return false
+}
+
+func (vs *BLSCommitVoteSet) String() string {
+	return fmt.Sprintf("BLSVoteSet(R=%d,ID=%v,len(Signers)=%d,len(NTS)=%d)",
+		vs.Round, vs.BlockPartSetIDAndAppData, len(vs.Timestamps), len(vs.NTSDProves))
+}
+
+func (vs *BLSCommitVoteSet) signerCount() int {
+	cnt := 0
+	for i := 0; i < vs.Signers.Len(); i++ {
+		if vs.Signers.Get(i) {
+			cnt += 1
+		}
+	}
+	return cnt
+}
+
+func (vs *BLSCommitVoteSet) VerifyBlock(block module.BlockData, validators module.ValidatorList) ([]bool, error) {
+	if block.Height() == 0 || validators == nil {
+		if len(vs.Timestamps) == 0 {
+			return nil, nil
+		}
+		return nil, errors.Errorf("voters for height 0 or nil validator list")
+	}
+	if vs.ctx == nil {
+		return nil, errors.InvalidStateError.New("NoBLSKeyContext")
+	}
+	if !vs.ctx.GetRevision(block.Result()).Has(module.BLSCommitVotes) {
+		return nil, errors.InvalidStateError.Errorf(
+			"BLS commit votes are not allowed height=%d", block.Height())
+	}
+	if vs.Signers == nil || vs.Signers.Verify() != nil || vs.Signers.Len() != validators.Len() {
+		return nil, errors.Errorf("invalid signers for validators(%d)", validators.Len())
+	}
+	if vs.signerCount() != len(vs.Timestamps) {
+		return nil, errors.Errorf("signers(%d) != timestamps(%d)",
+			vs.signerCount(), len(vs.Timestamps))
+	}
+	if !enoughVote(len(vs.Timestamps), validators.Len()) {
+		return nil, errors.Errorf("votes(%d) <= 2/3 of validators(%d)",
+			len(vs.Timestamps), validators.Len())
+	}
+	sig, err := crypto.ParseBLSSignature(vs.Signature)
+	if err != nil {
+		return nil, errors.Wrap(err, "invalid BLS signature")
+	}
+
+	vset := make([]bool, validators.Len())
+	pks := make([]*crypto.BLSPublicKey, 0, len(vs.Timestamps))
+	hashes := make([][]byte, 0, len(vs.Timestamps))
+	for i := range vset {
+		if !vs.Signers.Get(i) {
+			continue
+		}
+		v, _ := validators.Get(i)
+		pkBytes, err := vs.ctx.GetBLSPublicKey(block.Result(), v.Address())
+		if err != nil {
+			return nil, err
+		}
+		if pkBytes == nil {
+			return nil, errors.Errorf("no BLS public key for validator %v", v.Address())
+		}
+		pk, err := crypto.ParseBLSPublicKey(pkBytes)
+		if err != nil {
+			return nil, err
+		}
+		msg := newVoteMessage()
+		msg.Height = block.Height()
+		msg.Round = vs.Round
+		msg.Type = VoteTypePrecommit
+		msg.SetRoundDecision(block.ID(), vs.BlockPartSetIDAndAppData, nil)
+		msg.Timestamp = vs.Timestamps[len(pks)]
+		pks = append(pks, pk)
+		hashes = append(hashes, msg.hash())
+		vset[i] = true
+	}
+	if !crypto.VerifyAggregatedBLSSignature(pks, hashes, sig) {
+		return nil, errors.Errorf("invalid aggregated BLS signature height=%d", block.Height())
+	}
+	return vset, nil
+}
+
+// NewBLSCommitVoteSet returns a new BLSCommitVoteSet for precommit messages
+// and BLS signatures of their hashes. validators is the validator list of
+// the height of messages.
+func NewBLSCommitVoteSet(
+	msgs []*VoteMessage,
+	sigs [][]byte,
+	validators module.ValidatorList,
+	ntsdProves [][]byte,
+) (*BLSCommitVoteSet, error) {
+	if len(msgs) == 0 || len(msgs) != len(sigs) {
+		return nil, errors.IllegalArgumentError.Errorf(
+			"InvalidVotes(msgs=%d,sigs=%d)", len(msgs), len(sigs))
+	}
+	type signer struct {
+		index int
+		ts    int64
+		sig   *crypto.BLSSignature
+	}
+	signers := make([]signer, len(msgs))
+	rdd := msgs[0].RoundDecisionDigest()
+	for i, msg := range msgs {
+		if !bytes.Equal(rdd, msg.RoundDecisionDigest()) {
+			return nil, errors.Errorf(
+				"NewBLSCommitVoteSet: bad RDD in messages msgs[0].BlockID=%s msgs[i].BlockID=%s i=%d",
+				common.HexPre(msgs[0].BlockID),
+				common.HexPre(msg.BlockID),
+				i,
+			)
+		}
+		index := validators.IndexOf(msg.address())
+		if index < 0 {
+			return nil, errors.Errorf("bad voter %v at index %d", msg.address(), i)
+		}
+		sig, err := crypto.ParseBLSSignature(sigs[i])
+		if err != nil {
+			return nil, err
+		}
+		signers[i] = signer{index, msg.Timestamp, sig}
+	}
+	sort.Slice(signers, func(i, j int) bool {
+		return signers[i].index < signers[j].index
+	})
+
+	vs := &BLSCommitVoteSet{NTSDProves: ntsdProves}
+	vs.Round = msgs[0].Round
+	vs.BlockPartSetIDAndAppData = msgs[0].BlockPartSetIDAndNTSVoteCount
+	vs.Signers = NewBitArray(validators.Len())
+	blsSigs := make([]*crypto.BLSSignature, len(signers))
+	for i, s := range signers {
+		if vs.Signers.Get(s.index) {
+			return nil, errors.Errorf("duplicated voter index=%d", s.index)
+		}
+		vs.Signers.Set(s.index)
+		vs.Timestamps = append(vs.Timestamps, s.ts)
+		blsSigs[i] = s.sig
+	}
+	vs.Signature = crypto.AggregateBLSSignatures(blsSigs).Bytes()
+	return vs, nil
+}
+
+func newBLSCommitVoteSetFromBytes(bs []byte, ctx BLSKeyContext) (*BLSCommitVoteSet, error) {
+	if len(bs) == 0 || bs[0] != blsCommitVoteSetPrefix {
+		return nil, errors.IllegalArgumentError.New("NotBLSCommitVoteSet")
+	}
+	vs := &BLSCommitVoteSet{ctx: ctx}
+	if _, err := vlCodec.UnmarshalFromBytes(bs[1:], vs); err != nil {
+		return nil, err
+	}
+	return vs, nil
+}
+
+// NewCommitVoteSetDecoder returns the decoder for BLSCommitVoteSet and
+// commit vote sets of dec. BLSCommitVoteSet is valid only for blocks of the
+// revision with module.BLSCommitVotes.
+func NewCommitVoteSetDecoder(
+	dec module.CommitVoteSetDecoder,
+	ctx BLSKeyContext,
+) module.CommitVoteSetDecoder {
+	return func(bs []byte) module.CommitVoteSet {
+		if len(bs) > 0 && bs[0] == blsCommitVoteSetPrefix {
+			vs, err := newBLSCommitVoteSetFromBytes(bs, ctx)
+			if err != nil {
+				return nil
+			}
+			return vs
+		}
+		return dec(bs)
+	}
+}
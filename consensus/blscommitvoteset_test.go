@@ -0,0 +1,182 @@
+/*
+ * Copyright 2026 ICON Foundation
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package consensus
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/icon-project/goloop/common/crypto"
+	"github.com/icon-project/goloop/common/db"
+	"github.com/icon-project/goloop/common/wallet"
+	"github.com/icon-project/goloop/module"
+	"github.com/icon-project/goloop/service/state"
+)
+
+type testBLSKeyContext struct {
+	rev  module.Revision
+	keys map[string][]byte
+}
+
+func (ctx *testBLSKeyContext) GetRevision(result []byte) module.Revision {
+	return ctx.rev
+}
+
+func (ctx *testBLSKeyContext) GetBLSPublicKey(result []byte, addr module.Address) ([]byte, error) {
+	return ctx.keys[addr.String()], nil
+}
+
+type testBLSBlock struct {
+	module.BlockData
+	height int64
+	id     []byte
+}
+
+func (b *testBLSBlock) Height() int64 {
+	return b.height
+}
+
+func (b *testBLSBlock) ID() []byte {
+	return b.id
+}
+
+func (b *testBLSBlock) Result() []byte {
+	return nil
+}
+
+type testBLSVoter struct {
+	w   module.Wallet
+	bls module.BaseWallet
+}
+
+func newTestBLSVoters(t *testing.T, n int) ([]testBLSVoter, module.ValidatorList, *testBLSKeyContext) {
+	ctx := &testBLSKeyContext{
+		rev:  module.BLSCommitVotes,
+		keys: make(map[string][]byte),
+	}
+	voters := make([]testBLSVoter, n)
+	vs := make([]module.Validator, n)
+	for i := range voters {
+		w := wallet.New()
+		sk, _ := crypto.GenerateBLSKeyPair()
+		bw := wallet.NewBLSFromPrivateKey(sk)
+		voters[i] = testBLSVoter{w, bw}
+		var err error
+		vs[i], err = state.ValidatorFromAddress(w.Address())
+		assert.NoError(t, err)
+		ctx.keys[w.Address().String()] = bw.PublicKey()
+	}
+	vl, err := state.ValidatorSnapshotFromSlice(db.NewMapDB(), vs)
+	assert.NoError(t, err)
+	return voters, vl, ctx
+}
+
+func newTestBLSVotes(t *testing.T, voters []testBLSVoter, blk module.BlockData) ([]*VoteMessage, [][]byte) {
+	psid := &PartSetID{Count: 1, Hash: crypto.SHA3Sum256([]byte("parts"))}
+	var msgs []*VoteMessage
+	var sigs [][]byte
+	for i, v := range voters {
+		msg := NewVoteMessage(v.w, VoteTypePrecommit, blk.Height(), 1, blk.ID(),
+			psid, int64(100+i), nil, nil, 0)
+		sig, err := v.bls.Sign(msg.hash())
+		assert.NoError(t, err)
+		msgs = append(msgs, msg)
+		sigs = append(sigs, sig)
+	}
+	return msgs, sigs
+}
+
+func TestBLSCommitVoteSet_VerifyBlock(t *testing.T) {
+	voters, vl, ctx := newTestBLSVoters(t, 4)
+	blk := &testBLSBlock{height: 10, id: crypto.SHA3Sum256([]byte("block"))}
+	msgs, sigs := newTestBLSVotes(t, voters, blk)
+
+	// votes in reverse order of validators
+	vs, err := NewBLSCommitVoteSet(
+		[]*VoteMessage{msgs[3], msgs[1], msgs[0]},
+		[][]byte{sigs[3], sigs[1], sigs[0]},
+		vl, nil,
+	)
+	assert.NoError(t, err)
+	assert.Equal(t, []int64{100, 101, 103}, vs.Timestamps)
+	assert.Equal(t, int64(101), vs.Timestamp())
+	assert.Equal(t, []int64{100, 101, 103}, vs.Timestamps)
+
+	dec := NewCommitVoteSetDecoder(NewCommitVoteSetFromBytes, ctx)
+	cvs := dec(vs.Bytes())
+	assert.IsType(t, &BLSCommitVoteSet{}, cvs)
+	assert.Equal(t, vs.Hash(), cvs.Hash())
+	assert.Equal(t, vs.BlockVoteSetBytes(), cvs.BlockVoteSetBytes())
+	vset, err := cvs.VerifyBlock(blk, vl)
+	assert.NoError(t, err)
+	assert.Equal(t, []bool{true, true, false, true}, vset)
+
+	// other formats are decoded by the base decoder
+	cvl := NewCommitVoteList(nil, msgs...)
+	assert.IsType(t, &CommitVoteList{}, dec(cvl.Bytes()))
+	assert.Nil(t, dec([]byte{blsCommitVoteSetPrefix, 0xff}))
+
+	// not allowed before the revision
+	ctx.rev = module.NoRevision
+	_, err = cvs.VerifyBlock(blk, vl)
+	assert.Error(t, err)
+	ctx.rev = module.BLSCommitVotes
+
+	// votes for other block
+	_, err = cvs.VerifyBlock(&testBLSBlock{height: 10, id: crypto.SHA3Sum256([]byte("other"))}, vl)
+	assert.Error(t, err)
+
+	// not enough votes
+	vs2, err := NewBLSCommitVoteSet(msgs[:2], sigs[:2], vl, nil)
+	assert.NoError(t, err)
+	_, err = dec(vs2.Bytes()).VerifyBlock(blk, vl)
+	assert.Error(t, err)
+
+	// tampered timestamp
+	vs3, err := NewBLSCommitVoteSet(msgs, sigs, vl, nil)
+	assert.NoError(t, err)
+	vs3.Timestamps[2] += 1
+	_, err = dec(vs3.Bytes()).VerifyBlock(blk, vl)
+	assert.Error(t, err)
+
+	// BLS key not registered
+	vs4, err := NewBLSCommitVoteSet(msgs, sigs, vl, nil)
+	assert.NoError(t, err)
+	delete(ctx.keys, voters[2].w.Address().String())
+	_, err = dec(vs4.Bytes()).VerifyBlock(blk, vl)
+	assert.Error(t, err)
+}
+
+func TestBLSCommitVoteSet_NTSDProves(t *testing.T) {
+	voters, vl, ctx := newTestBLSVoters(t, 1)
+	blk := &testBLSBlock{height: 3, id: crypto.SHA3Sum256([]byte("block"))}
+	msgs, sigs := newTestBLSVotes(t, voters, blk)
+
+	proves := [][]byte{{1, 2}, {3}}
+	vs, err := NewBLSCommitVoteSet(msgs, sigs, vl, proves)
+	assert.NoError(t, err)
+	cvs := NewCommitVoteSetDecoder(NewCommitVoteSetFromBytes, ctx)(vs.Bytes())
+	assert.Equal(t, 2, cvs.NTSDProofCount())
+	assert.Equal(t, proves[1], cvs.NTSDProofAt(1))
+
+	// block vote set bytes don't include proves
+	vs.NTSDProves = nil
+	assert.Equal(t, vs.BlockVoteSetBytes(), cvs.BlockVoteSetBytes())
+	_, err = cvs.VerifyBlock(blk, vl)
+	assert.NoError(t, err)
+}
@@ -84,14 +84,19 @@ func (bvl *blockCommitVoteList) String() string {
 }
 
 func (bvl *blockCommitVoteList) Timestamp() int64 {
-	l := len(bvl.Items)
-	if l == 0 {
-		return 0
-	}
-	ts := make([]int64, l)
+	ts := make([]int64, len(bvl.Items))
 	for i := range ts {
 		ts[i] = bvl.Items[i].Timestamp
 	}
+	return medianTimestamp(ts)
+}
+
+// medianTimestamp returns the median of timestamps. It sorts timestamps.
+func medianTimestamp(ts []int64) int64 {
+	l := len(ts)
+	if l == 0 {
+		return 0
+	}
 	sort.Slice(ts, func(i, j int) bool {
 		return ts[i] < ts[j]
 	})
@@ -4,6 +4,7 @@ import (
 	"bytes"
 	"encoding/binary"
 	"fmt"
+	"math/big"
 	"math/rand"
 	"path"
 	"time"
@@ -99,6 +100,7 @@ type consensus struct {
 
 	lastBlock          module.Block
 	validators         module.ValidatorList
+	proposers          ProposerSelector
 	prevValidators     addressIndexer
 	members            module.MemberList
 	minimizeBlockGen   bool
@@ -212,6 +214,9 @@ func (cs *consensus) _resetForNewHeight(prevBlock module.Block, votes *voteSet)
 	}
 	cs.minimizeBlockGen = cs.c.ServiceManager().GetMinimizeBlockGen(cs.lastBlock.Result())
 	cs.roundLimit = int32(cs.c.ServiceManager().GetRoundLimit(cs.lastBlock.Result(), cs.validators.Len()))
+	proposers, err := cs.newProposerSelector()
+	cs.log.Must(err)
+	cs.proposers = proposers
 	cs.sentPatch = false
 	cs.lastVotes = votes
 	cs.hvs.reset(cs.validators.Len())
@@ -1227,7 +1232,7 @@ func (cs *consensus) ntsVoteBaseAndDecisionProofParts(
 			cs.round,
 			ntsHashEntry.NetworkTypeSectionHash,
 		)
-		pp, err := pc.NewProofPart(ntsd.Hash(), cs.c)
+		pp, err := pc.NewProofPart(ntsd, cs.c)
 		if err != nil {
 			return nil, nil, err
 		}
@@ -1307,23 +1312,32 @@ func (cs *consensus) doSendVote(vt VoteType, blockParts *blockPartSet) error {
 	return err
 }
 
-func getProposerIndex(
-	validators module.ValidatorList,
-	height int64,
-	round int32,
-) int {
-	return int((height + int64(round)) % int64(validators.Len()))
+// newProposerSelector returns the selector for the next height. It fails if
+// weights of validators are not available, since selecting proposers with
+// other weights makes the node disagree with others on proposers.
+func (cs *consensus) newProposerSelector() (ProposerSelector, error) {
+	sm := cs.c.ServiceManager()
+	result := cs.lastBlock.Result()
+	rev := sm.GetRevision(result)
+	var weights []*big.Int
+	if wg, ok := sm.(ValidatorWeightGetter); ok && rev.Has(module.StakeWeightedProposer) {
+		var err error
+		if weights, err = wg.GetValidatorWeights(result, cs.validators); err != nil {
+			return nil, errors.Wrapf(err, "FailToGetValidatorWeights(height=%d)", cs.lastBlock.Height())
+		}
+	}
+	return NewProposerSelector(rev, cs.validators, weights, cs.lastBlock.Hash()), nil
 }
 
 func (cs *consensus) getProposerIndex(height int64, round int32) int {
-	return getProposerIndex(cs.validators, height, round)
+	return cs.proposers.ProposerIndex(height, round)
 }
 
 func (cs *consensus) isProposerFor(height int64, round int32) bool {
 	if cs.validators == nil || cs.validators.Len() == 0 {
 		return false
 	}
-	pindex := getProposerIndex(cs.validators, height, round)
+	pindex := cs.getProposerIndex(height, round)
 	v, _ := cs.validators.Get(pindex)
 	if v == nil {
 		return false
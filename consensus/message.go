@@ -142,6 +142,7 @@ type ProposalMessageV1 struct {
 func NewProposalMessageV1() *ProposalMessageV1 {
 	msg := &ProposalMessageV1{}
 	msg.signedBase._byteser = msg
+	msg.signedBase._kind = module.SignProposal
 	return msg
 }
 
@@ -153,6 +154,7 @@ type ProposalMessage struct {
 func NewProposalMessage() *ProposalMessage {
 	msg := &ProposalMessage{}
 	msg.signedBase._byteser = msg
+	msg.signedBase._kind = module.SignProposal
 	return msg
 }
 
@@ -292,6 +294,7 @@ func newVoteMessage() *VoteMessage {
 	msg.signedBase._byteser = &blockVoteByteser{
 		msg: msg,
 	}
+	msg.signedBase._kind = module.SignVote
 	return msg
 }
 
@@ -342,7 +345,7 @@ func NewVoteMessageFromBlock(
 			round,
 			ntd.NetworkTypeSectionHash(),
 		)
-		pp, err := pc.NewProofPart(ntsd.Hash(), wp)
+		pp, err := pc.NewProofPart(ntsd, wp)
 		if err != nil {
 			return nil, err
 		}
@@ -23,6 +23,7 @@ import (
 
 	"github.com/icon-project/goloop/btp/ntm"
 	"github.com/icon-project/goloop/common/codec"
+	"github.com/icon-project/goloop/common/crypto"
 	"github.com/icon-project/goloop/common/wallet"
 	"github.com/icon-project/goloop/module"
 )
@@ -160,7 +161,7 @@ func TestVoteMessage_VerifyMismatchBetweenAppDataAndNTSDProofPartsLen(t *testing
 	msg.Timestamp = 10
 	msg.NTSDProofParts = make([][]byte, 1)
 	pc, _ := ntm.ForUID("eth").NewProofContext([][]byte{w.PublicKey()})
-	pp, _ := pc.NewProofPart([]byte("abc"), wp)
+	pp, _ := pc.NewProofPart(pc.NewDecision([]byte("abc"), 1, 1, 0, []byte("abc")), wp)
 	msg.NTSDProofParts[0] = pp.Bytes()
 	_ = msg.Sign(w)
 	assert.Error(msg.Verify(theNilVerifyCtx))
@@ -183,7 +184,7 @@ func TestVoteMessage_VerifyMismatchBetweenNTSVoteBasesAndNTSDProofParts(t *testi
 	msg.Timestamp = 10
 	msg.NTSDProofParts = make([][]byte, 1)
 	pc, _ := ntm.ForUID("eth").NewProofContext([][]byte{w.PublicKey()})
-	pp, _ := pc.NewProofPart([]byte("abc"), wp)
+	pp, _ := pc.NewProofPart(pc.NewDecision([]byte("abc"), 1, 1, 0, []byte("abc")), wp)
 	msg.NTSDProofParts[0] = pp.Bytes()
 	_ = msg.Sign(w)
 	assert.Error(msg.Verify(theNilVerifyCtx))
@@ -335,3 +336,50 @@ func TestProposalMessage_SendV2ReceiveV2(t *testing.T) {
 	assert.EqualValues(t, 1, msg2.POLRound)
 	assert.EqualValues(t, 0, msg2.NID)
 }
+
+type messageSignerWallet struct {
+	module.Wallet
+	kind module.SignKind
+	data []byte
+}
+
+func (w *messageSignerWallet) SignMessage(kind module.SignKind, data []byte) ([]byte, error) {
+	w.kind = kind
+	w.data = data
+	return w.Sign(crypto.SHA3Sum256(data))
+}
+
+func TestSignedBase_SignMessage(t *testing.T) {
+	w := &messageSignerWallet{Wallet: wallet.New()}
+
+	pm := NewProposalMessage()
+	pm.Height = 3
+	pm.Round = 2
+	pm.BlockPartSetID = &PartSetID{1, []byte{0, 1, 2}}
+	pm.POLRound = -1
+	assert.NoError(t, pm.Sign(w))
+	assert.Equal(t, module.SignProposal, w.kind)
+	assert.Equal(t, pm.bytes(), w.data)
+	assert.NoError(t, pm.Verify(theNilVerifyCtx))
+	var hr struct {
+		Height int64
+		Round  int32
+	}
+	codec.MustUnmarshalFromBytes(w.data, &hr)
+	assert.EqualValues(t, 3, hr.Height)
+	assert.EqualValues(t, 2, hr.Round)
+
+	vm := NewVoteMessage(w, VoteTypePrecommit, 4, 1, make([]byte, 32),
+		nil, 10, nil, nil, 0)
+	assert.Equal(t, module.SignVote, w.kind)
+	assert.NoError(t, vm.Verify(theNilVerifyCtx))
+	var hrt struct {
+		Height int64
+		Round  int32
+		Type   VoteType
+	}
+	codec.MustUnmarshalFromBytes(w.data, &hrt)
+	assert.EqualValues(t, 4, hrt.Height)
+	assert.EqualValues(t, 1, hrt.Round)
+	assert.Equal(t, VoteTypePrecommit, hrt.Type)
+}
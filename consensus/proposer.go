@@ -0,0 +1,148 @@
+/*
+ * Copyright 2026 ICON Foundation
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package consensus
+
+import (
+	"math/big"
+	"sort"
+
+	"github.com/icon-project/goloop/common/codec"
+	"github.com/icon-project/goloop/common/crypto"
+	"github.com/icon-project/goloop/module"
+)
+
+// ProposerSelector selects the proposer of a round among validators of
+// the height.
+type ProposerSelector interface {
+	ProposerIndex(height int64, round int32) int
+}
+
+// ValidatorWeightGetter is implemented by service managers providing
+// weights (e.g. stakes) of validators for the proposer selection.
+type ValidatorWeightGetter interface {
+	GetValidatorWeights(result []byte, vl module.ValidatorList) ([]*big.Int, error)
+}
+
+type roundRobinSelector struct {
+	size int64
+}
+
+func (s *roundRobinSelector) ProposerIndex(height int64, round int32) int {
+	return int((height + int64(round)) % s.size)
+}
+
+// weightTable maps a point in [0, total) to the index of the validator
+// owning the point.
+type weightTable struct {
+	cumulative []*big.Int
+}
+
+func (t *weightTable) total() *big.Int {
+	return t.cumulative[len(t.cumulative)-1]
+}
+
+func (t *weightTable) indexOf(point *big.Int) int {
+	return sort.Search(len(t.cumulative), func(i int) bool {
+		return point.Cmp(t.cumulative[i]) < 0
+	})
+}
+
+// newWeightTable returns the table for weights. It uses the same weight for
+// all validators if weights are not valid.
+func newWeightTable(size int, weights []*big.Int) *weightTable {
+	sum := new(big.Int)
+	valid := len(weights) == size
+	for _, w := range weights {
+		if w == nil || w.Sign() < 0 {
+			valid = false
+			break
+		}
+		sum.Add(sum, w)
+	}
+	if !valid || sum.Sign() == 0 {
+		weights = nil
+	}
+	t := &weightTable{cumulative: make([]*big.Int, size)}
+	sum = new(big.Int)
+	for i := 0; i < size; i++ {
+		if weights != nil {
+			sum = new(big.Int).Add(sum, weights[i])
+		} else {
+			sum = big.NewInt(int64(i + 1))
+		}
+		t.cumulative[i] = sum
+	}
+	return t
+}
+
+// weylMultiplier is 2^64 divided by the golden ratio. Points of successive
+// slots are spread evenly, so each validator proposes in proportion to its
+// weight.
+const weylMultiplier uint64 = 0x9E3779B97F4A7C15
+
+// weightedSelector selects the proposer in proportion to its weight. It's
+// deterministic for the height and the round.
+type weightedSelector struct {
+	*weightTable
+}
+
+func (s *weightedSelector) ProposerIndex(height int64, round int32) int {
+	slot := uint64(height + int64(round))
+	point := new(big.Int).SetUint64(slot * weylMultiplier)
+	point.Mul(point, s.total())
+	point.Rsh(point, 64)
+	return s.indexOf(point)
+}
+
+// randomSelector selects the proposer with the random point seeded by the
+// hash of the previous block. Nobody knows the proposers of the height
+// before the previous block is decided.
+type randomSelector struct {
+	*weightTable
+	seed []byte
+}
+
+func (s *randomSelector) ProposerIndex(height int64, round int32) int {
+	bs := crypto.SHA3Sum256(codec.BC.MustMarshalToBytes([]interface{}{
+		s.seed, height, round,
+	}))
+	point := new(big.Int).SetBytes(bs)
+	point.Mod(point, s.total())
+	return s.indexOf(point)
+}
+
+// NewProposerSelector returns the selector for the revision of the state of
+// the previous block. Weights are used only with StakeWeightedProposer.
+func NewProposerSelector(
+	rev module.Revision,
+	validators module.ValidatorList,
+	weights []*big.Int,
+	seed []byte,
+) ProposerSelector {
+	size := validators.Len()
+	if !rev.Has(module.StakeWeightedProposer) {
+		weights = nil
+	}
+	switch {
+	case rev.Has(module.RandomProposer):
+		return &randomSelector{newWeightTable(size, weights), seed}
+	case rev.Has(module.StakeWeightedProposer):
+		return &weightedSelector{newWeightTable(size, weights)}
+	default:
+		return &roundRobinSelector{int64(size)}
+	}
+}
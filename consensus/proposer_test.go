@@ -0,0 +1,117 @@
+/*
+ * Copyright 2026 ICON Foundation
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package consensus
+
+import (
+	"bytes"
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/icon-project/goloop/common"
+	"github.com/icon-project/goloop/common/db"
+	"github.com/icon-project/goloop/module"
+	"github.com/icon-project/goloop/service/state"
+)
+
+func newTestValidators(t *testing.T, n int) module.ValidatorList {
+	vs := make([]module.Validator, n)
+	for i := range vs {
+		addr := common.NewAccountAddress(bytes.Repeat([]byte{byte(i + 1)}, 20))
+		v, err := state.ValidatorFromAddress(addr)
+		assert.NoError(t, err)
+		vs[i] = v
+	}
+	vl, err := state.ValidatorSnapshotFromSlice(db.NewMapDB(), vs)
+	assert.NoError(t, err)
+	return vl
+}
+
+func countProposers(ps ProposerSelector, n int, height int64, slots int) []int {
+	counts := make([]int, n)
+	for i := 0; i < slots; i++ {
+		counts[ps.ProposerIndex(height+int64(i), 0)] += 1
+	}
+	return counts
+}
+
+func TestProposerSelector_RoundRobin(t *testing.T) {
+	vl := newTestValidators(t, 4)
+	ps := NewProposerSelector(module.NoRevision, vl, nil, nil)
+	assert.Equal(t, 3, ps.ProposerIndex(10, 1))
+	assert.Equal(t, 0, ps.ProposerIndex(10, 2))
+
+	// weights are ignored without StakeWeightedProposer
+	ps = NewProposerSelector(module.NoRevision, vl, []*big.Int{
+		big.NewInt(1), big.NewInt(0), big.NewInt(0), big.NewInt(0),
+	}, nil)
+	assert.Equal(t, []int{1, 1, 1, 1}, countProposers(ps, 4, 10, 4))
+}
+
+func TestProposerSelector_StakeWeighted(t *testing.T) {
+	vl := newTestValidators(t, 4)
+	weights := []*big.Int{
+		big.NewInt(400), big.NewInt(300), big.NewInt(200), big.NewInt(100),
+	}
+	ps := NewProposerSelector(module.StakeWeightedProposer, vl, weights, nil)
+	counts := countProposers(ps, 4, 1, 1000)
+	for i, w := range []int{400, 300, 200, 100} {
+		assert.InDelta(t, w, counts[i], 5)
+	}
+	assert.Equal(t, ps.ProposerIndex(7, 1), ps.ProposerIndex(8, 0))
+
+	// validator without weight never proposes
+	weights[3] = new(big.Int)
+	ps = NewProposerSelector(module.StakeWeightedProposer, vl, weights, nil)
+	assert.Equal(t, 0, countProposers(ps, 4, 1, 1000)[3])
+
+	// the same weight for all if weights are not valid
+	ps = NewProposerSelector(module.StakeWeightedProposer, vl, weights[:2], nil)
+	counts = countProposers(ps, 4, 1, 1000)
+	for _, c := range counts {
+		assert.InDelta(t, 250, c, 5)
+	}
+}
+
+func TestProposerSelector_Random(t *testing.T) {
+	vl := newTestValidators(t, 4)
+	seed1 := bytes.Repeat([]byte{1}, 32)
+	seed2 := bytes.Repeat([]byte{2}, 32)
+
+	ps1 := NewProposerSelector(module.RandomProposer, vl, nil, seed1)
+	ps2 := NewProposerSelector(module.RandomProposer, vl, nil, seed1)
+	same := true
+	for r := int32(0); r < 16; r++ {
+		idx := ps1.ProposerIndex(10, r)
+		assert.True(t, idx >= 0 && idx < 4)
+		assert.Equal(t, idx, ps2.ProposerIndex(10, r))
+		if idx != NewProposerSelector(module.RandomProposer, vl, nil, seed2).ProposerIndex(10, r) {
+			same = false
+		}
+	}
+	assert.False(t, same)
+
+	rev := module.RandomProposer | module.StakeWeightedProposer
+	weights := []*big.Int{
+		big.NewInt(0), big.NewInt(1), big.NewInt(0), big.NewInt(0),
+	}
+	ps := NewProposerSelector(rev, vl, weights, seed1)
+	for r := int32(0); r < 16; r++ {
+		assert.Equal(t, 1, ps.ProposerIndex(10, r))
+	}
+}
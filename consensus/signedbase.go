@@ -15,6 +15,7 @@ type byteser interface {
 type signedBase struct {
 	// shall be initialized
 	_byteser  byteser
+	_kind     module.SignKind
 	Signature common.Signature
 
 	_hash      []byte
@@ -58,7 +59,13 @@ func (s *signedBase) verify() error {
 func (s *signedBase) Sign(wallet module.Wallet) error {
 	s._hash = nil
 	s._publicKey = nil
-	sigBS, err := wallet.Sign(s.hash())
+	var sigBS []byte
+	var err error
+	if ms, ok := wallet.(module.MessageSigner); ok && s._kind != module.SignHash {
+		sigBS, err = ms.SignMessage(s._kind, s._byteser.bytes())
+	} else {
+		sigBS, err = wallet.Sign(s.hash())
+	}
 	if err != nil {
 		return errors.Errorf("sendVote : %v", err)
 	}
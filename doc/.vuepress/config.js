@@ -37,6 +37,12 @@ module.exports = {
                     ['/metric', "Metric"],
                 ]
             },
+            {
+                title: 'Execution Engine',
+                children: [
+                    ['/wasm_ee', "WASM Execution Engine"],
+                ]
+            },
             //EndOfSidebar
         ],
         lastUpdated: 'Last Updated', // string | boolean
@@ -48,6 +48,8 @@ const (
 	Revision26
 	Revision27
 	Revision28
+	Revision29
+	Revision30
 	RevisionReserved
 )
 
@@ -129,6 +131,10 @@ const (
 	RevisionRecoverUnderIssuance = Revision27
 
 	RevisionSetBondRequirementRate = Revision28
+
+	RevisionWeightedProposer = Revision29
+
+	RevisionBLSCommitVotes = Revision30
 )
 
 var revisionFlags []module.Revision
@@ -149,6 +155,8 @@ var toggleFlagsOnRevision = []struct {
 	{RevisionFixJCLSteps, module.FixJCLSteps},
 	{RevisionChainScoreEventLog, module.ReportConfigureEvents},
 	{RevisionIISS4R1, module.ReportDoubleSign},
+	{RevisionWeightedProposer, module.StakeWeightedProposer | module.RandomProposer},
+	{RevisionBLSCommitVotes, module.BLSCommitVotes},
 }
 
 func init() {
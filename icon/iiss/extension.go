@@ -127,6 +127,30 @@ func (s *ExtensionSnapshotImpl) NewState(readonly bool) state.ExtensionState {
 	}
 }
 
+// ValidatorWeights returns powers of validators at the start of the term.
+// Validators which are not elected in the term have no weight.
+func (s *ExtensionSnapshotImpl) ValidatorWeights(vl module.ValidatorList) ([]*big.Int, error) {
+	es := icstate.NewStateFromSnapshot(s.state, true, icutils.NewIconLogger(nil))
+	term := es.GetTermSnapshot()
+	if term == nil {
+		return nil, nil
+	}
+	powers := make(map[string]*big.Int)
+	for _, pss := range term.PRepSnapshots() {
+		powers[icutils.ToKey(pss.Owner())] = pss.Power()
+	}
+	weights := make([]*big.Int, vl.Len())
+	for i := range weights {
+		v, _ := vl.Get(i)
+		if power, ok := powers[icutils.ToKey(es.GetOwnerByNode(v.Address()))]; ok {
+			weights[i] = power
+		} else {
+			weights[i] = new(big.Int)
+		}
+	}
+	return weights, nil
+}
+
 func NewExtensionSnapshot(database db.Database, hash []byte) state.ExtensionSnapshot {
 	if hash == nil {
 		return &ExtensionSnapshotImpl{
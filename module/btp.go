@@ -41,7 +41,7 @@ type WalletProvider interface {
 type BTPProofContext interface {
 	Hash() []byte
 	Bytes() []byte
-	NewProofPart(decisionHash []byte, wp WalletProvider) (BTPProofPart, error)
+	NewProofPart(decision BytesHasher, wp WalletProvider) (BTPProofPart, error)
 	NewProofPartFromBytes(ppBytes []byte) (BTPProofPart, error)
 	VerifyPart(decisionHash []byte, pp BTPProofPart) (int, error)
 	NewProof() BTPProof
@@ -20,6 +20,26 @@ type Wallet interface {
 	Address() Address
 }
 
+type SignKind int
+
+const (
+	SignHash SignKind = iota
+	SignProposal
+	SignVote
+	SignPeerAuth
+	SignTransaction
+	SignNTSDecision
+)
+
+// MessageSigner is implemented by Wallet which needs to inspect messages
+// before signing, e.g. to prevent double signing. SignMessage returns the
+// signature for SHA3-256 hash of data. For SignHash, data is the hash itself.
+// For SignNTSDecision, data is the message made by ntm.NewDecisionMessage,
+// and the decision in it is hashed by the network type module.
+type MessageSigner interface {
+	SignMessage(kind SignKind, data []byte) ([]byte, error)
+}
+
 type Chain interface {
 	Database() db.Database
 	DoDBTask(func(database db.Database))
@@ -38,6 +58,11 @@ type Chain interface {
 	ChildrenLimit() int
 	NephewsLimit() int
 	ValidateTxOnSend() bool
+	FlatStateSync() bool
+	TxPoolPolicy() string
+	SenderTxCount() int
+	SenderTxBytes() int
+	PeerTxRate() int
 	Genesis() []byte
 	GenesisStorage() GenesisStorage
 	CommitVoteSetDecoder() CommitVoteSetDecoder
@@ -77,6 +102,25 @@ type Chain interface {
 	WalletFor(dsa string) BaseWallet
 }
 
+// DBSnapshot is a pinned read-only snapshot of the chain database.
+type DBSnapshot interface {
+	ID() string
+	Height() int64
+	Database() db.Database
+	CreatedAt() time.Time
+	ExpiresAt() time.Time
+}
+
+// DBSnapshotManager is implemented by a Chain supporting pinned snapshots
+// of its database. Pinned snapshots are kept until they are released,
+// expired or the database is closed.
+type DBSnapshotManager interface {
+	PinDBSnapshot() (DBSnapshot, error)
+	GetDBSnapshot(id string) (DBSnapshot, error)
+	ReleaseDBSnapshot(id string) error
+	DBSnapshots() []DBSnapshot
+}
+
 type Regulator interface {
 	MaxTxCount() int
 	OnPropose(now time.Time)
@@ -39,6 +39,10 @@ const (
 	ReportDoubleSign
 	FixJCLSteps
 	ReportConfigureEvents
+	StakeWeightedProposer
+	RandomProposer
+	WASMContracts
+	BLSCommitVotes
 	LastRevisionBit
 
 	UseNIDInConsensusMessage = ReportDoubleSign
@@ -41,6 +41,7 @@ const (
 	TraceModeNone TraceMode = iota
 	TraceModeInvoke
 	TraceModeBalanceChange
+	TraceModeCallTree
 )
 
 type OpType int
@@ -81,3 +82,24 @@ type TraceCallback interface {
 	OnFrameExit(success bool) error
 	OnBalanceChange(opType OpType, from, to Address, amount *big.Int) error
 }
+
+// TraceCall is the information of the call given to CallTraceCallback.
+type TraceCall struct {
+	Type      string
+	From      Address
+	To        Address
+	Value     *big.Int
+	StepLimit *big.Int
+	Method    string
+	Params    interface{}
+}
+
+// CallTraceCallback is implemented by TraceCallback to get structured
+// information of calls with TraceModeCallTree. OnCallStart and OnCallEnd
+// are paired, and OnEvent and OnStorageAccess are for the last started call.
+type CallTraceCallback interface {
+	OnCallStart(call *TraceCall) error
+	OnCallEnd(status error, stepUsed *big.Int, result interface{}) error
+	OnEvent(addr Address, indexed, data [][]byte) error
+	OnStorageAccess(write bool, key []byte) error
+}
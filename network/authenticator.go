@@ -97,6 +97,10 @@ func (a *Authenticator) onPacket(pkt *Packet, p *Peer) {
 func (a *Authenticator) Signature(content []byte) []byte {
 	defer a.mtx.Unlock()
 	a.mtx.Lock()
+	if ms, ok := a.wallet.(module.MessageSigner); ok {
+		sb, _ := ms.SignMessage(module.SignPeerAuth, content)
+		return sb
+	}
 	h := crypto.SHA3Sum256(content)
 	sb, _ := a.wallet.Sign(h)
 	return sb
@@ -0,0 +1,141 @@
+package node
+
+import (
+	"github.com/icon-project/goloop/block"
+	"github.com/icon-project/goloop/common"
+	"github.com/icon-project/goloop/common/codec"
+	"github.com/icon-project/goloop/common/db"
+	"github.com/icon-project/goloop/module"
+)
+
+// DBEntryView is a key-value pair of a bucket. Decoded is set only if
+// decoding is requested, and the bucket has known format.
+type DBEntryView struct {
+	Key     common.HexBytes `json:"key"`
+	Value   common.HexBytes `json:"value"`
+	Decoded interface{}     `json:"decoded,omitempty"`
+}
+
+type DBEntriesView struct {
+	Entries []*DBEntryView  `json:"entries"`
+	Next    common.HexBytes `json:"next,omitempty"`
+}
+
+// DBDecoder decodes an entry of a bucket. It may use the database to
+// follow references (e.g. block header hash to block header).
+// It returns nil if the entry can't be decoded.
+type DBDecoder func(dbase db.Database, key, value []byte) interface{}
+
+var dbDecoders = map[db.BucketID]DBDecoder{
+	db.BlockHeaderHashByHeight:  decodeBlockHeaderHash,
+	db.TransactionLocatorByHash: decodeTransactionLocator,
+	db.ChainProperty:            decodeChainProperty,
+}
+
+func DecodeDBEntry(dbase db.Database, id db.BucketID, key, value []byte) interface{} {
+	if d, ok := dbDecoders[id]; ok {
+		return d(dbase, key, value)
+	}
+	return nil
+}
+
+type BlockHeaderView struct {
+	Version                common.HexInt32 `json:"version"`
+	Height                 common.HexInt64 `json:"height"`
+	Timestamp              common.HexInt64 `json:"timestamp"`
+	Proposer               common.HexBytes `json:"proposer"`
+	PrevID                 common.HexBytes `json:"prevID"`
+	VotesHash              common.HexBytes `json:"votesHash"`
+	NextValidatorsHash     common.HexBytes `json:"nextValidatorsHash"`
+	PatchTransactionsHash  common.HexBytes `json:"patchTransactionsHash"`
+	NormalTransactionsHash common.HexBytes `json:"normalTransactionsHash"`
+	LogsBloom              common.HexBytes `json:"logsBloom"`
+	Result                 common.HexBytes `json:"result"`
+	NSFilter               common.HexBytes `json:"nsFilter,omitempty"`
+}
+
+type BlockHeaderHashView struct {
+	Height common.HexInt64  `json:"height"`
+	Hash   common.HexBytes  `json:"hash"`
+	Header *BlockHeaderView `json:"header,omitempty"`
+}
+
+func decodeBlockHeaderHash(dbase db.Database, key, value []byte) interface{} {
+	var height int64
+	if _, err := codec.BC.UnmarshalFromBytes(key, &height); err != nil {
+		return nil
+	}
+	v := &BlockHeaderHashView{
+		Height: common.HexInt64{Value: height},
+		Hash:   value,
+	}
+	if dbase == nil {
+		return v
+	}
+	bk, err := dbase.GetBucket(db.BytesByHash)
+	if err != nil {
+		return v
+	}
+	bs, err := bk.Get(value)
+	if err != nil || bs == nil {
+		return v
+	}
+	var h block.V2HeaderFormat
+	if _, err := codec.BC.UnmarshalFromBytes(bs, &h); err != nil {
+		return v
+	}
+	v.Header = &BlockHeaderView{
+		Version:                common.HexInt32{Value: int32(h.Version)},
+		Height:                 common.HexInt64{Value: h.Height},
+		Timestamp:              common.HexInt64{Value: h.Timestamp},
+		Proposer:               h.Proposer,
+		PrevID:                 h.PrevID,
+		VotesHash:              h.VotesHash,
+		NextValidatorsHash:     h.NextValidatorsHash,
+		PatchTransactionsHash:  h.PatchTransactionsHash,
+		NormalTransactionsHash: h.NormalTransactionsHash,
+		LogsBloom:              h.LogsBloom,
+		Result:                 h.Result,
+		NSFilter:               h.NSFilter,
+	}
+	return v
+}
+
+type TransactionLocatorView struct {
+	BlockHeight      common.HexInt64 `json:"blockHeight"`
+	TransactionGroup string          `json:"transactionGroup"`
+	IndexInGroup     common.HexInt32 `json:"indexInGroup"`
+}
+
+func decodeTransactionLocator(_ db.Database, _, value []byte) interface{} {
+	var loc module.TransactionLocator
+	if _, err := codec.BC.UnmarshalFromBytes(value, &loc); err != nil {
+		return nil
+	}
+	group := "normal"
+	if loc.TransactionGroup == module.TransactionGroupPatch {
+		group = "patch"
+	}
+	return &TransactionLocatorView{
+		BlockHeight:      common.HexInt64{Value: loc.BlockHeight},
+		TransactionGroup: group,
+		IndexInGroup:     common.HexInt32{Value: int32(loc.IndexInGroup)},
+	}
+}
+
+type ChainPropertyView struct {
+	Name  string      `json:"name"`
+	Value interface{} `json:"value,omitempty"`
+}
+
+// decodeChainProperty returns the name of the property. Most of properties
+// are integers (e.g. last block height), so it decodes the value as an
+// integer if possible.
+func decodeChainProperty(_ db.Database, key, value []byte) interface{} {
+	v := &ChainPropertyView{Name: string(key)}
+	var i int64
+	if remain, err := codec.BC.UnmarshalFromBytes(value, &i); err == nil && len(remain) == 0 {
+		v.Value = common.HexInt64{Value: i}
+	}
+	return v
+}
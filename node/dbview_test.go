@@ -0,0 +1,142 @@
+/*
+ * Copyright 2026 ICON Foundation
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package node
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/icon-project/goloop/block"
+	"github.com/icon-project/goloop/common"
+	"github.com/icon-project/goloop/common/codec"
+	"github.com/icon-project/goloop/common/db"
+	"github.com/icon-project/goloop/module"
+)
+
+func TestDecodeDBEntry(t *testing.T) {
+	dbase := db.NewMapDB()
+	hash := []byte{0x01, 0x02}
+	bk, err := dbase.GetBucket(db.BytesByHash)
+	assert.NoError(t, err)
+	assert.NoError(t, bk.Set(hash, codec.BC.MustMarshalToBytes(&block.V2HeaderFormat{
+		Version:   2,
+		Height:    10,
+		Timestamp: 1000,
+	})))
+
+	v := DecodeDBEntry(dbase, db.BlockHeaderHashByHeight, codec.BC.MustMarshalToBytes(int64(10)), hash)
+	if bhv, ok := v.(*BlockHeaderHashView); assert.True(t, ok) {
+		assert.EqualValues(t, 10, bhv.Height.Value)
+		assert.Equal(t, common.HexBytes(hash), bhv.Hash)
+		if assert.NotNil(t, bhv.Header) {
+			assert.EqualValues(t, 10, bhv.Header.Height.Value)
+			assert.EqualValues(t, 1000, bhv.Header.Timestamp.Value)
+		}
+	}
+
+	// header is not available
+	v = DecodeDBEntry(dbase, db.BlockHeaderHashByHeight, codec.BC.MustMarshalToBytes(int64(11)), []byte{0x03})
+	if bhv, ok := v.(*BlockHeaderHashView); assert.True(t, ok) {
+		assert.Nil(t, bhv.Header)
+	}
+
+	v = DecodeDBEntry(nil, db.TransactionLocatorByHash, []byte{0x01},
+		codec.BC.MustMarshalToBytes(&module.TransactionLocator{
+			BlockHeight:      5,
+			TransactionGroup: module.TransactionGroupPatch,
+			IndexInGroup:     3,
+		}))
+	assert.Equal(t, &TransactionLocatorView{
+		BlockHeight:      common.HexInt64{Value: 5},
+		TransactionGroup: "patch",
+		IndexInGroup:     common.HexInt32{Value: 3},
+	}, v)
+
+	v = DecodeDBEntry(nil, db.ChainProperty, []byte("block.lastHeight"), codec.BC.MustMarshalToBytes(int64(7)))
+	assert.Equal(t, &ChainPropertyView{Name: "block.lastHeight", Value: common.HexInt64{Value: 7}}, v)
+	v = DecodeDBEntry(nil, db.ChainProperty, []byte("config"), []byte("{}"))
+	assert.Equal(t, &ChainPropertyView{Name: "config"}, v)
+
+	// invalid format or unknown bucket
+	assert.Nil(t, DecodeDBEntry(nil, db.TransactionLocatorByHash, []byte{0x01}, []byte{0xff}))
+	assert.Nil(t, DecodeDBEntry(nil, db.BytesByHash, []byte{0x01}, []byte{0x01}))
+}
+
+func listTestEntries(t *testing.T, dbase db.Database, query string) *DBEntriesView {
+	e := echo.New()
+	req := httptest.NewRequest(http.MethodGet, "/?"+query, nil)
+	rec := httptest.NewRecorder()
+	ctx := e.NewContext(req, rec)
+	ctx.SetParamNames(ParamBK)
+	ctx.SetParamValues(string(db.ChainProperty))
+	bk, err := dbase.GetBucket(db.ChainProperty)
+	assert.NoError(t, err)
+	ctx.Set("database", dbase)
+	ctx.Set("bucket", bk)
+
+	r := new(Rest)
+	assert.NoError(t, r.BucketListEntries(ctx))
+	assert.Equal(t, http.StatusOK, rec.Code)
+	v := new(DBEntriesView)
+	assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), v))
+	return v
+}
+
+func keysOf(v *DBEntriesView) []string {
+	var keys []string
+	for _, e := range v.Entries {
+		keys = append(keys, string(e.Key))
+	}
+	return keys
+}
+
+func TestRest_BucketListEntries(t *testing.T) {
+	dbase := db.NewMapDB()
+	bk, err := dbase.GetBucket(db.ChainProperty)
+	assert.NoError(t, err)
+	for _, k := range []string{"a1", "a2", "a3", "b1", "b2"} {
+		assert.NoError(t, bk.Set([]byte(k), []byte(k)))
+	}
+
+	v := listTestEntries(t, dbase, "limit=2")
+	assert.Equal(t, []string{"a1", "a2"}, keysOf(v))
+	assert.Equal(t, "a3", string(v.Next))
+
+	v = listTestEntries(t, dbase, "limit=2&cursor="+v.Next.String())
+	assert.Equal(t, []string{"a3", "b1"}, keysOf(v))
+	v = listTestEntries(t, dbase, "limit=2&cursor="+v.Next.String())
+	assert.Equal(t, []string{"b2"}, keysOf(v))
+	assert.Nil(t, v.Next)
+
+	// reverse with the prefix
+	prefix := common.HexBytes("a").String()
+	v = listTestEntries(t, dbase, "limit=2&reverse=true&prefix="+prefix)
+	assert.Equal(t, []string{"a3", "a2"}, keysOf(v))
+	v = listTestEntries(t, dbase, "limit=2&reverse=true&prefix="+prefix+"&cursor="+v.Next.String())
+	assert.Equal(t, []string{"a1"}, keysOf(v))
+	assert.Nil(t, v.Next)
+
+	v = listTestEntries(t, dbase, "limit=1&decode=true&prefix="+common.HexBytes("b").String())
+	if assert.Len(t, v.Entries, 1) {
+		assert.NotNil(t, v.Entries[0].Decoded)
+	}
+}
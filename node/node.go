@@ -16,6 +16,7 @@ import (
 	"github.com/icon-project/goloop/chain/gs"
 	"github.com/icon-project/goloop/common/errors"
 	"github.com/icon-project/goloop/common/log"
+	"github.com/icon-project/goloop/common/statepruner"
 	"github.com/icon-project/goloop/module"
 	"github.com/icon-project/goloop/network"
 	"github.com/icon-project/goloop/server"
@@ -30,6 +31,7 @@ var (
 
 type Node struct {
 	w    module.Wallet
+	bw   module.BaseWallet
 	nt   module.NetworkTransport
 	srv  *server.Manager
 	pm   eeproxy.Manager
@@ -228,7 +230,7 @@ func (n *Node) _add(cfg *chain.Config) (module.Chain, error) {
 		return nil, err
 	}
 
-	c := &Chain{chain.NewChain(n.w, n.nt, n.srv, n.pm, n.logger, cfg), cfg, false}
+	c := &Chain{chain.NewChain(n.w, n.bw, n.nt, n.srv, n.pm, n.logger, cfg), cfg, false}
 	if err := c.Init(); err != nil {
 		return nil, err
 	}
@@ -413,6 +415,13 @@ func (n *Node) JoinChain(
 		ChildrenLimit:    p.ChildrenLimit,
 		NephewsLimit:     p.NephewsLimit,
 		ValidateTxOnSend: p.ValidateTxOnSend,
+		StateSync:        p.StateSync,
+		TxPoolPolicy:     p.TxPoolPolicy,
+		SenderTxCount:    p.SenderTxCount,
+		SenderTxBytes:    p.SenderTxBytes,
+		PeerTxRate:       p.PeerTxRate,
+		LogsIndex:        p.LogsIndex,
+		StatePruning:     p.StatePruning,
 	}
 
 	if err := cfg.Save(); err != nil {
@@ -529,7 +538,10 @@ func (n *Node) PruneChain(cid int, dbt string, height int64) error {
 	return c.Prune(gs, dbt, height)
 }
 
-func (n *Node) BackupChain(cid int, manual bool) (string, error) {
+// BackupChain starts to backup the chain. For incremental and differential
+// backups, the parent is the latest backup (or the latest full backup for
+// differential) of the chain if base is not specified.
+func (n *Node) BackupChain(cid int, manual bool, typ string, base string) (string, error) {
 	defer n.mtx.RUnlock()
 	n.mtx.RLock()
 
@@ -550,7 +562,69 @@ func (n *Node) BackupChain(cid int, manual bool) (string, error) {
 	name := fmt.Sprintf("%#x_%#x_%s_%s.zip", c.CID(), c.NID(), c.Channel(),
 		now.Format("20060102-150405"))
 	file := path.Join(backupDir, name)
-	return name, c.Backup(file, []string{ChainGenesisZipFileName, ChainConfigFileName})
+	extra := []string{ChainGenesisZipFileName, ChainConfigFileName}
+	switch typ {
+	case "", chain.BackupTypeFull:
+		if base != "" {
+			return "", errors.IllegalArgumentError.New("BaseForFullBackup")
+		}
+		return name, c.Backup(file, extra)
+	case chain.BackupTypeIncremental, chain.BackupTypeDifferential:
+		if base == "" {
+			if base, err = n._latestBackupOf(c, typ == chain.BackupTypeDifferential); err != nil {
+				return "", err
+			}
+		}
+		params, err := json.Marshal(&chain.BackupParams{
+			File:   file,
+			Extra:  extra,
+			Type:   typ,
+			Parent: path.Join(backupDir, path.Base(base)),
+		})
+		if err != nil {
+			return "", err
+		}
+		return name, c.RunTask(chain.BackupTask, params)
+	default:
+		return "", errors.IllegalArgumentError.Errorf(
+			"InvalidBackupType(type=%s)", typ)
+	}
+}
+
+// _latestBackupOf returns the name of the latest backup of the chain having
+// the manifest. If full is true, it returns the latest full backup.
+func (n *Node) _latestBackupOf(c *Chain, full bool) (string, error) {
+	backupDir := n.cfg.ResolveAbsolute(n.cfg.BackupDir)
+	fis, err := os.ReadDir(backupDir)
+	if err != nil {
+		return "", err
+	}
+	var latest string
+	var height int64 = -1
+	for _, fi := range fis {
+		if !fi.Type().IsRegular() || strings.HasPrefix(fi.Name(), chain.TemporalBackupFile) {
+			continue
+		}
+		info, err := chain.GetBackupInfoOf(path.Join(backupDir, fi.Name()))
+		if err != nil || info.Type == "" {
+			continue
+		}
+		if int(info.CID.Value) != c.CID() || int(info.NID.Value) != c.NID() ||
+			info.Channel != c.Channel() {
+			continue
+		}
+		if full && info.Type != chain.BackupTypeFull {
+			continue
+		}
+		if info.Height > height {
+			latest, height = fi.Name(), info.Height
+		}
+	}
+	if latest == "" {
+		return "", errors.NotFoundError.Errorf(
+			"NoParentBackup(cid=%#x,full=%v)", c.CID(), full)
+	}
+	return latest, nil
 }
 
 type BackupInfo struct {
@@ -770,6 +844,48 @@ func (n *Node) ConfigureChain(cid int, key string, value string) error {
 			} else {
 				c.cfg.ValidateTxOnSend = bc
 			}
+		case "stateSync":
+			if !chain.IsStateSyncOption(value) {
+				return errors.Errorf("InvalidStateSyncOption(%s)", value)
+			}
+			c.cfg.StateSync = value
+		case "txPoolPolicy":
+			if !chain.IsTxPoolPolicyOption(value) {
+				return errors.Errorf("InvalidTxPoolPolicyOption(%s)", value)
+			}
+			c.cfg.TxPoolPolicy = value
+		case "senderTxCount":
+			if intVal, err := strconv.Atoi(value); err != nil {
+				return errors.Wrapf(err, "invalid value type")
+			} else {
+				c.cfg.SenderTxCount = intVal
+			}
+		case "senderTxBytes":
+			if intVal, err := strconv.Atoi(value); err != nil {
+				return errors.Wrapf(err, "invalid value type")
+			} else {
+				c.cfg.SenderTxBytes = intVal
+			}
+		case "peerTxRate":
+			if intVal, err := strconv.Atoi(value); err != nil {
+				return errors.Wrapf(err, "invalid value type")
+			} else {
+				c.cfg.PeerTxRate = intVal
+			}
+		case "logsIndex":
+			if bc, err := strconv.ParseBool(value); err != nil {
+				return errors.Wrapf(err, "InvalidValueType(exp=bool,val=%s)", value)
+			} else {
+				c.cfg.LogsIndex = bc
+			}
+		case "statePruning":
+			if intVal, err := strconv.Atoi(value); err != nil {
+				return errors.Wrapf(err, "invalid value type")
+			} else if intVal != 0 && intVal < statepruner.MinKeep {
+				return errors.Errorf("InvalidStatePruning(%d,min=%d)", intVal, statepruner.MinKeep)
+			} else {
+				c.cfg.StatePruning = intVal
+			}
 		default:
 			return errors.Errorf("not found key %s", key)
 		}
@@ -914,6 +1030,7 @@ func (n *Node) Configure(key string, value string) error {
 
 func NewNode(
 	w module.Wallet,
+	bw module.BaseWallet,
 	cfg *StaticConfig,
 	l log.Logger,
 ) *Node {
@@ -970,6 +1087,7 @@ func NewNode(
 
 	n := &Node{
 		w:        w,
+		bw:       bw,
 		nt:       nt,
 		srv:      srv,
 		pm:       pm,
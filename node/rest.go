@@ -20,6 +20,7 @@ import (
 	"github.com/icon-project/goloop/common"
 	"github.com/icon-project/goloop/common/db"
 	"github.com/icon-project/goloop/common/errors"
+	"github.com/icon-project/goloop/common/statepruner"
 	"github.com/icon-project/goloop/module"
 	"github.com/icon-project/goloop/network"
 	"github.com/icon-project/goloop/server"
@@ -38,9 +39,20 @@ const (
 	UrlUserRes  = "/:" + ParamID
 	TaskID      = "task"
 
-	UrlDB    = "/db"
-	ParamBK  = "bucket"
-	ParamKey = "key"
+	UrlDB         = "/db"
+	ParamBK       = "bucket"
+	ParamKey      = "key"
+	UrlSnapshot   = "/snapshot"
+	ParamSnapshot = "snapshot"
+	UrlExport     = "/export"
+	ParamPrefix   = "prefix"
+	ParamCursor   = "cursor"
+	ParamLimit    = "limit"
+	ParamReverse  = "reverse"
+	ParamDecode   = "decode"
+
+	DefaultDBListLimit = 100
+	MaxDBListLimit     = 10000
 )
 
 type Rest struct {
@@ -103,6 +115,13 @@ type ChainConfig struct {
 	ChildrenLimit    *int   `json:"childrenLimit,omitempty"`
 	NephewsLimit     *int   `json:"nephewsLimit,omitempty"`
 	ValidateTxOnSend bool   `json:"validateTxOnSend,omitempty"`
+	StateSync        string `json:"stateSync,omitempty"`
+	TxPoolPolicy     string `json:"txPoolPolicy,omitempty"`
+	SenderTxCount    int    `json:"senderTxCount,omitempty"`
+	SenderTxBytes    int    `json:"senderTxBytes,omitempty"`
+	PeerTxRate       int    `json:"peerTxRate,omitempty"`
+	LogsIndex        bool   `json:"logsIndex,omitempty"`
+	StatePruning     int    `json:"statePruning,omitempty"`
 }
 
 type ChainResetParam struct {
@@ -121,7 +140,9 @@ type ChainPruneParam struct {
 }
 
 type ChainBackupParam struct {
-	Manual bool `json:"manual,omitempty"`
+	Manual bool   `json:"manual,omitempty"`
+	Type   string `json:"type,omitempty"`
+	Base   string `json:"base,omitempty"`
 }
 
 type ConfigureParam struct {
@@ -185,6 +206,13 @@ func NewChainConfig(cfg *chain.Config) *ChainConfig {
 		ChildrenLimit:    cfg.ChildrenLimit,
 		NephewsLimit:     cfg.NephewsLimit,
 		ValidateTxOnSend: cfg.ValidateTxOnSend,
+		StateSync:        cfg.StateSync,
+		TxPoolPolicy:     cfg.TxPoolPolicy,
+		SenderTxCount:    cfg.SenderTxCount,
+		SenderTxBytes:    cfg.SenderTxBytes,
+		PeerTxRate:       cfg.PeerTxRate,
+		LogsIndex:        cfg.LogsIndex,
+		StatePruning:     cfg.StatePruning,
 	}
 	return v
 }
@@ -197,6 +225,22 @@ func RegisterInspectFunc(name string, f InspectFunc) error {
 	return nil
 }
 
+type statePrunerHolder interface {
+	StatePruner() *statepruner.Pruner
+}
+
+func inspectStatePruning(c module.Chain, informal bool) map[string]interface{} {
+	if nc, ok := c.(*Chain); ok {
+		c = nc.Chain
+	}
+	if sh, ok := c.(statePrunerHolder); ok {
+		if sp := sh.StatePruner(); sp != nil {
+			return sp.Inspect(informal)
+		}
+	}
+	return nil
+}
+
 func RegisterRest(n *Node) {
 	r := Rest{
 		n: n,
@@ -216,6 +260,7 @@ func RegisterRest(n *Node) {
 	_ = RegisterInspectFunc("metrics", metric.Inspect)
 	_ = RegisterInspectFunc("network", network.Inspect)
 	_ = RegisterInspectFunc("service", service.Inspect)
+	_ = RegisterInspectFunc("statePruning", inspectStatePruning)
 
 	// json rpc
 	n.srv.RegisterAPIHandler(n.cliSrv.e.Group("/api"))
@@ -421,7 +466,7 @@ func (r *Rest) BackupChain(ctx echo.Context) error {
 	if err := ctx.Bind(param); err != nil {
 		return echo.ErrBadRequest
 	}
-	if name, err := r.n.BackupChain(c.CID(), param.Manual); err != nil {
+	if name, err := r.n.BackupChain(c.CID(), param.Manual, param.Type, param.Base); err != nil {
 		return err
 	} else {
 		return ctx.String(http.StatusOK, name)
@@ -664,33 +709,146 @@ func (r *Rest) ResponseStatsView(resp *echo.Response) error {
 }
 
 func (r *Rest) RegisterDBHandlers(g *echo.Group) {
-	bg := g.Group("/:"+ParamCID+"/:"+ParamBK, r.ChainInjector, r.BucketInjector)
-	bg.GET("/:"+ParamKey, r.BucketGetValue)
+	sg := g.Group("/:"+ParamCID+UrlSnapshot, r.ChainInjector, r.SnapshotManagerInjector)
+	sg.GET("", r.GetDBSnapshots)
+	sg.POST("", r.PinDBSnapshot)
+	sg.DELETE("/:"+ParamID, r.ReleaseDBSnapshot)
+
+	bg := g.Group("/:"+ParamCID+"/:"+ParamBK, r.ChainInjector)
+	bg.GET("", r.BucketListEntries, r.BucketInjector)
+	bg.GET(UrlExport, r.BucketExport, r.SnapshotBucketInjector)
+	bg.GET("/:"+ParamKey, r.BucketGetValue, r.BucketInjector)
+}
+
+func handleWithBucket(ctx echo.Context, database db.Database, next echo.HandlerFunc) error {
+	bk, err := database.GetBucket(db.BucketID(ctx.Param(ParamBK)))
+	if err != nil {
+		return ctx.String(http.StatusInternalServerError, "BucketFailure")
+	}
+	ctx.Set("database", database)
+	ctx.Set("bucket", bk)
+	return next(ctx)
+}
+
+// handleWithPinnedBucket handles the request with the bucket of the pinned
+// snapshot specified by the query parameter "snapshot". It returns false if
+// the parameter is not specified.
+func handleWithPinnedBucket(ctx echo.Context, next echo.HandlerFunc) (bool, error) {
+	id := ctx.QueryParam(ParamSnapshot)
+	if len(id) == 0 {
+		return false, nil
+	}
+	chain := ctx.Get("chain").(*Chain)
+	sm, ok := chain.Chain.(module.DBSnapshotManager)
+	if !ok {
+		return true, ctx.String(http.StatusNotImplemented, "SnapshotNotSupported")
+	}
+	snapshot, err := sm.GetDBSnapshot(id)
+	if err != nil {
+		return true, ctx.String(http.StatusNotFound, err.Error())
+	}
+	return true, handleWithBucket(ctx, snapshot.Database(), next)
 }
 
+// BucketInjector injects the bucket of the chain database. If the query
+// parameter "snapshot" is specified, then it uses the pinned snapshot.
 func (r *Rest) BucketInjector(next echo.HandlerFunc) echo.HandlerFunc {
 	return func(ctx echo.Context) error {
+		if ok, err := handleWithPinnedBucket(ctx, next); ok {
+			return err
+		}
 		chain := ctx.Get("chain").(*Chain)
-		bkID := db.BucketID(ctx.Param(ParamBK))
 		var ret error
 		chain.DoDBTask(func(database db.Database) {
 			if database == nil {
 				ret = ctx.String(http.StatusServiceUnavailable, "NoDatabase")
 				return
 			}
-			bk, err := database.GetBucket(bkID)
-			if err != nil {
-				ret = ctx.String(http.StatusInternalServerError, "BucketFailure")
-				return
-			}
-			ctx.Set("bucket", bk)
-			ret = next(ctx)
-			return
+			ret = handleWithBucket(ctx, database, next)
 		})
 		return ret
 	}
 }
 
+// SnapshotBucketInjector injects the bucket of a snapshot of the chain
+// database. Without the query parameter "snapshot", it pins a snapshot for
+// the request, so long requests don't block tasks closing the database.
+func (r *Rest) SnapshotBucketInjector(next echo.HandlerFunc) echo.HandlerFunc {
+	return func(ctx echo.Context) error {
+		if ok, err := handleWithPinnedBucket(ctx, next); ok {
+			return err
+		}
+		chain := ctx.Get("chain").(*Chain)
+		sm, ok := chain.Chain.(module.DBSnapshotManager)
+		if !ok {
+			return ctx.String(http.StatusNotImplemented, "SnapshotNotSupported")
+		}
+		snapshot, err := sm.PinDBSnapshot()
+		if err != nil {
+			return ctx.String(http.StatusServiceUnavailable, err.Error())
+		}
+		defer sm.ReleaseDBSnapshot(snapshot.ID())
+		return handleWithBucket(ctx, snapshot.Database(), next)
+	}
+}
+
+type DBSnapshotView struct {
+	ID        string          `json:"id"`
+	Height    common.HexInt64 `json:"height"`
+	CreatedAt time.Time       `json:"createdAt"`
+	ExpiresAt time.Time       `json:"expiresAt"`
+}
+
+func NewDBSnapshotView(s module.DBSnapshot) *DBSnapshotView {
+	return &DBSnapshotView{
+		ID:        s.ID(),
+		Height:    common.HexInt64{Value: s.Height()},
+		CreatedAt: s.CreatedAt(),
+		ExpiresAt: s.ExpiresAt(),
+	}
+}
+
+func (r *Rest) SnapshotManagerInjector(next echo.HandlerFunc) echo.HandlerFunc {
+	return func(ctx echo.Context) error {
+		chain := ctx.Get("chain").(*Chain)
+		sm, ok := chain.Chain.(module.DBSnapshotManager)
+		if !ok {
+			return ctx.String(http.StatusNotImplemented, "SnapshotNotSupported")
+		}
+		ctx.Set("snapshots", sm)
+		return next(ctx)
+	}
+}
+
+func (r *Rest) GetDBSnapshots(ctx echo.Context) error {
+	sm := ctx.Get("snapshots").(module.DBSnapshotManager)
+	l := make([]*DBSnapshotView, 0)
+	for _, s := range sm.DBSnapshots() {
+		l = append(l, NewDBSnapshotView(s))
+	}
+	return ctx.JSON(http.StatusOK, l)
+}
+
+func (r *Rest) PinDBSnapshot(ctx echo.Context) error {
+	sm := ctx.Get("snapshots").(module.DBSnapshotManager)
+	s, err := sm.PinDBSnapshot()
+	if err != nil {
+		return err
+	}
+	return ctx.JSON(http.StatusOK, NewDBSnapshotView(s))
+}
+
+func (r *Rest) ReleaseDBSnapshot(ctx echo.Context) error {
+	sm := ctx.Get("snapshots").(module.DBSnapshotManager)
+	if err := sm.ReleaseDBSnapshot(ctx.Param(ParamID)); err != nil {
+		if errors.NotFoundError.Equals(err) {
+			return echo.NewHTTPError(http.StatusNotFound, err.Error())
+		}
+		return err
+	}
+	return ctx.String(http.StatusOK, "OK")
+}
+
 func (r *Rest) BucketGetValue(ctx echo.Context) error {
 	bk := ctx.Get("bucket").(db.Bucket)
 	keyStr := ctx.Param(ParamKey)
@@ -708,6 +866,153 @@ func (r *Rest) BucketGetValue(ctx echo.Context) error {
 	return ctx.JSON(http.StatusOK, value)
 }
 
+// hexQueryParam returns bytes of the hex encoded query parameter.
+// It returns nil if the parameter is not specified.
+func hexQueryParam(ctx echo.Context, name string) ([]byte, error) {
+	s := ctx.QueryParam(name)
+	if len(s) == 0 {
+		return nil, nil
+	}
+	if len(s) >= 2 && s[0:2] == "0x" {
+		s = s[2:]
+	}
+	bs, err := hex.DecodeString(s)
+	if err != nil {
+		return nil, echo.NewHTTPError(http.StatusBadRequest,
+			fmt.Sprintf("InvalidParam(%s:%s)", name, s))
+	}
+	if bs == nil {
+		bs = []byte{}
+	}
+	return bs, nil
+}
+
+func boolQueryParam(ctx echo.Context, name string) (bool, error) {
+	s := ctx.QueryParam(name)
+	if len(s) == 0 {
+		return false, nil
+	}
+	v, err := strconv.ParseBool(s)
+	if err != nil {
+		return false, echo.NewHTTPError(http.StatusBadRequest,
+			fmt.Sprintf("InvalidParam(%s:%s)", name, s))
+	}
+	return v, nil
+}
+
+// BucketListEntries returns entries of the bucket in key order.
+// Returned entries are limited by the query parameter "limit", and "next"
+// of the result can be used as "cursor" of the following request.
+func (r *Rest) BucketListEntries(ctx echo.Context) error {
+	database := ctx.Get("database").(db.Database)
+	bk := ctx.Get("bucket").(db.Bucket)
+
+	prefix, err := hexQueryParam(ctx, ParamPrefix)
+	if err != nil {
+		return err
+	}
+	cursor, err := hexQueryParam(ctx, ParamCursor)
+	if err != nil {
+		return err
+	}
+	reverse, err := boolQueryParam(ctx, ParamReverse)
+	if err != nil {
+		return err
+	}
+	decode, err := boolQueryParam(ctx, ParamDecode)
+	if err != nil {
+		return err
+	}
+	limit := DefaultDBListLimit
+	if s := ctx.QueryParam(ParamLimit); len(s) > 0 {
+		limit, err = strconv.Atoi(s)
+		if err != nil || limit <= 0 || limit > MaxDBListLimit {
+			return echo.NewHTTPError(http.StatusBadRequest,
+				fmt.Sprintf("InvalidLimit(limit:%s)", s))
+		}
+	}
+
+	// cursor is the first key to return for forward iteration, and the
+	// last returned key(exclusive) for reverse iteration.
+	rg := &db.Range{Prefix: prefix, Reverse: reverse}
+	if reverse {
+		rg.Limit = cursor
+	} else {
+		rg.Start = cursor
+	}
+	it, err := db.NewIterator(bk, rg)
+	if err != nil {
+		if errors.UnsupportedError.Equals(err) {
+			return echo.NewHTTPError(http.StatusNotImplemented, err.Error())
+		}
+		return err
+	}
+	defer it.Release()
+
+	bkID := db.BucketID(ctx.Param(ParamBK))
+	v := &DBEntriesView{Entries: make([]*DBEntryView, 0)}
+	for it.Has() {
+		if len(v.Entries) == limit {
+			if reverse {
+				v.Next = v.Entries[limit-1].Key
+			} else {
+				v.Next = it.Key()
+			}
+			break
+		}
+		e := &DBEntryView{Key: it.Key(), Value: it.Value()}
+		if decode {
+			e.Decoded = DecodeDBEntry(database, bkID, e.Key, e.Value)
+		}
+		v.Entries = append(v.Entries, e)
+		if err := it.Next(); err != nil {
+			return err
+		}
+	}
+	return ctx.JSON(http.StatusOK, v)
+}
+
+// BucketExport streams all entries of the bucket in key order as
+// newline delimited JSON objects.
+func (r *Rest) BucketExport(ctx echo.Context) error {
+	bk := ctx.Get("bucket").(db.Bucket)
+	prefix, err := hexQueryParam(ctx, ParamPrefix)
+	if err != nil {
+		return err
+	}
+	it, err := db.NewIterator(bk, &db.Range{Prefix: prefix})
+	if err != nil {
+		if errors.UnsupportedError.Equals(err) {
+			return echo.NewHTTPError(http.StatusNotImplemented, err.Error())
+		}
+		return err
+	}
+	defer it.Release()
+
+	resp := ctx.Response()
+	resp.Header().Set(echo.HeaderContentType, echo.MIMEApplicationJSONCharsetUTF8)
+	resp.WriteHeader(http.StatusOK)
+	enc := json.NewEncoder(resp)
+	for cnt := 0; it.Has(); {
+		if err := enc.Encode(&DBEntryView{Key: it.Key(), Value: it.Value()}); err != nil {
+			if EqualsSyscallErrno(err, syscall.EPIPE) {
+				// closed by client
+				return nil
+			}
+			return err
+		}
+		if cnt += 1; cnt%MaxDBListLimit == 0 {
+			resp.Flush()
+		}
+		// the status is already sent, so it aborts the response
+		if err := it.Next(); err != nil {
+			return err
+		}
+	}
+	resp.Flush()
+	return nil
+}
+
 func EqualsSyscallErrno(err error, sen syscall.Errno) bool {
 	if oe, ok := err.(*net.OpError); ok {
 		if se, ok := oe.Err.(*os.SyscallError); ok {
@@ -23,6 +23,7 @@ import (
 	"os"
 	"path"
 	"sync"
+	"time"
 
 	"github.com/icon-project/goloop/chain"
 	"github.com/icon-project/goloop/common/codec"
@@ -129,8 +130,13 @@ func (m *RestoreManager) Start(node *Node, file string, baseDir string, overwrit
 		return err
 	}
 
+	entries, parents, err := openBackupChain(file, zr, info)
+	if err != nil {
+		return err
+	}
+
 	go func() {
-		if err := m._restore(node, zr, tmpDir, overwrite); err != nil {
+		if err := m._restore(node, zr, parents, entries, tmpDir, overwrite); err != nil {
 			node.logger.Debugf("Restore failed err=%+v", err)
 			if errors.InterruptedError.Equals(err) {
 				m._setState(RestoreNone, nil)
@@ -146,10 +152,88 @@ func (m *RestoreManager) Start(node *Node, file string, baseDir string, overwrit
 	m.overwrite = overwrite
 	m.state = RestoreStarted
 	m.current = 0
-	m.total = len(zr.File)
+	m.total = len(entries)
 	return nil
 }
 
+// restoreEntry is a file to be extracted for restore. Checksum is empty
+// for backups without manifest. Modification time is restored, so next
+// incremental backups can find files not changed since the backup.
+type restoreEntry struct {
+	file     *zip.File
+	checksum string
+	modTime  time.Time
+}
+
+// openBackupChain returns files to be extracted for restoring the backup.
+// If the backup has the manifest, then it also opens other backups having
+// files of the chain, and the files are verified with their checksums.
+func openBackupChain(file string, zr *zip.ReadCloser, info *chain.BackupInfo) (
+	entries []restoreEntry, parents []*zip.ReadCloser, ret error,
+) {
+	manifest, err := chain.ReadBackupManifest(&zr.Reader)
+	if err != nil {
+		return nil, nil, err
+	}
+	if manifest == nil {
+		for _, f := range zr.File {
+			entries = append(entries, restoreEntry{file: f, modTime: f.Modified})
+		}
+		return entries, nil, nil
+	}
+
+	defer func() {
+		if ret != nil {
+			for _, pzr := range parents {
+				pzr.Close()
+			}
+		}
+	}()
+	files := map[string]map[string]*zip.File{
+		"": zipFilesOf(&zr.Reader),
+	}
+	for _, name := range manifest.Backups() {
+		pzr, err := zip.OpenReader(path.Join(path.Dir(file), name))
+		if err != nil {
+			return nil, parents, errors.NotFoundError.Wrapf(err,
+				"ParentBackupNotFound(name=%s)", name)
+		}
+		parents = append(parents, pzr)
+		pi, err := chain.ReadBackupInfo(&pzr.Reader)
+		if err != nil {
+			return nil, parents, errors.IllegalArgumentError.Wrapf(err,
+				"InvalidBackupInfo(name=%s)", name)
+		}
+		if pi.NID != info.NID || pi.CID != info.CID ||
+			pi.Channel != info.Channel || pi.Height > info.Height {
+			return nil, parents, errors.IllegalArgumentError.Errorf(
+				"IncompatibleParent(name=%s)", name)
+		}
+		files[name] = zipFilesOf(&pzr.Reader)
+	}
+	for _, f := range manifest.Files {
+		zf, ok := files[f.Backup][f.Name]
+		if !ok {
+			return nil, parents, errors.CriticalFormatError.Errorf(
+				"MissingBackupFile(file=%s,backup=%s)", f.Name, f.Backup)
+		}
+		entries = append(entries, restoreEntry{
+			file:     zf,
+			checksum: f.SHA256,
+			modTime:  time.Unix(0, f.ModTime),
+		})
+	}
+	return entries, parents, nil
+}
+
+func zipFilesOf(zr *zip.Reader) map[string]*zip.File {
+	files := make(map[string]*zip.File, len(zr.File))
+	for _, f := range zr.File {
+		files[f.Name] = f
+	}
+	return files
+}
+
 func (m *RestoreManager) _onRestored(idx int) error {
 	m.lock.Lock()
 	defer m.lock.Unlock()
@@ -201,7 +285,8 @@ func (m *RestoreManager) _setStateInLock(s RestoreState, e error) {
 	}
 }
 
-func zipExtract(file *zip.File, tmpDir string) (ret error) {
+func zipExtract(e restoreEntry, tmpDir string) (ret error) {
+	file := e.file
 	rc, err := file.Open()
 	if err != nil {
 		return err
@@ -229,21 +314,34 @@ func zipExtract(file *zip.File, tmpDir string) (ret error) {
 	}
 	defer fd.Close()
 
-	_, err = io.Copy(fd, rc)
-
-	return err
+	cr := chain.NewChecksumReader(rc)
+	if _, err = io.Copy(fd, cr); err != nil {
+		return err
+	}
+	if e.checksum != "" && cr.Checksum() != e.checksum {
+		return errors.CriticalHashError.Errorf(
+			"ChecksumMismatch(file=%s,exp=%s,calc=%s)",
+			file.Name, e.checksum, cr.Checksum())
+	}
+	if !e.modTime.IsZero() {
+		return os.Chtimes(target, e.modTime, e.modTime)
+	}
+	return nil
 }
 
-func (m *RestoreManager) _restore(node *Node, zr *zip.ReadCloser, tmpDir string, overwrite bool) (ret error) {
+func (m *RestoreManager) _restore(node *Node, zr *zip.ReadCloser, parents []*zip.ReadCloser, entries []restoreEntry, tmpDir string, overwrite bool) (ret error) {
 	defer func() {
 		if ret != nil {
 			os.RemoveAll(tmpDir)
 		}
 	}()
 	defer zr.Close()
+	for _, pzr := range parents {
+		defer pzr.Close()
+	}
 
-	for idx, file := range zr.File {
-		if err := zipExtract(file, tmpDir); err != nil {
+	for idx, e := range entries {
+		if err := zipExtract(e, tmpDir); err != nil {
 			return err
 		}
 		if err := m._onRestored(idx); err != nil {
@@ -0,0 +1,171 @@
+/*
+ * Copyright 2026 ICON Foundation
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package node
+
+import (
+	"archive/zip"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"os"
+	"path"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/icon-project/goloop/chain"
+	"github.com/icon-project/goloop/common"
+	"github.com/icon-project/goloop/common/codec"
+	"github.com/icon-project/goloop/common/errors"
+)
+
+type testBackupFile struct {
+	name    string
+	content string
+	backup  string
+}
+
+func checksumOf(content string) string {
+	h := sha256.Sum256([]byte(content))
+	return hex.EncodeToString(h[:])
+}
+
+// writeTestBackup writes the backup with the manifest. Files of other
+// backups are only listed in the manifest.
+func writeTestBackup(t *testing.T, file string, height int64, mt time.Time, files []testBackupFile) {
+	fd, err := os.Create(file)
+	assert.NoError(t, err)
+	defer fd.Close()
+	zw := zip.NewWriter(fd)
+
+	info := &chain.BackupInfo{
+		NID:     common.HexInt32{Value: 1},
+		CID:     common.HexInt32{Value: 1},
+		Channel: "test",
+		Height:  height,
+		Codec:   codec.BC.Name(),
+		Type:    chain.BackupTypeFull,
+	}
+	bs, err := json.Marshal(info)
+	assert.NoError(t, err)
+	assert.NoError(t, zw.SetComment(string(bs)))
+
+	var manifest chain.BackupManifest
+	for _, f := range files {
+		manifest.Files = append(manifest.Files, &chain.BackupFile{
+			Name:    f.name,
+			Size:    int64(len(f.content)),
+			ModTime: mt.UnixNano(),
+			SHA256:  checksumOf(f.content),
+			Backup:  f.backup,
+		})
+		if f.backup != "" {
+			continue
+		}
+		w, err := zw.Create(f.name)
+		assert.NoError(t, err)
+		_, err = w.Write([]byte(f.content))
+		assert.NoError(t, err)
+	}
+	w, err := zw.Create(chain.BackupManifestFile)
+	assert.NoError(t, err)
+	assert.NoError(t, json.NewEncoder(w).Encode(&manifest))
+	assert.NoError(t, zw.Close())
+}
+
+func restoreTestBackup(t *testing.T, file string, dir string) error {
+	zr, err := zip.OpenReader(file)
+	assert.NoError(t, err)
+	defer zr.Close()
+	info, err := chain.ReadBackupInfo(&zr.Reader)
+	assert.NoError(t, err)
+
+	entries, parents, err := openBackupChain(file, zr, info)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		for _, pzr := range parents {
+			pzr.Close()
+		}
+	}()
+	for _, e := range entries {
+		if err := zipExtract(e, dir); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func TestRestore_Incremental(t *testing.T) {
+	base := t.TempDir()
+	mt1 := time.Unix(1700000000, 123456789)
+	mt2 := mt1.Add(time.Second)
+	full := path.Join(base, "full.zip")
+	writeTestBackup(t, full, 10, mt1, []testBackupFile{
+		{name: "db/a", content: "a0"},
+		{name: "db/b", content: "b0"},
+	})
+	inc := path.Join(base, "inc.zip")
+	writeTestBackup(t, inc, 20, mt2, []testBackupFile{
+		{name: "db/a", content: "a0", backup: "full.zip"},
+		{name: "db/b", content: "b1"},
+	})
+
+	dir := path.Join(base, "restore")
+	assert.NoError(t, restoreTestBackup(t, inc, dir))
+	for name, content := range map[string]string{"db/a": "a0", "db/b": "b1"} {
+		bs, err := os.ReadFile(path.Join(dir, name))
+		assert.NoError(t, err)
+		assert.Equal(t, content, string(bs))
+
+		// modification time in the manifest is restored
+		st, err := os.Stat(path.Join(dir, name))
+		assert.NoError(t, err)
+		assert.Equal(t, mt2.UnixNano(), st.ModTime().UnixNano())
+	}
+}
+
+func TestRestore_MissingParent(t *testing.T) {
+	base := t.TempDir()
+	inc := path.Join(base, "inc.zip")
+	writeTestBackup(t, inc, 20, time.Now(), []testBackupFile{
+		{name: "db/a", content: "a0", backup: "full.zip"},
+	})
+	err := restoreTestBackup(t, inc, path.Join(base, "restore"))
+	assert.True(t, errors.NotFoundError.Equals(err), "err=%+v", err)
+
+	// the parent without the file
+	writeTestBackup(t, path.Join(base, "full.zip"), 10, time.Now(), nil)
+	err = restoreTestBackup(t, inc, path.Join(base, "restore"))
+	assert.True(t, errors.CriticalFormatError.Equals(err), "err=%+v", err)
+}
+
+func TestRestore_ChecksumMismatch(t *testing.T) {
+	base := t.TempDir()
+	full := path.Join(base, "full.zip")
+	writeTestBackup(t, full, 10, time.Now(), []testBackupFile{
+		{name: "db/a", content: "a0"},
+	})
+	inc := path.Join(base, "inc.zip")
+	writeTestBackup(t, inc, 20, time.Now(), []testBackupFile{
+		{name: "db/a", content: "a1", backup: "full.zip"},
+	})
+	err := restoreTestBackup(t, inc, path.Join(base, "restore"))
+	assert.True(t, errors.CriticalHashError.Equals(err), "err=%+v", err)
+}
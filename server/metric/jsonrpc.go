@@ -70,11 +70,26 @@ var (
 			stats.Int64("jsonrpc_get_trace_avg", "moving average of jsonrpc debug_getTrace method", "ns"),
 			emptyMks,
 		},
+		"debug_traceBlock": {
+			stats.Int64("jsonrpc_trace_block", "jsonrpc debug_traceBlock method", "ns"),
+			stats.Int64("jsonrpc_trace_block_avg", "moving average of jsonrpc debug_traceBlock method", "ns"),
+			emptyMks,
+		},
 		"debug_estimateStep": {
 			stats.Int64("jsonrpc_estimate_step", "jsonrpc debug_estimateStep method", "ns"),
 			stats.Int64("jsonrpc_estimate_step_avg", "moving average of jsonrpc debug_estimateStep method", "ns"),
 			emptyMks,
 		},
+		"debug_simulateTransactions": {
+			stats.Int64("jsonrpc_simulate_transactions", "jsonrpc debug_simulateTransactions method", "ns"),
+			stats.Int64("jsonrpc_simulate_transactions_avg", "moving average of jsonrpc debug_simulateTransactions method", "ns"),
+			emptyMks,
+		},
+		"debug_getStateDiff": {
+			stats.Int64("jsonrpc_get_state_diff", "jsonrpc debug_getStateDiff method", "ns"),
+			stats.Int64("jsonrpc_get_state_diff_avg", "moving average of jsonrpc debug_getStateDiff method", "ns"),
+			emptyMks,
+		},
 		"rosetta_getTrace": {
 			stats.Int64("jsonrpc_rosetta_trace_", "jsonrpc rosetta_getTrace method", "ns"),
 			stats.Int64("jsonrpc_rosetta_trace_avg", "moving average of jsonrpc rosetta_getTTrace method", "ns"),
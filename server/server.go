@@ -252,9 +252,17 @@ func (srv *Manager) RegisterAPIHandler(g *echo.Group) {
 	// group for websocket
 	ws := g.Group("")
 	ws.Use(srv.CheckRPC())
+	ws.Use(func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(ctx echo.Context) error {
+			ctx.Set("includeDebug", srv.IncludeDebug())
+			return next(ctx)
+		}
+	})
 	ws.GET("/v3/:channel/block", srv.wssm.RunBlockSession, ChainInjector(srv))
 	ws.GET("/v3/:channel/event", srv.wssm.RunEventSession, ChainInjector(srv))
 	ws.GET("/v3/:channel/btp", srv.wssm.RunBtpSession, ChainInjector(srv))
+	ws.GET("/v3/:channel/pending", srv.wssm.RunPendingSession, srv.CheckDebug(), ChainInjector(srv))
+	ws.GET("/v3/:channel/ws", srv.wssm.RunRPCSession, ChainInjector(srv))
 }
 
 func (srv *Manager) RegisterMetricsHandler(g *echo.Group) {
@@ -4,6 +4,7 @@ import (
 	"bytes"
 	"encoding/base64"
 	"encoding/hex"
+	"encoding/json"
 	"fmt"
 	"strconv"
 	"strings"
@@ -16,6 +17,7 @@ import (
 	"github.com/icon-project/goloop/common/db"
 	"github.com/icon-project/goloop/common/errors"
 	"github.com/icon-project/goloop/common/intconv"
+	"github.com/icon-project/goloop/common/statepruner"
 	"github.com/icon-project/goloop/module"
 	"github.com/icon-project/goloop/server/jsonrpc"
 	"github.com/icon-project/goloop/server/metric"
@@ -53,6 +55,7 @@ func MethodRepository(mtr *metric.JsonrpcMetric) *jsonrpc.MethodRepository {
 	mr.RegisterMethod("icx_getProofForEvents", getProofForEvents)
 	mr.RegisterMethod("icx_getScoreStatus", getScoreStatus)
 	mr.RegisterMethod("icx_getNetworkInfo", getNetworkInfo)
+	mr.RegisterMethod("icx_getLogs", getLogs)
 
 	mr.RegisterMethod("btp_getNetworkInfo", getBTPNetworkInfo)
 	mr.RegisterMethod("btp_getNetworkTypeInfo", getBTPNetworkTypeInfo)
@@ -136,6 +139,32 @@ func (c *contextWithChain) CheckBaseHeight(height int64) error {
 	return nil
 }
 
+type stateFloorGetter interface {
+	StateFloor() (int64, error)
+}
+
+// CheckStateHeight returns jsonrpc.ErrorCodeNotFound for lower height
+// than the floor of world states broken by the state pruning.
+func (c *contextWithChain) CheckStateHeight(height int64) error {
+	sfg, ok := c.chain.(stateFloorGetter)
+	if !ok {
+		return nil
+	}
+	floor, err := sfg.StateFloor()
+	if err != nil {
+		return c.AsRPCError(err)
+	}
+	return checkStateFloor(height, floor)
+}
+
+func checkStateFloor(height, floor int64) error {
+	if height < floor {
+		return jsonrpc.ErrorCodeNotFound.Errorf(
+			"PrunedState(height=%d,floor=%d)", height, floor)
+	}
+	return nil
+}
+
 type contextWithBM struct {
 	contextWithChain
 	bm module.BlockManager
@@ -186,6 +215,19 @@ type contextWithSM struct {
 	sm module.ServiceManager
 }
 
+// GetStateBlockByHeight returns the block at the height, whose world state
+// is required for the query.
+func (c *contextWithSM) GetStateBlockByHeight(height jsonrpc.HexInt) (module.Block, error) {
+	blk, err := c.GetBlockByHeight(height)
+	if err != nil {
+		return nil, err
+	}
+	if err = c.CheckStateHeight(blk.Height()); err != nil {
+		return nil, err
+	}
+	return blk, nil
+}
+
 func (c *contextWithSM) Init(ctx *jsonrpc.Context) error {
 	if err := c.contextWithBM.Init(ctx); err != nil {
 		return err
@@ -305,15 +347,35 @@ func call(ctx *jsonrpc.Context, params *jsonrpc.Params) (interface{}, error) {
 		return nil, jsonrpc.ErrorCodeInvalidParams.Wrap(err, c.debug)
 	}
 
-	blk, err := c.GetBlockByHeight(param.Height)
+	if len(param.StateOverrides) > 0 {
+		if !c.debug {
+			return nil, jsonrpc.ErrorCodeInvalidRequest.New(
+				"StateOverridesNotAllowed")
+		}
+		if len(param.Snapshot) > 0 {
+			return nil, jsonrpc.ErrorCodeInvalidParams.New(
+				"StateOverridesWithSnapshot")
+		}
+		return callWithOverrides(&c, &param, params)
+	}
+	if len(param.Snapshot) > 0 {
+		return callInSnapshot(&c, &param, params)
+	}
+
+	blk, err := c.GetStateBlockByHeight(param.Height)
 	if err != nil {
 		return nil, err
 	}
 
 	bi := common.NewBlockInfo(blk.Height(), blk.Timestamp())
 	result, err := c.sm.Call(blk.Result(), blk.NextValidators(), params.RawMessage(), bi)
+	return c.callResult(result, err)
+}
+
+func (c *contextWithSM) callResult(result interface{}, err error) (interface{}, error) {
 	if err != nil {
-		if service.InvalidQueryError.Equals(err) {
+		if service.InvalidQueryError.Equals(err) ||
+			service.InvalidStateOverrideError.Equals(err) {
 			return nil, jsonrpc.ErrorCodeInvalidParams.Wrap(err, c.debug)
 		} else if scoreresult.IsValid(err) {
 			return nil, jsonrpc.ErrScore(err, c.debug)
@@ -325,6 +387,102 @@ func call(ctx *jsonrpc.Context, params *jsonrpc.Params) (interface{}, error) {
 	}
 }
 
+type stateOverrider interface {
+	CallWithOverrides(result []byte, vl module.ValidatorList, js []byte, bi module.BlockInfo, overrides []byte) (interface{}, error)
+	ExecuteTransactionWithOverrides(result []byte, vh []byte, js []byte, bi module.BlockInfo, overrides []byte) (module.Receipt, error)
+}
+
+// withoutStateOverrides returns the parameters without stateOverrides, and
+// the state manager supporting them.
+func withoutStateOverrides(c *contextWithSM, params *jsonrpc.Params) (stateOverrider, []byte, error) {
+	so, ok := c.sm.(stateOverrider)
+	if !ok {
+		return nil, nil, jsonrpc.ErrorCodeInvalidRequest.New("StateOverridesNotSupported")
+	}
+	var jso map[string]json.RawMessage
+	if err := json.Unmarshal(params.RawMessage(), &jso); err != nil {
+		return nil, nil, jsonrpc.ErrorCodeInvalidParams.Wrap(err, c.debug)
+	}
+	delete(jso, "stateOverrides")
+	js, err := json.Marshal(jso)
+	if err != nil {
+		return nil, nil, jsonrpc.ErrorCodeSystem.Wrap(err, c.debug)
+	}
+	return so, js, nil
+}
+
+// callWithOverrides runs the query after applying stateOverrides to the
+// virtual world state of the block.
+func callWithOverrides(c *contextWithSM, param *CallParam, params *jsonrpc.Params) (interface{}, error) {
+	so, js, err := withoutStateOverrides(c, params)
+	if err != nil {
+		return nil, err
+	}
+	blk, err := c.GetStateBlockByHeight(param.Height)
+	if err != nil {
+		return nil, err
+	}
+	bi := common.NewBlockInfo(blk.Height(), blk.Timestamp())
+	result, err := so.CallWithOverrides(blk.Result(), blk.NextValidators(), js, bi, param.StateOverrides)
+	return c.callResult(result, err)
+}
+
+type snapshotCaller interface {
+	CallInDatabase(dbase db.Database, result []byte, vl module.ValidatorList, js []byte, bi module.BlockInfo) (interface{}, error)
+}
+
+// callInSnapshot runs the query against the pinned database snapshot.
+// Height defaults to the height of the snapshot, and it can't be greater
+// than the height of the snapshot.
+func callInSnapshot(c *contextWithSM, param *CallParam, params *jsonrpc.Params) (interface{}, error) {
+	sm, ok := c.sm.(snapshotCaller)
+	if !ok {
+		return nil, jsonrpc.ErrorCodeInvalidRequest.New("SnapshotCallNotSupported")
+	}
+	sdb, err := getDBSnapshot(c.chain, param.Snapshot)
+	if err != nil {
+		return nil, c.AsRPCError(err)
+	}
+	height := sdb.Height()
+	if param.Height != "" {
+		h, err := param.Height.Int64()
+		if err != nil {
+			return nil, jsonrpc.ErrorCodeInvalidParams.Wrap(err, c.debug)
+		}
+		if h > height {
+			return nil, jsonrpc.ErrorCodeInvalidParams.Errorf(
+				"HeightOverSnapshot(height=%d,snapshot=%d)", h, height)
+		}
+		height = h
+	}
+	if err = c.CheckBaseHeight(height); err != nil {
+		return nil, err
+	}
+	floor, err := statepruner.ReadFloor(sdb.Database())
+	if err != nil {
+		return nil, c.AsRPCError(err)
+	}
+	if err = checkStateFloor(height, floor); err != nil {
+		return nil, err
+	}
+	blk, err := c.bm.GetBlockByHeight(height)
+	if err != nil {
+		return nil, c.AsRPCError(err)
+	}
+	bi := common.NewBlockInfo(blk.Height(), blk.Timestamp())
+	result, err := sm.CallInDatabase(sdb.Database(), blk.Result(), blk.NextValidators(), params.RawMessage(), bi)
+	return c.callResult(result, err)
+}
+
+// getDBSnapshot returns the pinned database snapshot of the chain.
+func getDBSnapshot(chain module.Chain, id string) (module.DBSnapshot, error) {
+	sm, ok := chain.(module.DBSnapshotManager)
+	if !ok {
+		return nil, errors.UnsupportedError.New("DBSnapshotNotSupported")
+	}
+	return sm.GetDBSnapshot(id)
+}
+
 func getBalance(ctx *jsonrpc.Context, params *jsonrpc.Params) (interface{}, error) {
 	var c contextWithSM
 	if err := c.Init(ctx); err != nil {
@@ -337,7 +495,7 @@ func getBalance(ctx *jsonrpc.Context, params *jsonrpc.Params) (interface{}, erro
 	}
 
 	var balance common.HexInt
-	blk, err := c.GetBlockByHeight(param.Height)
+	blk, err := c.GetStateBlockByHeight(param.Height)
 	if err != nil {
 		return nil, err
 	}
@@ -361,7 +519,7 @@ func getScoreApi(ctx *jsonrpc.Context, params *jsonrpc.Params) (interface{}, err
 		return nil, jsonrpc.ErrorCodeInvalidParams.Wrap(err, c.debug)
 	}
 
-	b, err := c.GetBlockByHeight(param.Height)
+	b, err := c.GetStateBlockByHeight(param.Height)
 	if err != nil {
 		return nil, err
 	}
@@ -394,7 +552,7 @@ func getTotalSupply(ctx *jsonrpc.Context, params *jsonrpc.Params) (interface{},
 		}
 	}
 
-	b, err := c.GetBlockByHeight(height)
+	b, err := c.GetStateBlockByHeight(height)
 	if err != nil {
 		return nil, err
 	}
@@ -444,7 +602,10 @@ func getTransactionResult(ctx *jsonrpc.Context, params *jsonrpc.Params) (interfa
 		return nil, jsonrpc.ErrorCodeSystem.Wrap(err, c.debug)
 	}
 
-	result := res.(map[string]interface{})
+	result, ok := res.(map[string]interface{})
+	if !ok {
+		return nil, jsonrpc.ErrorCodeSystem.Errorf("InvalidStateDiff(type=%T)", res)
+	}
 	result["blockHash"] = "0x" + hex.EncodeToString(blk.ID())
 	result["blockHeight"] = "0x" + strconv.FormatInt(blk.Height(), 16)
 	result["txIndex"] = "0x" + strconv.FormatInt(int64(txInfo.Index()), 16)
@@ -482,7 +643,10 @@ func getTransactionByHash(ctx *jsonrpc.Context, params *jsonrpc.Params) (interfa
 	if err = c.CheckBaseHeight(blk.Height()); err != nil {
 		return nil, err
 	}
-	result := res.(map[string]interface{})
+	result, ok := res.(map[string]interface{})
+	if !ok {
+		return nil, jsonrpc.ErrorCodeSystem.Errorf("InvalidStateDiff(type=%T)", res)
+	}
 	result["blockHash"] = "0x" + hex.EncodeToString(blk.ID())
 	result["blockHeight"] = "0x" + strconv.FormatInt(blk.Height(), 16)
 	result["txIndex"] = "0x" + strconv.FormatInt(int64(txInfo.Index()), 16)
@@ -517,6 +681,9 @@ func sendTransaction(ctx *jsonrpc.Context, params *jsonrpc.Params) (interface{},
 		if service.TransactionPoolOverflowError.Equals(err) {
 			return nil, jsonrpc.ErrorCodeTxPoolOverflow.Wrap(err, c.debug)
 		}
+		if service.SenderQuotaExceededError.Equals(err) {
+			return nil, jsonrpc.ErrorCodeQuotaExceeded.Wrap(err, c.debug)
+		}
 		return nil, jsonrpc.ErrorCodeSystem.Wrap(err, c.debug)
 	}
 
@@ -714,7 +881,7 @@ func getScoreStatus(ctx *jsonrpc.Context, params *jsonrpc.Params) (interface{},
 		return nil, jsonrpc.ErrorCodeInvalidParams.Wrap(err, c.debug)
 	}
 
-	b, err := c.GetBlockByHeight(param.Height)
+	b, err := c.GetStateBlockByHeight(param.Height)
 	if err != nil {
 		return nil, err
 	}
@@ -778,7 +945,7 @@ func getBTPNetworkInfo(ctx *jsonrpc.Context, params *jsonrpc.Params) (interface{
 		return nil, jsonrpc.ErrorCodeInvalidParams.Wrap(err, c.debug)
 	}
 
-	blk, err := c.GetBlockByHeight(param.Height)
+	blk, err := c.GetStateBlockByHeight(param.Height)
 	if err != nil {
 		return nil, err
 	}
@@ -814,7 +981,7 @@ func getBTPNetworkTypeInfo(ctx *jsonrpc.Context, params *jsonrpc.Params) (interf
 		return nil, jsonrpc.ErrorCodeInvalidParams.Wrap(err, c.debug)
 	}
 
-	blk, err := c.GetBlockByHeight(param.Height)
+	blk, err := c.GetStateBlockByHeight(param.Height)
 	if err != nil {
 		return nil, err
 	}
@@ -1042,6 +1209,9 @@ func sendTransactionAndWait(ctx *jsonrpc.Context, params *jsonrpc.Params) (inter
 		if service.TransactionPoolOverflowError.Equals(err) {
 			return nil, jsonrpc.ErrorCodeTxPoolOverflow.Wrap(err, c.debug)
 		}
+		if service.SenderQuotaExceededError.Equals(err) {
+			return nil, jsonrpc.ErrorCodeQuotaExceeded.Wrap(err, c.debug)
+		}
 		return nil, jsonrpc.ErrorCodeSystem.Wrap(err, c.debug)
 	}
 
@@ -1134,7 +1304,10 @@ func waitTransactionResultOnChannel(c *contextWithBM, id []byte, timeout time.Du
 	if err != nil {
 		return nil, jsonrpc.ErrorCodeSystem.Wrap(err, c.debug)
 	}
-	result := res.(map[string]interface{})
+	result, ok := res.(map[string]interface{})
+	if !ok {
+		return nil, jsonrpc.ErrorCodeSystem.Errorf("InvalidStateDiff(type=%T)", res)
+	}
 	result["blockHash"] = "0x" + hex.EncodeToString(blk.ID())
 	result["blockHeight"] = "0x" + strconv.FormatInt(blk.Height(), 16)
 	result["txIndex"] = "0x" + strconv.FormatInt(int64(txInfo.Index()), 16)
@@ -1148,7 +1321,12 @@ func DebugMethodRepository(mtr *metric.JsonrpcMetric) *jsonrpc.MethodRepository
 	RegisterValidationRule(mr.Validator())
 
 	mr.RegisterMethod("debug_getTrace", getTrace)
+	mr.RegisterMethod("debug_traceBlock", traceBlock)
 	mr.RegisterMethod("debug_estimateStep", estimateStep)
+	mr.RegisterMethod("debug_simulateTransactions", simulateTransactions)
+	mr.RegisterMethod("debug_getStateDiff", getStateDiff)
+	mr.RegisterMethod("debug_getPendingTransactions", getPendingTransactions)
+	mr.RegisterMethod("debug_getPoolStatus", getPoolStatus)
 
 	return mr
 }
@@ -1159,7 +1337,7 @@ func getTrace(ctx *jsonrpc.Context, params *jsonrpc.Params) (interface{}, error)
 		return nil, err
 	}
 
-	var param TransactionHashParam
+	var param TraceParam
 	if err := params.Convert(&param); err != nil {
 		return nil, jsonrpc.ErrorCodeInvalidParams.Wrap(err, c.debug)
 	}
@@ -1182,6 +1360,9 @@ func getTrace(ctx *jsonrpc.Context, params *jsonrpc.Params) (interface{}, error)
 	if err = c.CheckBaseHeight(blk.Height()); err != nil {
 		return nil, err
 	}
+	if err = c.CheckStateHeight(blk.Height()); err != nil {
+		return nil, err
+	}
 	_, err = txInfo.GetReceipt()
 	if block.ResultNotFinalizedError.Equals(err) {
 		return nil, jsonrpc.ErrorCodeExecuting.New("Executing")
@@ -1210,8 +1391,13 @@ func getTrace(ctx *jsonrpc.Context, params *jsonrpc.Params) (interface{}, error)
 		logs:    make([]interface{}, 0, 100),
 		channel: make(chan interface{}, 10),
 	}
+	traceMode := module.TraceModeInvoke
+	if param.Mode == TraceModeCallTree {
+		traceMode = module.TraceModeCallTree
+		cb.ct = trace.NewCallTracer()
+	}
 	ti := module.TraceInfo{
-		TraceMode: module.TraceModeInvoke,
+		TraceMode: traceMode,
 		Range:     module.TraceRangeTransaction,
 		Group:     txInfo.Group(),
 		Index:     txInfo.Index(),
@@ -1230,11 +1416,79 @@ func getTrace(ctx *jsonrpc.Context, params *jsonrpc.Params) (interface{}, error)
 			return nil, jsonrpc.ErrorCodeSystemTimeout.Errorf(
 				"Not enough time to get result of %x", param.Hash.Bytes())
 		case <-cb.channel:
+			if traceMode == module.TraceModeCallTree {
+				return cb.callTreeToJSON(txInfo.Index()), nil
+			}
 			return cb.invokeTraceToJSON(), nil
 		}
 	}
 }
 
+// traceBlock replays all transactions in the block, and returns call trees
+// of them with the step profile aggregated by contract and method.
+func traceBlock(ctx *jsonrpc.Context, params *jsonrpc.Params) (interface{}, error) {
+	var c contextWithSM
+	if err := c.Init(ctx); err != nil {
+		return nil, err
+	}
+
+	var param BlockHeightParam
+	if err := params.Convert(&param); err != nil {
+		return nil, jsonrpc.ErrorCodeInvalidParams.Wrap(err, c.debug)
+	}
+
+	blk, err := c.GetStateBlockByHeight(param.Height)
+	if err != nil {
+		return nil, err
+	}
+	nblk, err := c.bm.GetBlockByHeight(blk.Height() + 1)
+	if errors.NotFoundError.Equals(err) {
+		return nil, jsonrpc.ErrorCodeExecuting.New("Executing")
+	} else if err != nil {
+		return nil, jsonrpc.ErrorCodeSystem.Wrap(err, c.debug)
+	}
+	csi, err := c.bm.NewConsensusInfo(blk)
+	if err != nil {
+		return nil, jsonrpc.ErrorCodeSystem.Wrap(err, c.debug)
+	}
+	tr1, err := c.sm.CreateInitialTransition(blk.Result(), blk.NextValidators())
+	if err != nil {
+		return nil, jsonrpc.ErrorCodeSystem.Wrap(err, c.debug)
+	}
+	tr2, err := c.sm.CreateTransition(tr1, blk.NormalTransactions(), blk, csi, true)
+	if err != nil {
+		return nil, jsonrpc.ErrorCodeSystem.Wrap(err, c.debug)
+	}
+	tr2 = c.sm.PatchTransition(tr2, nblk.PatchTransactions(), nblk)
+
+	cb := &traceCallback{
+		channel: make(chan interface{}, 10),
+		ct:      trace.NewCallTracer(),
+	}
+	ti := module.TraceInfo{
+		TraceMode:  module.TraceModeCallTree,
+		TraceBlock: trace.NewTraceBlock(blk.ID(), nil),
+		Range:      module.TraceRangeBlock,
+		Callback:   cb,
+	}
+	canceller, err := tr2.ExecuteForTrace(ti)
+	if err != nil {
+		return nil, jsonrpc.ErrorCodeSystem.Wrap(err, c.debug)
+	}
+
+	timer := time.After(time.Second * 60)
+	for {
+		select {
+		case <-timer:
+			canceller()
+			return nil, jsonrpc.ErrorCodeSystemTimeout.Errorf(
+				"Not enough time to trace block height=%d", blk.Height())
+		case <-cb.channel:
+			return cb.blockTraceToJSON(blk), nil
+		}
+	}
+}
+
 func estimateStep(ctx *jsonrpc.Context, params *jsonrpc.Params) (interface{}, error) {
 	var c contextWithSM
 	if err := c.Init(ctx); err != nil {
@@ -1261,13 +1515,32 @@ func estimateStep(ctx *jsonrpc.Context, params *jsonrpc.Params) (interface{}, er
 	bi := common.NewBlockInfo(blk.Height()+1, newTS)
 
 	// execute transaction
-	rct, err := c.sm.ExecuteTransaction(
-		blk.Result(),
-		blk.NextValidators().Hash(),
-		params.RawMessage(),
-		bi,
-	)
+	var rct module.Receipt
+	if len(param.StateOverrides) > 0 {
+		var so stateOverrider
+		var js []byte
+		if so, js, err = withoutStateOverrides(&c, params); err != nil {
+			return nil, err
+		}
+		rct, err = so.ExecuteTransactionWithOverrides(
+			blk.Result(),
+			blk.NextValidators().Hash(),
+			js,
+			bi,
+			param.StateOverrides,
+		)
+	} else {
+		rct, err = c.sm.ExecuteTransaction(
+			blk.Result(),
+			blk.NextValidators().Hash(),
+			params.RawMessage(),
+			bi,
+		)
+	}
 	if err != nil {
+		if service.InvalidStateOverrideError.Equals(err) {
+			return nil, jsonrpc.ErrorCodeInvalidParams.Wrap(err, c.debug)
+		}
 		return nil, jsonrpc.ErrorCodeServer.Wrap(err, c.debug)
 	}
 	if status := rct.Status(); status != module.StatusSuccess {
@@ -1283,6 +1556,100 @@ func estimateStep(ctx *jsonrpc.Context, params *jsonrpc.Params) (interface{}, er
 	return steps, nil
 }
 
+type txSimulator interface {
+	SimulateTransactions(result []byte, vh []byte, txs [][]byte, bi module.BlockInfo, overrides []byte, ti *module.TraceInfo) ([]interface{}, error)
+}
+
+// simulateTransactions executes the transactions in order on the state of
+// the block without storing them. Without height, they are executed on the
+// last block in the same way as estimateStep.
+func simulateTransactions(ctx *jsonrpc.Context, params *jsonrpc.Params) (interface{}, error) {
+	var c contextWithSM
+	if err := c.Init(ctx); err != nil {
+		return nil, err
+	}
+
+	var param SimulateTransactionsParam
+	if err := params.Convert(&param); err != nil {
+		return nil, jsonrpc.ErrorCodeInvalidParams.Wrap(err, c.debug)
+	}
+	var raw struct {
+		Transactions []json.RawMessage `json:"transactions"`
+	}
+	if err := json.Unmarshal(params.RawMessage(), &raw); err != nil {
+		return nil, jsonrpc.ErrorCodeInvalidParams.Wrap(err, c.debug)
+	}
+	txs := make([][]byte, len(raw.Transactions))
+	for i, tx := range raw.Transactions {
+		txs[i] = tx
+	}
+
+	ts, ok := c.sm.(txSimulator)
+	if !ok {
+		return nil, jsonrpc.ErrorCodeInvalidRequest.New("SimulationNotSupported")
+	}
+
+	var bi module.BlockInfo
+	var blk module.Block
+	var err error
+	if param.Height == "" {
+		if blk, err = c.bm.GetLastBlock(); err != nil {
+			return nil, jsonrpc.ErrorCodeServer.Wrap(err, c.debug)
+		}
+		oldTS := blk.Timestamp()
+		newTS := common.UnixMicroFromTime(time.Now())
+		if newTS <= oldTS {
+			newTS = oldTS + 1
+		}
+		bi = common.NewBlockInfo(blk.Height()+1, newTS)
+	} else {
+		if blk, err = c.GetStateBlockByHeight(param.Height); err != nil {
+			return nil, err
+		}
+		bi = common.NewBlockInfo(blk.Height(), blk.Timestamp())
+	}
+
+	var ti *module.TraceInfo
+	var cb *traceCallback
+	if param.TraceMode == TraceModeCallTree {
+		cb = &traceCallback{
+			channel: make(chan interface{}, 10),
+			ct:      trace.NewCallTracer(),
+		}
+		ti = &module.TraceInfo{
+			TraceMode: module.TraceModeCallTree,
+			Range:     module.TraceRangeBlock,
+			Callback:  cb,
+		}
+	}
+
+	rcts, err := ts.SimulateTransactions(
+		blk.Result(),
+		blk.NextValidators().Hash(),
+		txs,
+		bi,
+		param.StateOverrides,
+		ti,
+	)
+	if err != nil {
+		if service.InvalidTransactionError.Equals(err) ||
+			service.InvalidStateOverrideError.Equals(err) {
+			return nil, jsonrpc.ErrorCodeInvalidParams.Wrap(err, c.debug)
+		}
+		return nil, jsonrpc.ErrorCodeServer.Wrap(err, c.debug)
+	}
+	if cb != nil {
+		for i, rct := range rcts {
+			rct.(map[string]interface{})["calls"] = cb.callsOf(i)
+		}
+	}
+	return map[string]interface{}{
+		"blockHeight":  "0x" + strconv.FormatInt(bi.Height(), 16),
+		"timestamp":    "0x" + strconv.FormatInt(bi.Timestamp(), 16),
+		"transactions": rcts,
+	}, nil
+}
+
 type MissingTransactionInfo interface {
 	ReplaceID(height int64, id []byte) []byte
 	GetLocationOf(id []byte) (int64, int, bool)
@@ -1296,6 +1663,123 @@ func findMissingTransactionInfoOf(cid int) MissingTransactionInfo {
 	return nil
 }
 
+type stateDiffer interface {
+	GetStateDiff(from, to []byte, txs ...module.TransactionList) (interface{}, error)
+}
+
+// getStateDiff returns accounts and storage entries changed by the
+// transactions in the block. The result of them is stored in the next
+// block, so it's available after the next block is finalized.
+func getStateDiff(ctx *jsonrpc.Context, params *jsonrpc.Params) (interface{}, error) {
+	var c contextWithSM
+	if err := c.Init(ctx); err != nil {
+		return nil, err
+	}
+
+	var param BlockHeightParam
+	if err := params.Convert(&param); err != nil {
+		return nil, jsonrpc.ErrorCodeInvalidParams.Wrap(err, c.debug)
+	}
+
+	sd, ok := c.sm.(stateDiffer)
+	if !ok {
+		return nil, jsonrpc.ErrorCodeInvalidRequest.New("StateDiffNotSupported")
+	}
+	blk, err := c.GetStateBlockByHeight(param.Height)
+	if err != nil {
+		return nil, err
+	}
+	nblk, err := c.bm.GetBlockByHeight(blk.Height() + 1)
+	if err != nil {
+		return nil, c.AsRPCError(err)
+	}
+	res, err := sd.GetStateDiff(blk.Result(), nblk.Result(),
+		blk.NormalTransactions(), nblk.PatchTransactions())
+	if err != nil {
+		return nil, jsonrpc.ErrorCodeSystem.Wrap(err, c.debug)
+	}
+	result, ok := res.(map[string]interface{})
+	if !ok {
+		return nil, jsonrpc.ErrorCodeSystem.Errorf("InvalidStateDiff(type=%T)", res)
+	}
+	result["blockHash"] = "0x" + hex.EncodeToString(blk.ID())
+	result["blockHeight"] = "0x" + strconv.FormatInt(blk.Height(), 16)
+	return result, nil
+}
+
+const (
+	defaultPendingTransactionsLimit = 100
+	maxPendingTransactionsLimit     = 1000
+)
+
+type poolInspector interface {
+	GetPendingTransactions(groups []module.TransactionGroup, from, to module.Address, skip, limit int) (interface{}, error)
+	GetPoolStatus() (interface{}, error)
+}
+
+// getPendingTransactions returns transactions in the transaction pools in
+// the order they would be included in blocks.
+func getPendingTransactions(ctx *jsonrpc.Context, params *jsonrpc.Params) (interface{}, error) {
+	var c contextWithSM
+	if err := c.Init(ctx); err != nil {
+		return nil, err
+	}
+
+	var param PendingTransactionsParam
+	if !params.IsEmpty() {
+		if err := params.Convert(&param); err != nil {
+			return nil, jsonrpc.ErrorCodeInvalidParams.Wrap(err, c.debug)
+		}
+	}
+
+	pi, ok := c.sm.(poolInspector)
+	if !ok {
+		return nil, jsonrpc.ErrorCodeInvalidRequest.New("PoolInspectionNotSupported")
+	}
+	groups, err := service.TxGroupsOf(param.Group)
+	if err != nil {
+		return nil, jsonrpc.ErrorCodeInvalidParams.Wrap(err, c.debug)
+	}
+	var from, to module.Address
+	if param.From != "" {
+		from = param.From.Address()
+	}
+	if param.To != "" {
+		to = param.To.Address()
+	}
+	skip, limit := int(param.Skip.Value()), int(param.Limit.Value())
+	if skip < 0 || limit < 0 {
+		return nil, jsonrpc.ErrorCodeInvalidParams.New("NegativeSkipOrLimit")
+	}
+	if limit == 0 {
+		limit = defaultPendingTransactionsLimit
+	} else if limit > maxPendingTransactionsLimit {
+		limit = maxPendingTransactionsLimit
+	}
+	res, err := pi.GetPendingTransactions(groups, from, to, skip, limit)
+	if err != nil {
+		return nil, jsonrpc.ErrorCodeSystem.Wrap(err, c.debug)
+	}
+	return res, nil
+}
+
+func getPoolStatus(ctx *jsonrpc.Context, params *jsonrpc.Params) (interface{}, error) {
+	var c contextWithSM
+	if err := c.Init(ctx); err != nil {
+		return nil, err
+	}
+
+	pi, ok := c.sm.(poolInspector)
+	if !ok {
+		return nil, jsonrpc.ErrorCodeInvalidRequest.New("PoolInspectionNotSupported")
+	}
+	res, err := pi.GetPoolStatus()
+	if err != nil {
+		return nil, jsonrpc.ErrorCodeSystem.Wrap(err, c.debug)
+	}
+	return res, nil
+}
+
 func getTraceForRosetta(ctx *jsonrpc.Context, params *jsonrpc.Params) (interface{}, error) {
 	var c contextWithSM
 	if err := c.Init(ctx); err != nil {
@@ -1311,6 +1795,9 @@ func getTraceForRosetta(ctx *jsonrpc.Context, params *jsonrpc.Params) (interface
 	if err != nil {
 		return nil, err
 	}
+	if err = c.CheckStateHeight(blk.Height()); err != nil {
+		return nil, err
+	}
 
 	csi, err := c.bm.NewConsensusInfo(blk)
 	if err != nil {
@@ -0,0 +1,330 @@
+/*
+ * Copyright 2026 ICON Foundation
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package v3
+
+import (
+	"bytes"
+
+	"github.com/icon-project/goloop/common"
+	"github.com/icon-project/goloop/common/errors"
+	"github.com/icon-project/goloop/common/intconv"
+	"github.com/icon-project/goloop/common/logsindex"
+	"github.com/icon-project/goloop/module"
+	"github.com/icon-project/goloop/server/jsonrpc"
+	"github.com/icon-project/goloop/service/scoreapi"
+	"github.com/icon-project/goloop/service/txresult"
+)
+
+const (
+	defaultLogsLimit = 100
+	maxLogsLimit     = 1000
+
+	// maxLogsBlockRange is the maximum number of blocks to scan with
+	// headers of blocks.
+	maxLogsBlockRange = 5000
+
+	// maxIndexedLogsBlockRange is the maximum number of blocks to scan
+	// if all blocks are in the logs index.
+	maxIndexedLogsBlockRange = 500000
+)
+
+type logsIndexer interface {
+	LogsIndex() *logsindex.Index
+}
+
+// logFilter matches event logs with the signature, the address and the
+// values of the parameters. Nil value of a parameter matches any value.
+type logFilter struct {
+	addr      module.Address
+	signature string
+	indexed   [][]byte
+	data      [][]byte
+	lb        *txresult.LogsBloom
+}
+
+func newLogFilter(addr module.Address, signature string, indexed, data []*string) (*logFilter, error) {
+	name, pts := txresult.DecomposeEventSignature(signature)
+	if len(name) == 0 || pts == nil || len(pts) < len(indexed)+len(data) {
+		return nil, errors.IllegalArgumentError.Errorf("InvalidEventSignature(%s)", signature)
+	}
+	for idx, pt := range pts {
+		if !scoreapi.DataTypeOf(pt).UsableForEvent() {
+			return nil, errors.IllegalArgumentError.Errorf(
+				"InvalidParameterType(idx=%d,type=%s)", idx, pt)
+		}
+	}
+	f := &logFilter{
+		addr:      addr,
+		signature: signature,
+		indexed:   make([][]byte, len(indexed)),
+		data:      make([][]byte, len(data)),
+		lb:        txresult.NewLogsBloom(nil),
+	}
+	if addr != nil {
+		f.lb.AddAddressOfLog(addr)
+	}
+	f.lb.AddIndexedOfLog(0, []byte(signature))
+	for i, arg := range append(append([]*string{}, indexed...), data...) {
+		if arg == nil {
+			continue
+		}
+		bs, err := txresult.EventDataStringToBytesByType(pts[i], *arg)
+		if err != nil {
+			return nil, errors.IllegalArgumentError.Wrapf(err,
+				"InvalidEventData(idx=%d,value=%s)", i, *arg)
+		}
+		if i < len(indexed) {
+			f.indexed[i] = bs
+			f.lb.AddIndexedOfLog(i+1, bs)
+		} else {
+			f.data[i-len(indexed)] = bs
+		}
+	}
+	return f, nil
+}
+
+func (f *logFilter) match(el module.EventLog) bool {
+	indexed, data := el.Indexed(), el.Data()
+	if len(indexed) == 0 || !bytes.Equal([]byte(f.signature), indexed[0]) {
+		return false
+	}
+	if f.addr != nil && !f.addr.Equal(el.Address()) {
+		return false
+	}
+	if len(indexed) <= len(f.indexed) || len(data) < len(f.data) {
+		return false
+	}
+	for i, v := range f.indexed {
+		if v != nil && !bytes.Equal(v, indexed[i+1]) {
+			return false
+		}
+	}
+	for i, v := range f.data {
+		if v != nil && !bytes.Equal(v, data[i]) {
+			return false
+		}
+	}
+	return true
+}
+
+// logCandidates returns heights of blocks in [from, to] whose logs blooms
+// contain the bloom of the filter. It uses the index for indexed blocks, and
+// headers of blocks for others.
+func (c *contextWithSM) logCandidates(idx *logsindex.Index, lb module.LogsBloom, from, to int64) ([]int64, error) {
+	lo, hi := int64(1), int64(0)
+	if idx != nil {
+		start, next := idx.Range()
+		lo, hi = from, to
+		if lo < start {
+			lo = start
+		}
+		if hi >= next {
+			hi = next - 1
+		}
+	}
+	var heights []int64
+	for h := from; h <= to; h++ {
+		if h == lo && lo <= hi {
+			hs, err := idx.Match(lb.LogBytes(), lo, hi)
+			if err != nil {
+				return nil, err
+			}
+			heights = append(heights, hs...)
+			h = hi
+			continue
+		}
+		blk, err := c.bm.GetBlockByHeight(h)
+		if err != nil {
+			return nil, err
+		}
+		if blk.LogsBloom().Contain(lb) {
+			heights = append(heights, h)
+		}
+	}
+	return heights, nil
+}
+
+func logsIndexOf(chain module.Chain) *logsindex.Index {
+	if li, ok := chain.(logsIndexer); ok {
+		return li.LogsIndex()
+	}
+	return nil
+}
+
+func isIndexed(idx *logsindex.Index, from, to int64) bool {
+	if idx == nil {
+		return false
+	}
+	start, next := idx.Range()
+	return from >= start && to < next
+}
+
+// getLogs returns event logs of transactions in blocks of [fromBlock,
+// toBlock] matching the filter. Receipts of transactions in a block are
+// stored in the next block, so it scans blocks of [fromBlock+1, toBlock+1].
+// If there are more logs than the limit, it returns the parameters for the
+// next query.
+func getLogs(ctx *jsonrpc.Context, params *jsonrpc.Params) (interface{}, error) {
+	var c contextWithSM
+	if err := c.Init(ctx); err != nil {
+		return nil, err
+	}
+
+	var param LogsParam
+	if err := params.Convert(&param); err != nil {
+		return nil, jsonrpc.ErrorCodeInvalidParams.Wrap(err, c.debug)
+	}
+	var addr module.Address
+	if param.Address != "" {
+		addr = param.Address.Address()
+	}
+	f, err := newLogFilter(addr, param.Event, param.Indexed, param.Data)
+	if err != nil {
+		return nil, jsonrpc.ErrorCodeInvalidParams.Wrap(err, c.debug)
+	}
+	from := param.FromBlock.Value()
+	if err := c.CheckBaseHeight(from); err != nil {
+		return nil, err
+	}
+	skip, limit := int(param.Skip.Value()), int(param.Limit.Value())
+	if skip < 0 || limit < 0 {
+		return nil, jsonrpc.ErrorCodeInvalidParams.New("NegativeSkipOrLimit")
+	}
+	if limit == 0 {
+		limit = defaultLogsLimit
+	} else if limit > maxLogsLimit {
+		limit = maxLogsLimit
+	}
+
+	last, err := c.bm.GetLastBlock()
+	if err != nil {
+		return nil, c.AsRPCError(err)
+	}
+	lastTo := last.Height() - 1
+	idx := logsIndexOf(c.chain)
+	var to int64
+	if param.ToBlock != "" {
+		to = param.ToBlock.Value()
+		if to < from {
+			return nil, jsonrpc.ErrorCodeInvalidParams.Errorf(
+				"InvalidBlockRange(from=%d,to=%d)", from, to)
+		}
+		if to > lastTo {
+			return nil, jsonrpc.ErrorCodeInvalidParams.Errorf(
+				"ResultNotFinalized(to=%d,last=%d)", to, lastTo)
+		}
+		maxRange := int64(maxLogsBlockRange)
+		if isIndexed(idx, from+1, to+1) {
+			maxRange = maxIndexedLogsBlockRange
+		}
+		if to-from+1 > maxRange {
+			return nil, jsonrpc.ErrorCodeInvalidParams.Errorf(
+				"TooLargeBlockRange(from=%d,to=%d,max=%d)", from, to, maxRange)
+		}
+	} else {
+		if from > lastTo {
+			return nil, jsonrpc.ErrorCodeInvalidParams.Errorf(
+				"ResultNotFinalized(from=%d,last=%d)", from, lastTo)
+		}
+		to = from + maxIndexedLogsBlockRange - 1
+		if to > lastTo {
+			to = lastTo
+		}
+		if !isIndexed(idx, from+1, to+1) && to-from+1 > maxLogsBlockRange {
+			to = from + maxLogsBlockRange - 1
+		}
+	}
+
+	heights, err := c.logCandidates(idx, f.lb, from+1, to+1)
+	if err != nil {
+		return nil, c.AsRPCError(err)
+	}
+
+	logs := make([]interface{}, 0)
+	for _, rh := range heights {
+		rblk, err := c.bm.GetBlockByHeight(rh)
+		if err != nil {
+			return nil, c.AsRPCError(err)
+		}
+		blk, err := c.bm.GetBlockByHeight(rh - 1)
+		if err != nil {
+			return nil, c.AsRPCError(err)
+		}
+		rl, err := c.sm.ReceiptListFromResult(rblk.Result(), module.TransactionGroupNormal)
+		if err != nil {
+			return nil, c.AsRPCError(err)
+		}
+		matched := 0
+		for it, txIdx := rl.Iterator(), 0; it.Has(); _, txIdx = it.Next(), txIdx+1 {
+			r, err := it.Get()
+			if err != nil {
+				return nil, c.AsRPCError(err)
+			}
+			if !r.LogsBloom().Contain(f.lb) {
+				continue
+			}
+			var txHash []byte
+			for eit, logIdx := r.EventLogIterator(), 0; eit.Has(); _, logIdx = eit.Next(), logIdx+1 {
+				el, err := eit.Get()
+				if err != nil {
+					return nil, c.AsRPCError(err)
+				}
+				if !f.match(el) {
+					continue
+				}
+				matched += 1
+				if blk.Height() == from && matched <= skip {
+					continue
+				}
+				if len(logs) == limit {
+					return map[string]interface{}{
+						"logs": logs,
+						"next": map[string]interface{}{
+							"fromBlock": intconv.FormatInt(blk.Height()),
+							"skip":      intconv.FormatInt(int64(matched - 1)),
+						},
+					}, nil
+				}
+				if txHash == nil {
+					tx, err := blk.NormalTransactions().Get(txIdx)
+					if err != nil {
+						return nil, c.AsRPCError(err)
+					}
+					txHash = tx.ID()
+				}
+				logs = append(logs, map[string]interface{}{
+					"blockHeight": intconv.FormatInt(blk.Height()),
+					"blockHash":   common.HexBytes(blk.ID()),
+					"txIndex":     intconv.FormatInt(int64(txIdx)),
+					"txHash":      common.HexBytes(txHash),
+					"logIndex":    intconv.FormatInt(int64(logIdx)),
+					"eventLog":    el,
+				})
+			}
+		}
+	}
+	result := map[string]interface{}{
+		"logs": logs,
+	}
+	if to < lastTo && param.ToBlock == "" {
+		result["next"] = map[string]interface{}{
+			"fromBlock": intconv.FormatInt(to + 1),
+			"skip":      "0x0",
+		}
+	}
+	return result, nil
+}
@@ -0,0 +1,100 @@
+/*
+ * Copyright 2026 ICON Foundation
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package v3
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/icon-project/goloop/common"
+	"github.com/icon-project/goloop/common/intconv"
+	"github.com/icon-project/goloop/module"
+	"github.com/icon-project/goloop/service/txresult"
+)
+
+type testEventLog struct {
+	addr    module.Address
+	indexed [][]byte
+	data    [][]byte
+}
+
+func (l *testEventLog) Address() module.Address {
+	return l.addr
+}
+
+func (l *testEventLog) Indexed() [][]byte {
+	return l.indexed
+}
+
+func (l *testEventLog) Data() [][]byte {
+	return l.data
+}
+
+func strPtr(s string) *string {
+	return &s
+}
+
+func TestLogFilter(t *testing.T) {
+	const sig = "Transfer(Address,Address,int)"
+	score := common.MustNewAddressFromString("cx0000000000000000000000000000000000000001")
+	other := common.MustNewAddressFromString("cx0000000000000000000000000000000000000002")
+	from := common.MustNewAddressFromString("hx0000000000000000000000000000000000000003")
+	to := common.MustNewAddressFromString("hx0000000000000000000000000000000000000004")
+	el := &testEventLog{
+		addr:    score,
+		indexed: [][]byte{[]byte(sig), from.Bytes(), to.Bytes()},
+		data:    [][]byte{intconv.Int64ToBytes(10)},
+	}
+	lb := txresult.NewLogsBloom(nil)
+	lb.AddLog(el.addr, el.indexed)
+
+	tests := []struct {
+		name    string
+		addr    module.Address
+		sig     string
+		indexed []*string
+		data    []*string
+		match   bool
+	}{
+		{"Signature", nil, sig, nil, nil, true},
+		{"Address", score, sig, nil, nil, true},
+		{"OtherAddress", other, sig, nil, nil, false},
+		{"OtherSignature", nil, "Transfer(Address,Address,int,bytes)", nil, nil, false},
+		{"Indexed", score, sig, []*string{nil, strPtr(to.String())}, nil, true},
+		{"OtherIndexed", score, sig, []*string{strPtr(to.String())}, nil, false},
+		{"Data", nil, sig, []*string{nil, nil}, []*string{strPtr("0xa")}, true},
+		{"OtherData", nil, sig, []*string{nil, nil}, []*string{strPtr("0xb")}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f, err := newLogFilter(tt.addr, tt.sig, tt.indexed, tt.data)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.match, f.match(el))
+			if tt.match {
+				assert.True(t, lb.Contain(f.lb))
+			}
+		})
+	}
+
+	_, err := newLogFilter(nil, "Transfer", nil, nil)
+	assert.Error(t, err)
+	_, err = newLogFilter(nil, "Transfer(int)", []*string{nil, nil}, nil)
+	assert.Error(t, err)
+	_, err = newLogFilter(nil, sig, []*string{strPtr("invalid")}, nil)
+	assert.Error(t, err)
+}
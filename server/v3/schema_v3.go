@@ -1,6 +1,8 @@
 package v3
 
 import (
+	"encoding/json"
+
 	"github.com/icon-project/goloop/common/intconv"
 	"github.com/icon-project/goloop/server/jsonrpc"
 )
@@ -9,6 +11,11 @@ const (
 	Version = 3
 )
 
+const (
+	TraceModeInvoke   = "invoke"
+	TraceModeCallTree = "callTree"
+)
+
 var (
 	VersionValue = jsonrpc.HexInt(intconv.FormatInt(Version))
 )
@@ -26,11 +33,13 @@ type BlockHashParam struct {
 }
 
 type CallParam struct {
-	FromAddress jsonrpc.Address `json:"from,omitempty" validate:"optional,t_addr_eoa"`
-	ToAddress   jsonrpc.Address `json:"to" validate:"required,t_addr_score"`
-	DataType    string          `json:"dataType" validate:"required,call"`
-	Data        interface{}     `json:"data"`
-	Height      jsonrpc.HexInt  `json:"height,omitempty" validate:"optional,t_int"`
+	FromAddress    jsonrpc.Address `json:"from,omitempty" validate:"optional,t_addr_eoa"`
+	ToAddress      jsonrpc.Address `json:"to" validate:"required,t_addr_score"`
+	DataType       string          `json:"dataType" validate:"required,call"`
+	Data           interface{}     `json:"data"`
+	Height         jsonrpc.HexInt  `json:"height,omitempty" validate:"optional,t_int"`
+	Snapshot       string          `json:"snapshot,omitempty"`
+	StateOverrides json.RawMessage `json:"stateOverrides,omitempty"`
 }
 
 type AddressParam struct {
@@ -47,11 +56,30 @@ type TransactionHashParam struct {
 	Hash jsonrpc.HexBytes `json:"txHash" validate:"required,t_hash"`
 }
 
+type TraceParam struct {
+	Hash jsonrpc.HexBytes `json:"txHash" validate:"required,t_hash"`
+	Mode string           `json:"mode,omitempty" validate:"optional,oneof=invoke callTree"`
+}
+
 type TransactionParamForEstimate struct {
+	Version        jsonrpc.HexInt  `json:"version" validate:"required,t_int"`
+	FromAddress    jsonrpc.Address `json:"from" validate:"required,t_addr_eoa"`
+	ToAddress      jsonrpc.Address `json:"to" validate:"required,t_addr"`
+	Value          jsonrpc.HexInt  `json:"value,omitempty" validate:"optional,t_int"`
+	Timestamp      jsonrpc.HexInt  `json:"timestamp" validate:"required,t_int"`
+	NetworkID      jsonrpc.HexInt  `json:"nid" validate:"required,t_int"`
+	Nonce          jsonrpc.HexInt  `json:"nonce,omitempty" validate:"optional,t_int"`
+	DataType       string          `json:"dataType,omitempty" validate:"optional,call|deploy|message|deposit"`
+	Data           interface{}     `json:"data,omitempty"`
+	StateOverrides json.RawMessage `json:"stateOverrides,omitempty"`
+}
+
+type TransactionParamForSimulate struct {
 	Version     jsonrpc.HexInt  `json:"version" validate:"required,t_int"`
 	FromAddress jsonrpc.Address `json:"from" validate:"required,t_addr_eoa"`
 	ToAddress   jsonrpc.Address `json:"to" validate:"required,t_addr"`
 	Value       jsonrpc.HexInt  `json:"value,omitempty" validate:"optional,t_int"`
+	StepLimit   jsonrpc.HexInt  `json:"stepLimit,omitempty" validate:"optional,t_int"`
 	Timestamp   jsonrpc.HexInt  `json:"timestamp" validate:"required,t_int"`
 	NetworkID   jsonrpc.HexInt  `json:"nid" validate:"required,t_int"`
 	Nonce       jsonrpc.HexInt  `json:"nonce,omitempty" validate:"optional,t_int"`
@@ -59,6 +87,13 @@ type TransactionParamForEstimate struct {
 	Data        interface{}     `json:"data,omitempty"`
 }
 
+type SimulateTransactionsParam struct {
+	Transactions   []TransactionParamForSimulate `json:"transactions" validate:"required,min=1,max=32,dive"`
+	Height         jsonrpc.HexInt                `json:"height,omitempty" validate:"optional,t_int"`
+	StateOverrides json.RawMessage               `json:"stateOverrides,omitempty"`
+	TraceMode      string                        `json:"traceMode,omitempty" validate:"optional,oneof=callTree"`
+}
+
 type TransactionParam struct {
 	Version     jsonrpc.HexInt  `json:"version" validate:"required,t_int"`
 	FromAddress jsonrpc.Address `json:"from" validate:"required,t_addr_eoa"`
@@ -94,6 +129,14 @@ type RosettaTraceParam struct {
 	Height jsonrpc.HexInt   `json:"height,omitempty" validate:"optional,gte=0,t_int"`
 }
 
+type PendingTransactionsParam struct {
+	From  jsonrpc.Address `json:"from,omitempty" validate:"optional,t_addr_eoa"`
+	To    jsonrpc.Address `json:"to,omitempty" validate:"optional,t_addr"`
+	Group string          `json:"group,omitempty" validate:"optional,oneof=patch normal"`
+	Skip  jsonrpc.HexInt  `json:"skip,omitempty" validate:"optional,t_int"`
+	Limit jsonrpc.HexInt  `json:"limit,omitempty" validate:"optional,t_int"`
+}
+
 type BTPQueryParam struct {
 	Height jsonrpc.HexInt `json:"height,omitempty" validate:"optional,t_int"`
 	Id     jsonrpc.HexInt `json:"id" validate:"required,t_int"`
@@ -103,3 +146,14 @@ type BTPMessagesParam struct {
 	Height    jsonrpc.HexInt `json:"height" validate:"required,t_int"`
 	NetworkId jsonrpc.HexInt `json:"networkID" validate:"required,t_int"`
 }
+
+type LogsParam struct {
+	FromBlock jsonrpc.HexInt  `json:"fromBlock" validate:"required,t_int"`
+	ToBlock   jsonrpc.HexInt  `json:"toBlock,omitempty" validate:"optional,t_int"`
+	Address   jsonrpc.Address `json:"address,omitempty" validate:"optional,t_addr_score"`
+	Event     string          `json:"event" validate:"required"`
+	Indexed   []*string       `json:"indexed,omitempty"`
+	Data      []*string       `json:"data,omitempty"`
+	Skip      jsonrpc.HexInt  `json:"skip,omitempty" validate:"optional,t_int"`
+	Limit     jsonrpc.HexInt  `json:"limit,omitempty" validate:"optional,t_int"`
+}
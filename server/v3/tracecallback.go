@@ -19,6 +19,7 @@ type traceCallback struct {
 	ts      time.Time
 	channel chan interface{}
 	bt      *trace.BalanceTracer
+	ct      *trace.CallTracer
 }
 
 type traceLog struct {
@@ -69,6 +70,64 @@ func (t *traceCallback) invokeTraceToJSON() interface{} {
 	return result
 }
 
+func (t *traceCallback) callTreeToJSON(txIndex int) interface{} {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	calls := t.ct.CallsOf(txIndex)
+	if calls == nil {
+		calls = []interface{}{}
+	}
+	result := map[string]interface{}{
+		"calls": calls,
+	}
+	if t.last == nil {
+		result["status"] = "0x1"
+	} else {
+		result["status"] = "0x0"
+		status, _ := scoreresult.StatusOf(t.last)
+		result["failure"] = map[string]interface{}{
+			"code":    status,
+			"message": t.last.Error(),
+		}
+	}
+	return result
+}
+
+// callsOf returns the call tree of the transaction regardless of the result.
+func (t *traceCallback) callsOf(txIndex int) []interface{} {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	if calls := t.ct.CallsOf(txIndex); calls != nil {
+		return calls
+	}
+	return []interface{}{}
+}
+
+func (t *traceCallback) blockTraceToJSON(blk module.Block) interface{} {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	result := map[string]interface{}{
+		"blockHash":    "0x" + hex.EncodeToString(blk.ID()),
+		"blockHeight":  fmt.Sprintf("%#x", blk.Height()),
+		"transactions": t.ct.ToJSON(),
+		"profile":      t.ct.ProfileToJSON(),
+	}
+	if t.last == nil {
+		result["status"] = "0x1"
+	} else {
+		result["status"] = "0x0"
+		status, _ := scoreresult.StatusOf(t.last)
+		result["failure"] = map[string]interface{}{
+			"code":    status,
+			"message": t.last.Error(),
+		}
+	}
+	return result
+}
+
 func (t *traceCallback) balanceChangeToJSON(blk module.Block) interface{} {
 	t.lock.Lock()
 	defer t.lock.Unlock()
@@ -94,6 +153,11 @@ func (t *traceCallback) OnTransactionStart(txIndex int, txHash []byte, isBlockTx
 		defer t.lock.Unlock()
 		return t.bt.OnTransactionStart(txIndex, txHash, isBlockTx)
 	}
+	if t.ct != nil {
+		t.lock.Lock()
+		defer t.lock.Unlock()
+		return t.ct.OnTransactionStart(txIndex, txHash, isBlockTx)
+	}
 	return nil
 }
 
@@ -105,6 +169,9 @@ func (t *traceCallback) OnTransactionReset() error {
 	if t.bt != nil {
 		return t.bt.OnTransactionReset()
 	}
+	if t.ct != nil {
+		return t.ct.OnTransactionReset()
+	}
 	return nil
 }
 
@@ -114,6 +181,11 @@ func (t *traceCallback) OnTransactionEnd(txIndex int, txHash []byte) error {
 		defer t.lock.Unlock()
 		return t.bt.OnTransactionEnd(txIndex, txHash)
 	}
+	if t.ct != nil {
+		t.lock.Lock()
+		defer t.lock.Unlock()
+		return t.ct.OnTransactionEnd(txIndex, txHash)
+	}
 	return nil
 }
 
@@ -143,3 +215,39 @@ func (t *traceCallback) OnBalanceChange(opType module.OpType, from, to module.Ad
 	}
 	return nil
 }
+
+func (t *traceCallback) OnCallStart(call *module.TraceCall) error {
+	if t.ct != nil {
+		t.lock.Lock()
+		defer t.lock.Unlock()
+		return t.ct.OnCallStart(call)
+	}
+	return nil
+}
+
+func (t *traceCallback) OnCallEnd(status error, stepUsed *big.Int, result interface{}) error {
+	if t.ct != nil {
+		t.lock.Lock()
+		defer t.lock.Unlock()
+		return t.ct.OnCallEnd(status, stepUsed, result)
+	}
+	return nil
+}
+
+func (t *traceCallback) OnEvent(addr module.Address, indexed, data [][]byte) error {
+	if t.ct != nil {
+		t.lock.Lock()
+		defer t.lock.Unlock()
+		return t.ct.OnEvent(addr, indexed, data)
+	}
+	return nil
+}
+
+func (t *traceCallback) OnStorageAccess(write bool, key []byte) error {
+	if t.ct != nil {
+		t.lock.Lock()
+		defer t.lock.Unlock()
+		return t.ct.OnStorageAccess(write, key)
+	}
+	return nil
+}
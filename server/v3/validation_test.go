@@ -107,3 +107,47 @@ func TestTransactionParamValidator(t *testing.T) {
 		assert.Fail(t, "validate fail", err.Error())
 	}
 }
+
+func TestSimulateTransactionsParamValidator(t *testing.T) {
+	validator := jsonrpc.NewValidator()
+	RegisterValidationRule(validator)
+
+	tx := `{
+		"version": "0x3",
+		"from": "hx4873b94352c8c1f3b2f09aaeccea31ce9e90bd31",
+		"to": "cx059e19601bcb1424884f4ef19addc0a03de9e9cd",
+		"stepLimit": "0x100000",
+		"timestamp": "0x563a6cf330136",
+		"nid": "0x3",
+		"dataType": "call",
+		"data": {"method": "approve"}
+	}`
+	noFrom := `{
+		"version": "0x3",
+		"to": "cx059e19601bcb1424884f4ef19addc0a03de9e9cd",
+		"timestamp": "0x563a6cf330136",
+		"nid": "0x3"
+	}`
+	cases := []struct {
+		name  string
+		js    string
+		valid bool
+	}{
+		{"Valid", `{"transactions":[` + tx + `,` + tx + `],"traceMode":"callTree"}`, true},
+		{"NoTransactions", `{"transactions":[]}`, false},
+		{"InvalidTransaction", `{"transactions":[` + tx + `,` + noFrom + `]}`, false},
+		{"InvalidTraceMode", `{"transactions":[` + tx + `],"traceMode":"invoke"}`, false},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			var param SimulateTransactionsParam
+			err := jsonrpc.UnmarshalWithValidate([]byte(c.js), &param, validator)
+			if c.valid {
+				assert.NoError(t, err)
+				assert.Len(t, param.Transactions, 2)
+			} else {
+				assert.Error(t, err)
+			}
+		})
+	}
+}
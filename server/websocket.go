@@ -28,6 +28,24 @@ type WebSocketUpgrader interface {
 	Upgrade(ctx echo.Context) (WebSocketConn, error)
 }
 
+// wsStream is a request of a websocket session, which sends notifications
+// until the client stops it.
+type wsStream interface {
+	// Prepare checks the request for the chain. It returns the response
+	// to be sent to the client on failure, or nil if it's ready to run.
+	Prepare(chain module.Chain) *WSResponse
+
+	// Run sends notifications to out until it fails or ech returns
+	// an error.
+	Run(out wsOutput, ech <-chan error, logger log.Logger) error
+}
+
+// wsOutput delivers notifications of a stream to the client.
+type wsOutput interface {
+	WriteJSON(v interface{}) error
+	response(code int, msg string) error
+}
+
 type wsSession struct {
 	lock  sync.Mutex
 	c     WebSocketConn
@@ -45,9 +63,11 @@ type wsSessionManager struct {
 // ProgressNotification is used to notify the height of the processed block
 // If it's enabled, then it would be sent on every interval. If there are
 // some notifications to be sent, it would be also sent regardless of the
-// interval.
+// interval. For resumable streams, it has the cursor to resume after the
+// block.
 type ProgressNotification struct {
 	Progress common.HexInt64 `json:"progress"`
+	Cursor   *StreamCursor   `json:"cursor,omitempty"`
 }
 
 func newWSSessionManager(logger log.Logger, maxSession int) *wsSessionManager {
@@ -172,6 +192,30 @@ func (wm *wsSessionManager) initSession(ctx echo.Context, reqPtr interface{}) (*
 	return wss, nil
 }
 
+// runSession serves a session with single stream. The first message of the
+// client is the request of the stream.
+func (wm *wsSessionManager) runSession(ctx echo.Context, s wsStream) error {
+	wss, err := wm.initSession(ctx, s)
+	if err != nil {
+		return err
+	}
+	defer wm.StopSession(wss)
+
+	if res := s.Prepare(wss.chain); res != nil {
+		_ = wss.WriteJSON(res)
+		return nil
+	}
+
+	_ = wss.response(0, "")
+
+	ech := make(chan error, 1)
+	wss.RunLoop(ech)
+
+	err = s.Run(wss, ech, wm.logger)
+	wm.logger.Warnf("%+v\n", err)
+	return nil
+}
+
 func (wm *wsSessionManager) chain(ctx echo.Context) (module.Chain, error) {
 	c, ok := ctx.Get("chain").(module.Chain)
 	if !ok {
@@ -6,6 +6,7 @@ import (
 	"github.com/labstack/echo/v4"
 
 	"github.com/icon-project/goloop/common"
+	"github.com/icon-project/goloop/common/log"
 	"github.com/icon-project/goloop/module"
 	"github.com/icon-project/goloop/server/jsonrpc"
 )
@@ -14,7 +15,11 @@ type BlockRequest struct {
 	Height       common.HexInt64 `json:"height"`
 	EventFilters []*EventFilter  `json:"eventFilters,omitempty"`
 	Logs         common.HexBool  `json:"logs,omitempty"`
+	Cursor       *StreamCursor   `json:"cursor,omitempty"`
+	Heartbeat    common.HexInt64 `json:"heartbeat,omitempty"`
 	bn           BlockNotification
+	bm           module.BlockManager
+	sm           module.ServiceManager
 }
 
 type BlockNotification struct {
@@ -23,41 +28,46 @@ type BlockNotification struct {
 	Indexes [][]common.HexInt32   `json:"indexes,omitempty"`
 	Events  [][][]common.HexInt32 `json:"events,omitempty"`
 	Logs    [][][]module.EventLog `json:"logs,omitempty"`
+	Cursor  *StreamCursor         `json:"cursor,omitempty"`
 }
 
 func (wm *wsSessionManager) RunBlockSession(ctx echo.Context) error {
-	var br BlockRequest
-	wss, err := wm.initSession(ctx, &br)
-	if err != nil {
-		return err
-	}
-	defer wm.StopSession(wss)
+	return wm.runSession(ctx, new(BlockRequest))
+}
 
-	if err := br.Compile(); err != nil {
-		_ = wss.response(int(jsonrpc.ErrorCodeInvalidParams), err.Error())
-		return nil
+func (r *BlockRequest) Prepare(chain module.Chain) *WSResponse {
+	if err := r.Compile(); err != nil {
+		return &WSResponse{Code: int(jsonrpc.ErrorCodeInvalidParams), Message: err.Error()}
 	}
-
-	bm := wss.chain.BlockManager()
-	sm := wss.chain.ServiceManager()
-	if bm == nil || sm == nil {
-		_ = wss.response(int(jsonrpc.ErrorCodeServer), "Stopped")
-		return nil
+	if r.Cursor != nil {
+		r.Height = r.Cursor.Height
 	}
 
-	h := br.Height.Value
-	if gh := wss.chain.GenesisStorage().Height(); gh > h {
-		_ = wss.response(int(jsonrpc.ErrorCodeInvalidParams),
-			fmt.Sprintf("given height(%d) is lower than genesis height(%d)", h, gh))
-		return nil
+	r.bm = chain.BlockManager()
+	r.sm = chain.ServiceManager()
+	if r.bm == nil || r.sm == nil {
+		return &WSResponse{Code: int(jsonrpc.ErrorCodeServer), Message: "Stopped"}
 	}
 
-	_ = wss.response(0, "")
+	h := r.Height.Value
+	if gh := chain.GenesisStorage().Height(); gh > h {
+		return &WSResponse{
+			Code:    int(jsonrpc.ErrorCodeInvalidParams),
+			Message: fmt.Sprintf("given height(%d) is lower than genesis height(%d)", h, gh),
+		}
+	}
+	return nil
+}
 
-	ech := make(chan error, 1)
-	wss.RunLoop(ech)
+func (br *BlockRequest) Run(out wsOutput, ech <-chan error, logger log.Logger) error {
+	bm, sm := br.bm, br.sm
+	h := br.Height.Value
+	hb := newHeartbeat(br.Heartbeat.Value)
+	defer hb.Stop()
 
+	var err error
 	var bch <-chan module.Block
+	var pn ProgressNotification
 	indexes := make([][]common.HexInt32, len(br.EventFilters))
 	events := make([][][]common.HexInt32, len(br.EventFilters))
 	eventLogs := make([][][]module.EventLog, len(br.EventFilters))
@@ -69,17 +79,33 @@ func (wm *wsSessionManager) RunBlockSession(ctx echo.Context) error {
 	var rl module.ReceiptList
 loop:
 	for {
-		bch, err = bm.WaitForBlock(h)
-		if err != nil {
-			break loop
+		if bch == nil {
+			bch, err = bm.WaitForBlock(h)
+			if err != nil {
+				break loop
+			}
 		}
 		select {
 		case err = <-ech:
 			break loop
+		case <-hb.C:
+			pn.Progress.Value = h - 1
+			if br.Cursor != nil {
+				pn.Cursor = cursorAt(h, 0, 0)
+				if br.Cursor.Before(h, 0, 0) {
+					pn.Cursor = br.Cursor
+				}
+			}
+			if err := out.WriteJSON(&pn); err != nil {
+				logger.Infof("fail to write json ProgressNotification(height=%d)", h-1)
+				break loop
+			}
+			continue loop
 		case blk, ok := <-bch:
 			if !ok {
 				break loop
 			}
+			bch = nil
 			br.bn.Height = common.HexInt64{Value: h}
 			br.bn.Hash = blk.ID()
 			if rl != nil {
@@ -116,7 +142,11 @@ loop:
 						if err != nil {
 							break loop
 						}
-						if es, logs, err := f.MatchEvents(r, br.Logs.Value); err == nil && len(es) > 0 {
+						es, logs, err := f.MatchEvents(r, br.Logs.Value)
+						if err == nil {
+							es, logs = br.Cursor.TrimEvents(h, index, es, logs)
+						}
+						if err == nil && len(es) > 0 {
 							if len(br.bn.Indexes) < 1 {
 								br.bn.Indexes = indexes[:]
 								br.bn.Events = events[:]
@@ -134,15 +164,18 @@ loop:
 					}
 				}
 			}
-			if err = wss.WriteJSON(&br.bn); err != nil {
-				wm.logger.Infof("fail to write json BlockNotification err:%+v\n", err)
+			if br.Cursor != nil {
+				br.bn.Cursor = cursorAt(h+1, 0, 0)
+			}
+			if err = out.WriteJSON(&br.bn); err != nil {
+				logger.Infof("fail to write json BlockNotification err:%+v\n", err)
 				break loop
 			}
+			hb.Reset()
 		}
 		h++
 	}
-	wm.logger.Warnf("%+v\n", err)
-	return nil
+	return err
 }
 
 func (r *BlockRequest) Compile() error {
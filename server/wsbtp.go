@@ -7,6 +7,7 @@ import (
 	"github.com/labstack/echo/v4"
 
 	"github.com/icon-project/goloop/common"
+	"github.com/icon-project/goloop/common/log"
 	"github.com/icon-project/goloop/module"
 	"github.com/icon-project/goloop/server/jsonrpc"
 )
@@ -17,6 +18,9 @@ type BTPRequest struct {
 	ProofFlag        common.HexBool  `json:"proofFlag"`
 	ProgressInterval common.HexInt64 `json:"progressInterval,omitempty"`
 	bn               BTPNotification
+	bm               module.BlockManager
+	sm               module.ServiceManager
+	cs               module.Consensus
 }
 
 type BTPNotification struct {
@@ -25,40 +29,37 @@ type BTPNotification struct {
 }
 
 func (wm *wsSessionManager) RunBtpSession(ctx echo.Context) error {
-	var br BTPRequest
-	wss, err := wm.initSession(ctx, &br)
-	if err != nil {
-		return err
-	}
-	defer wm.StopSession(wss)
+	return wm.runSession(ctx, new(BTPRequest))
+}
 
-	bm := wss.chain.BlockManager()
-	sm := wss.chain.ServiceManager()
-	cs := wss.chain.Consensus()
-	if bm == nil || sm == nil || cs == nil {
-		_ = wss.response(int(jsonrpc.ErrorCodeServer), "Stopped")
-		return nil
+func (br *BTPRequest) Prepare(chain module.Chain) *WSResponse {
+	br.bm = chain.BlockManager()
+	br.sm = chain.ServiceManager()
+	br.cs = chain.Consensus()
+	if br.bm == nil || br.sm == nil || br.cs == nil {
+		return &WSResponse{Code: int(jsonrpc.ErrorCodeServer), Message: "Stopped"}
 	}
 
 	h := br.Height.Value
-	if gh := wss.chain.GenesisStorage().Height(); gh > h {
-		_ = wss.response(int(jsonrpc.ErrorCodeInvalidParams),
-			fmt.Sprintf("given height(%d) is lower than genesis height(%d)", h, gh))
-		return nil
+	if gh := chain.GenesisStorage().Height(); gh > h {
+		return &WSResponse{
+			Code:    int(jsonrpc.ErrorCodeInvalidParams),
+			Message: fmt.Sprintf("given height(%d) is lower than genesis height(%d)", h, gh),
+		}
 	}
+	return nil
+}
 
-	_ = wss.response(0, "")
-
-	ech := make(chan error, 1)
-	wss.RunLoop(ech)
+func (br *BTPRequest) Run(out wsOutput, ech <-chan error, logger log.Logger) error {
+	bm, sm, cs := br.bm, br.sm, br.cs
+	h := br.Height.Value
 
 	var bch <-chan module.Block
-
 	block, err := bm.GetLastBlock()
 	nw, err := sm.BTPNetworkFromResult(block.Result(), br.NetworkId.Value)
 	if err != nil {
-		wm.logger.Infof("not found nid=%d height=%d, err:%+v\n", br.NetworkId.Value, h, err)
-		return nil
+		logger.Infof("not found nid=%d height=%d, err:%+v\n", br.NetworkId.Value, h, err)
+		return err
 	}
 
 	var pn ProgressNotification;
@@ -79,8 +80,8 @@ loop:
 			if nw.StartHeight()+1 <= h {
 				nw, err := sm.BTPNetworkFromResult(blk.Result(), br.NetworkId.Value)
 				if !nw.Open() {
-					wm.logger.Infof("network is closed (height=%d, err:%+v)\n", h, err)
-					_ = wss.response(int(jsonrpc.ErrorCodeInvalidParams),
+					logger.Infof("network is closed (height=%d, err:%+v)\n", h, err)
+					_ = out.response(int(jsonrpc.ErrorCodeInvalidParams),
 						fmt.Sprintf("network is closed ( height(%d) , networkId(%d)", h, br.NetworkId))
 					break loop
 				}
@@ -99,8 +100,8 @@ loop:
 						br.bn.Proof = base64.StdEncoding.EncodeToString(proof)
 					}
 
-					if err = wss.WriteJSON(&br.bn); err != nil {
-						wm.logger.Infof("fail to write json BtpNotification err:%+v\n", err)
+					if err = out.WriteJSON(&br.bn); err != nil {
+						logger.Infof("fail to write json BtpNotification err:%+v\n", err)
 						break loop
 					}
 					msgSent += 1
@@ -111,8 +112,8 @@ loop:
 				last := pn.Progress.Value
 				if last == 0 || (h-last) >= pi || msgSent > 0 {
 					pn.Progress.Value = h
-					if err := wss.WriteJSON(&pn); err != nil {
-						wm.logger.Infof("fail to write json ProgressNotification(height=%d)", h)
+					if err := out.WriteJSON(&pn); err != nil {
+						logger.Infof("fail to write json ProgressNotification(height=%d)", h)
 						break loop
 					}
 				}
@@ -120,6 +121,5 @@ loop:
 		}
 		h++
 	}
-	wm.logger.Warnf("%+v\n", err)
-	return nil
+	return err
 }
@@ -0,0 +1,110 @@
+/*
+ * Copyright 2026 ICON Foundation
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package server
+
+import (
+	"time"
+
+	"github.com/icon-project/goloop/common"
+	"github.com/icon-project/goloop/module"
+)
+
+const minHeartbeatInterval = 100 * time.Millisecond
+
+// StreamCursor is a position in the stream of events, at the event of
+// the transaction in the block. Notifications of a resumable stream carry
+// the cursor to resume after them, so a client may restart the stream
+// with it without missing or duplicating notifications.
+type StreamCursor struct {
+	Height common.HexInt64 `json:"height"`
+	Index  common.HexInt32 `json:"index"`
+	Event  common.HexInt32 `json:"event"`
+}
+
+func cursorAt(height int64, index, event int32) *StreamCursor {
+	return &StreamCursor{
+		Height: common.HexInt64{Value: height},
+		Index:  common.HexInt32{Value: index},
+		Event:  common.HexInt32{Value: event},
+	}
+}
+
+// Before returns whether the position is before the cursor.
+func (c *StreamCursor) Before(height int64, index, event int32) bool {
+	if c == nil {
+		return false
+	}
+	if height != c.Height.Value {
+		return height < c.Height.Value
+	}
+	if index != c.Index.Value {
+		return index < c.Index.Value
+	}
+	return event < c.Event.Value
+}
+
+// TrimEvents removes events of the transaction before the cursor from
+// the matched events.
+func (c *StreamCursor) TrimEvents(
+	height int64, index int32, es []common.HexInt32, logs []module.EventLog,
+) ([]common.HexInt32, []module.EventLog) {
+	if c == nil || len(es) == 0 || !c.Before(height, index, es[0].Value) {
+		return es, logs
+	}
+	i := 0
+	for i < len(es) && c.Before(height, index, es[i].Value) {
+		i++
+	}
+	if len(logs) > 0 {
+		logs = logs[i:]
+	}
+	return es[i:], logs
+}
+
+// heartbeat ticks on every interval to notify the progress while no
+// notifications are sent. C is nil if it's disabled.
+type heartbeat struct {
+	C <-chan time.Time
+	t *time.Ticker
+	d time.Duration
+}
+
+func newHeartbeat(ms int64) *heartbeat {
+	hb := new(heartbeat)
+	if ms > 0 {
+		hb.d = time.Duration(ms) * time.Millisecond
+		if hb.d < minHeartbeatInterval {
+			hb.d = minHeartbeatInterval
+		}
+		hb.t = time.NewTicker(hb.d)
+		hb.C = hb.t.C
+	}
+	return hb
+}
+
+// Reset delays the next tick after a notification is sent.
+func (hb *heartbeat) Reset() {
+	if hb.t != nil {
+		hb.t.Reset(hb.d)
+	}
+}
+
+func (hb *heartbeat) Stop() {
+	if hb.t != nil {
+		hb.t.Stop()
+	}
+}
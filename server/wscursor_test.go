@@ -0,0 +1,136 @@
+/*
+ * Copyright 2026 ICON Foundation
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package server
+
+import (
+	"encoding/json"
+	"io"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/icon-project/goloop/common"
+	"github.com/icon-project/goloop/common/log"
+	"github.com/icon-project/goloop/module"
+)
+
+func TestStreamCursor_Before(t *testing.T) {
+	c := cursorAt(10, 2, 1)
+	assert.True(t, c.Before(9, 5, 5))
+	assert.True(t, c.Before(10, 1, 5))
+	assert.True(t, c.Before(10, 2, 0))
+	assert.False(t, c.Before(10, 2, 1))
+	assert.False(t, c.Before(10, 3, 0))
+	assert.False(t, c.Before(11, 0, 0))
+
+	var nc *StreamCursor
+	assert.False(t, nc.Before(0, 0, 0))
+}
+
+func TestStreamCursor_TrimEvents(t *testing.T) {
+	c := cursorAt(10, 2, 1)
+	es := []common.HexInt32{{Value: 0}, {Value: 1}, {Value: 3}}
+	logs := []module.EventLog{nil, nil, nil}
+
+	es2, logs2 := c.TrimEvents(10, 2, es, logs)
+	assert.Equal(t, es[1:], es2)
+	assert.Len(t, logs2, 2)
+
+	es2, logs2 = c.TrimEvents(10, 1, es, logs)
+	assert.Empty(t, es2)
+	assert.Empty(t, logs2)
+
+	es2, logs2 = c.TrimEvents(10, 3, es, nil)
+	assert.Equal(t, es, es2)
+	assert.Nil(t, logs2)
+}
+
+func TestEventRequest_Resume(t *testing.T) {
+	logger := log.New()
+	logger.SetOutput(io.Discard)
+
+	cch := make(chan *testWebSocketConn, 1)
+	upgrader := newTestWebsocketUpgrader(func(ctx echo.Context, conn *testWebSocketConn) {
+		cch <- conn
+	})
+	wm := newWSSessionManagerWithUpgrader(logger, 1, upgrader)
+
+	stop := make(chan struct{})
+	defer close(stop)
+	blkReceipts := blockReceipts{
+		"2": testReceiptList{
+			newTestReceipt([]*testEventLog{
+				newTestEventLog("cx01", "EventLog1()", nil, nil),
+			}),
+			newTestReceipt([]*testEventLog{
+				newTestEventLog("cx02", "EventLog1()", nil, nil),
+				newTestEventLog("cx04", "EventLog2()", nil, nil),
+				newTestEventLog("cx03", "EventLog1()", nil, nil),
+			}),
+		},
+	}
+	chain := newTestChain(0,
+		func(h int64) (getBlockFunc, error) {
+			return func() module.Block {
+				if h > 2 {
+					<-stop
+				}
+				return &testBlock{
+					height: h,
+					result: "2",
+					lb:     blkReceipts["2"].LogsBloom(),
+				}
+			}, nil
+		},
+		blkReceipts,
+	)
+	go wm.RunRPCSession(newTestContext(chain))
+	conn := <-cch
+
+	msg := testRPCCall(t, conn, "subscribe", map[string]interface{}{
+		"type":      "event",
+		"event":     "EventLog1()",
+		"heartbeat": "0x64",
+		"cursor": map[string]interface{}{
+			"height": "0x2",
+			"index":  "0x1",
+			"event":  "0x1",
+		},
+	})
+	assert.Nil(t, msg.Error)
+
+	// events before the cursor are skipped
+	msg = testRPCRead(t, conn)
+	var en EventNotification
+	bs, _ := json.Marshal(msg.Params.Result)
+	assert.NoError(t, json.Unmarshal(bs, &en))
+	assert.EqualValues(t, 2, en.Height.Value)
+	assert.EqualValues(t, 1, en.Index.Value)
+	assert.Equal(t, []common.HexInt32{{Value: 2}}, en.Events)
+	assert.Equal(t, cursorAt(2, 2, 0), en.Cursor)
+
+	// heartbeat while waiting for the next block
+	msg = testRPCRead(t, conn)
+	var pn ProgressNotification
+	bs, _ = json.Marshal(msg.Params.Result)
+	assert.NoError(t, json.Unmarshal(bs, &pn))
+	assert.EqualValues(t, 2, pn.Progress.Value)
+	assert.Equal(t, cursorAt(3, 0, 0), pn.Cursor)
+
+	wm.StopAllSessions()
+}
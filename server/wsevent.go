@@ -8,6 +8,7 @@ import (
 
 	"github.com/icon-project/goloop/common"
 	"github.com/icon-project/goloop/common/errors"
+	"github.com/icon-project/goloop/common/log"
 	"github.com/icon-project/goloop/module"
 	"github.com/icon-project/goloop/server/jsonrpc"
 	"github.com/icon-project/goloop/service/scoreapi"
@@ -19,8 +20,14 @@ type EventRequest struct {
 	Height           common.HexInt64 `json:"height"`
 	Logs             common.HexBool  `json:"logs,omitempty"`
 	ProgressInterval common.HexInt64 `json:"progressInterval,omitempty"`
+	Cursor           *StreamCursor   `json:"cursor,omitempty"`
+	Heartbeat        common.HexInt64 `json:"heartbeat,omitempty"`
 
 	Filters EventFilters `json:"eventFilters,omitempty"`
+
+	filters EventFilters
+	bm      module.BlockManager
+	sm      module.ServiceManager
 }
 
 type EventFilters []*EventFilter
@@ -43,6 +50,7 @@ type EventNotification struct {
 	Index  common.HexInt32   `json:"index"`
 	Events []common.HexInt32 `json:"events"`
 	Logs   []module.EventLog `json:"logs,omitempty"`
+	Cursor *StreamCursor     `json:"cursor,omitempty"`
 }
 
 // FilteredByLogBloom returns applicable event filters.
@@ -101,54 +109,75 @@ func (fs EventFilters) filterEvents(r module.Receipt, v func(fi, idx int, log mo
 }
 
 func (wm *wsSessionManager) RunEventSession(ctx echo.Context) error {
-	var er EventRequest
-	wss, err := wm.initSession(ctx, &er)
-	if err != nil {
-		return err
-	}
-	defer wm.StopSession(wss)
+	return wm.runSession(ctx, new(EventRequest))
+}
 
+func (er *EventRequest) Prepare(chain module.Chain) *WSResponse {
 	filters, err := er.Compile()
 	if err != nil {
-		_ = wss.response(int(jsonrpc.ErrorCodeInvalidParams), "bad event request parameter")
-		return nil
+		return &WSResponse{Code: int(jsonrpc.ErrorCodeInvalidParams), Message: "bad event request parameter"}
+	}
+	er.filters = filters
+	if er.Cursor != nil {
+		er.Height = er.Cursor.Height
 	}
 
-	bm := wss.chain.BlockManager()
-	sm := wss.chain.ServiceManager()
-	if bm == nil || sm == nil {
-		_ = wss.response(int(jsonrpc.ErrorCodeServer), "Stopped")
-		return nil
+	er.bm = chain.BlockManager()
+	er.sm = chain.ServiceManager()
+	if er.bm == nil || er.sm == nil {
+		return &WSResponse{Code: int(jsonrpc.ErrorCodeServer), Message: "Stopped"}
 	}
 
 	h := er.Height.Value
-	if gh := wss.chain.GenesisStorage().Height(); gh > h {
-		_ = wss.response(int(jsonrpc.ErrorCodeInvalidParams),
-			fmt.Sprintf("given height(%d) is lower than genesis height(%d)", h, gh))
-		return nil
+	if gh := chain.GenesisStorage().Height(); gh > h {
+		return &WSResponse{
+			Code:    int(jsonrpc.ErrorCodeInvalidParams),
+			Message: fmt.Sprintf("given height(%d) is lower than genesis height(%d)", h, gh),
+		}
 	}
+	return nil
+}
 
-	_ = wss.response(0, "")
-
-	ech := make(chan error, 1)
-	wss.RunLoop(ech)
+func (er *EventRequest) Run(out wsOutput, ech <-chan error, logger log.Logger) error {
+	filters, bm, sm := er.filters, er.bm, er.sm
+	h := er.Height.Value
+	cursor := er.Cursor
+	resumable := cursor != nil
+	hb := newHeartbeat(er.Heartbeat.Value)
+	defer hb.Stop()
 
+	var err error
 	var bch <-chan module.Block
 	var pn ProgressNotification;
 loop:
 	for {
-		bch, err = bm.WaitForBlock(h)
-		if err != nil {
-			break loop
+		if bch == nil {
+			bch, err = bm.WaitForBlock(h)
+			if err != nil {
+				break loop
+			}
 		}
 		msgSent := 0
 		select {
 		case err = <-ech:
 			break loop
+		case <-hb.C:
+			// pn keeps the last progress for ProgressInterval
+			var hn ProgressNotification
+			hn.Progress.Value = h - 1
+			if resumable {
+				hn.Cursor = cursor
+			}
+			if err := out.WriteJSON(&hn); err != nil {
+				logger.Infof("fail to write json ProgressNotification(height=%d)", h-1)
+				break loop
+			}
+			continue loop
 		case blk, ok := <-bch:
 			if !ok {
 				break loop
 			}
+			bch = nil
 			filters2, contained := filters.FilteredByLogBloom(blk.LogsBloom())
 			if !contained {
 				break
@@ -164,14 +193,23 @@ loop:
 					break loop
 				}
 				if es, el, err := filters2.MatchEvents(r, er.Logs.Value); err == nil && len(es) > 0 {
+					es, el = cursor.TrimEvents(h, index, es, el)
+					if len(es) == 0 {
+						index++
+						continue
+					}
 					var en EventNotification
 					en.Height.Value = h
 					en.Hash = blk.ID()
 					en.Index.Value = index
 					en.Events = es
 					en.Logs = el
-					if err := wss.WriteJSON(&en); err != nil {
-						wm.logger.Infof("fail to write json EventNotification err:%+v\n", err)
+					if resumable {
+						cursor = cursorAt(h, index+1, 0)
+						en.Cursor = cursor
+					}
+					if err := out.WriteJSON(&en); err != nil {
+						logger.Infof("fail to write json EventNotification err:%+v\n", err)
 						break loop
 					}
 					msgSent++
@@ -179,21 +217,30 @@ loop:
 				index++
 			}
 		}
+		if resumable {
+			cursor = cursorAt(h+1, 0, 0)
+		}
 		// notify progress
 		if pi := er.ProgressInterval.Value ; pi > 0 {
 			last := pn.Progress.Value
 			if last == 0 || (h-last) >= pi || msgSent>0 {
 				pn.Progress.Value = h
-				if err := wss.WriteJSON(&pn); err != nil {
-					wm.logger.Infof("fail to write json ProgressNotification(height=%d)", h)
+				if resumable {
+					pn.Cursor = cursor
+				}
+				if err := out.WriteJSON(&pn); err != nil {
+					logger.Infof("fail to write json ProgressNotification(height=%d)", h)
 					break loop
 				}
+				msgSent++
 			}
 		}
+		if msgSent > 0 {
+			hb.Reset()
+		}
 		h++
 	}
-	wm.logger.Warnf("%+v\n", err)
-	return nil
+	return err
 }
 
 func (f *EventFilter) Compile() error {
@@ -0,0 +1,163 @@
+package server
+
+import (
+	"github.com/labstack/echo/v4"
+
+	"github.com/icon-project/goloop/common"
+	"github.com/icon-project/goloop/common/log"
+	"github.com/icon-project/goloop/module"
+	"github.com/icon-project/goloop/server/jsonrpc"
+	"github.com/icon-project/goloop/service"
+)
+
+const configPendingEventQueueSize = 1024
+
+type PendingRequest struct {
+	From        *common.Address `json:"from,omitempty"`
+	To          *common.Address `json:"to,omitempty"`
+	Group       string          `json:"group,omitempty"`
+	Transaction common.HexBool  `json:"transaction,omitempty"`
+	groups      map[module.TransactionGroup]bool
+	pw          txPoolWatcher
+}
+
+type PendingNotification struct {
+	Type        string          `json:"type"`
+	Group       string          `json:"group"`
+	Hash        common.HexBytes `json:"txHash"`
+	From        module.Address  `json:"from,omitempty"`
+	To          module.Address  `json:"to,omitempty"`
+	Reason      string          `json:"reason,omitempty"`
+	Transaction interface{}     `json:"transaction,omitempty"`
+}
+
+type txPoolWatcher interface {
+	WatchTransactionPool(cb func(ev *service.TxPoolEvent)) func()
+}
+
+type txWithAddresses interface {
+	From() module.Address
+	To() module.Address
+}
+
+func (r *PendingRequest) Compile() error {
+	groups, err := service.TxGroupsOf(r.Group)
+	if err != nil {
+		return err
+	}
+	r.groups = make(map[module.TransactionGroup]bool)
+	for _, g := range groups {
+		r.groups[g] = true
+	}
+	return nil
+}
+
+func (r *PendingRequest) Match(ev *service.TxPoolEvent) bool {
+	if !r.groups[ev.Group] {
+		return false
+	}
+	if r.From == nil && r.To == nil {
+		return true
+	}
+	tx, ok := ev.Tx.(txWithAddresses)
+	if !ok {
+		return false
+	}
+	if r.From != nil && !r.From.Equal(tx.From()) {
+		return false
+	}
+	if r.To != nil && !r.To.Equal(tx.To()) {
+		return false
+	}
+	return true
+}
+
+func (r *PendingRequest) NotificationOf(ev *service.TxPoolEvent) (*PendingNotification, error) {
+	pn := &PendingNotification{
+		Type:  ev.Type,
+		Group: service.TxGroupName(ev.Group),
+		Hash:  ev.Tx.ID(),
+	}
+	if tx, ok := ev.Tx.(txWithAddresses); ok {
+		pn.From = tx.From()
+		pn.To = tx.To()
+	}
+	if ev.Err != nil {
+		pn.Reason = ev.Err.Error()
+	}
+	if r.Transaction.Value && ev.Type == service.TxPoolEventAdd {
+		jso, err := ev.Tx.ToJSON(module.JSONVersion3)
+		if err != nil {
+			return nil, err
+		}
+		pn.Transaction = jso
+	}
+	return pn, nil
+}
+
+// RunPendingSession streams transactions entering and leaving transaction
+// pools. If the client can't follow the events, then the session is closed
+// with ErrorLackOfResource.
+func (wm *wsSessionManager) RunPendingSession(ctx echo.Context) error {
+	return wm.runSession(ctx, new(PendingRequest))
+}
+
+func (r *PendingRequest) Prepare(chain module.Chain) *WSResponse {
+	if err := r.Compile(); err != nil {
+		return &WSResponse{Code: int(jsonrpc.ErrorCodeInvalidParams), Message: err.Error()}
+	}
+
+	sm := chain.ServiceManager()
+	if sm == nil {
+		return &WSResponse{Code: int(jsonrpc.ErrorCodeServer), Message: "Stopped"}
+	}
+	pw, ok := sm.(txPoolWatcher)
+	if !ok {
+		return &WSResponse{Code: int(jsonrpc.ErrorCodeInvalidRequest), Message: "PoolWatchNotSupported"}
+	}
+	r.pw = pw
+	return nil
+}
+
+func (r *PendingRequest) Run(out wsOutput, ech <-chan error, logger log.Logger) error {
+	evch := make(chan *service.TxPoolEvent, configPendingEventQueueSize)
+	och := make(chan bool, 1)
+	cancel := r.pw.WatchTransactionPool(func(ev *service.TxPoolEvent) {
+		if !r.Match(ev) {
+			return
+		}
+		select {
+		case evch <- ev:
+		default:
+			select {
+			case och <- true:
+			default:
+			}
+		}
+	})
+	defer cancel()
+
+	var err error
+loop:
+	for {
+		select {
+		case err = <-ech:
+			break loop
+		case <-och:
+			_ = out.response(int(jsonrpc.ErrorLackOfResource), "too many pending events")
+			break loop
+		case ev := <-evch:
+			var pn *PendingNotification
+			if pn, err = r.NotificationOf(ev); err != nil {
+				logger.Infof("fail to make PendingNotification err:%+v\n", err)
+				err = nil
+				continue
+			}
+			if err = out.WriteJSON(pn); err != nil {
+				logger.Infof("fail to write json PendingNotification err:%+v\n", err)
+				break loop
+			}
+		}
+	}
+	return err
+}
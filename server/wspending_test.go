@@ -0,0 +1,177 @@
+/*
+ * Copyright 2026 ICON Foundation
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package server
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/icon-project/goloop/common"
+	"github.com/icon-project/goloop/common/errors"
+	"github.com/icon-project/goloop/common/log"
+	"github.com/icon-project/goloop/module"
+	"github.com/icon-project/goloop/service"
+)
+
+type testPendingTx struct {
+	module.Transaction
+	id   []byte
+	from module.Address
+	to   module.Address
+}
+
+func (t *testPendingTx) ID() []byte {
+	return t.id
+}
+
+func (t *testPendingTx) From() module.Address {
+	return t.from
+}
+
+func (t *testPendingTx) To() module.Address {
+	return t.to
+}
+
+func (t *testPendingTx) ToJSON(version module.JSONVersion) (interface{}, error) {
+	return map[string]interface{}{
+		"from":   t.from,
+		"to":     t.to,
+		"txHash": common.HexBytes(t.id),
+	}, nil
+}
+
+func TestPendingRequest_Compile(t *testing.T) {
+	for _, group := range []string{"", "patch", "normal"} {
+		pr := &PendingRequest{Group: group}
+		assert.NoError(t, pr.Compile())
+	}
+	pr := &PendingRequest{Group: "invalid"}
+	assert.Error(t, pr.Compile())
+}
+
+func TestPendingRequest_Match(t *testing.T) {
+	addr1 := common.MustNewAddressFromString("hx0000000000000000000000000000000000000001")
+	addr2 := common.MustNewAddressFromString("hx0000000000000000000000000000000000000002")
+	tx := &testPendingTx{id: []byte{0x01}, from: addr1, to: addr2}
+	ev := &service.TxPoolEvent{
+		Type:  service.TxPoolEventAdd,
+		Group: module.TransactionGroupNormal,
+		Tx:    tx,
+	}
+
+	tests := []struct {
+		name string
+		req  PendingRequest
+		want bool
+	}{
+		{"All", PendingRequest{}, true},
+		{"Group", PendingRequest{Group: "normal"}, true},
+		{"OtherGroup", PendingRequest{Group: "patch"}, false},
+		{"From", PendingRequest{From: addr1}, true},
+		{"OtherFrom", PendingRequest{From: addr2}, false},
+		{"To", PendingRequest{To: addr2}, true},
+		{"OtherTo", PendingRequest{To: addr1}, false},
+		{"FromTo", PendingRequest{From: addr1, To: addr2}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pr := tt.req
+			assert.NoError(t, pr.Compile())
+			assert.Equal(t, tt.want, pr.Match(ev))
+		})
+	}
+}
+
+func TestPendingRequest_NotificationOf(t *testing.T) {
+	addr1 := common.MustNewAddressFromString("hx0000000000000000000000000000000000000001")
+	addr2 := common.MustNewAddressFromString("cx0000000000000000000000000000000000000002")
+	tx := &testPendingTx{id: []byte{0x01}, from: addr1, to: addr2}
+
+	pr := &PendingRequest{Transaction: common.HexBool{Value: true}}
+	assert.NoError(t, pr.Compile())
+
+	pn, err := pr.NotificationOf(&service.TxPoolEvent{
+		Type:  service.TxPoolEventAdd,
+		Group: module.TransactionGroupPatch,
+		Tx:    tx,
+	})
+	assert.NoError(t, err)
+	bs, err := json.Marshal(pn)
+	assert.NoError(t, err)
+	var obj map[string]interface{}
+	assert.NoError(t, json.Unmarshal(bs, &obj))
+	assert.Equal(t, "add", obj["type"])
+	assert.Equal(t, "patch", obj["group"])
+	assert.Equal(t, "0x01", obj["txHash"])
+	assert.Equal(t, addr1.String(), obj["from"])
+	assert.Equal(t, addr2.String(), obj["to"])
+	assert.NotNil(t, obj["transaction"])
+	assert.Nil(t, obj["reason"])
+
+	pn, err = pr.NotificationOf(&service.TxPoolEvent{
+		Type:  service.TxPoolEventDrop,
+		Group: module.TransactionGroupNormal,
+		Tx:    tx,
+		Err:   errors.TimeoutError.New("Expired"),
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, "drop", pn.Type)
+	assert.Equal(t, "normal", pn.Group)
+	assert.NotEmpty(t, pn.Reason)
+	assert.Nil(t, pn.Transaction)
+}
+
+type testPoolWatcher []*service.TxPoolEvent
+
+func (w testPoolWatcher) WatchTransactionPool(cb func(ev *service.TxPoolEvent)) func() {
+	for _, ev := range w {
+		cb(ev)
+	}
+	return func() {}
+}
+
+type testWSOutput struct {
+	err error
+}
+
+func (o *testWSOutput) WriteJSON(v interface{}) error {
+	return o.err
+}
+
+func (o *testWSOutput) response(code int, msg string) error {
+	return nil
+}
+
+func TestPendingRequest_Run(t *testing.T) {
+	addr1 := common.MustNewAddressFromString("hx0000000000000000000000000000000000000001")
+	addr2 := common.MustNewAddressFromString("hx0000000000000000000000000000000000000002")
+	tx := &testPendingTx{id: []byte{0x01}, from: addr1, to: addr2}
+
+	pr := &PendingRequest{}
+	assert.NoError(t, pr.Compile())
+	pr.pw = testPoolWatcher{{
+		Type:  service.TxPoolEventAdd,
+		Group: module.TransactionGroupNormal,
+		Tx:    tx,
+	}}
+
+	werr := errors.New("WriteFailure")
+	err := pr.Run(&testWSOutput{err: werr}, make(chan error), log.New())
+	assert.Equal(t, werr, err)
+}
@@ -0,0 +1,332 @@
+/*
+ * Copyright 2026 ICON Foundation
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package server
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"sync"
+
+	"github.com/labstack/echo/v4"
+
+	"github.com/icon-project/goloop/common"
+	"github.com/icon-project/goloop/common/log"
+	"github.com/icon-project/goloop/server/jsonrpc"
+)
+
+const (
+	// DefaultWSMaxSubscription is the maximum number of subscriptions
+	// on a connection of the JSON-RPC websocket.
+	DefaultWSMaxSubscription = 16
+
+	methodSubscribe    = "subscribe"
+	methodUnsubscribe  = "unsubscribe"
+	methodSubscription = "subscription"
+)
+
+const (
+	SubscriptionTypeBlock   = "block"
+	SubscriptionTypeEvent   = "event"
+	SubscriptionTypeBTP     = "btp"
+	SubscriptionTypePending = "pending"
+)
+
+var errUnsubscribed = errors.New("unsubscribed")
+
+type UnsubscribeParam struct {
+	ID common.HexInt64 `json:"id"`
+}
+
+// SubscriptionNotification is the parameter of the notification for
+// the subscription. Result is the notification of the stream. If the
+// subscription is terminated by the server, it has Error instead.
+type SubscriptionNotification struct {
+	Subscription common.HexInt64 `json:"subscription"`
+	Result       interface{}     `json:"result,omitempty"`
+	Error        *jsonrpc.Error  `json:"error,omitempty"`
+}
+
+type subscriptionMessage struct {
+	Version string                    `json:"jsonrpc"`
+	Method  string                    `json:"method"`
+	Params  *SubscriptionNotification `json:"params"`
+}
+
+// wsSubscription is a stream on the JSON-RPC websocket. It implements
+// wsOutput to wrap notifications of the stream.
+type wsSubscription struct {
+	id     int64
+	wss    *wsSession
+	stream wsStream
+	ech    chan error
+	failed bool
+}
+
+func (s *wsSubscription) notify(sn *SubscriptionNotification) error {
+	sn.Subscription.Value = s.id
+	return s.wss.WriteJSON(&subscriptionMessage{
+		Version: jsonrpc.Version,
+		Method:  methodSubscription,
+		Params:  sn,
+	})
+}
+
+func (s *wsSubscription) WriteJSON(v interface{}) error {
+	return s.notify(&SubscriptionNotification{Result: v})
+}
+
+func (s *wsSubscription) response(code int, msg string) error {
+	if code == 0 {
+		return nil
+	}
+	s.failed = true
+	return s.notify(&SubscriptionNotification{
+		Error: &jsonrpc.Error{Code: jsonrpc.ErrorCode(code), Message: msg},
+	})
+}
+
+func (s *wsSubscription) stop() {
+	select {
+	case s.ech <- errUnsubscribed:
+	default:
+	}
+}
+
+type wsRPCSession struct {
+	lock   sync.Mutex
+	wss    *wsSession
+	debug  bool
+	logger log.Logger
+
+	lastID int64
+	subs   map[int64]*wsSubscription
+	wg     sync.WaitGroup
+}
+
+func newStream(typ string, debug bool) wsStream {
+	switch typ {
+	case SubscriptionTypeBlock:
+		return new(BlockRequest)
+	case SubscriptionTypeEvent:
+		return new(EventRequest)
+	case SubscriptionTypeBTP:
+		return new(BTPRequest)
+	case SubscriptionTypePending:
+		if debug {
+			return new(PendingRequest)
+		}
+	}
+	return nil
+}
+
+// decodeStream returns the stream for the parameter of subscribe. The
+// parameter is the request of the stream with "type" field for the type
+// of the stream.
+func decodeStream(params json.RawMessage, debug bool) (wsStream, error) {
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(params, &fields); err != nil {
+		return nil, err
+	}
+	var typ string
+	if err := json.Unmarshal(fields["type"], &typ); err != nil {
+		return nil, errors.New("invalid subscription type")
+	}
+	s := newStream(typ, debug)
+	if s == nil {
+		return nil, errors.New("unknown subscription type:" + typ)
+	}
+	delete(fields, "type")
+	bs, err := json.Marshal(fields)
+	if err != nil {
+		return nil, err
+	}
+	jd := json.NewDecoder(bytes.NewBuffer(bs))
+	jd.DisallowUnknownFields()
+	if err := jd.Decode(s); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+func (rs *wsRPCSession) subscribe(params json.RawMessage) (*wsSubscription, *jsonrpc.Error) {
+	s, err := decodeStream(params, rs.debug)
+	if err != nil {
+		return nil, jsonrpc.ErrorCodeInvalidParams.Wrap(err, rs.debug)
+	}
+
+	rs.lock.Lock()
+	defer rs.lock.Unlock()
+
+	if len(rs.subs) >= DefaultWSMaxSubscription {
+		return nil, jsonrpc.ErrorLackOfResource.New("too many subscriptions")
+	}
+	if res := s.Prepare(rs.wss.chain); res != nil {
+		return nil, &jsonrpc.Error{
+			Code:    jsonrpc.ErrorCode(res.Code),
+			Message: res.Message,
+		}
+	}
+	rs.lastID += 1
+	sub := &wsSubscription{
+		id:     rs.lastID,
+		wss:    rs.wss,
+		stream: s,
+		ech:    make(chan error, 1),
+	}
+	rs.subs[sub.id] = sub
+	return sub, nil
+}
+
+// run runs the stream of the subscription. If the stream stops without
+// request of the client, then it notifies the termination.
+func (rs *wsRPCSession) run(sub *wsSubscription) {
+	defer rs.wg.Done()
+
+	err := sub.stream.Run(sub, sub.ech, rs.logger)
+	if err != errUnsubscribed {
+		rs.logger.Warnf("subscription id=%d stops err=%+v", sub.id, err)
+		if !sub.failed {
+			msg := "Terminated"
+			if err != nil {
+				msg = err.Error()
+			}
+			_ = sub.response(int(jsonrpc.ErrorCodeServer), msg)
+		}
+	}
+
+	rs.lock.Lock()
+	defer rs.lock.Unlock()
+	if rs.subs[sub.id] == sub {
+		delete(rs.subs, sub.id)
+	}
+}
+
+func (rs *wsRPCSession) unsubscribe(params json.RawMessage) (interface{}, *jsonrpc.Error) {
+	var param UnsubscribeParam
+	if err := json.Unmarshal(params, &param); err != nil {
+		return nil, jsonrpc.ErrorCodeInvalidParams.Wrap(err, rs.debug)
+	}
+
+	rs.lock.Lock()
+	defer rs.lock.Unlock()
+
+	sub, ok := rs.subs[param.ID.Value]
+	if !ok {
+		return nil, jsonrpc.ErrorCodeNotFound.Errorf(
+			"UnknownSubscription(id=%s)", param.ID)
+	}
+	delete(rs.subs, sub.id)
+	sub.stop()
+	return true, nil
+}
+
+// handle handles the request, and sends the response. Streams of new
+// subscriptions start after the response, so the result comes first.
+func (rs *wsRPCSession) handle(msg []byte) error {
+	resp := &jsonrpc.Response{Version: jsonrpc.Version}
+	var req jsonrpc.Request
+	var sub *wsSubscription
+	if err := json.Unmarshal(msg, &req); err != nil {
+		resp.Error = jsonrpc.ErrorCodeJsonParse.Wrap(err, rs.debug)
+	} else if resp.ID = req.ID; req.Version != jsonrpc.Version || req.Method == nil {
+		resp.Error = jsonrpc.ErrInvalidRequest()
+	} else {
+		switch *req.Method {
+		case methodSubscribe:
+			if sub, resp.Error = rs.subscribe(req.Params); sub != nil {
+				resp.Result = common.HexInt64{Value: sub.id}
+			}
+		case methodUnsubscribe:
+			resp.Result, resp.Error = rs.unsubscribe(req.Params)
+		default:
+			resp.Error = jsonrpc.ErrMethodNotFound()
+		}
+		if req.ID == nil {
+			resp = nil
+		}
+	}
+	if resp != nil {
+		if err := rs.wss.WriteJSON(resp); err != nil {
+			return err
+		}
+	}
+	if sub != nil {
+		rs.wg.Add(1)
+		go rs.run(sub)
+	}
+	return nil
+}
+
+func (rs *wsRPCSession) stopAll() {
+	rs.lock.Lock()
+	for id, sub := range rs.subs {
+		sub.stop()
+		delete(rs.subs, id)
+	}
+	rs.lock.Unlock()
+
+	rs.wg.Wait()
+}
+
+// RunRPCSession serves JSON-RPC over the websocket. A client may hold
+// multiple subscriptions of blocks, events, BTP blocks and pending
+// transactions on the connection with subscribe and unsubscribe. The
+// connection is counted as a session regardless of number of subscriptions.
+func (wm *wsSessionManager) RunRPCSession(ctx echo.Context) error {
+	chain, err := wm.chain(ctx)
+	if err != nil {
+		return err
+	}
+	debug, _ := ctx.Get("includeDebug").(bool)
+
+	c, err := wm.upgrader.Upgrade(ctx)
+	if err != nil {
+		return err
+	}
+
+	wss := wm.NewSession(c, chain)
+	if wss == nil {
+		c.WriteJSON(&jsonrpc.Response{
+			Version: jsonrpc.Version,
+			Error:   jsonrpc.ErrorLackOfResource.New("too many monitor"),
+		})
+		c.Close()
+		return errors.New("too many monitor")
+	}
+	defer wm.StopSession(wss)
+
+	rs := &wsRPCSession{
+		wss:    wss,
+		debug:  debug,
+		logger: wm.logger,
+		subs:   make(map[int64]*wsSubscription),
+	}
+	defer rs.stopAll()
+
+	for {
+		_, msg, err := c.ReadMessage()
+		if err != nil {
+			wm.logger.Warnf("%+v\n", err)
+			return nil
+		}
+		if err := rs.handle(msg); err != nil {
+			wm.logger.Infof("fail to write json Response err:%+v\n", err)
+			return nil
+		}
+	}
+}
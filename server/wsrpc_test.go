@@ -0,0 +1,154 @@
+/*
+ * Copyright 2026 ICON Foundation
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package server
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/icon-project/goloop/common/log"
+	"github.com/icon-project/goloop/module"
+	"github.com/icon-project/goloop/server/jsonrpc"
+)
+
+type testRPCMessage struct {
+	Version string                    `json:"jsonrpc"`
+	Method  string                    `json:"method,omitempty"`
+	Params  *SubscriptionNotification `json:"params,omitempty"`
+	Result  json.RawMessage           `json:"result,omitempty"`
+	Error   *jsonrpc.Error            `json:"error,omitempty"`
+	ID      interface{}               `json:"id,omitempty"`
+}
+
+func testRPCCall(t *testing.T, conn *testWebSocketConn, method string, params interface{}) *testRPCMessage {
+	err := conn.clientWriteJSON(map[string]interface{}{
+		"jsonrpc": "2.0",
+		"method":  method,
+		"params":  params,
+		"id":      1,
+	})
+	assert.NoError(t, err)
+	return testRPCRead(t, conn)
+}
+
+func testRPCRead(t *testing.T, conn *testWebSocketConn) *testRPCMessage {
+	bs, err := conn.clientRead()
+	assert.NoError(t, err)
+	msg := new(testRPCMessage)
+	assert.NoError(t, json.Unmarshal(bs, msg))
+	return msg
+}
+
+func TestWSSessionManager_RunRPCSession(t *testing.T) {
+	logger := log.New()
+	logger.SetOutput(io.Discard)
+
+	cch := make(chan *testWebSocketConn, 1)
+	upgrader := newTestWebsocketUpgrader(func(ctx echo.Context, conn *testWebSocketConn) {
+		cch <- conn
+	})
+	wm := newWSSessionManagerWithUpgrader(logger, 1, upgrader)
+
+	stop := make(chan struct{})
+	defer close(stop)
+	chain := newTestChain(0,
+		func(h int64) (getBlockFunc, error) {
+			return func() module.Block {
+				if h > 2 {
+					<-stop
+				}
+				return &testBlock{
+					height: h,
+					result: "empty",
+				}
+			}, nil
+		},
+		blockReceipts{
+			"empty": testReceiptList{},
+		},
+	)
+	done := make(chan error, 1)
+	go func() {
+		done <- wm.RunRPCSession(newTestContext(chain))
+	}()
+	conn := <-cch
+
+	// another connection exceeds the session limit
+	go wm.RunRPCSession(newTestContext(chain))
+	conn2 := <-cch
+	msg := testRPCRead(t, conn2)
+	assert.EqualValues(t, jsonrpc.ErrorLackOfResource, msg.Error.Code)
+
+	msg = testRPCCall(t, conn, "subscribe", map[string]interface{}{
+		"type":   "block",
+		"height": "0x1",
+	})
+	assert.Nil(t, msg.Error)
+	assert.Equal(t, `"0x1"`, string(msg.Result))
+
+	for _, h := range []int64{1, 2} {
+		msg = testRPCRead(t, conn)
+		assert.Equal(t, "subscription", msg.Method)
+		assert.EqualValues(t, 1, msg.Params.Subscription.Value)
+		bn := msg.Params.Result.(map[string]interface{})
+		assert.Equal(t, "0x"+hex.EncodeToString(testHeightToBlockID(h)), bn["hash"])
+	}
+
+	msg = testRPCCall(t, conn, "unknown", nil)
+	assert.EqualValues(t, jsonrpc.ErrorCodeMethodNotFound, msg.Error.Code)
+
+	msg = testRPCCall(t, conn, "unsubscribe", map[string]interface{}{"id": "0x1"})
+	assert.Nil(t, msg.Error)
+	assert.Equal(t, "true", string(msg.Result))
+
+	msg = testRPCCall(t, conn, "unsubscribe", map[string]interface{}{"id": "0x1"})
+	assert.EqualValues(t, jsonrpc.ErrorCodeNotFound, msg.Error.Code)
+
+	for _, params := range []map[string]interface{}{
+		{"type": "unknown"},
+		{"type": "pending"},
+		{"type": "block", "height": "0x1", "unknownField": "0x1"},
+		{"type": "event", "height": "0x1", "event": "Invalid"},
+	} {
+		msg = testRPCCall(t, conn, "subscribe", params)
+		assert.EqualValues(t, jsonrpc.ErrorCodeInvalidParams, msg.Error.Code)
+	}
+
+	for i := 0; i < DefaultWSMaxSubscription; i++ {
+		msg = testRPCCall(t, conn, "subscribe", map[string]interface{}{
+			"type":   "event",
+			"height": "0x3",
+			"event":  "EventLog()",
+		})
+		assert.Nil(t, msg.Error)
+	}
+	msg = testRPCCall(t, conn, "subscribe", map[string]interface{}{
+		"type":   "block",
+		"height": "0x3",
+	})
+	assert.EqualValues(t, jsonrpc.ErrorLackOfResource, msg.Error.Code)
+
+	wm.StopAllSessions()
+	assert.NoError(t, <-done)
+	_, err := conn.clientRead()
+	assert.Error(t, err)
+}
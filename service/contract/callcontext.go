@@ -148,18 +148,25 @@ func (cc *callContext) pushFrame(handler ContractHandler, limit *big.Int) *callF
 		frame.snapshot = cc.GetSnapshot()
 	}
 	logger.OnFrameEnter(cc.frame.fid)
+	if logger.TraceMode() == module.TraceModeCallTree {
+		logger.OnCallStart(traceCallOf(handler, limit))
+	}
 	frame.fid = cc.nextFID
 	cc.nextFID += 1
 	cc.frame = frame
 	return frame
 }
 
-func (cc *callContext) popFrame(success bool) *callFrame {
+func (cc *callContext) popFrame(status error, result *codec.TypedObj) *callFrame {
 	cc.lock.Lock()
 	defer cc.lock.Unlock()
 
 	frame := cc.frame
+	success := status == nil
 	cc.frame.log.OnFrameExit(success, &frame.stepUsed)
+	if frame.log.TraceMode() == module.TraceModeCallTree {
+		frame.log.OnCallEnd(status, frame.getStepUsed(), resultForTrace(result))
+	}
 	if !frame.isReadOnly {
 		if success {
 			frame.parent.applyFrameLogsOf(frame)
@@ -214,6 +221,7 @@ func (cc *callContext) addLogToFrame(addr module.Address, indexed [][]byte, data
 		addr, indexed[0],
 		common.SliceOfHexBytes(indexed[1:]),
 		common.SliceOfHexBytes(data))
+	cc.frame.log.OnEvent(addr, indexed, data)
 	cc.frame.addLog(addr, indexed, data)
 	return nil
 }
@@ -356,6 +364,7 @@ func (cc *callContext) cleanUpFrames(target *callFrame, err error) {
 	for cc.frame != nil && cc.frame.handler != nil {
 		frame := cc.frame
 		cc.frame = frame.parent
+		frame.log.OnCallEnd(err, frame.getStepUsed(), nil)
 		if ach, ok := frame.handler.(AsyncContractHandler); ok {
 			achs = append(achs, ach)
 		}
@@ -385,7 +394,7 @@ func (cc *callContext) handleResult(target *callFrame, status error, result *cod
 		return false
 	}
 
-	current := cc.popFrame(status == nil)
+	current := cc.popFrame(status, result)
 	if current == nil {
 		return false
 	}
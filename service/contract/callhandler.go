@@ -425,6 +425,7 @@ func (h *CallHandler) GetValue(key []byte) ([]byte, error) {
 		h.cc.DoIOTask(func() {
 			value, err = h.store.GetValue(key)
 		})
+		h.Log.OnStorageAccess(false, key)
 		if err != nil {
 			h.Log.TSystemf("GETVALUE key=<%x> err=%+v", key, err)
 		} else {
@@ -448,6 +449,7 @@ func (h *CallHandler) SetValue(key []byte, value []byte) ([]byte, error) {
 		h.cc.DoIOTask(func() {
 			old, err = h.store.SetValue(key, value)
 		})
+		h.Log.OnStorageAccess(true, key)
 		if err != nil {
 			h.Log.TSystemf("SETVALUE key=<%x> value=<%x> err=%+v", key, value, err)
 		} else {
@@ -471,6 +473,7 @@ func (h *CallHandler) DeleteValue(key []byte) ([]byte, error) {
 		h.cc.DoIOTask(func() {
 			old, err = h.store.DeleteValue(key)
 		})
+		h.Log.OnStorageAccess(true, key)
 		if err != nil {
 			h.Log.TSystemf("DELETE key=<%x> err=%+v", key, err)
 		} else {
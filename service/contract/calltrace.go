@@ -0,0 +1,93 @@
+package contract
+
+import (
+	"encoding/json"
+	"math/big"
+
+	"github.com/icon-project/goloop/common"
+	"github.com/icon-project/goloop/common/codec"
+	"github.com/icon-project/goloop/module"
+)
+
+const (
+	CallTypeCall     = "call"
+	CallTypeTransfer = "transfer"
+	CallTypeDeploy   = "deploy"
+	CallTypeAccept   = "accept"
+	CallTypeDeposit  = "deposit"
+	CallTypePatch    = "patch"
+	CallTypeDSR      = "dsr"
+	CallTypeGetAPI   = "getAPI"
+)
+
+func (h *CommonHandler) commonHandler() *CommonHandler {
+	return h
+}
+
+// traceCallOf returns the information of the call handled by the handler
+// for TraceModeCallTree.
+func traceCallOf(handler ContractHandler, limit *big.Int) *module.TraceCall {
+	call := &module.TraceCall{
+		StepLimit: limit,
+	}
+	if ch, ok := handler.(interface{ commonHandler() *CommonHandler }); ok {
+		h := ch.commonHandler()
+		call.From = h.From
+		call.To = h.To
+		call.Value = h.Value
+	}
+	switch h := handler.(type) {
+	case *CallHandler:
+		call.Type = CallTypeCall
+		call.Method = h.name
+		call.Params = h.paramsForTrace()
+	case *TransferAndCallHandler:
+		call.Type = CallTypeCall
+		call.Method = h.name
+		call.Params = h.paramsForTrace()
+	case *TransferHandler:
+		call.Type = CallTypeTransfer
+	case *DeployHandler:
+		call.Type = CallTypeDeploy
+		if len(h.params) > 0 {
+			call.Params = json.RawMessage(h.params)
+		}
+	case *AcceptHandler:
+		call.Type = CallTypeAccept
+	case *DepositHandler:
+		call.Type = CallTypeDeposit
+		if h.data != nil {
+			call.Method = h.data.Action
+		}
+	case *patchHandler:
+		call.Type = CallTypePatch
+	case *DSRHandler:
+		call.Type = CallTypeDSR
+	case *callGetAPIHandler:
+		call.Type = CallTypeGetAPI
+	default:
+		// handlers of platforms (ex. icon.TransferHandler) only transfer coins.
+		call.Type = CallTypeTransfer
+	}
+	return call
+}
+
+func (h *CallHandler) paramsForTrace() interface{} {
+	if h.paramObj != nil {
+		if obj, err := common.DecodeAnyForJSON(h.paramObj); err == nil {
+			return obj
+		}
+	}
+	if len(h.params) > 0 && json.Valid(h.params) {
+		return json.RawMessage(h.params)
+	}
+	return nil
+}
+
+func resultForTrace(result *codec.TypedObj) interface{} {
+	if result == nil {
+		return nil
+	}
+	obj, _ := common.DecodeAnyForJSON(result)
+	return obj
+}
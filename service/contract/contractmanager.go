@@ -352,6 +352,7 @@ func NewContractManager(db db.Database, contractDir string, log log.Logger) (Con
 
 const (
 	javaCode               = "code.jar"
+	wasmCode               = "code.wasm"
 	tmpRoot                = "tmp"
 	tmpPattern             = "tmp-*"
 	contractPythonRootFile = "package.json"
@@ -448,6 +449,23 @@ func storeJava(path string, code []byte, log log.Logger) error {
 	return nil
 }
 
+func storeWASM(path string, code []byte, log log.Logger) error {
+	if err := validateWASM(code); err != nil {
+		return err
+	}
+	if _, err := os.Stat(path); os.IsNotExist(err) {
+		if err = os.MkdirAll(path, 0755); err != nil {
+			return errors.WithCode(err, errors.CriticalIOError)
+		}
+	}
+	sPath := filepath.Join(path, wasmCode)
+	if err := os.WriteFile(sPath, code, 0644); err != nil {
+		_ = os.RemoveAll(sPath)
+		return errors.WithCode(err, errors.CriticalIOError)
+	}
+	return nil
+}
+
 func storeByEEType(e state.EEType, path string, code []byte, log log.Logger) error {
 	var err error
 	switch e {
@@ -455,6 +473,8 @@ func storeByEEType(e state.EEType, path string, code []byte, log log.Logger) err
 		err = storePython(path, code, log)
 	case state.JavaEE:
 		err = storeJava(path, code, log)
+	case state.WASMEE:
+		err = storeWASM(path, code, log)
 	default:
 		err = scoreresult.Errorf(module.StatusInvalidParameter,
 			"UnexpectedEEType(%v)\n", e)
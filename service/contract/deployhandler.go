@@ -330,6 +330,11 @@ func (h *DeployHandler) DoExecuteSync(cc CallContext) (error, *codec.TypedObj, m
 			h.contentType, cc.GetEnabledEETypes().String()), nil, nil
 	}
 
+	if h.eeType == state.WASMEE && !cc.Revision().Has(module.WASMContracts) {
+		return scoreresult.InvalidParameterError.Errorf("UnsupportedContentType(ct=%s,rev=%d)",
+			h.contentType, cc.Revision().Value()), nil, nil
+	}
+
 	if update == false {
 		if as.InitContractAccount(h.From) == false {
 			return errors.ErrExecutionFail, nil, nil
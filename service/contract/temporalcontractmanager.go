@@ -0,0 +1,87 @@
+package contract
+
+import (
+	"container/list"
+	"encoding/hex"
+	"os"
+	"path/filepath"
+	"sync"
+
+	"github.com/icon-project/goloop/common/errors"
+	"github.com/icon-project/goloop/service/state"
+)
+
+const temporalStorePattern = "goloop-contract-*"
+
+// TemporalContractManager stores codes added with AddCode in a temporal
+// directory instead of the contract store, so codes only used for the
+// simulation (ex. state overrides) are not kept. Others are handled by the
+// base contract manager. Close removes the temporal directory.
+type TemporalContractManager struct {
+	ContractManager
+
+	lock  sync.Mutex
+	dir   string
+	codes map[string]bool
+}
+
+// AddCode makes the code of the hash to be stored in the temporal directory.
+func (cm *TemporalContractManager) AddCode(codeHash []byte) {
+	cm.lock.Lock()
+	defer cm.lock.Unlock()
+
+	cm.codes[string(codeHash)] = true
+}
+
+func (cm *TemporalContractManager) PrepareContractStore(
+	ws state.WorldState, contract state.ContractState) (ContractStore, error) {
+	codeHash := contract.CodeHash()
+
+	cm.lock.Lock()
+	defer cm.lock.Unlock()
+
+	if !cm.codes[string(codeHash)] {
+		return cm.ContractManager.PrepareContractStore(ws, contract)
+	}
+	if cm.dir == "" {
+		dir, err := os.MkdirTemp("", temporalStorePattern)
+		if err != nil {
+			return nil, errors.WithCode(err, errors.CriticalIOError)
+		}
+		cm.dir = dir
+	}
+	path := filepath.Join(cm.dir, "0x"+hex.EncodeToString(codeHash))
+	if _, err := os.Stat(path); os.IsNotExist(err) {
+		code, err := contract.Code()
+		if err != nil {
+			return nil, err
+		}
+		if err := storeByEEType(contract.EEType(), path, code, cm.Logger()); err != nil {
+			os.RemoveAll(path)
+			return nil, err
+		}
+	}
+	sc := &storageCache{clients: list.New()}
+	cs := &contractStoreImpl{ch: make(chan error, 1)}
+	sc.push(cs)
+	sc.complete(path, nil)
+	return cs, nil
+}
+
+// Close removes the temporal directory.
+func (cm *TemporalContractManager) Close() {
+	cm.lock.Lock()
+	defer cm.lock.Unlock()
+
+	if cm.dir != "" {
+		os.RemoveAll(cm.dir)
+		cm.dir = ""
+	}
+}
+
+func NewTemporalContractManager(cm ContractManager) *TemporalContractManager {
+	return &TemporalContractManager{
+		ContractManager: cm,
+		codes:           make(map[string]bool),
+	}
+}
@@ -0,0 +1,65 @@
+package contract
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/icon-project/goloop/common"
+	"github.com/icon-project/goloop/common/db"
+	"github.com/icon-project/goloop/common/log"
+	"github.com/icon-project/goloop/service/state"
+)
+
+func deployTestContract(t *testing.T, ws state.WorldState, addr string, code []byte) state.ContractState {
+	as := ws.GetAccountState(common.MustNewAddressFromString(addr).ID())
+	as.InitContractAccount(common.MustNewAddressFromString("hx1"))
+	_, err := as.DeployContract(code, state.JavaEE, state.CTAppJava, nil, []byte(addr))
+	assert.NoError(t, err)
+	return as.NextContract()
+}
+
+func TestTemporalContractManager(t *testing.T) {
+	dbase := db.NewMapDB()
+	root := t.TempDir()
+	base, err := NewContractManager(dbase, root, log.New())
+	assert.NoError(t, err)
+
+	ws := state.NewWorldState(dbase, nil, nil, nil, nil)
+	c1 := deployTestContract(t, ws, "cx1", []byte("code1"))
+	c2 := deployTestContract(t, ws, "cx2", []byte("code2"))
+
+	cm := NewTemporalContractManager(base)
+	cm.AddCode(c1.CodeHash())
+
+	cs, err := cm.PrepareContractStore(ws, c1)
+	assert.NoError(t, err)
+	p1, err := cs.WaitResult()
+	assert.NoError(t, err)
+	cs.Dispose()
+	assert.False(t, strings.HasPrefix(p1, root))
+	bs, err := os.ReadFile(filepath.Join(p1, javaCode))
+	assert.NoError(t, err)
+	assert.Equal(t, []byte("code1"), bs)
+
+	// others are stored in the contract store
+	cs, err = cm.PrepareContractStore(ws, c2)
+	assert.NoError(t, err)
+	p2, err := cs.WaitResult()
+	assert.NoError(t, err)
+	cs.Dispose()
+	assert.Equal(t, root, filepath.Dir(p2))
+
+	cm.Close()
+	_, err = os.Stat(p1)
+	assert.True(t, os.IsNotExist(err))
+	_, err = os.Stat(p2)
+	assert.NoError(t, err)
+
+	entries, err := os.ReadDir(root)
+	assert.NoError(t, err)
+	assert.Len(t, entries, 1)
+}
@@ -0,0 +1,20 @@
+package contract
+
+import (
+	"github.com/icon-project/goloop/service/eeproxy"
+	"github.com/icon-project/goloop/service/scoreresult"
+)
+
+// validateWASM checks whether the module can be executed by the engine.
+// The module is decoded and validated with its API and imports, so only
+// contracts which the engine can run deterministically are stored.
+func validateWASM(code []byte) error {
+	if len(code) > ContentSizeLimit {
+		return scoreresult.IllegalFormatError.Errorf(
+			"OversizeContent(size=%d,limit=%d)", len(code), ContentSizeLimit)
+	}
+	if err := eeproxy.ValidateWASMCode(code); err != nil {
+		return scoreresult.IllegalFormatError.Wrap(err, "InvalidWASMModule")
+	}
+	return nil
+}
@@ -0,0 +1,90 @@
+package contract
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/icon-project/goloop/service/scoreresult"
+)
+
+var wasmHeader = []byte{0x00, 0x61, 0x73, 0x6d, 0x01, 0x00, 0x00, 0x00}
+
+func wasmVarUint(v int) []byte {
+	var bs []byte
+	for {
+		b := byte(v & 0x7f)
+		v >>= 7
+		if v == 0 {
+			return append(bs, b)
+		}
+		bs = append(bs, b|0x80)
+	}
+}
+
+func wasmName(s string) []byte {
+	return append(wasmVarUint(len(s)), s...)
+}
+
+func wasmSection(id byte, content []byte) []byte {
+	return append(append([]byte{id}, wasmVarUint(len(content))...), content...)
+}
+
+func wasmModule(sections ...[]byte) []byte {
+	code := append([]byte{}, wasmHeader...)
+	for _, s := range sections {
+		code = append(code, s...)
+	}
+	return code
+}
+
+func wasmAPISection(api string) []byte {
+	return wasmSection(0x00, append(wasmName("score_api"), api...))
+}
+
+func TestValidateWASM(t *testing.T) {
+	typeSec := wasmSection(0x01, []byte{0x01, 0x60, 0x00, 0x00})
+	funcSec := wasmSection(0x03, []byte{0x01, 0x00})
+	exportSec := wasmSection(0x07, append(append([]byte{0x01}, wasmName("hello")...), 0x00, 0x00))
+	codeSec := wasmSection(0x0a, []byte{0x01, 0x02, 0x00, 0x0b})
+	apiSec := wasmAPISection(`[{"type":"function","name":"hello","inputs":[],"outputs":[]}]`)
+	importSec := func(name string) []byte {
+		im := append(wasmName("env"), wasmName(name)...)
+		return wasmSection(0x02, append(append([]byte{0x01}, im...), 0x00, 0x00))
+	}
+
+	cases := []struct {
+		name  string
+		code  []byte
+		valid bool
+	}{
+		{"Basic", wasmModule(apiSec, typeSec, funcSec, exportSec, codeSec), true},
+		{"NoMethod", wasmModule(wasmAPISection(`[]`)), true},
+		{"NoAPI", wasmModule(typeSec, funcSec, exportSec, codeSec), false},
+		{"InvalidAPI", wasmModule(wasmAPISection(`{}`), typeSec, funcSec, exportSec, codeSec), false},
+		{"NoFunction", wasmModule(wasmAPISection(`[{"type":"function","name":"bye"}]`),
+			typeSec, funcSec, exportSec, codeSec), false},
+		{"DefaultParam", wasmModule(wasmAPISection(
+			`[{"type":"function","name":"hello","inputs":[{"name":"a","type":"int","default":"0x1"}]}]`),
+			typeSec, funcSec, exportSec, codeSec), false},
+		{"UnknownImport", wasmModule(apiSec, typeSec, importSec("exit"), funcSec, exportSec, codeSec), false},
+		{"InvalidImportType", wasmModule(apiSec, typeSec, importSec("log"), funcSec, exportSec, codeSec), false},
+		{"NoHeader", []byte{0x00, 0x61, 0x73, 0x6d}, false},
+		{"InvalidVersion", []byte{0x00, 0x61, 0x73, 0x6d, 0x02, 0x00, 0x00, 0x00}, false},
+		{"Order", wasmModule(apiSec, funcSec, typeSec), false},
+		{"Start", wasmModule(apiSec, typeSec, funcSec, exportSec, []byte{0x08, 0x01, 0x00}, codeSec), false},
+		{"Unknown", wasmModule(apiSec, []byte{0x0d, 0x00}), false},
+		{"Truncated", wasmModule(apiSec, []byte{0x01, 0x05, 0x01}), false},
+		{"Oversize", wasmModule(wasmSection(0x00, make([]byte, ContentSizeLimit))), false},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			err := validateWASM(c.code)
+			if c.valid {
+				assert.NoError(t, err)
+			} else {
+				assert.True(t, scoreresult.IllegalFormatError.Equals(err), "err=%+v", err)
+			}
+		})
+	}
+}
@@ -0,0 +1,755 @@
+package eeproxy
+
+import (
+	"encoding/json"
+	"math"
+	"math/big"
+	"os"
+	"path/filepath"
+
+	"github.com/icon-project/goloop/common"
+	"github.com/icon-project/goloop/common/codec"
+	"github.com/icon-project/goloop/common/errors"
+	"github.com/icon-project/goloop/common/log"
+	"github.com/icon-project/goloop/common/wasm"
+	"github.com/icon-project/goloop/module"
+	"github.com/icon-project/goloop/service/scoreapi"
+	"github.com/icon-project/goloop/service/scoreresult"
+)
+
+const (
+	// WASMCodeFile is the name of the module file in the code path.
+	WASMCodeFile = "code.wasm"
+	// WASMAPISection is the name of the custom section for the API of the
+	// contract. It has the API in the format of icx_getScoreApi.
+	WASMAPISection = "score_api"
+	// WASMHostModule is the name of the module for host functions.
+	WASMHostModule = "env"
+
+	wasmFallbackExport = "fallback"
+	wasmInstallMethod  = "on_install"
+	wasmUpdateMethod   = "on_update"
+)
+
+var (
+	i32 = wasm.I32
+
+	// wasmHostTypes is the types of host functions which can be imported
+	// by contracts.
+	wasmHostTypes = map[string]wasm.FuncType{
+		"input":        {Params: []wasm.ValueType{i32, i32}, Results: []wasm.ValueType{i32}},
+		"set_result":   {Params: []wasm.ValueType{i32, i32}},
+		"revert":       {Params: []wasm.ValueType{i32, i32, i32}},
+		"get_value":    {Params: []wasm.ValueType{i32, i32, i32, i32}, Results: []wasm.ValueType{i32}},
+		"set_value":    {Params: []wasm.ValueType{i32, i32, i32, i32}},
+		"delete_value": {Params: []wasm.ValueType{i32, i32}},
+		"emit_event":   {Params: []wasm.ValueType{i32, i32}},
+		"call":         {Params: []wasm.ValueType{i32, i32, i32, i32}, Results: []wasm.ValueType{i32}},
+		"get_info":     {Params: []wasm.ValueType{i32, i32}, Results: []wasm.ValueType{i32}},
+		"get_message":  {Params: []wasm.ValueType{i32, i32}, Results: []wasm.ValueType{i32}},
+		"get_balance":  {Params: []wasm.ValueType{i32, i32, i32, i32}, Results: []wasm.ValueType{i32}},
+		"log":          {Params: []wasm.ValueType{i32, i32}},
+	}
+)
+
+type wasmContract struct {
+	module *wasm.Module
+	info   *scoreapi.Info
+}
+
+type wasmAPIField struct {
+	Name   string         `json:"name"`
+	Type   string         `json:"type"`
+	Fields []wasmAPIField `json:"fields"`
+}
+
+type wasmAPIParam struct {
+	Name    string          `json:"name"`
+	Type    string          `json:"type"`
+	Indexed common.HexBool  `json:"indexed"`
+	Default json.RawMessage `json:"default"`
+	Fields  []wasmAPIField  `json:"fields"`
+}
+
+type wasmAPIOutput struct {
+	Type string `json:"type"`
+}
+
+type wasmAPIMethod struct {
+	Type     string          `json:"type"`
+	Name     string          `json:"name"`
+	Inputs   []wasmAPIParam  `json:"inputs"`
+	Outputs  []wasmAPIOutput `json:"outputs"`
+	ReadOnly common.HexBool  `json:"readonly"`
+	Payable  common.HexBool  `json:"payable"`
+	Isolated common.HexBool  `json:"isolated"`
+}
+
+func wasmDataTypeOf(s string) (scoreapi.DataType, error) {
+	t := scoreapi.DataTypeOf(s)
+	if t == scoreapi.Unknown {
+		return t, errors.IllegalArgumentError.Errorf("UnknownType(%s)", s)
+	}
+	return t, nil
+}
+
+func wasmFieldsOf(jfs []wasmAPIField) ([]scoreapi.Field, error) {
+	var fields []scoreapi.Field
+	for _, jf := range jfs {
+		t, err := wasmDataTypeOf(jf.Type)
+		if err != nil {
+			return nil, err
+		}
+		sub, err := wasmFieldsOf(jf.Fields)
+		if err != nil {
+			return nil, err
+		}
+		fields = append(fields, scoreapi.Field{Name: jf.Name, Type: t, Fields: sub})
+	}
+	return fields, nil
+}
+
+func (jm *wasmAPIMethod) toMethod() (*scoreapi.Method, error) {
+	m := &scoreapi.Method{Name: jm.Name}
+	switch jm.Type {
+	case "function":
+		m.Type = scoreapi.Function
+		m.Flags = scoreapi.FlagExternal
+	case "fallback":
+		m.Type = scoreapi.Fallback
+	case "eventlog":
+		m.Type = scoreapi.Event
+	default:
+		return nil, errors.IllegalArgumentError.Errorf("UnknownMethodType(%s)", jm.Type)
+	}
+	if jm.ReadOnly.Value {
+		m.Flags |= scoreapi.FlagReadOnly
+	}
+	if jm.Payable.Value {
+		m.Flags |= scoreapi.FlagPayable
+	}
+	if jm.Isolated.Value {
+		m.Flags |= scoreapi.FlagIsolated
+	}
+	for i, jp := range jm.Inputs {
+		if len(jp.Default) > 0 {
+			return nil, errors.UnsupportedError.Errorf(
+				"DefaultNotSupported(method=%s,param=%s)", jm.Name, jp.Name)
+		}
+		t, err := wasmDataTypeOf(jp.Type)
+		if err != nil {
+			return nil, err
+		}
+		fields, err := wasmFieldsOf(jp.Fields)
+		if err != nil {
+			return nil, err
+		}
+		if m.Type == scoreapi.Event && jp.Indexed.Value {
+			if m.Indexed != i {
+				return nil, errors.IllegalArgumentError.Errorf(
+					"IndexedAfterData(event=%s)", jm.Name)
+			}
+			m.Indexed += 1
+		}
+		m.Inputs = append(m.Inputs, scoreapi.Parameter{
+			Name:   jp.Name,
+			Type:   t,
+			Fields: fields,
+		})
+	}
+	if m.Type != scoreapi.Event {
+		m.Indexed = len(m.Inputs)
+	}
+	if len(jm.Outputs) > 1 {
+		return nil, errors.UnsupportedError.Errorf("MultipleOutputs(method=%s)", jm.Name)
+	}
+	for _, jo := range jm.Outputs {
+		t, err := wasmDataTypeOf(jo.Type)
+		if err != nil {
+			return nil, err
+		}
+		switch t {
+		case scoreapi.Integer, scoreapi.String, scoreapi.Bytes, scoreapi.Bool, scoreapi.Address:
+		default:
+			return nil, errors.UnsupportedError.Errorf(
+				"UnsupportedOutput(method=%s,type=%s)", jm.Name, jo.Type)
+		}
+		m.Outputs = append(m.Outputs, t)
+	}
+	return m, nil
+}
+
+// exportOf returns the name of the exported function for the method.
+func wasmExportOf(method *scoreapi.Method) string {
+	if method.IsFallback() {
+		return wasmFallbackExport
+	}
+	return method.Name
+}
+
+// newWASMContract decodes the module and the API of the contract, and checks
+// whether the module has the functions for the API and imports only host
+// functions.
+func newWASMContract(code []byte) (*wasmContract, error) {
+	m, err := wasm.Decode(code)
+	if err != nil {
+		return nil, err
+	}
+	for _, im := range m.Imports() {
+		ft, ok := wasmHostTypes[im.Name]
+		if im.Module != WASMHostModule || !ok {
+			return nil, errors.NotFoundError.Errorf(
+				"UnknownImport(module=%s,name=%s)", im.Module, im.Name)
+		}
+		if !ft.Equal(&im.Type) {
+			return nil, errors.IllegalArgumentError.Errorf(
+				"InvalidImportType(name=%s)", im.Name)
+		}
+	}
+
+	api, ok := m.CustomSection(WASMAPISection)
+	if !ok {
+		return nil, errors.NotFoundError.New("NoAPISection")
+	}
+	var jms []wasmAPIMethod
+	if err := json.Unmarshal(api, &jms); err != nil {
+		return nil, errors.IllegalArgumentError.Wrap(err, "InvalidAPISection")
+	}
+	var methods []*scoreapi.Method
+	names := make(map[string]bool)
+	for i := range jms {
+		method, err := jms[i].toMethod()
+		if err != nil {
+			return nil, err
+		}
+		if !method.IsEvent() {
+			name := wasmExportOf(method)
+			if names[name] {
+				return nil, errors.IllegalArgumentError.Errorf("DuplicateMethod(%s)", name)
+			}
+			names[name] = true
+			ft, ok := m.ExportedFunction(name)
+			if !ok {
+				return nil, errors.NotFoundError.Errorf("NoFunctionForMethod(%s)", name)
+			}
+			if len(ft.Params) != 0 || len(ft.Results) != 0 {
+				return nil, errors.IllegalArgumentError.Errorf(
+					"InvalidFunctionType(%s)", name)
+			}
+		}
+		methods = append(methods, method)
+	}
+	// on_install and on_update can be omitted, then they do nothing.
+	for _, name := range []string{wasmInstallMethod, wasmUpdateMethod} {
+		if !names[name] {
+			methods = append(methods, &scoreapi.Method{
+				Type: scoreapi.Function,
+				Name: name,
+			})
+		}
+	}
+	return &wasmContract{
+		module: m,
+		info:   scoreapi.NewInfo(methods),
+	}, nil
+}
+
+func loadWASMContract(path string) (*wasmContract, error) {
+	code, err := os.ReadFile(filepath.Join(path, WASMCodeFile))
+	if err != nil {
+		return nil, errors.WithCode(err, errors.CriticalIOError)
+	}
+	return newWASMContract(code)
+}
+
+// ValidateWASMCode checks whether the module can be executed by the engine.
+func ValidateWASMCode(code []byte) error {
+	_, err := newWASMContract(code)
+	return err
+}
+
+// wasmRevert is the error for the revert by the contract.
+type wasmRevert struct {
+	code int32
+	msg  string
+}
+
+func (r *wasmRevert) Error() string {
+	return r.msg
+}
+
+// wasmStatusOf returns the status and the message of the error.
+func wasmStatusOf(err error) (errors.Code, string) {
+	if err == nil {
+		return errors.Success, ""
+	}
+	if rv, ok := err.(*wasmRevert); ok {
+		code := int64(scoreresult.RevertedError) + int64(rv.code)
+		if rv.code < 0 || code > int64(module.StatusLimit) {
+			code = int64(module.StatusLimit)
+		}
+		return errors.Code(code), rv.msg
+	}
+	switch {
+	case errors.Is(err, wasm.ErrOutOfStep):
+		return scoreresult.OutOfStepError, err.Error()
+	case errors.Is(err, wasm.ErrStackOverflow):
+		return scoreresult.StackOverflowError, err.Error()
+	}
+	code := errors.CodeOf(err)
+	if code > errors.Success && code < errors.CodeGeneral {
+		return code, err.Error()
+	}
+	switch code {
+	case errors.IllegalArgumentError, errors.NotFoundError, errors.UnsupportedError:
+		return scoreresult.IllegalFormatError, err.Error()
+	default:
+		return scoreresult.UnknownFailureError, err.Error()
+	}
+}
+
+// wasmCall is an execution of the contract for INVOKE.
+type wasmCall struct {
+	ex       *wasmExecutor
+	msg      *invokeMessage
+	method   *scoreapi.Method
+	info     map[string]interface{}
+	costs    map[string]int64
+	in       *wasm.Instance
+	result   *codec.TypedObj
+	stepUsed int64
+}
+
+func (c *wasmCall) readOnly() bool {
+	return c.msg.Flag&InvokeFlagReadOnly != 0
+}
+
+func toInt64(v interface{}) int64 {
+	switch obj := v.(type) {
+	case *common.HexInt:
+		return obj.Int64()
+	case *big.Int:
+		return obj.Int64()
+	default:
+		return 0
+	}
+}
+
+func stepLimitOf(v *big.Int) int64 {
+	if v.IsInt64() {
+		return v.Int64()
+	}
+	if v.Sign() < 0 {
+		return 0
+	}
+	return math.MaxInt64
+}
+
+func (c *wasmCall) run() error {
+	contract, err := c.ex.engine.contractOf(c.msg.Code)
+	if err != nil {
+		return err
+	}
+	c.method = contract.info.GetMethod(c.msg.Method)
+	if c.method == nil || c.method.IsEvent() {
+		return scoreresult.MethodNotFoundError.Errorf("MethodNotFound(%s)", c.msg.Method)
+	}
+	if v, err := common.DecodeAny(c.msg.Info); err == nil {
+		c.info, _ = v.(map[string]interface{})
+	}
+	c.costs = make(map[string]int64)
+	if costs, ok := c.info[wasmInfoStepCosts].(map[string]interface{}); ok {
+		for k, v := range costs {
+			c.costs[k] = toInt64(v)
+		}
+	}
+
+	name := wasmExportOf(c.method)
+	if _, ok := contract.module.ExportedFunction(name); !ok {
+		if name == wasmInstallMethod || name == wasmUpdateMethod {
+			return nil
+		}
+		return scoreresult.MethodNotFoundError.Errorf("MethodNotFound(%s)", name)
+	}
+	in, err := wasm.NewInstance(contract.module, c.imports(), stepLimitOf(&c.msg.Limit.Int))
+	if err != nil {
+		return scoreresult.IllegalFormatError.Wrap(err, "FailToInstantiate")
+	}
+	c.in = in
+	c.ex.push(in)
+	defer c.ex.pop()
+	_, err = in.Call(name)
+	c.stepUsed = in.StepUsed()
+	return err
+}
+
+const (
+	wasmInfoStepCosts = "StepCosts"
+
+	wasmStepGet        = "get"
+	wasmStepSet        = "set"
+	wasmStepReplace    = "replace"
+	wasmStepDelete     = "delete"
+	wasmStepEventLog   = "eventLog"
+	wasmStepAPICall    = "apiCall"
+	wasmStepSchema     = "schema"
+	wasmStepGetBase    = "getBase"
+	wasmStepSetBase    = "setBase"
+	wasmStepDeleteBase = "deleteBase"
+	wasmStepLogBase    = "logBase"
+	wasmStepLog        = "log"
+)
+
+// chargeFor charges steps for the storage access or the event with the
+// size. Base steps are charged from schema 1 like other engines.
+func (c *wasmCall) chargeFor(base, t string, size int) error {
+	steps := c.costs[t] * int64(size)
+	if c.costs[wasmStepSchema] >= 1 {
+		steps += c.costs[base]
+	}
+	return c.in.Charge(steps)
+}
+
+func (c *wasmCall) read(ptr, size uint64) ([]byte, error) {
+	return c.in.Read(uint32(ptr), uint32(size))
+}
+
+// write writes the data up to the capacity, and returns the size of the data.
+func (c *wasmCall) write(ptr, capacity uint64, data []byte) ([]uint64, error) {
+	if uint64(len(data)) > math.MaxInt32 {
+		return nil, errors.IllegalArgumentError.New("TooLargeData")
+	}
+	n := len(data)
+	if uint64(n) > uint64(uint32(capacity)) {
+		n = int(uint32(capacity))
+	}
+	if err := c.in.Write(uint32(ptr), data[:n]); err != nil {
+		return nil, err
+	}
+	return []uint64{uint64(len(data))}, nil
+}
+
+func (c *wasmCall) writeJSON(ptr, capacity uint64, v interface{}) ([]uint64, error) {
+	bs, err := json.Marshal(v)
+	if err != nil {
+		return nil, errors.InvalidStateError.Wrap(err, "FailToMarshal")
+	}
+	return c.write(ptr, capacity, bs)
+}
+
+func (c *wasmCall) checkWritable() error {
+	if c.readOnly() {
+		return scoreresult.AccessDeniedError.New("WriteInReadOnly")
+	}
+	return nil
+}
+
+func (c *wasmCall) input(args []uint64) ([]uint64, error) {
+	var params interface{} = []interface{}{}
+	if c.msg.Params != nil {
+		var err error
+		if params, err = common.DecodeAnyForJSON(c.msg.Params); err != nil {
+			return nil, scoreresult.InvalidParameterError.Wrap(err, "InvalidParams")
+		}
+	}
+	return c.writeJSON(args[0], args[1], params)
+}
+
+func (c *wasmCall) setResult(args []uint64) ([]uint64, error) {
+	bs, err := c.read(args[0], args[1])
+	if err != nil {
+		return nil, err
+	}
+	if len(c.method.Outputs) == 0 {
+		c.result = nil
+		return nil, nil
+	}
+	if c.result, err = c.method.Outputs[0].ConvertJSONToTypedObj(bs, nil, true); err != nil {
+		return nil, err
+	}
+	return nil, nil
+}
+
+func (c *wasmCall) revert(args []uint64) ([]uint64, error) {
+	bs, err := c.read(args[1], args[2])
+	if err != nil {
+		return nil, err
+	}
+	return nil, &wasmRevert{code: int32(args[0]), msg: string(bs)}
+}
+
+func (c *wasmCall) getValue(args []uint64) ([]uint64, error) {
+	key, err := c.read(args[0], args[1])
+	if err != nil {
+		return nil, err
+	}
+	var m getValueMessage
+	if err := c.ex.conn.SendAndReceive(msgGETVALUE, key, &m); err != nil {
+		return nil, err
+	}
+	if err := c.chargeFor(wasmStepGetBase, wasmStepGet, len(m.Value)); err != nil {
+		return nil, err
+	}
+	if !m.Success {
+		return []uint64{uint64(math.MaxUint32)}, nil
+	}
+	return c.write(args[2], args[3], m.Value)
+}
+
+func (c *wasmCall) setValue(args []uint64) ([]uint64, error) {
+	if err := c.checkWritable(); err != nil {
+		return nil, err
+	}
+	key, err := c.read(args[0], args[1])
+	if err != nil {
+		return nil, err
+	}
+	value, err := c.read(args[2], args[3])
+	if err != nil {
+		return nil, err
+	}
+	m := setValueMessage{Key: key, Flag: flagOLDVALUE, Value: value}
+	var old oldValueMessage
+	if err := c.ex.conn.SendAndReceive(msgSETVALUE, &m, &old); err != nil {
+		return nil, err
+	}
+	t := wasmStepSet
+	if old.HasOld {
+		t = wasmStepReplace
+	}
+	return nil, c.chargeFor(wasmStepSetBase, t, len(value))
+}
+
+func (c *wasmCall) deleteValue(args []uint64) ([]uint64, error) {
+	if err := c.checkWritable(); err != nil {
+		return nil, err
+	}
+	key, err := c.read(args[0], args[1])
+	if err != nil {
+		return nil, err
+	}
+	m := setValueMessage{Key: key, Flag: flagDELETE | flagOLDVALUE}
+	var old oldValueMessage
+	if err := c.ex.conn.SendAndReceive(msgSETVALUE, &m, &old); err != nil {
+		return nil, err
+	}
+	return nil, c.chargeFor(wasmStepDeleteBase, wasmStepDelete, old.OldSize)
+}
+
+type wasmEvent struct {
+	Indexed []common.HexBytes `json:"indexed"`
+	Data    []common.HexBytes `json:"data"`
+}
+
+func toBytesList(hbs []common.HexBytes) [][]byte {
+	bss := make([][]byte, len(hbs))
+	for i, hb := range hbs {
+		bss[i] = hb
+	}
+	return bss
+}
+
+func (c *wasmCall) emitEvent(args []uint64) ([]uint64, error) {
+	if err := c.checkWritable(); err != nil {
+		return nil, err
+	}
+	bs, err := c.read(args[0], args[1])
+	if err != nil {
+		return nil, err
+	}
+	var e wasmEvent
+	if err := json.Unmarshal(bs, &e); err != nil {
+		return nil, scoreresult.InvalidParameterError.Wrap(err, "InvalidEvent")
+	}
+	if len(e.Indexed) == 0 {
+		return nil, scoreresult.InvalidParameterError.New("NoEventSignature")
+	}
+	m := eventMessage{Indexed: toBytesList(e.Indexed), Data: toBytesList(e.Data)}
+	size := 0
+	for _, bs := range m.Indexed {
+		size += len(bs)
+	}
+	for _, bs := range m.Data {
+		size += len(bs)
+	}
+	t := wasmStepEventLog
+	if c.costs[wasmStepSchema] >= 1 {
+		t = wasmStepLog
+	}
+	if err := c.chargeFor(wasmStepLogBase, t, size); err != nil {
+		return nil, err
+	}
+	return nil, c.ex.conn.Send(msgEVENT, &m)
+}
+
+type wasmTypedValue struct {
+	Type  string          `json:"type"`
+	Value json.RawMessage `json:"value"`
+}
+
+type wasmCallRequest struct {
+	To     common.Address   `json:"to"`
+	Value  *common.HexInt   `json:"value"`
+	Method string           `json:"method"`
+	Params []wasmTypedValue `json:"params"`
+}
+
+// wasmCallFailure is returned by call for the failure of the call. The
+// status of the failure is returned as negative value.
+func wasmCallFailure(status errors.Code) []uint64 {
+	return []uint64{uint64(uint32(-int32(status)))}
+}
+
+func (c *wasmCall) call(args []uint64) ([]uint64, error) {
+	bs, err := c.read(args[0], args[1])
+	if err != nil {
+		return nil, err
+	}
+	var req wasmCallRequest
+	if err := json.Unmarshal(bs, &req); err != nil {
+		return nil, scoreresult.InvalidParameterError.Wrap(err, "InvalidCallRequest")
+	}
+	params := make([]interface{}, len(req.Params))
+	for i, p := range req.Params {
+		t := scoreapi.DataTypeOf(p.Type)
+		switch t.Tag() {
+		case scoreapi.TInteger, scoreapi.TString, scoreapi.TBytes, scoreapi.TBool, scoreapi.TAddress:
+		default:
+			return nil, scoreresult.InvalidParameterError.Errorf(
+				"UnsupportedParameterType(%s)", p.Type)
+		}
+		if params[i], err = t.ConvertJSONToTypedObj(p.Value, nil, true); err != nil {
+			return nil, err
+		}
+	}
+	data, err := common.EncodeAny(map[string]interface{}{
+		"method": req.Method,
+		"params": params,
+	})
+	if err != nil {
+		return nil, scoreresult.InvalidParameterError.Wrap(err, "InvalidCallRequest")
+	}
+	m := callMessage{
+		To:       req.To,
+		DataType: "call",
+		Data:     data,
+	}
+	if req.Value != nil {
+		if req.Value.Sign() < 0 {
+			return nil, scoreresult.InvalidParameterError.New("NegativeValue")
+		}
+		if req.Value.Sign() > 0 {
+			if err := c.checkWritable(); err != nil {
+				return nil, err
+			}
+		}
+		m.Value.Set(&req.Value.Int)
+	}
+	m.Limit.SetInt64(stepLimitOf(&c.msg.Limit.Int) - c.in.StepUsed())
+	if err := c.ex.conn.Send(msgCALL, &m); err != nil {
+		return nil, err
+	}
+	r, err := c.ex.waitResult()
+	if err != nil {
+		return nil, err
+	}
+	if err := c.in.Charge(stepLimitOf(&r.StepUsed.Int)); err != nil {
+		return nil, err
+	}
+	status, _ := StatusToCodeAndFlag(r.Status)
+	if status != errors.Success {
+		return wasmCallFailure(status), nil
+	}
+	result, err := common.DecodeAnyForJSON(r.Result)
+	if err != nil {
+		return nil, scoreresult.UnknownFailureError.Wrap(err, "InvalidCallResult")
+	}
+	return c.writeJSON(args[2], args[3], result)
+}
+
+func (c *wasmCall) getInfo(args []uint64) ([]uint64, error) {
+	info := make(map[string]interface{})
+	for k, v := range c.info {
+		if k == wasmInfoStepCosts {
+			continue
+		}
+		var err error
+		if info[k], err = common.AnyForJSON(v); err != nil {
+			return nil, errors.InvalidStateError.Wrap(err, "InvalidInfo")
+		}
+	}
+	return c.writeJSON(args[0], args[1], info)
+}
+
+type wasmMessage struct {
+	From  *common.Address `json:"from"`
+	To    *common.Address `json:"to"`
+	Value *common.HexInt  `json:"value"`
+}
+
+func (c *wasmCall) getMessage(args []uint64) ([]uint64, error) {
+	return c.writeJSON(args[0], args[1], &wasmMessage{
+		From:  c.msg.From,
+		To:    &c.msg.To,
+		Value: &c.msg.Value,
+	})
+}
+
+func (c *wasmCall) getBalance(args []uint64) ([]uint64, error) {
+	bs, err := c.read(args[0], args[1])
+	if err != nil {
+		return nil, err
+	}
+	var addr common.Address
+	if err := addr.SetString(string(bs)); err != nil {
+		return nil, scoreresult.InvalidParameterError.Wrap(err, "InvalidAddress")
+	}
+	if err := c.in.Charge(c.costs[wasmStepAPICall]); err != nil {
+		return nil, err
+	}
+	var balance common.HexInt
+	if err := c.ex.conn.SendAndReceive(msgGETBALANCE, &addr, &balance); err != nil {
+		return nil, err
+	}
+	return c.write(args[2], args[3], []byte(balance.String()))
+}
+
+func (c *wasmCall) log(args []uint64) ([]uint64, error) {
+	bs, err := c.read(args[0], args[1])
+	if err != nil {
+		return nil, err
+	}
+	m := logMessage{Level: log.DebugLevel, Message: string(bs)}
+	if c.msg.Flag&InvokeFlagTrace != 0 {
+		m.Flag |= LogFlagTrace
+	}
+	return nil, c.ex.conn.Send(msgLOG, &m)
+}
+
+func (c *wasmCall) imports() wasm.Imports {
+	funcs := map[string]func(args []uint64) ([]uint64, error){
+		"input":        c.input,
+		"set_result":   c.setResult,
+		"revert":       c.revert,
+		"get_value":    c.getValue,
+		"set_value":    c.setValue,
+		"delete_value": c.deleteValue,
+		"emit_event":   c.emitEvent,
+		"call":         c.call,
+		"get_info":     c.getInfo,
+		"get_message":  c.getMessage,
+		"get_balance":  c.getBalance,
+		"log":          c.log,
+	}
+	hosts := make(map[string]*wasm.HostFunction, len(funcs))
+	for name, f := range funcs {
+		f := f
+		hosts[name] = &wasm.HostFunction{
+			Type: wasmHostTypes[name],
+			Call: func(in *wasm.Instance, args []uint64) ([]uint64, error) {
+				return f(args)
+			},
+		}
+	}
+	return wasm.Imports{WASMHostModule: hosts}
+}
@@ -0,0 +1,356 @@
+package eeproxy
+
+import (
+	"sync"
+
+	"github.com/icon-project/goloop/common"
+	"github.com/icon-project/goloop/common/cache"
+	"github.com/icon-project/goloop/common/codec"
+	"github.com/icon-project/goloop/common/errors"
+	"github.com/icon-project/goloop/common/ipc"
+	"github.com/icon-project/goloop/common/log"
+	"github.com/icon-project/goloop/common/wasm"
+)
+
+const (
+	WASMEE = "wasmee"
+
+	wasmEEVersion         = 1
+	wasmContractCacheSize = 64
+)
+
+type wasmInstance struct {
+	uid    string
+	status InstanceStatus
+	ex     *wasmExecutor
+}
+
+// wasmExecutionEngine runs executors in the node process. Each executor
+// connects to the manager like executors of other engines, and runs WASM
+// contracts with the interpreter of common/wasm. Contracts can't access
+// anything but the host functions, which get and set states through the
+// proxy.
+type wasmExecutionEngine struct {
+	lock      sync.Mutex
+	target    int
+	instances map[string]*wasmInstance
+	net, addr string
+	contracts *cache.LRUCache
+	logger    log.Logger
+}
+
+func (e *wasmExecutionEngine) Type() string {
+	return "wasm"
+}
+
+func (e *wasmExecutionEngine) Kill(uid string) (bool, error) {
+	e.lock.Lock()
+	defer e.lock.Unlock()
+
+	if is, ok := e.instances[uid]; ok {
+		return true, is.ex.kill()
+	} else {
+		return false, nil
+	}
+}
+
+func (e *wasmExecutionEngine) Init(net, addr string) error {
+	e.net = net
+	e.addr = addr
+	return nil
+}
+
+func (e *wasmExecutionEngine) SetInstances(n int) error {
+	e.lock.Lock()
+	defer e.lock.Unlock()
+
+	if n < 0 {
+		return errors.ErrIllegalArgument
+	}
+
+	e.target = n
+	for e.target > len(e.instances) {
+		if err := e.startNew(); err != nil {
+			e.logger.Errorf("Fail to start execution engine err=%+v", err)
+			return err
+		}
+	}
+	return nil
+}
+
+func (e *wasmExecutionEngine) OnAttach(uid string) bool {
+	e.lock.Lock()
+	defer e.lock.Unlock()
+
+	if is, ok := e.instances[uid]; ok {
+		is.status = instanceOnline
+		return true
+	}
+	return false
+}
+
+func (e *wasmExecutionEngine) OnEnd(uid string) bool {
+	return true
+}
+
+func (e *wasmExecutionEngine) run(is *wasmInstance) {
+	for {
+		err := is.ex.serve()
+		e.logger.Tracef("Serve result uid=%s err=%+v\n", is.uid, err)
+
+		e.lock.Lock()
+		if is.status != instanceOnline {
+			e.logger.Warnf("It's not correctly started status=%s err=%+v",
+				is.status, err)
+			e.term(is)
+			e.lock.Unlock()
+			return
+		}
+		if len(e.instances) > e.target {
+			e.logger.Tracef("End the instance uid=%s\n", is.uid)
+			e.term(is)
+			e.lock.Unlock()
+			return
+		}
+		e.logger.Warnf("Instance uid=%s is killed err=%+v",
+			is.uid, err)
+		e.term(is)
+
+		e.init(is)
+		if err := e.start(is); err != nil {
+			e.logger.Errorf("Fail to start instance err=%+v", err)
+			e.term(is)
+			e.lock.Unlock()
+			return
+		}
+		e.lock.Unlock()
+	}
+}
+
+func (e *wasmExecutionEngine) init(i *wasmInstance) {
+	i.uid = newUID()
+	e.instances[i.uid] = i
+	i.ex = nil
+	i.status = instanceStopped
+}
+
+func (e *wasmExecutionEngine) start(i *wasmInstance) error {
+	e.logger.Infof("start instance uid=%s", i.uid)
+	conn, err := ipc.Dial(e.net, e.addr)
+	if err != nil {
+		return err
+	}
+	i.ex = newWASMExecutor(e, i.uid, conn)
+	i.status = instanceStarted
+	return nil
+}
+
+func (e *wasmExecutionEngine) term(i *wasmInstance) {
+	if i.ex != nil {
+		_ = i.ex.conn.Close()
+	}
+	delete(e.instances, i.uid)
+}
+
+func (e *wasmExecutionEngine) startNew() error {
+	i := new(wasmInstance)
+	e.init(i)
+	if err := e.start(i); err != nil {
+		e.term(i)
+		return err
+	}
+	go e.run(i)
+	return nil
+}
+
+func (e *wasmExecutionEngine) OnConnect(conn ipc.Connection, version uint16) error {
+	return common.ErrUnsupported
+}
+
+func (e *wasmExecutionEngine) OnClose(conn ipc.Connection) bool {
+	return false
+}
+
+// contractOf returns the contract stored in the code path.
+func (e *wasmExecutionEngine) contractOf(path string) (*wasmContract, error) {
+	c, err := e.contracts.Get([]byte(path))
+	if err != nil {
+		return nil, err
+	}
+	return c.(*wasmContract), nil
+}
+
+// wasmExecutor handles messages from the proxy through the connection.
+// Contracts called by the running contract are handled recursively while
+// it waits for the result of the call.
+type wasmExecutor struct {
+	engine *wasmExecutionEngine
+	uid    string
+	conn   ipc.Connection
+	log    log.Logger
+
+	lock    sync.Mutex
+	running []*wasm.Instance
+	killed  bool
+
+	waiting int
+	result  *resultMessage
+	closed  bool
+}
+
+func (ex *wasmExecutor) serve() error {
+	err := ex.conn.Send(msgVERSION, &versionMessage{
+		Version: wasmEEVersion,
+		UID:     ex.uid,
+		Type:    ex.engine.Type(),
+	})
+	if err != nil {
+		return err
+	}
+	for !ex.closed {
+		if err := ex.conn.HandleMessage(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// kill stops running contracts and closes the connection.
+func (ex *wasmExecutor) kill() error {
+	ex.lock.Lock()
+	ex.killed = true
+	for _, in := range ex.running {
+		in.Abort()
+	}
+	ex.lock.Unlock()
+	return ex.conn.Close()
+}
+
+func (ex *wasmExecutor) push(in *wasm.Instance) {
+	ex.lock.Lock()
+	defer ex.lock.Unlock()
+	if ex.killed {
+		in.Abort()
+	}
+	ex.running = append(ex.running, in)
+}
+
+func (ex *wasmExecutor) pop() {
+	ex.lock.Lock()
+	defer ex.lock.Unlock()
+	ex.running = ex.running[:len(ex.running)-1]
+}
+
+// waitResult handles messages until it gets the result of the call.
+func (ex *wasmExecutor) waitResult() (*resultMessage, error) {
+	ex.waiting += 1
+	defer func() {
+		ex.waiting -= 1
+	}()
+	for ex.result == nil {
+		if ex.closed {
+			return nil, errors.InvalidStateError.New("ConnectionClosed")
+		}
+		if err := ex.conn.HandleMessage(); err != nil {
+			return nil, err
+		}
+	}
+	r := ex.result
+	ex.result = nil
+	return r, nil
+}
+
+func (ex *wasmExecutor) HandleMessage(c ipc.Connection, msg uint, data []byte) error {
+	switch msg {
+	case msgINVOKE:
+		var m invokeMessage
+		if _, err := codec.MP.UnmarshalFromBytes(data, &m); err != nil {
+			return err
+		}
+		return ex.invoke(&m)
+
+	case msgGETAPI:
+		var path string
+		if _, err := codec.MP.UnmarshalFromBytes(data, &path); err != nil {
+			return err
+		}
+		var m getAPIMessage
+		if c, err := ex.engine.contractOf(path); err != nil {
+			ex.log.Warnf("Fail to load contract path=%s err=%+v", path, err)
+			m.Status, _ = wasmStatusOf(err)
+		} else {
+			m.Status = errors.Success
+			m.Info = c.info
+		}
+		return ex.conn.Send(msgGETAPI, &m)
+
+	case msgRESULT:
+		var m resultMessage
+		if _, err := codec.MP.UnmarshalFromBytes(data, &m); err != nil {
+			return err
+		}
+		if ex.waiting == 0 || ex.result != nil {
+			return errors.InvalidStateError.New("UnexpectedResult")
+		}
+		ex.result = &m
+		return nil
+
+	case msgCLOSE:
+		ex.closed = true
+		return nil
+
+	default:
+		return errors.IllegalArgumentError.Errorf("UnknownMessage(msg=%d)", msg)
+	}
+}
+
+func (ex *wasmExecutor) invoke(m *invokeMessage) error {
+	call := &wasmCall{ex: ex, msg: m}
+	err := call.run()
+	status, msg := wasmStatusOf(err)
+	if err != nil {
+		ex.log.Debugf("Execution fails method=%s status=%d err=%+v",
+			m.Method, status, err)
+	}
+
+	var r resultMessage
+	r.Status = status
+	r.StepUsed.SetInt64(call.stepUsed)
+	if status == errors.Success {
+		r.Result = call.result
+		if r.Result == nil {
+			r.Result = codec.Nil
+		}
+	} else {
+		r.Result = common.MustEncodeAny(msg)
+	}
+	r.EID = m.EID
+	if m.State != nil {
+		r.PrevEID = m.State.PrevEID
+	}
+	return ex.conn.Send(msgRESULT, &r)
+}
+
+func newWASMExecutor(e *wasmExecutionEngine, uid string, conn ipc.Connection) *wasmExecutor {
+	ex := &wasmExecutor{
+		engine: e,
+		uid:    uid,
+		conn:   conn,
+		log:    e.logger.WithFields(log.Fields{log.FieldKeyEID: uid}),
+	}
+	conn.SetHandler(msgINVOKE, ex)
+	conn.SetHandler(msgGETAPI, ex)
+	conn.SetHandler(msgRESULT, ex)
+	conn.SetHandler(msgCLOSE, ex)
+	return ex
+}
+
+func NewWASMEE(logger log.Logger) (Engine, error) {
+	var e wasmExecutionEngine
+	e.instances = make(map[string]*wasmInstance)
+	e.contracts = cache.NewLRUCache(wasmContractCacheSize, func(key []byte) (interface{}, error) {
+		return loadWASMContract(string(key))
+	})
+	e.logger = logger.WithFields(log.Fields{log.FieldKeyModule: WASMEE})
+	return &e, nil
+}
@@ -15,6 +15,9 @@ const (
 	NotContractAddressError
 	InvalidPatchDataError
 	CommittedTransactionError
+	SenderQuotaExceededError
+	TransactionRateLimitedError
+	InvalidStateOverrideError
 )
 
 var (
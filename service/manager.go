@@ -96,8 +96,13 @@ func NewManager(chain module.Chain, nm module.NetworkManager,
 	dsm := newDSRManager(logger)
 	pTxPool := NewTransactionPool(module.TransactionGroupPatch, chain.PatchTxPoolSize(), tim, pMetric, logger)
 	nTxPool := NewTransactionPool(module.TransactionGroupNormal, chain.NormalTxPoolSize(), tim, nMetric, logger)
+	if err := nTxPool.SetPolicy(chain.TxPoolPolicy()); err != nil {
+		return nil, err
+	}
+	nTxPool.SetSenderQuota(chain.SenderTxCount(), chain.SenderTxBytes())
 	tm := NewTransactionManager(chain.NID(), tsc, pTxPool, nTxPool, tim, logger)
 	syncm := ssync.NewSyncManager(chain.Database(), chain.NetworkManager(), plt, logger)
+	syncm.SetFlatSync(chain.FlatStateSync())
 
 	mgr := &manager{
 		patchMetric:  pMetric,
@@ -121,6 +126,7 @@ func NewManager(chain module.Chain, nm module.NetworkManager,
 	}
 	if nm != nil {
 		mgr.txReactor = NewTransactionReactor(nm, tm)
+		mgr.txReactor.SetPeerRate(chain.PeerTxRate())
 	}
 	return mgr, nil
 }
@@ -490,12 +496,86 @@ func (m *manager) SendTransaction(result []byte, height int64, txi interface{})
 func (m *manager) Call(resultHash []byte,
 	vl module.ValidatorList, js []byte, bi module.BlockInfo,
 ) (interface{}, error) {
-	type callJSON struct {
-		To       common.Address  `json:"to"`
-		DataType *string         `json:"dataType"`
-		Data     json.RawMessage `json:"data"`
+	jso, err := parseCallJSON(js)
+	if err != nil {
+		return nil, err
+	}
+	wss, err := m.trc.GetWorldSnapshot(resultHash, vl.Hash())
+	if err != nil {
+		return nil, err
+	}
+	return m.query(wss, jso, bi)
+}
+
+// CallInDatabase is same as Call except that it reads the world state
+// from the database instead of the chain database. It's used to run
+// queries against a snapshot of the chain database.
+func (m *manager) CallInDatabase(dbase db.Database, resultHash []byte,
+	vl module.ValidatorList, js []byte, bi module.BlockInfo,
+) (interface{}, error) {
+	jso, err := parseCallJSON(js)
+	if err != nil {
+		return nil, err
+	}
+	wss, err := newWorldSnapshot(dbase, m.plt, resultHash, vl)
+	if err != nil {
+		return nil, err
+	}
+	return m.query(wss, jso, bi)
+}
+
+// CallWithOverrides is same as Call except that it applies the state
+// overrides to the world state before running the query. Overrides are
+// applied to the virtual world state, so they are not stored.
+func (m *manager) CallWithOverrides(resultHash []byte,
+	vl module.ValidatorList, js []byte, bi module.BlockInfo, overrides []byte,
+) (interface{}, error) {
+	jso, err := parseCallJSON(js)
+	if err != nil {
+		return nil, err
+	}
+	wss, err := m.trc.GetWorldSnapshot(resultHash, vl.Hash())
+	if err != nil {
+		return nil, err
+	}
+	cm := contract.NewTemporalContractManager(m.cm)
+	defer cm.Close()
+	wss, err = m.overrideWorldSnapshot(cm, wss, overrides, bi)
+	if err != nil {
+		return nil, err
+	}
+	return m.queryWith(cm, wss, jso, bi)
+}
+
+// overrideWorldSnapshot returns the snapshot of the virtual world state
+// with overrides applied. Codes in overrides are stored by the contract
+// manager, and it should be used for the following executions.
+func (m *manager) overrideWorldSnapshot(cm *contract.TemporalContractManager,
+	wss state.WorldSnapshot, overrides []byte, bi module.BlockInfo,
+) (state.WorldSnapshot, error) {
+	so, err := parseStateOverrides(overrides)
+	if err != nil {
+		return nil, err
 	}
+	ws, err := state.WorldStateFromSnapshot(wss)
+	if err != nil {
+		return nil, err
+	}
+	wc := state.NewWorldContext(ws, bi, nil, m.plt)
+	ctx := contract.NewContext(wc, cm, m.eem, m.chain, m.log, nil, eeproxy.ForQuery)
+	if err := so.apply(ctx); err != nil {
+		return nil, err
+	}
+	return ws.GetSnapshot(), nil
+}
 
+type callJSON struct {
+	To       common.Address  `json:"to"`
+	DataType *string         `json:"dataType"`
+	Data     json.RawMessage `json:"data"`
+}
+
+func parseCallJSON(js []byte) (*callJSON, error) {
 	var jso callJSON
 	if json.Unmarshal(js, &jso) != nil {
 		return nil, InvalidQueryError.Errorf("FailToParse(%s)", string(js))
@@ -503,20 +583,22 @@ func (m *manager) Call(resultHash []byte,
 	if jso.DataType == nil || *jso.DataType != contract.DataTypeCall {
 		return nil, InvalidQueryError.New("InvalidDataType")
 	}
+	return &jso, nil
+}
 
-	var wc state.WorldContext
-	if wss, err := m.trc.GetWorldSnapshot(resultHash, vl.Hash()); err == nil {
-		ws := state.NewReadOnlyWorldState(wss)
-		wc = state.NewWorldContext(ws, bi, nil, m.plt)
-	} else {
-		return nil, err
-	}
+func (m *manager) query(wss state.WorldSnapshot, jso *callJSON, bi module.BlockInfo) (interface{}, error) {
+	return m.queryWith(m.cm, wss, jso, bi)
+}
+
+func (m *manager) queryWith(cm contract.ContractManager, wss state.WorldSnapshot, jso *callJSON, bi module.BlockInfo) (interface{}, error) {
+	ws := state.NewReadOnlyWorldState(wss)
+	wc := state.NewWorldContext(ws, bi, nil, m.plt)
 
-	qh, err := NewQueryHandler(m.cm, &jso.To, jso.Data)
+	qh, err := NewQueryHandler(cm, &jso.To, jso.Data)
 	if err != nil {
 		return nil, err
 	}
-	return qh.Query(contract.NewContext(wc, m.cm, m.eem, m.chain, m.log, nil, eeproxy.ForQuery))
+	return qh.Query(contract.NewContext(wc, cm, m.eem, m.chain, m.log, nil, eeproxy.ForQuery))
 }
 
 func (m *manager) ValidatorListFromHash(hash []byte) module.ValidatorList {
@@ -712,6 +794,23 @@ func (m *manager) GetRoundLimit(result []byte, vl int) int64 {
 	return limit
 }
 
+// validatorWeigher is implemented by extension snapshots of platforms
+// having stakes of validators.
+type validatorWeigher interface {
+	ValidatorWeights(vl module.ValidatorList) ([]*big.Int, error)
+}
+
+func (m *manager) GetValidatorWeights(result []byte, vl module.ValidatorList) ([]*big.Int, error) {
+	wss, err := m.trc.GetWorldSnapshot(result, nil)
+	if err != nil {
+		return nil, err
+	}
+	if vw, ok := wss.GetExtensionSnapshot().(validatorWeigher); ok {
+		return vw.ValidatorWeights(vl)
+	}
+	return nil, nil
+}
+
 func (m *manager) GetMinimizeBlockGen(result []byte) bool {
 	as, err := m.getSystemByteStoreState(result)
 	if err != nil {
@@ -857,6 +956,22 @@ func (m *manager) ExportResult(result []byte, vh []byte, d db.Database) error {
 	return e.Run()
 }
 
+// ResolveState requests entries of the world state of the result to the
+// builder. Validators and BTP data are not requested, because they are
+// also referred by blocks.
+func (m *manager) ResolveState(result []byte, bd merkle.Builder) error {
+	r, err := newTransitionResultFromBytes(result)
+	if err != nil {
+		return err
+	}
+	if len(r.StateHash) == 0 {
+		return nil
+	}
+	ess := m.plt.NewExtensionWithBuilder(bd, r.ExtensionData)
+	_, err = state.NewWorldSnapshotWithBuilder(bd, r.StateHash, nil, ess, nil)
+	return err
+}
+
 func (m *manager) ImportResult(result []byte, vh []byte, src db.Database) error {
 	r, err := newTransitionResultFromBytes(result)
 	if err != nil {
@@ -871,6 +986,13 @@ func (m *manager) ImportResult(result []byte, vh []byte, src db.Database) error
 }
 
 func (m *manager) ExecuteTransaction(result []byte, vh []byte, js []byte, bi module.BlockInfo) (module.Receipt, error) {
+	return m.ExecuteTransactionWithOverrides(result, vh, js, bi, nil)
+}
+
+// ExecuteTransactionWithOverrides is same as ExecuteTransaction except that
+// it applies the state overrides before executing the transaction if
+// overrides is not empty.
+func (m *manager) ExecuteTransactionWithOverrides(result []byte, vh []byte, js []byte, bi module.BlockInfo, overrides []byte) (module.Receipt, error) {
 	tx, err := transaction.NewTransactionFromJSON(js)
 	if err != nil {
 		return nil, err
@@ -879,7 +1001,9 @@ func (m *manager) ExecuteTransaction(result []byte, vh []byte, js []byte, bi mod
 		return nil, scoreresult.InvalidParameterError.Wrap(err, "InvalidTransaction")
 	}
 
-	txh, err := tx.GetHandler(m.cm)
+	cm := contract.NewTemporalContractManager(m.cm)
+	defer cm.Close()
+	txh, err := tx.GetHandler(cm)
 	if err != nil {
 		return nil, err
 	}
@@ -887,6 +1011,9 @@ func (m *manager) ExecuteTransaction(result []byte, vh []byte, js []byte, bi mod
 
 	var wc state.WorldContext
 	wss, err := m.trc.GetWorldSnapshot(result, vh)
+	if err == nil && len(overrides) > 0 {
+		wss, err = m.overrideWorldSnapshot(cm, wss, overrides, bi)
+	}
 	if err == nil {
 		ws, err := state.WorldStateFromSnapshot(wss)
 		if err != nil {
@@ -896,7 +1023,7 @@ func (m *manager) ExecuteTransaction(result []byte, vh []byte, js []byte, bi mod
 	} else {
 		return nil, err
 	}
-	ctx := contract.NewContext(wc, m.cm, m.eem, m.chain, m.log, nil, eeproxy.ForQuery)
+	ctx := contract.NewContext(wc, cm, m.eem, m.chain, m.log, nil, eeproxy.ForQuery)
 	ctx.SetTransactionInfo(&state.TransactionInfo{
 		Group:     module.TransactionGroupNormal,
 		Index:     0,
@@ -910,6 +1037,103 @@ func (m *manager) ExecuteTransaction(result []byte, vh []byte, js []byte, bi mod
 	return txh.Execute(ctx, wss, true)
 }
 
+// SimulateTransactions executes the transactions in order sharing the state
+// changes made by the previous ones, and returns receipts of them in JSON
+// with txIndex and txHash. It applies the state overrides first if overrides
+// is not empty. Transactions without stepLimit are executed in the same way
+// as ExecuteTransaction. Nothing is stored, and ti may be nil.
+func (m *manager) SimulateTransactions(result []byte, vh []byte, txs [][]byte,
+	bi module.BlockInfo, overrides []byte, ti *module.TraceInfo,
+) ([]interface{}, error) {
+	txos := make([]transaction.Transaction, len(txs))
+	for i, js := range txs {
+		tx, err := transaction.NewTransactionFromJSON(js)
+		if err != nil {
+			return nil, InvalidTransactionError.Wrapf(err, "InvalidTransaction(idx=%d)", i)
+		}
+		if err := tx.Verify(); err != nil && !transaction.InvalidSignatureError.Equals(err) {
+			return nil, InvalidTransactionError.Wrapf(err, "InvalidTransaction(idx=%d)", i)
+		}
+		txos[i] = tx
+	}
+
+	wss, err := m.trc.GetWorldSnapshot(result, vh)
+	if err != nil {
+		return nil, err
+	}
+	cm := contract.NewTemporalContractManager(m.cm)
+	defer cm.Close()
+	if len(overrides) > 0 {
+		if wss, err = m.overrideWorldSnapshot(cm, wss, overrides, bi); err != nil {
+			return nil, err
+		}
+	}
+	ws, err := state.WorldStateFromSnapshot(wss)
+	if err != nil {
+		return nil, err
+	}
+	wc := state.NewWorldContext(ws, bi, nil, m.plt)
+	ctx := contract.NewContext(wc, cm, m.eem, m.chain, m.log, ti, eeproxy.ForQuery)
+
+	cumulativeSteps := new(big.Int)
+	rcts := make([]interface{}, len(txos))
+	for i, tx := range txos {
+		ctx.SetTransactionInfo(&state.TransactionInfo{
+			Group:     module.TransactionGroupNormal,
+			Index:     int32(i),
+			Hash:      tx.ID(),
+			From:      tx.From(),
+			Timestamp: tx.Timestamp(),
+			Nonce:     tx.Nonce(),
+		})
+		ctx.UpdateSystemInfo()
+		wcs := ctx.GetSnapshot()
+		traceLogger := ctx.GetTraceLogger(module.EPhaseTransaction)
+		traceLogger.OnTransactionStart(i, tx.ID())
+
+		txh, err := tx.GetHandler(cm)
+		if err != nil {
+			return nil, err
+		}
+		estimate := true
+		if limit := transaction.StepLimitOf(tx); limit != nil && limit.Sign() > 0 {
+			estimate = false
+		}
+		rct, err := txh.Execute(ctx, wcs, estimate)
+		txh.Dispose()
+		if err == nil {
+			err = m.plt.OnTransactionEnd(ctx, m.log, rct)
+		}
+		if err != nil {
+			return nil, err
+		}
+		cumulativeSteps.Add(cumulativeSteps, rct.StepUsed())
+		rct.SetCumulativeStepUsed(new(big.Int).Set(cumulativeSteps))
+		traceLogger.OnTransactionEnd(i, tx.ID(), tx.From(), ctx.Treasury(), ctx.Revision(), rct)
+		if rcts[i], err = simulatedReceiptToJSON(i, tx.ID(), rct); err != nil {
+			return nil, err
+		}
+	}
+	return rcts, nil
+}
+
+func simulatedReceiptToJSON(idx int, id []byte, rct txresult.Receipt) (interface{}, error) {
+	res, err := rct.ToJSON(module.JSONVersion3)
+	if err != nil {
+		return nil, err
+	}
+	jso := res.(map[string]interface{})
+	jso["txIndex"] = fmt.Sprintf("%#x", idx)
+	jso["txHash"] = fmt.Sprintf("%#x", id)
+	if reason := rct.Reason(); reason != nil && rct.Status() != module.StatusSuccess {
+		jso["failure"] = map[string]interface{}{
+			"code":    fmt.Sprintf("%#x", int(rct.Status())),
+			"message": reason.Error(),
+		}
+	}
+	return jso, nil
+}
+
 func (m *manager) AddSyncRequest(id db.BucketID, key []byte) error {
 	return m.syncer.AddRequest(id, key)
 }
@@ -29,6 +29,9 @@ const (
 	Revision7
 	Revision8
 	Revision9
+	Revision10
+	Revision11
+	Revision12
 	RevisionReserved
 )
 
@@ -54,6 +57,9 @@ var toggleFlagsOnRevision = []struct {
 	{Revision7, module.UseChainID | module.UseMPTOnEvents},
 	{Revision8, module.UseCompactAPIInfo},
 	{Revision9, module.MultipleFeePayers | module.FixJCLSteps | module.ReportConfigureEvents},
+	{Revision10, module.RandomProposer},
+	{Revision11, module.WASMContracts},
+	{Revision12, module.BLSCommitVotes},
 }
 
 func init() {
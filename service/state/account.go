@@ -493,6 +493,19 @@ func (s *accountSnapshotImpl) Store() trie.Immutable {
 	return store
 }
 
+// StorageHashOf returns the root hash of the storage of the account
+// encoded in the bytes. It returns nil if the account has no storage.
+func StorageHashOf(account []byte) ([]byte, error) {
+	ass := new(accountSnapshotImpl)
+	if err := ass.Reset(db.NewNullDB(), account); err != nil {
+		return nil, err
+	}
+	if store := ass.Store(); store != nil {
+		return store.Hash(), nil
+	}
+	return nil, nil
+}
+
 func newAccountSnapshot(dbase db.Database) *accountSnapshotImpl {
 	return &accountSnapshotImpl{
 		accountData: accountData{
@@ -0,0 +1,100 @@
+package state
+
+import (
+	"github.com/icon-project/goloop/common/db"
+	"github.com/icon-project/goloop/common/errors"
+	"github.com/icon-project/goloop/common/trie"
+	"github.com/icon-project/goloop/common/trie/trie_manager"
+	"github.com/icon-project/goloop/module"
+)
+
+// AccountDiff is a change of an account between two world states.
+// Old is nil for a new account, and New is nil for a removed account.
+type AccountDiff struct {
+	Op      trie.DiffOp
+	Key     []byte
+	Old     AccountSnapshot
+	New     AccountSnapshot
+	Storage []*StorageDiff
+}
+
+// StorageDiff is a change of an entry in the storage of an account.
+type StorageDiff struct {
+	Op  trie.DiffOp
+	Key []byte
+	Old []byte
+	New []byte
+}
+
+// AccountKeyOf returns the key of the account in the world state. Keys
+// returned by DiffWorldStates can be matched with it.
+func AccountKeyOf(addr module.Address) []byte {
+	return addressIDToKey(addr.ID())
+}
+
+func storeOf(as AccountSnapshot) trie.Immutable {
+	if ass, ok := as.(*accountSnapshotImpl); ok && ass != nil {
+		if store, ok := ass.store.(trie.Immutable); ok {
+			return store
+		}
+	}
+	return nil
+}
+
+func diffStorage(from, to AccountSnapshot) ([]*StorageDiff, error) {
+	s1, s2 := storeOf(from), storeOf(to)
+	if s1 == nil && s2 == nil {
+		return nil, nil
+	}
+	if s1 != nil && s2 != nil && s1.Equal(s2, false) {
+		return nil, nil
+	}
+	itr, err := trie_manager.NewDiffIterator(s1, s2)
+	if err != nil {
+		return nil, err
+	}
+	var diffs []*StorageDiff
+	for ; itr.Has(); itr.Next() {
+		op, key, old, value, err := itr.Get()
+		if err != nil {
+			return nil, err
+		}
+		diffs = append(diffs, &StorageDiff{
+			Op:  op,
+			Key: key,
+			Old: old,
+			New: value,
+		})
+	}
+	return diffs, nil
+}
+
+// DiffWorldStates returns changed accounts with changed storage entries
+// between two world states specified by their state hashes.
+func DiffWorldStates(dbase db.Database, from, to []byte) ([]*AccountDiff, error) {
+	a1 := trie_manager.NewImmutableForObject(dbase, from, AccountType)
+	a2 := trie_manager.NewImmutableForObject(dbase, to, AccountType)
+	itr, err := trie_manager.NewDiffIteratorForObject(a1, a2)
+	if err != nil {
+		return nil, err
+	}
+	diffs := make([]*AccountDiff, 0)
+	for ; itr.Has(); itr.Next() {
+		op, key, o1, o2, err := itr.Get()
+		if err != nil {
+			return nil, errors.Wrapf(err, "FailToDiffAccounts(key=%#x)", key)
+		}
+		diff := &AccountDiff{Op: op, Key: key}
+		if o1 != nil {
+			diff.Old = o1.(AccountSnapshot)
+		}
+		if o2 != nil {
+			diff.New = o2.(AccountSnapshot)
+		}
+		if diff.Storage, err = diffStorage(diff.Old, diff.New); err != nil {
+			return nil, errors.Wrapf(err, "FailToDiffStorage(key=%#x)", key)
+		}
+		diffs = append(diffs, diff)
+	}
+	return diffs, nil
+}
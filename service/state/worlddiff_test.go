@@ -0,0 +1,74 @@
+package state
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/icon-project/goloop/common"
+	"github.com/icon-project/goloop/common/db"
+	"github.com/icon-project/goloop/common/trie"
+)
+
+func TestDiffWorldStates(t *testing.T) {
+	database := db.NewMapDB()
+	addr1 := common.MustNewAddressFromString("hx0000000000000000000000000000000000000001")
+	addr2 := common.MustNewAddressFromString("cx0000000000000000000000000000000000000002")
+	addr3 := common.MustNewAddressFromString("hx0000000000000000000000000000000000000003")
+
+	ws := NewWorldState(database, nil, nil, nil, nil)
+	ws.GetAccountState(addr1.ID()).SetBalance(big.NewInt(100))
+	as2 := ws.GetAccountState(addr2.ID())
+	as2.SetBalance(big.NewInt(10))
+	_, err := as2.SetValue([]byte("k1"), []byte("v1"))
+	assert.NoError(t, err)
+	_, err = as2.SetValue([]byte("k2"), []byte("v2"))
+	assert.NoError(t, err)
+	s1 := ws.GetSnapshot()
+	assert.NoError(t, s1.Flush())
+
+	ws.GetAccountState(addr1.ID()).SetBalance(big.NewInt(90))
+	as2 = ws.GetAccountState(addr2.ID())
+	_, err = as2.SetValue([]byte("k1"), []byte("v1'"))
+	assert.NoError(t, err)
+	_, err = as2.DeleteValue([]byte("k2"))
+	assert.NoError(t, err)
+	ws.GetAccountState(addr3.ID()).SetBalance(big.NewInt(10))
+	s2 := ws.GetSnapshot()
+	assert.NoError(t, s2.Flush())
+
+	diffs, err := DiffWorldStates(database, s1.StateHash(), s2.StateHash())
+	assert.NoError(t, err)
+	assert.Len(t, diffs, 3)
+
+	byKey := make(map[string]*AccountDiff)
+	for _, d := range diffs {
+		byKey[string(d.Key)] = d
+	}
+
+	d1 := byKey[string(AccountKeyOf(addr1))]
+	assert.NotNil(t, d1)
+	assert.Equal(t, trie.DiffModified, d1.Op)
+	assert.Equal(t, int64(100), d1.Old.GetBalance().Int64())
+	assert.Equal(t, int64(90), d1.New.GetBalance().Int64())
+	assert.Empty(t, d1.Storage)
+
+	d2 := byKey[string(AccountKeyOf(addr2))]
+	assert.NotNil(t, d2)
+	assert.Equal(t, trie.DiffModified, d2.Op)
+	assert.Equal(t, []*StorageDiff{
+		{trie.DiffModified, []byte("k1"), []byte("v1"), []byte("v1'")},
+		{trie.DiffRemoved, []byte("k2"), []byte("v2"), nil},
+	}, d2.Storage)
+
+	d3 := byKey[string(AccountKeyOf(addr3))]
+	assert.NotNil(t, d3)
+	assert.Equal(t, trie.DiffAdded, d3.Op)
+	assert.Nil(t, d3.Old)
+	assert.Equal(t, int64(10), d3.New.GetBalance().Int64())
+
+	diffs, err = DiffWorldStates(database, s2.StateHash(), s2.StateHash())
+	assert.NoError(t, err)
+	assert.Empty(t, diffs)
+}
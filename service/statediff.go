@@ -0,0 +1,154 @@
+package service
+
+import (
+	"github.com/icon-project/goloop/common"
+	"github.com/icon-project/goloop/common/intconv"
+	"github.com/icon-project/goloop/module"
+	"github.com/icon-project/goloop/service/state"
+)
+
+// addressResolver maps account keys in the world state to addresses.
+// Account keys are hashes of addresses, so it can only resolve addresses
+// registered to it.
+type addressResolver map[string]module.Address
+
+func (r addressResolver) add(addr module.Address) {
+	if addr != nil {
+		r[string(state.AccountKeyOf(addr))] = addr
+	}
+}
+
+func (r addressResolver) addReceipts(rl module.ReceiptList) error {
+	if rl == nil {
+		return nil
+	}
+	for itr := rl.Iterator(); itr.Has(); itr.Next() {
+		rct, err := itr.Get()
+		if err != nil {
+			return err
+		}
+		r.add(rct.To())
+		r.add(rct.SCOREAddress())
+		for eitr := rct.EventLogIterator(); eitr.Has(); eitr.Next() {
+			ev, err := eitr.Get()
+			if err != nil {
+				return err
+			}
+			r.add(ev.Address())
+		}
+	}
+	return nil
+}
+
+func (r addressResolver) addTransactions(tl module.TransactionList) error {
+	if tl == nil {
+		return nil
+	}
+	for itr := tl.Iterator(); itr.Has(); itr.Next() {
+		tx, _, err := itr.Get()
+		if err != nil {
+			return err
+		}
+		r.add(tx.From())
+	}
+	return nil
+}
+
+func valueChangeToJSON(old, new []byte) map[string]interface{} {
+	jso := make(map[string]interface{})
+	if old != nil {
+		jso["old"] = common.HexBytes(old)
+	}
+	if new != nil {
+		jso["new"] = common.HexBytes(new)
+	}
+	return jso
+}
+
+func accountDiffToJSON(diff *state.AccountDiff, r addressResolver) map[string]interface{} {
+	jso := map[string]interface{}{
+		"key": common.HexBytes(diff.Key),
+		"op":  diff.Op.String(),
+	}
+	if addr, ok := r[string(diff.Key)]; ok {
+		jso["address"] = addr
+	}
+	if diff.Old == nil || diff.New == nil ||
+		diff.Old.GetBalance().Cmp(diff.New.GetBalance()) != 0 {
+		balance := make(map[string]interface{})
+		if diff.Old != nil {
+			balance["old"] = intconv.FormatBigInt(diff.Old.GetBalance())
+		}
+		if diff.New != nil {
+			balance["new"] = intconv.FormatBigInt(diff.New.GetBalance())
+		}
+		jso["balance"] = balance
+	}
+	var o1, o2 []byte
+	if diff.Old != nil {
+		o1 = diff.Old.Bytes()
+	}
+	if diff.New != nil {
+		o2 = diff.New.Bytes()
+	}
+	jso["account"] = valueChangeToJSON(o1, o2)
+
+	storage := make([]interface{}, 0, len(diff.Storage))
+	for _, sd := range diff.Storage {
+		sjso := valueChangeToJSON(sd.Old, sd.New)
+		sjso["key"] = common.HexBytes(sd.Key)
+		sjso["op"] = sd.Op.String()
+		storage = append(storage, sjso)
+	}
+	jso["storage"] = storage
+	return jso
+}
+
+// GetStateDiff returns changes of the world state from the result to
+// the other result in JSON. Addresses of changed accounts are shown only
+// if they are referenced by the transactions or by the receipts of the
+// result.
+func (m *manager) GetStateDiff(from, to []byte, txs ...module.TransactionList) (interface{}, error) {
+	tr1, err := newTransitionResultFromBytes(from)
+	if err != nil {
+		return nil, err
+	}
+	tr2, err := newTransitionResultFromBytes(to)
+	if err != nil {
+		return nil, err
+	}
+	diffs, err := state.DiffWorldStates(m.db, tr1.StateHash, tr2.StateHash)
+	if err != nil {
+		return nil, err
+	}
+
+	r := make(addressResolver)
+	r.add(state.SystemAddress)
+	r.add(state.ZeroAddress)
+	for _, tl := range txs {
+		if err := r.addTransactions(tl); err != nil {
+			return nil, err
+		}
+	}
+	for _, g := range []module.TransactionGroup{
+		module.TransactionGroupPatch, module.TransactionGroupNormal,
+	} {
+		rl, err := m.ReceiptListFromResult(to, g)
+		if err != nil {
+			return nil, err
+		}
+		if err := r.addReceipts(rl); err != nil {
+			return nil, err
+		}
+	}
+
+	accounts := make([]interface{}, 0, len(diffs))
+	for _, diff := range diffs {
+		accounts = append(accounts, accountDiffToJSON(diff, r))
+	}
+	return map[string]interface{}{
+		"from":     common.HexBytes(tr1.StateHash),
+		"to":       common.HexBytes(tr2.StateHash),
+		"accounts": accounts,
+	}, nil
+}
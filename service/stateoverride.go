@@ -0,0 +1,203 @@
+package service
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"math/big"
+	"sort"
+	"strings"
+
+	"github.com/icon-project/goloop/common"
+	"github.com/icon-project/goloop/common/crypto"
+	"github.com/icon-project/goloop/common/intconv"
+	"github.com/icon-project/goloop/module"
+	"github.com/icon-project/goloop/service/contract"
+	"github.com/icon-project/goloop/service/scoredb"
+	"github.com/icon-project/goloop/service/scoreresult"
+	"github.com/icon-project/goloop/service/state"
+)
+
+// stateOverrideCodeLimit is the limit of the total size of codes in
+// overrides.
+const stateOverrideCodeLimit = 512 * 1024
+
+type codeOverride struct {
+	ContentType string          `json:"contentType"`
+	Content     common.HexBytes `json:"content"`
+	Params      json.RawMessage `json:"params,omitempty"`
+	Owner       *common.Address `json:"owner,omitempty"`
+}
+
+type accountOverride struct {
+	Balance *common.HexInt             `json:"balance,omitempty"`
+	Code    *codeOverride              `json:"code,omitempty"`
+	Storage map[string]common.HexBytes `json:"storage,omitempty"`
+}
+
+type storageOverride struct {
+	key   []byte
+	value []byte
+}
+
+type stateOverride struct {
+	addr    module.Address
+	balance *big.Int
+	code    *codeOverride
+	storage []storageOverride
+}
+
+// stateOverrides is the set of changes applied to the world state before
+// running a query or a transaction for simulation. Storage keys are the
+// keys of the account storage (ex. storageWrites in the call tree), and
+// empty values remove them.
+type stateOverrides struct {
+	id       []byte
+	accounts []*stateOverride
+}
+
+func parseStateOverrides(js []byte) (*stateOverrides, error) {
+	var jso map[string]*accountOverride
+	if err := json.Unmarshal(js, &jso); err != nil {
+		return nil, InvalidStateOverrideError.Wrap(err, "FailToParse")
+	}
+	so := &stateOverrides{
+		id:       crypto.SHA3Sum256(js),
+		accounts: make([]*stateOverride, 0, len(jso)),
+	}
+	codeSize := 0
+	for key, ao := range jso {
+		addr, err := common.NewAddressFromString(key)
+		if err != nil {
+			return nil, InvalidStateOverrideError.Wrapf(err, "InvalidAddress(%s)", key)
+		}
+		if ao == nil {
+			continue
+		}
+		o := &stateOverride{addr: addr, code: ao.Code}
+		if ao.Balance != nil {
+			if ao.Balance.Sign() < 0 {
+				return nil, InvalidStateOverrideError.Errorf(
+					"NegativeBalance(addr=%s)", addr)
+			}
+			o.balance = ao.Balance.Value()
+		}
+		if ao.Code != nil {
+			if !addr.IsContract() {
+				return nil, InvalidStateOverrideError.Errorf(
+					"CodeForEOA(addr=%s)", addr)
+			}
+			if _, ok := state.EETypeFromContentType(ao.Code.ContentType); !ok {
+				return nil, InvalidStateOverrideError.Errorf(
+					"InvalidContentType(addr=%s,type=%s)", addr, ao.Code.ContentType)
+			}
+			if len(ao.Code.Content) == 0 {
+				return nil, InvalidStateOverrideError.Errorf(
+					"NoContent(addr=%s)", addr)
+			}
+			if codeSize += len(ao.Code.Content); codeSize > stateOverrideCodeLimit {
+				return nil, InvalidStateOverrideError.Errorf(
+					"CodeSizeOverLimit(limit=%d)", stateOverrideCodeLimit)
+			}
+		}
+		for k, v := range ao.Storage {
+			bk, err := hex.DecodeString(strings.TrimPrefix(k, "0x"))
+			if err != nil || len(bk) == 0 {
+				return nil, InvalidStateOverrideError.Errorf(
+					"InvalidStorageKey(addr=%s,key=%s)", addr, k)
+			}
+			o.storage = append(o.storage, storageOverride{bk, v})
+		}
+		sort.Slice(o.storage, func(i, j int) bool {
+			return string(o.storage[i].key) < string(o.storage[j].key)
+		})
+		so.accounts = append(so.accounts, o)
+	}
+	sort.Slice(so.accounts, func(i, j int) bool {
+		return string(so.accounts[i].addr.Bytes()) < string(so.accounts[j].addr.Bytes())
+	})
+	return so, nil
+}
+
+// installCode deploys the code to the address and calls on_install
+// (or on_update) of it regardless of the deployer white list and the audit.
+// The code is stored by the temporal contract manager of the context, so
+// it's not kept in the contract store.
+func (o *stateOverride) installCode(cc contract.CallContext, deployID []byte) error {
+	tcm, ok := cc.ContractManager().(*contract.TemporalContractManager)
+	if !ok {
+		return InvalidStateOverrideError.Errorf("CodeNotAllowed(addr=%s)", o.addr)
+	}
+	eeType, _ := state.EETypeFromContentType(o.code.ContentType)
+	if eeType == state.WASMEE && !cc.Revision().Has(module.WASMContracts) {
+		return InvalidStateOverrideError.Errorf(
+			"UnsupportedContentType(addr=%s,type=%s)", o.addr, o.code.ContentType)
+	}
+	as := cc.GetAccountState(o.addr.ID())
+	owner := as.ContractOwner()
+	if !as.IsContract() {
+		if o.code.Owner == nil {
+			return InvalidStateOverrideError.Errorf("NoOwner(addr=%s)", o.addr)
+		}
+		owner = o.code.Owner
+		as.InitContractAccount(owner)
+	}
+	if _, err := as.DeployContract(o.code.Content, eeType,
+		o.code.ContentType, o.code.Params, deployID); err != nil {
+		return err
+	}
+	tcm.AddCode(as.NextContract().CodeHash())
+	sysAs := cc.GetAccountState(state.SystemID)
+	h2a := scoredb.NewDictDB(sysAs, state.VarTxHashToAddress, 1)
+	if err := h2a.Set(deployID, o.addr); err != nil {
+		return err
+	}
+	ah := contract.NewAcceptHandler(
+		contract.NewCommonHandler(owner, o.addr, big.NewInt(0), false, cc.Logger()),
+		deployID, deployID)
+	status, _, _, _ := cc.Call(ah, cc.StepAvailable())
+	if status != nil {
+		return scoreresult.Validate(status)
+	}
+	return nil
+}
+
+func (o *stateOverride) apply(cc contract.CallContext, deployID []byte) error {
+	if o.code != nil {
+		if err := o.installCode(cc, deployID); err != nil {
+			return err
+		}
+	}
+	as := cc.GetAccountState(o.addr.ID())
+	if o.balance != nil {
+		as.SetBalance(o.balance)
+	}
+	for _, s := range o.storage {
+		if _, err := as.SetValue(s.key, s.value); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// apply applies overrides to the world state of the context. Codes are
+// installed first, so storage values set by on_install can be overridden.
+func (so *stateOverrides) apply(ctx contract.Context) error {
+	ctx.SetTransactionInfo(&state.TransactionInfo{
+		Group:     module.TransactionGroupNormal,
+		Index:     0,
+		Timestamp: ctx.BlockTimeStamp(),
+		Hash:      so.id,
+		From:      state.SystemAddress,
+	})
+	ctx.UpdateSystemInfo()
+	cc := contract.NewCallContext(ctx, ctx.GetStepLimit(state.StepLimitTypeInvoke), false)
+	defer cc.Dispose()
+	for i, o := range so.accounts {
+		deployID := crypto.SHA3Sum256(
+			append(intconv.Int64ToBytes(int64(i)), so.id...))
+		if err := o.apply(cc, deployID); err != nil {
+			return InvalidStateOverrideError.Wrapf(err, "FailToOverride(addr=%s)", o.addr)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,99 @@
+package service
+
+import (
+	"fmt"
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/icon-project/goloop/common"
+	"github.com/icon-project/goloop/common/db"
+	"github.com/icon-project/goloop/common/log"
+	"github.com/icon-project/goloop/module"
+	"github.com/icon-project/goloop/service/contract"
+	"github.com/icon-project/goloop/service/eeproxy"
+	"github.com/icon-project/goloop/service/state"
+)
+
+type revisionPlatform struct{}
+
+func (p revisionPlatform) ToRevision(value int) module.Revision {
+	return module.LatestRevision
+}
+
+func TestParseStateOverrides(t *testing.T) {
+	cases := []struct {
+		name string
+		js   string
+	}{
+		{"InvalidJSON", `[]`},
+		{"InvalidAddress", `{"xx100":{"balance":"0x1"}}`},
+		{"NegativeBalance", `{"hx100":{"balance":"-0x1"}}`},
+		{"CodeForEOA", `{"hx100":{"code":{"contentType":"application/java","content":"0x01"}}}`},
+		{"InvalidContentType", `{"cx100":{"code":{"contentType":"text/plain","content":"0x01"}}}`},
+		{"NoContent", `{"cx100":{"code":{"contentType":"application/java"}}}`},
+		{"InvalidStorageKey", `{"cx100":{"storage":{"0xzz":"0x01"}}}`},
+		{"EmptyStorageKey", `{"cx100":{"storage":{"0x":"0x01"}}}`},
+		{"CodeSizeOverLimit", fmt.Sprintf(
+			`{"cx100":{"code":{"contentType":"application/java","content":"0x%x"}}}`,
+			make([]byte, stateOverrideCodeLimit+1))},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			_, err := parseStateOverrides([]byte(c.js))
+			assert.True(t, InvalidStateOverrideError.Equals(err), "err=%+v", err)
+		})
+	}
+
+	so, err := parseStateOverrides([]byte(`{
+		"cx200": {"storage": {"0x02": "0x", "0x01": "0x0a"}},
+		"hx100": {"balance": "0x10"},
+		"cx300": null
+	}`))
+	assert.NoError(t, err)
+	assert.Len(t, so.accounts, 2)
+	assert.Equal(t, "hx0000000000000000000000000000000000000100", so.accounts[0].addr.String())
+	assert.Equal(t, big.NewInt(0x10), so.accounts[0].balance)
+	assert.Equal(t, []storageOverride{
+		{[]byte{0x01}, []byte{0x0a}},
+		{[]byte{0x02}, []byte{}},
+	}, so.accounts[1].storage)
+}
+
+func TestStateOverrides_Apply(t *testing.T) {
+	dbase := db.NewMapDB()
+	ws := state.NewWorldState(dbase, nil, nil, nil, nil)
+	eoa := common.MustNewAddressFromString("hx100")
+	score := common.MustNewAddressFromString("cx200")
+
+	as := ws.GetAccountState(score.ID())
+	_, err := as.SetValue([]byte{0x02}, []byte{0x0b})
+	assert.NoError(t, err)
+	_, err = as.SetValue([]byte{0x03}, []byte{0x0c})
+	assert.NoError(t, err)
+	ws.GetAccountState(eoa.ID()).SetBalance(big.NewInt(1))
+
+	so, err := parseStateOverrides([]byte(`{
+		"hx100": {"balance": "0x10"},
+		"cx200": {"storage": {"0x01": "0x0a", "0x02": "0x"}}
+	}`))
+	assert.NoError(t, err)
+
+	bi := common.NewBlockInfo(1, 1)
+	wc := state.NewWorldContext(ws, bi, nil, revisionPlatform{})
+	ctx := contract.NewContext(wc, nil, nil, nil, log.New(), nil, eeproxy.ForQuery)
+	assert.NoError(t, so.apply(ctx))
+
+	ass := ws.GetSnapshot().GetAccountSnapshot(score.ID())
+	v, err := ass.GetValue([]byte{0x01})
+	assert.NoError(t, err)
+	assert.Equal(t, []byte{0x0a}, v)
+	v, err = ass.GetValue([]byte{0x02})
+	assert.NoError(t, err)
+	assert.Nil(t, v)
+	v, err = ass.GetValue([]byte{0x03})
+	assert.NoError(t, err)
+	assert.Equal(t, []byte{0x0c}, v)
+	assert.Equal(t, big.NewInt(0x10), ws.GetAccountState(eoa.ID()).GetBalance())
+}
@@ -0,0 +1,352 @@
+package sync2
+
+import (
+	"bytes"
+	"encoding/binary"
+	"sync"
+	"time"
+
+	"golang.org/x/sync/errgroup"
+
+	"github.com/icon-project/goloop/common/db"
+	"github.com/icon-project/goloop/common/errors"
+	"github.com/icon-project/goloop/common/log"
+	"github.com/icon-project/goloop/common/merkle"
+	"github.com/icon-project/goloop/common/trie/trie_manager"
+	"github.com/icon-project/goloop/service/state"
+)
+
+const (
+	configRangeLimit        = 256
+	configMaxRangeLimit     = 1024
+	configRangeExpiredTime  = 3 * time.Second
+	configRangeRetryLimit   = 8
+	configRangeSegments     = 16
+	configRangePeerWaitTime = 10 * time.Second
+)
+
+// flatSyncer downloads the world state as flat key ranges of the account
+// trie and the storage tries, and it rebuilds the tries locally. Anything
+// it fails to download is left for the node sync, which heals the gaps.
+type flatSyncer struct {
+	mutex  sync.Mutex
+	waiter *sync.Cond
+	logger log.Logger
+
+	database db.Database
+	reactor  *ReactorV3
+
+	readyPool *peerPool
+	busy      int
+	excluded  map[string]bool
+	stopped   bool
+
+	progressCB ProgressCallback
+	received   int
+	positions  []uint64
+}
+
+func (s *flatSyncer) OnPeerJoin(p *peer) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if s.stopped || s.excluded[PeerIDToKey(p.id)] {
+		return
+	}
+	s.readyPool.push(p)
+	s.waiter.Broadcast()
+}
+
+func (s *flatSyncer) OnPeerLeave(p *peer) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	s.readyPool.remove(p.id)
+}
+
+// Stop stops on-going sync. Running requests are finished with
+// ErrInterrupted.
+func (s *flatSyncer) Stop() {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	s.logger.Debugln("Stop() flat syncer")
+	s.stopped = true
+	s.waiter.Broadcast()
+}
+
+func (s *flatSyncer) SetProgressCallback(cb ProgressCallback) {
+	s.progressCB = cb
+}
+
+func (s *flatSyncer) acquirePeer() (*peer, error) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	deadline := time.Now().Add(configRangePeerWaitTime)
+	for {
+		if s.stopped {
+			return nil, errors.ErrInterrupted
+		}
+		if p := s.readyPool.pop(); p != nil {
+			s.busy += 1
+			return p, nil
+		}
+		now := time.Now()
+		if s.busy == 0 && now.After(deadline) {
+			return nil, errors.NotFoundError.New("NoPeersForRange")
+		}
+		timer := time.AfterFunc(deadline.Sub(now), s.waiter.Broadcast)
+		s.waiter.Wait()
+		timer.Stop()
+	}
+}
+
+// releasePeer returns the peer to the pool. Peers returning invalid
+// ranges are excluded until the end of the sync.
+func (s *flatSyncer) releasePeer(p *peer, invalid bool) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	s.busy -= 1
+	if invalid {
+		s.excluded[PeerIDToKey(p.id)] = true
+	} else if !s.stopped {
+		s.readyPool.push(p)
+	}
+	s.waiter.Broadcast()
+}
+
+func (s *flatSyncer) fetchRange(root, start []byte) (keys, values [][]byte, more bool, err error) {
+	for i := 0; i < configRangeRetryLimit; i++ {
+		var p *peer
+		if p, err = s.acquirePeer(); err != nil {
+			return nil, nil, false, err
+		}
+		var res *responseRange
+		var invalid bool
+		res, err = s.reactor.RequestRange(p.id, root, start, configRangeLimit, configRangeExpiredTime)
+		if err == nil {
+			more, err = trie_manager.VerifyRangeProof(root, start, res.Keys, res.Values, res.Proof)
+			invalid = err != nil
+		}
+		s.releasePeer(p, invalid)
+		if err == nil {
+			return res.Keys, res.Values, more, nil
+		}
+		s.logger.Debugf("fetchRange() fail peer=%v root=%#x start=%#x err=%v",
+			p.id, root, start, err)
+	}
+	return nil, nil, false, err
+}
+
+// syncRange downloads entries of the trie whose keys are in [begin, end),
+// and it calls onEntry for each entry in key order. If end is nil, it has
+// no upper bound.
+func (s *flatSyncer) syncRange(root, begin, end []byte, onEntry func(k, v []byte) error) error {
+	start := begin
+	for {
+		keys, values, more, err := s.fetchRange(root, start)
+		if err != nil {
+			return err
+		}
+		for i, k := range keys {
+			if end != nil && bytes.Compare(k, end) >= 0 {
+				return nil
+			}
+			if err := onEntry(k, values[i]); err != nil {
+				return err
+			}
+		}
+		if !more || len(keys) == 0 {
+			return nil
+		}
+		start = append(append([]byte{}, keys[len(keys)-1]...), 0)
+	}
+}
+
+func (s *flatSyncer) hasNode(hash []byte) bool {
+	bk, err := s.database.GetBucket(db.MerkleTrie)
+	if err != nil {
+		return false
+	}
+	v, err := bk.Get(hash)
+	return err == nil && v != nil
+}
+
+// syncTrie downloads all entries of the trie and writes the rebuilt trie.
+func (s *flatSyncer) syncTrie(root []byte) error {
+	if s.hasNode(root) {
+		return nil
+	}
+	tr := trie_manager.NewMutable(s.database, nil)
+	if err := s.syncRange(root, nil, nil, func(k, v []byte) error {
+		_, err := tr.Set(k, v)
+		return err
+	}); err != nil {
+		return err
+	}
+	ss := tr.GetSnapshot()
+	if h := ss.Hash(); !bytes.Equal(h, root) {
+		return errors.InvalidStateError.Errorf("InvalidTrieHash(exp=%#x,calc=%#x)", root, h)
+	}
+	return ss.Flush()
+}
+
+func (s *flatSyncer) syncStorageOf(key, account []byte) {
+	sh, err := state.StorageHashOf(account)
+	if err != nil {
+		s.logger.Warnf("syncStorageOf() fail to decode account key=%#x err=%v", key, err)
+		return
+	}
+	if len(sh) == 0 {
+		return
+	}
+	if err := s.syncTrie(sh); err != nil {
+		s.logger.Debugf("syncStorageOf() leave storage for healing key=%#x root=%#x err=%v",
+			key, sh, err)
+	}
+}
+
+func segmentOf(i int) (begin, end []byte, pos uint64) {
+	if i > 0 {
+		begin = []byte{byte(i * 256 / configRangeSegments)}
+	}
+	if i < configRangeSegments-1 {
+		end = []byte{byte((i + 1) * 256 / configRangeSegments)}
+	}
+	pos = uint64(i) << 32 / configRangeSegments
+	return
+}
+
+func positionOf(key []byte) uint64 {
+	var b [4]byte
+	copy(b[:], key)
+	return uint64(binary.BigEndian.Uint32(b[:]))
+}
+
+// onAccount updates the progress. Keys of accounts are hashes, so the
+// number of remaining accounts is estimated with the positions of the
+// segments in the key space.
+func (s *flatSyncer) onAccount(seg int, key []byte) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	s.received += 1
+	s.positions[seg] = positionOf(key)
+	if s.received%configReportInterval == 0 {
+		s.reportProgressInLock()
+	}
+}
+
+func (s *flatSyncer) reportProgressInLock() {
+	var covered uint64
+	for i, pos := range s.positions {
+		_, _, begin := segmentOf(i)
+		covered += pos - begin
+	}
+	r, u := s.received, 0
+	if covered > 0 {
+		u = int(uint64(r)<<32/covered) - r
+	}
+	s.logger.Debugf("Progress flat received=%d estimated remaining=%d", r, u)
+
+	if s.progressCB == nil {
+		return
+	}
+	if err := s.progressCB(r, u); err != nil {
+		s.progressCB = nil
+	}
+}
+
+// resolveAccounts requests data referenced by accounts, which are not
+// included in the tries, such as contract codes.
+func (s *flatSyncer) resolveAccounts(root []byte, builder merkle.Builder) error {
+	accounts := trie_manager.NewImmutableForObject(s.database, root, state.AccountType)
+	for itr := accounts.Iterator(); itr.Has(); itr.Next() {
+		obj, _, err := itr.Get()
+		if err != nil {
+			return err
+		}
+		if err := obj.Resolve(builder); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// SyncAccounts downloads the account trie with the root and storage tries
+// of the accounts, and rebuilds them in the database of the builder. Then
+// it requests other data referenced by the accounts to the builder. If it
+// fails, nothing of the account trie is written, so the node sync may
+// resolve the account trie from the root.
+func (s *flatSyncer) SyncAccounts(root []byte, builder merkle.Builder) error {
+	if len(root) == 0 || s.hasNode(root) {
+		return nil
+	}
+
+	peers := s.reactor.WatchPeers(s)
+	defer s.reactor.UnwatchPeers(s)
+	s.mutex.Lock()
+	for _, p := range peers {
+		s.readyPool.push(p)
+	}
+	s.mutex.Unlock()
+
+	accounts := trie_manager.NewMutable(s.database, nil)
+	var lock sync.Mutex
+	var egrp errgroup.Group
+	for i := 0; i < configRangeSegments; i++ {
+		seg := i
+		begin, end, _ := segmentOf(seg)
+		egrp.Go(func() error {
+			return s.syncRange(root, begin, end, func(k, v []byte) error {
+				lock.Lock()
+				_, err := accounts.Set(k, v)
+				lock.Unlock()
+				if err != nil {
+					return err
+				}
+				s.syncStorageOf(k, v)
+				s.onAccount(seg, k)
+				return nil
+			})
+		})
+	}
+	if err := egrp.Wait(); err != nil {
+		return err
+	}
+
+	ss := accounts.GetSnapshot()
+	if h := ss.Hash(); !bytes.Equal(h, root) {
+		return errors.InvalidStateError.Errorf("InvalidAccountsHash(exp=%#x,calc=%#x)", root, h)
+	}
+	if err := ss.Flush(); err != nil {
+		return err
+	}
+
+	s.mutex.Lock()
+	for i := range s.positions {
+		s.positions[i] = uint64(i+1) << 32 / configRangeSegments
+	}
+	s.reportProgressInLock()
+	s.mutex.Unlock()
+
+	return s.resolveAccounts(root, builder)
+}
+
+func newFlatSyncer(database db.Database, reactor *ReactorV3, logger log.Logger) *flatSyncer {
+	s := &flatSyncer{
+		logger:    logger,
+		database:  database,
+		reactor:   reactor,
+		readyPool: newPeerPool(),
+		excluded:  make(map[string]bool),
+		positions: make([]uint64, configRangeSegments),
+	}
+	s.waiter = sync.NewCond(&s.mutex)
+	for i := range s.positions {
+		_, _, s.positions[i] = segmentOf(i)
+	}
+	return s
+}
@@ -0,0 +1,108 @@
+package sync2
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/icon-project/goloop/common/db"
+	"github.com/icon-project/goloop/common/log"
+	"github.com/icon-project/goloop/service/state"
+)
+
+func newFlatSyncTestState(t *testing.T, database db.Database, n int) []byte {
+	ws := state.NewWorldState(database, nil, nil, nil, nil)
+	for i := 0; i < n; i++ {
+		id := []byte(fmt.Sprintf("account%d", i))
+		as := ws.GetAccountState(id)
+		for j := 0; j <= i%4; j++ {
+			k := []byte(fmt.Sprintf("key%d", j))
+			_, err := as.SetValue(k, []byte(fmt.Sprintf("value%d.%d", i, j)))
+			assert.NoError(t, err)
+		}
+	}
+	ss := ws.GetSnapshot()
+	assert.NoError(t, ss.Flush())
+	return ss.StateHash()
+}
+
+func TestSyncFlatAccountSync(t *testing.T) {
+	logger := log.New()
+	logger.SetLevel(log.FatalLevel)
+
+	const accounts = 1000
+	srcdb := db.NewMapDB()
+	dstdb := db.NewMapDB()
+	ah := newFlatSyncTestState(t, srcdb, accounts)
+
+	srcNM := newTNetworkManager(createAPeerID())
+	dstNM := newTNetworkManager(createAPeerID())
+	NewSyncManager(srcdb, srcNM, dummyExBuilder, logger)
+	dstMgr := NewSyncManager(dstdb, dstNM, dummyExBuilder, logger)
+	dstMgr.SetFlatSync(true)
+	srcNM.join(dstNM)
+
+	var lastR, lastU int
+	syncer := dstMgr.NewSyncer(ah, nil, nil, nil, nil, nil, true)
+	syncer.SetProgressCallback(func(r, u int) error {
+		lastR, lastU = r, u
+		return nil
+	})
+	result, err := syncer.ForceSync()
+	assert.NoError(t, err)
+	assert.Equal(t, ah, result.Wss.StateHash())
+	assert.Equal(t, accounts, lastR)
+	assert.Equal(t, 0, lastU)
+
+	for i := 0; i < accounts; i++ {
+		as := result.Wss.GetAccountSnapshot([]byte(fmt.Sprintf("account%d", i)))
+		for j := 0; j <= i%4; j++ {
+			v, err := as.GetValue([]byte(fmt.Sprintf("key%d", j)))
+			assert.NoError(t, err)
+			assert.Equal(t, []byte(fmt.Sprintf("value%d.%d", i, j)), v)
+		}
+	}
+	assert.NoError(t, syncer.Finalize())
+}
+
+func TestSyncFlatAccountSyncWithHealing(t *testing.T) {
+	logger := log.New()
+	logger.SetLevel(log.FatalLevel)
+
+	const accounts = 300
+	fulldb := db.NewMapDB()
+	partdb := db.NewMapDB()
+	dstdb := db.NewMapDB()
+	ah := newFlatSyncTestState(t, fulldb, accounts)
+	assert.Equal(t, ah, newFlatSyncTestState(t, partdb, accounts))
+
+	// the peer serving flat ranges misses a storage
+	ws := state.NewWorldSnapshot(partdb, ah, nil, nil, nil)
+	ass := ws.GetAccountSnapshot([]byte("account7"))
+	sh, err := state.StorageHashOf(ass.Bytes())
+	assert.NoError(t, err)
+	assert.NotNil(t, sh)
+	bk, err := partdb.GetBucket(db.MerkleTrie)
+	assert.NoError(t, err)
+	assert.NoError(t, bk.Delete(sh))
+
+	fullNM := newTNetworkManager(createAPeerID())
+	partNM := newTNetworkManager(createAPeerID())
+	dstNM := newTNetworkManager(createAPeerID())
+	newSyncManagerV1(fulldb, fullNM, dummyExBuilder, logger)
+	NewSyncManager(partdb, partNM, dummyExBuilder, logger)
+	dstMgr := NewSyncManager(dstdb, dstNM, dummyExBuilder, logger)
+	dstMgr.SetFlatSync(true)
+	fullNM.join(dstNM)
+	partNM.join(dstNM)
+
+	result, err := dstMgr.NewSyncer(ah, nil, nil, nil, nil, nil, true).ForceSync()
+	assert.NoError(t, err)
+	assert.Equal(t, ah, result.Wss.StateHash())
+
+	as := result.Wss.GetAccountSnapshot([]byte("account7"))
+	v, err := as.GetValue([]byte("key3"))
+	assert.NoError(t, err)
+	assert.Equal(t, []byte("value7.3"), v)
+}
@@ -55,6 +55,8 @@ type Manager struct {
 	plt      Platform
 	ds       *dataSyncer
 	reactors []SyncReactor
+	ranges   *ReactorV3
+	flat     bool
 }
 
 type Result struct {
@@ -65,8 +67,18 @@ type Result struct {
 }
 
 func (m *Manager) NewSyncer(ah, prh, nrh, vh, ed, bh []byte, noBuffer bool) Syncer {
-	return newSyncerWithHashes(
+	s := newSyncerWithHashes(
 		m.db, m.reactors, m.plt, ah, prh, nrh, vh, ed, bh, m.logger, noBuffer)
+	if m.flat {
+		s.ranges = m.ranges
+	}
+	return s
+}
+
+// SetFlatSync enables or disables downloading accounts as flat key ranges
+// for syncers created after it.
+func (m *Manager) SetFlatSync(enable bool) {
+	m.flat = enable
 }
 
 func (m *Manager) AddRequest(id db.BucketID, key []byte) error {
@@ -108,6 +120,17 @@ func NewSyncManager(database db.Database, nm module.NetworkManager, plt Platform
 	reactorV2.ph = ph2
 	m.reactors = append(m.reactors, reactorV2)
 
+	reactorV3 := newReactorV3(database, logger)
+	pi3 := module.NewProtocolInfo(module.ProtoStateSync.ID(), 2)
+	ph3, err := nm.RegisterReactorForStreams("statesync3", pi3, reactorV3, protocolv3, configSyncPriority, module.NotRegisteredProtocolPolicyClose)
+	if err != nil {
+		logger.Panicf("Failed to register reactorV3 for stateSync3")
+		return nil
+	}
+	reactorV3.ph = ph3
+	m.reactors = append(m.reactors, reactorV3)
+	m.ranges = reactorV3
+
 	m.db = database
 	m.plt = plt
 	m.logger = logger
@@ -0,0 +1,45 @@
+package sync2
+
+import (
+	"fmt"
+
+	"github.com/icon-project/goloop/module"
+)
+
+// protocol message codes. It extends protocol v2 with flat key ranges.
+const (
+	protoV3RequestRange module.ProtocolInfo = iota + protoV2Response + 1
+	protoV3ResponseRange
+)
+
+var protocolv3 = []module.ProtocolInfo{
+	protoV2Request,
+	protoV2Response,
+	protoV3RequestRange,
+	protoV3ResponseRange,
+}
+
+type requestRange struct {
+	ReqID uint32
+	Root  []byte
+	Start []byte
+	Limit int
+}
+
+func (r *requestRange) String() string {
+	return fmt.Sprintf("ReqID=%d, Root=%#x, Start=%#x, Limit=%d",
+		r.ReqID, r.Root, r.Start, r.Limit)
+}
+
+type responseRange struct {
+	ReqID  uint32
+	Status errCode
+	Keys   [][]byte
+	Values [][]byte
+	Proof  [][]byte
+}
+
+func (r *responseRange) String() string {
+	return fmt.Sprintf("ReqID=%d, Status=%d, Keys=%d, Proof=%d",
+		r.ReqID, r.Status, len(r.Keys), len(r.Proof))
+}
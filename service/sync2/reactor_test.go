@@ -117,6 +117,7 @@ func TestReactorCommon(t *testing.T) {
 	}{
 		{"V1", args{newReactorV1(dbase, logger)}, 1},
 		{"V2", args{newReactorV2(dbase, logger)}, 2},
+		{"V3", args{newReactorV3(dbase, logger)}, 4},
 	}
 
 	p1 := network.NewPeerIDFromAddress(common.MustNewAddressFromString("hx77"))
@@ -0,0 +1,167 @@
+// Reactor for protocol v3
+
+package sync2
+
+import (
+	"time"
+
+	"github.com/icon-project/goloop/common/codec"
+	"github.com/icon-project/goloop/common/db"
+	"github.com/icon-project/goloop/common/errors"
+	"github.com/icon-project/goloop/common/log"
+	"github.com/icon-project/goloop/common/trie/trie_manager"
+	"github.com/icon-project/goloop/module"
+)
+
+type rangeRequest struct {
+	peer module.PeerID
+	ch   chan *responseRange
+}
+
+// ReactorV3 handles requests of protocol v2, and it serves flat key ranges
+// of tries with range proofs.
+type ReactorV3 struct {
+	ReactorV2
+	reqID    uint32
+	requests map[uint32]*rangeRequest
+}
+
+func (r *ReactorV3) OnReceive(pi module.ProtocolInfo, b []byte, id module.PeerID) (bool, error) {
+	switch pi {
+	case protoV3RequestRange:
+		r.logger.Tracef("OnReceive() pi=%d, peerid=%v", pi, id)
+		go r.onRequestRange(b, id)
+	case protoV3ResponseRange:
+		r.logger.Tracef("OnReceive() pi=%d, peerid=%v", pi, id)
+		go r.onResponseRange(b, id)
+	default:
+		return r.ReactorV2.OnReceive(pi, b, id)
+	}
+	return false, nil
+}
+
+func (r *ReactorV3) _resolveRange(req *requestRange) (errCode, [][]byte, [][]byte, [][]byte) {
+	if len(req.Root) == 0 {
+		return ErrNoData, nil, nil, nil
+	}
+	limit := req.Limit
+	if limit <= 0 || limit > configMaxRangeLimit {
+		limit = configMaxRangeLimit
+	}
+	tr := trie_manager.NewImmutable(r.database, req.Root)
+	keys, values, proof, err := trie_manager.GetRangeProof(tr, req.Start, limit)
+	if err != nil {
+		r.logger.Debugf("_resolveRange() fail to get range root=%#x start=%#x err=%v",
+			req.Root, req.Start, err)
+		return ErrNoData, nil, nil, nil
+	}
+	return NoError, keys, values, proof
+}
+
+func (r *ReactorV3) requestRange(msg []byte, id module.PeerID) *responseRange {
+	req := new(requestRange)
+	if _, err := codec.UnmarshalFromBytes(msg, req); err != nil {
+		r.logger.Infof("Failed to unmarshal error=%+v, len(msg)=%d", err, len(msg))
+		return nil
+	}
+
+	r.logger.Tracef("requestRange() request=%v", req)
+	status, keys, values, proof := r._resolveRange(req)
+	res := &responseRange{req.ReqID, status, keys, values, proof}
+	r.logger.Tracef("requestRange() response=%v peer=%v", res, id)
+	return res
+}
+
+func (r *ReactorV3) onRequestRange(msg []byte, id module.PeerID) {
+	res := r.requestRange(msg, id)
+	if res == nil {
+		return
+	}
+
+	b, err := codec.MarshalToBytes(res)
+	if err != nil {
+		r.logger.Warnf("Failed to marshal for responseRange=%v", res)
+		return
+	}
+	if err = r.ph.Unicast(protoV3ResponseRange, b, id); err != nil {
+		r.logger.Infof("onRequestRange() Failed to send data peer=%v", id)
+	}
+}
+
+func (r *ReactorV3) onResponseRange(msg []byte, id module.PeerID) {
+	res := new(responseRange)
+	if _, err := codec.UnmarshalFromBytes(msg, res); err != nil {
+		r.logger.Infof("Failed onReceive. err=%v", err)
+		return
+	}
+
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	req, ok := r.requests[res.ReqID]
+	if !ok || !req.peer.Equal(id) {
+		r.logger.Debugf("onResponseRange() peer=%v, reqID=%d: unknown request", id, res.ReqID)
+		return
+	}
+	delete(r.requests, res.ReqID)
+	req.ch <- res
+}
+
+// RequestRange requests entries of the trie with the root from the start
+// key to the peer, and waits for the response. The response is not
+// verified yet.
+func (r *ReactorV3) RequestRange(id module.PeerID, root, start []byte, limit int, expired time.Duration) (*responseRange, error) {
+	r.mutex.Lock()
+	reqID := r.reqID
+	r.reqID += 1
+	req := &rangeRequest{
+		peer: id,
+		ch:   make(chan *responseRange, 1),
+	}
+	r.requests[reqID] = req
+	r.mutex.Unlock()
+
+	defer func() {
+		r.mutex.Lock()
+		delete(r.requests, reqID)
+		r.mutex.Unlock()
+	}()
+
+	msg := &requestRange{reqID, root, start, limit}
+	b, _ := codec.MarshalToBytes(msg)
+	r.logger.Tracef("RequestRange() peer=%v request=%v", id, msg)
+	if err := r.ph.Unicast(protoV3RequestRange, b, id); err != nil {
+		return nil, err
+	}
+
+	timer := time.NewTimer(expired)
+	defer timer.Stop()
+	select {
+	case res := <-req.ch:
+		if res.Status != NoError {
+			return nil, errors.NotFoundError.Errorf(
+				"RangeNotAvailable(peer=%v,status=%s)", id, res.Status)
+		}
+		return res, nil
+	case <-timer.C:
+		return nil, errors.TimeoutError.Errorf(
+			"RangeRequestExpired(peer=%v,reqID=%d)", id, reqID)
+	}
+}
+
+func newReactorV3(database db.Database, logger log.Logger) *ReactorV3 {
+	reactor := &ReactorV3{
+		ReactorV2: ReactorV2{
+			ReactorCommon: ReactorCommon{
+				logger:    logger,
+				version:   protoV3,
+				readyPool: newPeerPool(),
+			},
+			database: database,
+		},
+		requests: make(map[uint32]*rangeRequest),
+	}
+	reactor.sender = reactor
+
+	return reactor
+}
@@ -20,7 +20,8 @@ import (
 const (
 	protoV1  byte = 1
 	protoV2  byte = 2
-	protoAny byte = protoV1 | protoV2
+	protoV3  byte = 4
+	protoAny byte = protoV1 | protoV2 | protoV3
 )
 
 type syncer struct {
@@ -32,6 +33,9 @@ type syncer struct {
 	reactors   []SyncReactor
 	processors []SyncProcessor
 	noBuffer   bool
+	ranges     *ReactorV3
+	fs         *flatSyncer
+	flatCount  int
 	progressCB ProgressCallback
 
 	ah  []byte // account hash
@@ -60,6 +64,9 @@ func (s *syncer) getStateBuilder(accountsHash, pReceiptsHash, nReceiptsHash, val
 	s.logger.Debugf("GetStateBuilder ah=%#x, prh=%#x, nrh=%#x, vlh=%#x, ed=%#x",
 		accountsHash, pReceiptsHash, nReceiptsHash, validatorListHash, extensionData)
 	builder := s.newMerkleBuilder()
+	if s.ranges != nil {
+		s.syncAccountsWithRanges(builder, accountsHash)
+	}
 	ess := s.plt.NewExtensionWithBuilder(builder, extensionData)
 
 	if wss, err := state.NewWorldSnapshotWithBuilder(builder, accountsHash, validatorListHash, ess, nil); err == nil {
@@ -72,6 +79,30 @@ func (s *syncer) getStateBuilder(accountsHash, pReceiptsHash, nReceiptsHash, val
 	return builder
 }
 
+// syncAccountsWithRanges downloads accounts as flat key ranges before
+// the node sync. On failure, the node sync resolves the rest of them.
+func (s *syncer) syncAccountsWithRanges(builder merkle.Builder, accountsHash []byte) {
+	s.mutex.Lock()
+	if s.reactors == nil {
+		s.mutex.Unlock()
+		return
+	}
+	fs := newFlatSyncer(builder.Database(), s.ranges, s.logger)
+	fs.SetProgressCallback(s.progressCB)
+	s.fs = fs
+	s.mutex.Unlock()
+
+	defer timeElapsed("FlatSync", s.logger)()
+	if err := fs.SyncAccounts(accountsHash, builder); err != nil {
+		s.logger.Infof("FlatSync fallback to node sync err=%v", err)
+	}
+
+	s.mutex.Lock()
+	s.fs = nil
+	s.flatCount = fs.received
+	s.mutex.Unlock()
+}
+
 func (s *syncer) getBTPBuilder(btpHash []byte) merkle.Builder {
 	s.logger.Debugf("GetBTPBuilder bh=%#x", btpHash)
 	if len(btpHash) == 0 {
@@ -108,7 +139,12 @@ func (s *syncer) newSyncProcessorWithBuilders(
 		return errors.InvalidStateError.Errorf("InvalidState(No Reactors)")
 	}
 
-	progress := newProgressSum(len(stateBuilders)+len(btpBuilders), s.progressCB)
+	count := len(stateBuilders) + len(btpBuilders)
+	progress := newProgressSum(count+1, s.progressCB)
+	if s.flatCount > 0 {
+		// accounts downloaded as flat ranges are counted as resolved
+		_ = progress.onProgress(count, s.flatCount, 0)
+	}
 
 	for _, builder := range stateBuilders {
 		// sync processor with v1,v2 protocol
@@ -120,7 +156,7 @@ func (s *syncer) newSyncProcessorWithBuilders(
 
 	var reactorsV2 []SyncReactor
 	for _, reactor := range s.reactors {
-		if reactor.GetVersion() == protoV2 {
+		if reactor.GetVersion() != protoV1 {
 			reactorsV2 = append(reactorsV2, reactor)
 		}
 	}
@@ -171,6 +207,9 @@ func (s *syncer) Stop() {
 	defer s.mutex.Unlock()
 
 	s.logger.Infof("Stop()")
+	if s.fs != nil {
+		s.fs.Stop()
+	}
 	for _, sp := range s.processors {
 		sp.Stop()
 	}
@@ -209,6 +248,9 @@ func (s *syncer) IsForceSyncing() bool {
 	s.mutex.Lock()
 	defer s.mutex.Unlock()
 
+	if s.fs != nil {
+		return true
+	}
 	for _, sp := range s.processors {
 		if sp.UnresolvedCount() > 0 {
 			return true
@@ -219,7 +261,7 @@ func (s *syncer) IsForceSyncing() bool {
 }
 
 func newSyncerWithHashes(database db.Database, reactors []SyncReactor, plt Platform,
-	ah, prh, nrh, vlh, ed, bh []byte, logger log.Logger, noBuffer bool) Syncer {
+	ah, prh, nrh, vlh, ed, bh []byte, logger log.Logger, noBuffer bool) *syncer {
 	s := &syncer{
 		logger:   logger,
 		database: database,
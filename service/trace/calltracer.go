@@ -0,0 +1,377 @@
+package trace
+
+import (
+	"encoding/hex"
+	"fmt"
+	"math/big"
+	"sort"
+
+	"github.com/icon-project/goloop/common"
+	"github.com/icon-project/goloop/common/errors"
+	"github.com/icon-project/goloop/module"
+	"github.com/icon-project/goloop/service/scoreresult"
+)
+
+type callEvent struct {
+	addr    module.Address
+	indexed [][]byte
+	data    [][]byte
+}
+
+func (e *callEvent) toJSON() map[string]interface{} {
+	return map[string]interface{}{
+		"scoreAddress": e.addr,
+		"indexed":      common.SliceOfHexBytes(e.indexed),
+		"data":         common.SliceOfHexBytes(e.data),
+	}
+}
+
+// keySet keeps keys in the order of the first access.
+type keySet struct {
+	keys  [][]byte
+	index map[string]bool
+}
+
+func (s *keySet) add(key []byte) {
+	if s.index == nil {
+		s.index = make(map[string]bool)
+	}
+	if s.index[string(key)] {
+		return
+	}
+	s.index[string(key)] = true
+	s.keys = append(s.keys, key)
+}
+
+func (s *keySet) toJSON() []string {
+	jso := make([]string, len(s.keys))
+	for i, key := range s.keys {
+		jso[i] = "0x" + hex.EncodeToString(key)
+	}
+	return jso
+}
+
+type callNode struct {
+	parent   *callNode
+	call     *module.TraceCall
+	done     bool
+	status   error
+	stepUsed *big.Int
+	result   interface{}
+	events   []*callEvent
+	reads    keySet
+	writes   keySet
+	calls    []*callNode
+}
+
+func (n *callNode) toJSON() map[string]interface{} {
+	jso := map[string]interface{}{
+		"type": n.call.Type,
+	}
+	if n.call.From != nil {
+		jso["from"] = n.call.From
+	}
+	if n.call.To != nil {
+		jso["to"] = n.call.To
+	}
+	if n.call.Value != nil {
+		jso["value"] = &common.HexInt{Int: *n.call.Value}
+	}
+	if n.call.StepLimit != nil {
+		jso["stepLimit"] = &common.HexInt{Int: *n.call.StepLimit}
+	}
+	if len(n.call.Method) > 0 {
+		jso["method"] = n.call.Method
+	}
+	if n.call.Params != nil {
+		jso["params"] = n.call.Params
+	}
+	if n.stepUsed != nil {
+		jso["stepUsed"] = &common.HexInt{Int: *n.stepUsed}
+	}
+	if !n.done {
+		jso["status"] = "0x0"
+		jso["failure"] = map[string]interface{}{
+			"code":    module.StatusUnknownFailure,
+			"message": "NotFinished",
+		}
+	} else if n.status == nil {
+		jso["status"] = "0x1"
+		if n.result != nil {
+			jso["result"] = n.result
+		}
+	} else {
+		jso["status"] = "0x0"
+		code, _ := scoreresult.StatusOf(n.status)
+		jso["failure"] = map[string]interface{}{
+			"code":    code,
+			"message": n.status.Error(),
+		}
+	}
+	if len(n.events) > 0 {
+		events := make([]interface{}, len(n.events))
+		for i, e := range n.events {
+			events[i] = e.toJSON()
+		}
+		jso["events"] = events
+	}
+	if len(n.reads.keys) > 0 {
+		jso["storageReads"] = n.reads.toJSON()
+	}
+	if len(n.writes.keys) > 0 {
+		jso["storageWrites"] = n.writes.toJSON()
+	}
+	if len(n.calls) > 0 {
+		jso["calls"] = callsToJSON(n.calls)
+	}
+	return jso
+}
+
+func callsToJSON(calls []*callNode) []interface{} {
+	jso := make([]interface{}, len(calls))
+	for i, c := range calls {
+		jso[i] = c.toJSON()
+	}
+	return jso
+}
+
+type callTx struct {
+	index     int
+	hash      []byte
+	isBlockTx bool
+	calls     []*callNode
+}
+
+func (t *callTx) toJSON() map[string]interface{} {
+	prefix := "0x"
+	if t.isBlockTx {
+		prefix = "bx"
+	}
+	return map[string]interface{}{
+		"txIndex": fmt.Sprintf("%#x", t.index),
+		"txHash":  prefix + hex.EncodeToString(t.hash),
+		"calls":   callsToJSON(t.calls),
+	}
+}
+
+// CallTracer builds trees of calls for each transaction with
+// TraceModeCallTree. Events and storage accesses are recorded in the
+// call made them, so they are kept even if the call fails.
+type CallTracer struct {
+	txs     []*callTx
+	current *callNode
+}
+
+func (ct *CallTracer) getCurrentTx() (*callTx, error) {
+	if len(ct.txs) == 0 {
+		return nil, errors.InvalidStateError.New("No transaction")
+	}
+	return ct.txs[len(ct.txs)-1], nil
+}
+
+func (ct *CallTracer) OnTransactionStart(txIndex int, txHash []byte, isBlockTx bool) error {
+	if ct.current != nil {
+		return errors.InvalidStateError.Errorf(
+			"Invalid current call: txIndex=%d txHash=%#x", txIndex, txHash)
+	}
+	ct.txs = append(ct.txs, &callTx{
+		index:     txIndex,
+		hash:      txHash,
+		isBlockTx: isBlockTx,
+	})
+	return nil
+}
+
+func (ct *CallTracer) OnTransactionReset() error {
+	tx, err := ct.getCurrentTx()
+	if err != nil {
+		return err
+	}
+	tx.calls = nil
+	ct.current = nil
+	return nil
+}
+
+func (ct *CallTracer) OnTransactionEnd(txIndex int, txHash []byte) error {
+	tx, err := ct.getCurrentTx()
+	if err != nil {
+		return err
+	}
+	if tx.index != txIndex {
+		return errors.InvalidStateError.Errorf(
+			"Invalid txIndex: curTxIndex=%d txIndex=%d", tx.index, txIndex)
+	}
+	if ct.current != nil {
+		return errors.InvalidStateError.New("Unfinished call")
+	}
+	return nil
+}
+
+func (ct *CallTracer) OnCallStart(call *module.TraceCall) error {
+	tx, err := ct.getCurrentTx()
+	if err != nil {
+		return err
+	}
+	node := &callNode{
+		parent: ct.current,
+		call:   call,
+	}
+	if ct.current != nil {
+		ct.current.calls = append(ct.current.calls, node)
+	} else {
+		tx.calls = append(tx.calls, node)
+	}
+	ct.current = node
+	return nil
+}
+
+func (ct *CallTracer) OnCallEnd(status error, stepUsed *big.Int, result interface{}) error {
+	node := ct.current
+	if node == nil {
+		return errors.InvalidStateError.New("No call")
+	}
+	node.done = true
+	node.status = status
+	node.stepUsed = stepUsed
+	node.result = result
+	ct.current = node.parent
+	return nil
+}
+
+func (ct *CallTracer) OnEvent(addr module.Address, indexed, data [][]byte) error {
+	if ct.current == nil {
+		return errors.InvalidStateError.New("No call")
+	}
+	ct.current.events = append(ct.current.events, &callEvent{
+		addr:    addr,
+		indexed: indexed,
+		data:    data,
+	})
+	return nil
+}
+
+func (ct *CallTracer) OnStorageAccess(write bool, key []byte) error {
+	if ct.current == nil {
+		return errors.InvalidStateError.New("No call")
+	}
+	if write {
+		ct.current.writes.add(key)
+	} else {
+		ct.current.reads.add(key)
+	}
+	return nil
+}
+
+// CallsOf returns the call tree of the transaction in JSON. It returns
+// nil if there is no such transaction.
+func (ct *CallTracer) CallsOf(txIndex int) []interface{} {
+	for _, tx := range ct.txs {
+		if tx.index == txIndex && !tx.isBlockTx {
+			return callsToJSON(tx.calls)
+		}
+	}
+	return nil
+}
+
+type profileEntry struct {
+	addr     module.Address
+	method   string
+	count    int
+	failures int
+	steps    big.Int
+	selfStep big.Int
+}
+
+func (e *profileEntry) toJSON() map[string]interface{} {
+	jso := map[string]interface{}{
+		"scoreAddress": e.addr,
+		"count":        fmt.Sprintf("%#x", e.count),
+		"failures":     fmt.Sprintf("%#x", e.failures),
+		"stepUsed":     &common.HexInt{Int: e.steps},
+		"selfStepUsed": &common.HexInt{Int: e.selfStep},
+	}
+	if len(e.method) > 0 {
+		jso["method"] = e.method
+	}
+	return jso
+}
+
+type stepProfile struct {
+	entries map[string]*profileEntry
+}
+
+// add adds steps of the calls to the contracts, and returns steps used by
+// the calls.
+func (p *stepProfile) add(calls []*callNode) *big.Int {
+	total := new(big.Int)
+	for _, c := range calls {
+		childSteps := p.add(c.calls)
+		if c.stepUsed == nil {
+			continue
+		}
+		total.Add(total, c.stepUsed)
+		if c.call.To == nil || !c.call.To.IsContract() {
+			continue
+		}
+		key := string(c.call.To.Bytes()) + "/" + c.call.Method
+		e, ok := p.entries[key]
+		if !ok {
+			e = &profileEntry{addr: c.call.To, method: c.call.Method}
+			p.entries[key] = e
+		}
+		e.count += 1
+		if c.status != nil {
+			e.failures += 1
+		}
+		e.steps.Add(&e.steps, c.stepUsed)
+		if self := new(big.Int).Sub(c.stepUsed, childSteps); self.Sign() > 0 {
+			e.selfStep.Add(&e.selfStep, self)
+		}
+	}
+	return total
+}
+
+// ProfileToJSON returns steps used by the calls aggregated by contract and
+// method in JSON. stepUsed includes steps used by the calls made by them,
+// and selfStepUsed excludes them. Entries are sorted by selfStepUsed in
+// descending order.
+func (ct *CallTracer) ProfileToJSON() []interface{} {
+	p := &stepProfile{entries: make(map[string]*profileEntry)}
+	for _, tx := range ct.txs {
+		p.add(tx.calls)
+	}
+	entries := make([]*profileEntry, 0, len(p.entries))
+	for _, e := range p.entries {
+		entries = append(entries, e)
+	}
+	sort.Slice(entries, func(i, j int) bool {
+		if c := entries[i].selfStep.Cmp(&entries[j].selfStep); c != 0 {
+			return c > 0
+		}
+		if c := entries[i].steps.Cmp(&entries[j].steps); c != 0 {
+			return c > 0
+		}
+		if a, b := entries[i].addr.String(), entries[j].addr.String(); a != b {
+			return a < b
+		}
+		return entries[i].method < entries[j].method
+	})
+	jso := make([]interface{}, len(entries))
+	for i, e := range entries {
+		jso[i] = e.toJSON()
+	}
+	return jso
+}
+
+// ToJSON returns call trees of all transactions in JSON.
+func (ct *CallTracer) ToJSON() []interface{} {
+	jso := make([]interface{}, 0, len(ct.txs))
+	for _, tx := range ct.txs {
+		jso = append(jso, tx.toJSON())
+	}
+	return jso
+}
+
+func NewCallTracer() *CallTracer {
+	return &CallTracer{}
+}
@@ -0,0 +1,171 @@
+package trace
+
+import (
+	"encoding/json"
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/icon-project/goloop/common"
+	"github.com/icon-project/goloop/module"
+	"github.com/icon-project/goloop/service/scoreresult"
+)
+
+func TestCallTracer_Basic(t *testing.T) {
+	ct := NewCallTracer()
+	eoa := common.MustNewAddressFromString("hx100")
+	score1 := common.MustNewAddressFromString("cx101")
+	score2 := common.MustNewAddressFromString("cx102")
+
+	txHash := newRandomHash(32)
+	assert.NoError(t, ct.OnTransactionStart(1, txHash, false))
+
+	// call from the transaction
+	assert.NoError(t, ct.OnCallStart(&module.TraceCall{
+		Type:      "call",
+		From:      eoa,
+		To:        score1,
+		Value:     big.NewInt(10),
+		StepLimit: big.NewInt(1000),
+		Method:    "transfer",
+		Params:    map[string]interface{}{"_to": "cx102"},
+	}))
+	assert.NoError(t, ct.OnStorageAccess(false, []byte{0x01}))
+	assert.NoError(t, ct.OnStorageAccess(true, []byte{0x01}))
+	assert.NoError(t, ct.OnStorageAccess(false, []byte{0x01}))
+
+	// successful inter-call
+	assert.NoError(t, ct.OnCallStart(&module.TraceCall{
+		Type: "call", From: score1, To: score2, Method: "ok",
+	}))
+	assert.NoError(t, ct.OnEvent(score2, [][]byte{[]byte("Ok()")}, nil))
+	assert.NoError(t, ct.OnCallEnd(nil, big.NewInt(100), "0x1"))
+
+	// failed inter-call
+	assert.NoError(t, ct.OnCallStart(&module.TraceCall{
+		Type: "call", From: score1, To: score2, Method: "fail",
+	}))
+	assert.NoError(t, ct.OnCallEnd(
+		scoreresult.New(module.StatusReverted+3, "Fail"), big.NewInt(50), nil))
+
+	assert.NoError(t, ct.OnCallEnd(nil, big.NewInt(500), nil))
+	assert.NoError(t, ct.OnTransactionEnd(1, txHash))
+
+	assert.Nil(t, ct.CallsOf(0))
+	calls := ct.CallsOf(1)
+	assert.Len(t, calls, 1)
+
+	bs, err := json.Marshal(calls[0])
+	assert.NoError(t, err)
+	var root map[string]interface{}
+	assert.NoError(t, json.Unmarshal(bs, &root))
+	assert.Equal(t, "call", root["type"])
+	assert.Equal(t, "hx0000000000000000000000000000000000000100", root["from"])
+	assert.Equal(t, "transfer", root["method"])
+	assert.Equal(t, "0xa", root["value"])
+	assert.Equal(t, "0x3e8", root["stepLimit"])
+	assert.Equal(t, "0x1f4", root["stepUsed"])
+	assert.Equal(t, "0x1", root["status"])
+	assert.Equal(t, []interface{}{"0x01"}, root["storageReads"])
+	assert.Equal(t, []interface{}{"0x01"}, root["storageWrites"])
+
+	children := root["calls"].([]interface{})
+	assert.Len(t, children, 2)
+	ok := children[0].(map[string]interface{})
+	assert.Equal(t, "0x1", ok["status"])
+	assert.Equal(t, "0x1", ok["result"])
+	assert.Len(t, ok["events"], 1)
+
+	fail := children[1].(map[string]interface{})
+	assert.Equal(t, "0x0", fail["status"])
+	failure := fail["failure"].(map[string]interface{})
+	assert.EqualValues(t, module.StatusReverted+3, failure["code"])
+	assert.Nil(t, fail["result"])
+}
+
+func TestCallTracer_Reset(t *testing.T) {
+	ct := NewCallTracer()
+	eoa := common.MustNewAddressFromString("hx100")
+	score := common.MustNewAddressFromString("cx101")
+	txHash := newRandomHash(32)
+
+	assert.Error(t, ct.OnCallStart(&module.TraceCall{Type: "call"}))
+	assert.NoError(t, ct.OnTransactionStart(0, txHash, false))
+	assert.Error(t, ct.OnEvent(score, nil, nil))
+	assert.Error(t, ct.OnCallEnd(nil, big.NewInt(0), nil))
+
+	assert.NoError(t, ct.OnCallStart(&module.TraceCall{
+		Type: "call", From: eoa, To: score,
+	}))
+	assert.Error(t, ct.OnTransactionEnd(0, txHash))
+
+	// calls before reset are dropped
+	assert.NoError(t, ct.OnTransactionReset())
+	assert.NoError(t, ct.OnCallStart(&module.TraceCall{
+		Type: "transfer", From: eoa, To: eoa,
+	}))
+	assert.NoError(t, ct.OnCallEnd(nil, big.NewInt(0), nil))
+	assert.NoError(t, ct.OnTransactionEnd(0, txHash))
+
+	jso := ct.ToJSON()
+	assert.Len(t, jso, 1)
+	tx := jso[0].(map[string]interface{})
+	calls := tx["calls"].([]interface{})
+	assert.Len(t, calls, 1)
+	assert.Equal(t, "transfer", calls[0].(map[string]interface{})["type"])
+}
+
+func TestCallTracer_Profile(t *testing.T) {
+	ct := NewCallTracer()
+	eoa := common.MustNewAddressFromString("hx100")
+	score1 := common.MustNewAddressFromString("cx101")
+	score2 := common.MustNewAddressFromString("cx102")
+
+	call := func(from, to module.Address, method string) {
+		assert.NoError(t, ct.OnCallStart(&module.TraceCall{
+			Type: "call", From: from, To: to, Method: method,
+		}))
+	}
+
+	for i := 0; i < 2; i++ {
+		txHash := newRandomHash(32)
+		assert.NoError(t, ct.OnTransactionStart(i, txHash, false))
+		call(eoa, score1, "run")
+		call(score1, score2, "get")
+		assert.NoError(t, ct.OnCallEnd(nil, big.NewInt(300), nil))
+		call(score1, eoa, "")
+		assert.NoError(t, ct.OnCallEnd(nil, big.NewInt(100), nil))
+		assert.NoError(t, ct.OnCallEnd(nil, big.NewInt(1000), nil))
+		assert.NoError(t, ct.OnTransactionEnd(i, txHash))
+	}
+	txHash := newRandomHash(32)
+	assert.NoError(t, ct.OnTransactionStart(2, txHash, false))
+	call(eoa, score2, "get")
+	assert.NoError(t, ct.OnCallEnd(
+		scoreresult.New(module.StatusReverted, "Fail"), big.NewInt(50), nil))
+	assert.NoError(t, ct.OnTransactionEnd(2, txHash))
+
+	bs, err := json.Marshal(ct.ProfileToJSON())
+	assert.NoError(t, err)
+	var profile []map[string]interface{}
+	assert.NoError(t, json.Unmarshal(bs, &profile))
+	assert.Equal(t, []map[string]interface{}{
+		{
+			"scoreAddress": "cx0000000000000000000000000000000000000101",
+			"method":       "run",
+			"count":        "0x2",
+			"failures":     "0x0",
+			"stepUsed":     "0x7d0",
+			"selfStepUsed": "0x4b0",
+		},
+		{
+			"scoreAddress": "cx0000000000000000000000000000000000000102",
+			"method":       "get",
+			"count":        "0x3",
+			"failures":     "0x1",
+			"stepUsed":     "0x28a",
+			"selfStepUsed": "0x28a",
+		},
+	}, profile)
+}
@@ -106,7 +106,7 @@ func (l *Logger) OnTransactionStart(txIndex int, txHash []byte) {
 	}
 
 	isBlockTx := txHash == nil
-	if isBlockTx && traceMode == module.TraceModeBalanceChange && l.traceBlock != nil {
+	if isBlockTx && traceMode != module.TraceModeInvoke && l.traceBlock != nil {
 		txHash = l.traceBlock.ID()
 	}
 
@@ -227,6 +227,48 @@ func (l *Logger) OnBalanceChange(opType module.OpType, from, to module.Address,
 	}
 }
 
+func (l *Logger) callTraceCallback() module.CallTraceCallback {
+	if l.traceMode != module.TraceModeCallTree {
+		return nil
+	}
+	cb, _ := l.cb.(module.CallTraceCallback)
+	return cb
+}
+
+func (l *Logger) OnCallStart(call *module.TraceCall) {
+	if cb := l.callTraceCallback(); cb != nil {
+		if err := cb.OnCallStart(call); err != nil {
+			l.Warnf("OnCallStart() error: type=%s from=%s to=%s err=%#v",
+				call.Type, call.From, call.To, err)
+		}
+	}
+}
+
+func (l *Logger) OnCallEnd(status error, stepUsed *big.Int, result interface{}) {
+	if cb := l.callTraceCallback(); cb != nil {
+		if err := cb.OnCallEnd(status, stepUsed, result); err != nil {
+			l.Warnf("OnCallEnd() error: status=%v err=%#v", status, err)
+		}
+	}
+}
+
+func (l *Logger) OnEvent(addr module.Address, indexed, data [][]byte) {
+	if cb := l.callTraceCallback(); cb != nil {
+		if err := cb.OnEvent(addr, indexed, data); err != nil {
+			l.Warnf("OnEvent() error: addr=%s err=%#v", addr, err)
+		}
+	}
+}
+
+func (l *Logger) OnStorageAccess(write bool, key []byte) {
+	if cb := l.callTraceCallback(); cb != nil {
+		if err := cb.OnStorageAccess(write, key); err != nil {
+			l.Warnf("OnStorageAccess() error: write=%t key=%#x err=%#v",
+				write, key, err)
+		}
+	}
+}
+
 func NewLogger(l log.Logger, ti *module.TraceInfo) *Logger {
 	tlog := &Logger{
 		Logger: l,
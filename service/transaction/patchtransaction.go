@@ -31,7 +31,15 @@ func NewPatchTransaction(p module.Patch, nid int, ts int64, w module.Wallet) (Tr
 	tx.Data = js
 
 	// sign
-	sig, err := w.Sign(v3tx.TxHash())
+	var sig []byte
+	if ms, ok := w.(module.MessageSigner); ok {
+		var bs []byte
+		if bs, err = tx.serialize(); err == nil {
+			sig, err = ms.SignMessage(module.SignTransaction, bs)
+		}
+	} else {
+		sig, err = w.Sign(v3tx.TxHash())
+	}
 	if err != nil {
 		return nil, err
 	}
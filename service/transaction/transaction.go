@@ -139,6 +139,35 @@ func Wrap(t Transaction) Transaction {
 	}
 }
 
+type stepLimiter interface {
+	GetStepLimit() *big.Int
+}
+
+// StepLimitOf returns the step limit of the transaction. It returns nil if
+// the transaction has no step limit.
+func StepLimitOf(t module.Transaction) *big.Int {
+	if sl, ok := Unwrap(t).(stepLimiter); ok {
+		return sl.GetStepLimit()
+	}
+	return nil
+}
+
+type dataHolder interface {
+	GetData() []byte
+}
+
+// InputBytesOf returns the number of bytes of the data charged for input
+// steps in the compact JSON form. It returns 0 if the transaction has no
+// data or the data is invalid.
+func InputBytesOf(t module.Transaction) int {
+	if dh, ok := Unwrap(t).(dataHolder); ok {
+		if cnt, err := countBytesOfCompactJSON(dh.GetData()); err == nil {
+			return cnt
+		}
+	}
+	return 0
+}
+
 func Unwrap(t module.Transaction) module.Transaction {
 	if tp, ok := t.(*transaction); ok {
 		return tp.Transaction
@@ -203,6 +203,11 @@ func (tx *transactionV2) Nonce() *big.Int {
 	return nil
 }
 
+// GetStepLimit returns the steps used by the fixed fee.
+func (tx *transactionV2) GetStepLimit() *big.Int {
+	return version2StepUsed
+}
+
 func (tx *transactionV2) To() module.Address {
 	return &tx.transactionV3Data.To
 }
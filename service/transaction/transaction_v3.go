@@ -35,7 +35,15 @@ type transactionV3Data struct {
 }
 
 func (tx *transactionV3Data) calcHash() ([]byte, error) {
-	// sha := sha3.New256()
+	bs, err := tx.serialize()
+	if err != nil {
+		return nil, err
+	}
+	return crypto.SHA3Sum256(bs), nil
+}
+
+// serialize returns the bytes for the hash of the transaction.
+func (tx *transactionV3Data) serialize() ([]byte, error) {
 	sha := bytes.NewBuffer(nil)
 	sha.Write([]byte("icx_sendTransaction"))
 
@@ -99,7 +107,7 @@ func (tx *transactionV3Data) calcHash() ([]byte, error) {
 	sha.Write([]byte(".version."))
 	sha.Write([]byte(tx.Version.String()))
 
-	return crypto.SHA3Sum256(sha.Bytes()), nil
+	return sha.Bytes(), nil
 }
 
 type transactionV3 struct {
@@ -338,6 +346,14 @@ func (tx *transactionV3) Nonce() *big.Int {
 	return nil
 }
 
+func (tx *transactionV3) GetStepLimit() *big.Int {
+	return &tx.transactionV3Data.StepLimit.Int
+}
+
+func (tx *transactionV3) GetData() []byte {
+	return tx.transactionV3Data.Data
+}
+
 func (tx *transactionV3) To() module.Address {
 	return &tx.transactionV3Data.To
 }
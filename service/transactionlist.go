@@ -1,6 +1,7 @@
 package service
 
 import (
+	"sort"
 	"time"
 
 	"github.com/icon-project/goloop/module"
@@ -17,11 +18,23 @@ func indexAndBucketKeyFromKey(k string) (int, string) {
 
 type transactionList struct {
 	size      int
+	bytes     int
 	listFront *txElement
 	listBack  *txElement
 
 	idMap        []map[string]*txElement
 	srcMapToLast []map[string]*txElement
+	srcUsage     map[string]*senderUsage
+
+	// feeOrder has elements in descending order of fee, and in arrival
+	// order for the same fee.
+	seq      uint64
+	feeOrder []*txElement
+}
+
+type senderUsage struct {
+	count int
+	bytes int
 }
 
 type txElement struct {
@@ -29,6 +42,12 @@ type txElement struct {
 	ts    int64
 	err   error
 
+	// fee is the bytes of the data charged for input steps. With the same
+	// step price and step costs, it ranks the fee paid by the transaction
+	// regardless of the result of the execution.
+	fee int
+	seq uint64
+
 	list               *transactionList
 	listNext, listPrev *txElement
 	srcNext, srcPrev   *txElement
@@ -177,7 +196,25 @@ func (l *transactionList) Add(tx transaction.Transaction, ts bool) error {
 		l.listBack = e
 	}
 	e.updateBloom()
+
+	e.fee = transaction.InputBytesOf(tx)
+	e.seq = l.seq
+	l.seq += 1
+	idx := l.feeIndexOf(e)
+	l.feeOrder = append(l.feeOrder, nil)
+	copy(l.feeOrder[idx+1:], l.feeOrder[idx:])
+	l.feeOrder[idx] = e
+
 	l.size += 1
+	l.bytes += len(tx.Bytes())
+
+	usage, ok := l.srcUsage[string(tx.From().ID())]
+	if !ok {
+		usage = new(senderUsage)
+		l.srcUsage[string(tx.From().ID())] = usage
+	}
+	usage.count += 1
+	usage.bytes += len(tx.Bytes())
 	return nil
 }
 
@@ -230,19 +267,67 @@ func (l *transactionList) Remove(t *txElement) bool {
 	tidBk, tidSlot := indexAndBucketKeyFromKey(string(t.value.ID()))
 	delete(l.idMap[tidBk], tidSlot)
 
+	if idx := l.feeIndexOf(t); idx < len(l.feeOrder) && l.feeOrder[idx] == t {
+		copy(l.feeOrder[idx:], l.feeOrder[idx+1:])
+		l.feeOrder[len(l.feeOrder)-1] = nil
+		l.feeOrder = l.feeOrder[:len(l.feeOrder)-1]
+	}
+
+	if usage, ok := l.srcUsage[string(t.value.From().ID())]; ok {
+		usage.count -= 1
+		usage.bytes -= len(t.value.Bytes())
+		if usage.count == 0 {
+			delete(l.srcUsage, string(t.value.From().ID()))
+		}
+	}
+
 	l.size -= 1
+	l.bytes -= len(t.value.Bytes())
 	t.list = nil
 	return true
 }
 
+// feeIndexOf returns the position of the element in feeOrder.
+func (l *transactionList) feeIndexOf(e *txElement) int {
+	return sort.Search(len(l.feeOrder), func(i int) bool {
+		o := l.feeOrder[i]
+		return o.fee < e.fee || (o.fee == e.fee && o.seq >= e.seq)
+	})
+}
+
 func (l *transactionList) Front() *txElement {
 	return l.listFront
 }
 
+// LastOf returns the latest transaction of the sender in timestamp order.
+func (l *transactionList) LastOf(from module.Address) *txElement {
+	uidBk, uidSlot := indexAndBucketKeyFromKey(string(from.ID()))
+	return l.srcMapToLast[uidBk][uidSlot]
+}
+
+// UsageOf returns the number and the total bytes of transactions of the
+// sender.
+func (l *transactionList) UsageOf(from module.Address) (int, int) {
+	if usage, ok := l.srcUsage[string(from.ID())]; ok {
+		return usage.count, usage.bytes
+	}
+	return 0, 0
+}
+
 func (l *transactionList) Len() int {
 	return l.size
 }
 
+// Bytes returns the total bytes of transactions in the list.
+func (l *transactionList) Bytes() int {
+	return l.bytes
+}
+
+// Senders returns the number of senders having transactions in the list.
+func (l *transactionList) Senders() int {
+	return len(l.srcUsage)
+}
+
 func (l *transactionList) HasTx(id []byte) bool {
 	tidBk, tidSlot := indexAndBucketKeyFromKey(string(id))
 	_, ok := l.idMap[tidBk][tidSlot]
@@ -261,6 +346,7 @@ func newTransactionList() *transactionList {
 
 	l.idMap = make([]map[string]*txElement, txBucketCount)
 	l.srcMapToLast = make([]map[string]*txElement, txBucketCount)
+	l.srcUsage = make(map[string]*senderUsage)
 	for i := 0; i < txBucketCount; i++ {
 		l.idMap[i] = make(map[string]*txElement)
 		l.srcMapToLast[i] = make(map[string]*txElement)
@@ -16,6 +16,7 @@ type mockTransaction struct {
 	id        []byte
 	from      module.Address
 	timeStamp int64
+	data      []byte
 }
 
 func (*mockTransaction) Group() module.TransactionGroup {
@@ -66,6 +67,10 @@ func (*mockTransaction) Nonce() *big.Int {
 	panic("implement me")
 }
 
+func (t *mockTransaction) GetData() []byte {
+	return t.data
+}
+
 func (t *mockTransaction) To() module.Address {
 	panic("implement me")
 }
@@ -67,7 +67,13 @@ type TransactionPool struct {
 	size int
 	tim  TXIDManager
 
-	list *transactionList
+	list   *transactionList
+	policy txPoolPolicy
+
+	senderCount int
+	senderBytes int
+
+	watchers []*txPoolWatcher
 
 	mutex sync.Mutex
 
@@ -83,6 +89,7 @@ func NewTransactionPool(group module.TransactionGroup, size int, tim TXIDManager
 		size:    size,
 		tim:     tim,
 		list:    newTransactionList(),
+		policy:  fifoPolicy{},
 		txm:     dummyTxWaiterManager{},
 		monitor: m,
 		pcm:     dummyPoolCapacityMonitor{},
@@ -112,6 +119,7 @@ func (tp *TransactionPool) DropOldTXs(bts int64) {
 			tp.log.Debugf("DROP TX: id=0x%x reason=%v", tx.ID(), iter.err)
 			drops = append(drops, TxDrop{tx.ID(), iter.err})
 			tp.monitor.OnDropTx(len(tx.Bytes()), direct)
+			tp.notifyInLock(TxPoolEventDrop, tx, iter.err)
 		}
 		iter = next
 	}
@@ -147,7 +155,8 @@ func (tp *TransactionPool) Candidate(wc state.WorldContext, maxBytes int, maxCou
 	dropped := make([]*txElement, 0, configDefaultTxSliceCapacity)
 	poolSize := tp.list.Len()
 	txSize := int(0)
-	for e := tp.list.Front(); e != nil && txSize < maxBytes && len(txs) < maxCount; e = e.Next() {
+	next := tp.policy.Iterate(tp.list)
+	for e := next(); e != nil && txSize < maxBytes && len(txs) < maxCount; e = next() {
 		tx := e.Value()
 		if err := tsr.CheckTx(tx); err != nil {
 			if ExpiredTransactionError.Equals(err) {
@@ -216,27 +225,72 @@ func (tp *TransactionPool) CheckTxs(wc state.WorldContext) bool {
 
 /*
 	return nil if tx is nil or tx is added to pool
-	return ErrTransactionPoolOverFlow if pool is full
+	return SenderQuotaExceededError if the sender has too many transactions
+	return ErrTransactionPoolOverFlow if pool is full and the policy
+	ranks tx lowest. Otherwise, the lowest one is evicted for tx.
 */
 func (tp *TransactionPool) Add(tx transaction.Transaction, direct bool) error {
 	if tx == nil {
 		return nil
 	}
-	tp.mutex.Lock()
-	defer tp.mutex.Unlock()
+	lock := common.LockForAutoCall(&tp.mutex)
+	defer lock.Unlock()
+
+	if tp.list.HasTx(tx.ID()) {
+		return ErrDuplicateTransaction
+	}
+	if err := tp.checkSenderQuota(tx); err != nil {
+		return err
+	}
 
+	var drops []TxDrop
 	if tp.list.Len() >= tp.size {
-		return ErrTransactionPoolOverFlow
+		e := tp.policy.Evict(tp.list, tx)
+		if e == nil {
+			return ErrTransactionPoolOverFlow
+		}
+		tp.list.Remove(e)
+		victim := e.Value()
+		e.err = TransactionPoolOverflowError.Errorf(
+			"EvictedByPolicy(policy=%s,tx=%#x)", tp.policy.Name(), tx.ID())
+		tp.log.Debugf("DROP TX: id=0x%x reason=%v", victim.ID(), e.err)
+		drops = append(drops, TxDrop{victim.ID(), e.err})
+		tp.monitor.OnDropTx(len(victim.Bytes()), e.ts != 0)
+		tp.notifyInLock(TxPoolEventDrop, victim, e.err)
 	}
 
 	err := tp.list.Add(tx, direct)
 	if err == nil {
 		tp.monitor.OnAddTx(len(tx.Bytes()), direct)
 		tp.pcm.OnPoolCapacityUpdated(tp.group, tp.size, tp.list.Len())
+		tp.notifyInLock(TxPoolEventAdd, tx, nil)
+	}
+	if len(drops) > 0 {
+		lock.CallAfterUnlock(func() {
+			tp.txm.OnTxDrops(drops)
+		})
 	}
 	return err
 }
 
+func (tp *TransactionPool) checkSenderQuota(tx transaction.Transaction) error {
+	if tp.senderCount <= 0 && tp.senderBytes <= 0 {
+		return nil
+	}
+	count, bytes := tp.list.UsageOf(tx.From())
+	if tp.senderCount > 0 && count+1 > tp.senderCount {
+		return SenderQuotaExceededError.Errorf(
+			"TooManyTransactions(from=%s,count=%d,limit=%d)",
+			tx.From(), count, tp.senderCount)
+	}
+	if tp.senderBytes > 0 && bytes+len(tx.Bytes()) > tp.senderBytes {
+		return SenderQuotaExceededError.Errorf(
+			"TooManyBytes(from=%s,bytes=%d,limit=%d)",
+			tx.From(), bytes, tp.senderBytes)
+	}
+	return nil
+}
+
 // removeList remove transactions when transactions are finalized.
 func (tp *TransactionPool) RemoveList(txs module.TransactionList) {
 	tp.mutex.Lock()
@@ -263,6 +317,7 @@ func (tp *TransactionPool) RemoveList(txs module.TransactionList) {
 				count += 1
 			}
 			tp.monitor.OnRemoveTx(len(t.Bytes()), ts != 0)
+			tp.notifyInLock(TxPoolEventRemove, t, nil)
 		}
 	}
 
@@ -299,6 +354,32 @@ func (tp *TransactionPool) SetTxManager(txm TxWaiterManager) {
 	tp.txm = txm
 }
 
+// SetPolicy sets the policy ordering transactions for candidates and
+// choosing the transaction to be evicted on overflow.
+func (tp *TransactionPool) SetPolicy(name string) error {
+	policy, err := newTxPoolPolicy(name)
+	if err != nil {
+		return err
+	}
+
+	tp.mutex.Lock()
+	defer tp.mutex.Unlock()
+
+	tp.policy = policy
+	return nil
+}
+
+// SetSenderQuota sets limits on the number and the total bytes of
+// transactions of a sender in the pool. Zero or negative value means no
+// limit.
+func (tp *TransactionPool) SetSenderQuota(count, bytes int) {
+	tp.mutex.Lock()
+	defer tp.mutex.Unlock()
+
+	tp.senderCount = count
+	tp.senderBytes = bytes
+}
+
 func (tp *TransactionPool) SetPoolCapacityMonitor(pcm PoolCapacityMonitor) {
 	tp.mutex.Lock()
 	defer tp.mutex.Unlock()
@@ -329,6 +410,7 @@ func (tp *TransactionPool) dropTransactions(txs []*txElement) {
 			tp.log.Debugf("DROP TX: id=0x%x reason=%v", tx.ID(), e.err)
 			drops = append(drops, TxDrop{tx.ID(), e.err})
 			tp.monitor.OnDropTx(len(tx.Bytes()), direct)
+			tp.notifyInLock(TxPoolEventDrop, tx, e.err)
 		}
 	}
 	lock.CallAfterUnlock(func() {
@@ -366,3 +448,78 @@ func (tp *TransactionPool) FilterTransactions(bloom *TxBloom, max int) []module.
 	}
 	return txs
 }
+
+type txPoolWatcher struct {
+	cb func(ev *TxPoolEvent)
+}
+
+// Watch registers the callback to be called whenever a transaction enters
+// or leaves the pool. The callback is called with the lock of the pool, so
+// it must not block. It returns the function to cancel it.
+func (tp *TransactionPool) Watch(cb func(ev *TxPoolEvent)) func() {
+	tp.mutex.Lock()
+	defer tp.mutex.Unlock()
+
+	w := &txPoolWatcher{cb: cb}
+	tp.watchers = append(tp.watchers, w)
+	return func() {
+		tp.mutex.Lock()
+		defer tp.mutex.Unlock()
+
+		for i, w2 := range tp.watchers {
+			if w2 == w {
+				last := len(tp.watchers) - 1
+				tp.watchers[i] = tp.watchers[last]
+				tp.watchers[last] = nil
+				tp.watchers = tp.watchers[:last]
+				break
+			}
+		}
+	}
+}
+
+func (tp *TransactionPool) notifyInLock(typ string, tx module.Transaction, err error) {
+	if len(tp.watchers) == 0 {
+		return
+	}
+	ev := &TxPoolEvent{
+		Type:  typ,
+		Group: tp.group,
+		Tx:    tx,
+		Err:   err,
+	}
+	for _, w := range tp.watchers {
+		w.cb(ev)
+	}
+}
+
+// Pending returns transactions accepted by the filter in the order of the
+// policy.
+func (tp *TransactionPool) Pending(filter func(tx transaction.Transaction) bool) []transaction.Transaction {
+	tp.mutex.Lock()
+	defer tp.mutex.Unlock()
+
+	var txs []transaction.Transaction
+	next := tp.policy.Iterate(tp.list)
+	for e := next(); e != nil; e = next() {
+		if tx := e.Value(); filter == nil || filter(tx) {
+			txs = append(txs, tx)
+		}
+	}
+	return txs
+}
+
+func (tp *TransactionPool) Status() *TxPoolStatus {
+	tp.mutex.Lock()
+	defer tp.mutex.Unlock()
+
+	return &TxPoolStatus{
+		Policy:      tp.policy.Name(),
+		Size:        tp.size,
+		Used:        tp.list.Len(),
+		Bytes:       tp.list.Bytes(),
+		Senders:     tp.list.Senders(),
+		SenderCount: tp.senderCount,
+		SenderBytes: tp.senderBytes,
+	}
+}
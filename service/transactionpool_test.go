@@ -11,6 +11,7 @@ import (
 	"github.com/icon-project/goloop/common/log"
 	"github.com/icon-project/goloop/common/txlocator"
 	"github.com/icon-project/goloop/module"
+	"github.com/icon-project/goloop/service/transaction"
 )
 
 type mockMonitor struct {
@@ -49,3 +50,89 @@ func TestTransactionPool_Add(t *testing.T) {
 		t.Error("Fail to add transaction with valid network ID")
 	}
 }
+
+func TestTransactionPool_SenderQuota(t *testing.T) {
+	dbase := db.NewMapDB()
+	tsc := NewTimestampChecker()
+	logger := log.New()
+	lm, err := txlocator.NewManager(dbase, logger)
+	assert.NoError(t, err)
+	tim, _ := NewTXIDManager(lm, tsc, nil)
+	pool := NewTransactionPool(module.TransactionGroupNormal, 5000, tim, &mockMonitor{}, logger)
+	pool.SetSenderQuota(2, 7)
+
+	addr1 := common.MustNewAddressFromString("hx1111111111111111111111111111111111111111")
+	addr2 := common.MustNewAddressFromString("hx2222222222222222222222222222222222222222")
+	assert.NoError(t, pool.Add(newMockTransaction([]byte("tx1"), addr1, 1), true))
+	assert.NoError(t, pool.Add(newMockTransaction([]byte("tx2"), addr1, 2), true))
+	err = pool.Add(newMockTransaction([]byte("tx3"), addr1, 3), true)
+	assert.True(t, SenderQuotaExceededError.Equals(err))
+
+	assert.NoError(t, pool.Add(newMockTransaction([]byte("tx4"), addr2, 1), true))
+	err = pool.Add(newMockTransaction([]byte("tx5..."), addr2, 2), true)
+	assert.True(t, SenderQuotaExceededError.Equals(err))
+
+	pool.list.RemoveTx(newMockTransaction([]byte("tx1"), addr1, 1))
+	assert.NoError(t, pool.Add(newMockTransaction([]byte("tx3"), addr1, 3), true))
+
+	pool.SetSenderQuota(0, 0)
+	assert.NoError(t, pool.Add(newMockTransaction([]byte("tx5..."), addr2, 2), true))
+	assert.Equal(t, 4, pool.Used())
+}
+
+func TestTransactionPool_Inspection(t *testing.T) {
+	dbase := db.NewMapDB()
+	tsc := NewTimestampChecker()
+	logger := log.New()
+	lm, err := txlocator.NewManager(dbase, logger)
+	assert.NoError(t, err)
+	tim, _ := NewTXIDManager(lm, tsc, nil)
+	pool := NewTransactionPool(module.TransactionGroupNormal, 2, tim, &mockMonitor{}, logger)
+	assert.NoError(t, pool.SetPolicy(TxPoolPolicyFairness))
+
+	var events []*TxPoolEvent
+	cancel := pool.Watch(func(ev *TxPoolEvent) {
+		events = append(events, ev)
+	})
+
+	addr1 := common.MustNewAddressFromString("hx1111111111111111111111111111111111111111")
+	addr2 := common.MustNewAddressFromString("hx2222222222222222222222222222222222222222")
+	assert.NoError(t, pool.Add(newMockTransaction([]byte("tx1"), addr1, 1), true))
+	assert.NoError(t, pool.Add(newMockTransaction([]byte("tx2"), addr1, 2), true))
+	assert.NoError(t, pool.Add(newMockTransaction([]byte("tx3"), addr2, 1), true))
+
+	if assert.Len(t, events, 4) {
+		assert.Equal(t, TxPoolEventAdd, events[0].Type)
+		assert.Equal(t, TxPoolEventAdd, events[1].Type)
+		assert.Equal(t, TxPoolEventDrop, events[2].Type)
+		assert.Equal(t, "tx2", string(events[2].Tx.ID()))
+		assert.True(t, TransactionPoolOverflowError.Equals(events[2].Err))
+		assert.Equal(t, TxPoolEventAdd, events[3].Type)
+		assert.Equal(t, "tx3", string(events[3].Tx.ID()))
+	}
+
+	var ids []string
+	for _, tx := range pool.Pending(nil) {
+		ids = append(ids, string(tx.ID()))
+	}
+	assert.Equal(t, []string{"tx1", "tx3"}, ids)
+
+	txs := pool.Pending(func(tx transaction.Transaction) bool {
+		return addr2.Equal(tx.From())
+	})
+	if assert.Len(t, txs, 1) {
+		assert.Equal(t, "tx3", string(txs[0].ID()))
+	}
+
+	status := pool.Status()
+	assert.Equal(t, TxPoolPolicyFairness, status.Policy)
+	assert.Equal(t, 2, status.Size)
+	assert.Equal(t, 2, status.Used)
+	assert.Equal(t, 6, status.Bytes)
+	assert.Equal(t, 2, status.Senders)
+
+	cancel()
+	pool.list.RemoveTx(newMockTransaction([]byte("tx3"), addr2, 1))
+	assert.NoError(t, pool.Add(newMockTransaction([]byte("tx4"), addr2, 2), true))
+	assert.Len(t, events, 4)
+}
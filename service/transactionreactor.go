@@ -1,6 +1,10 @@
 package service
 
 import (
+	"sync"
+
+	"golang.org/x/time/rate"
+
 	"github.com/icon-project/goloop/common/log"
 	"github.com/icon-project/goloop/module"
 	"github.com/icon-project/goloop/network"
@@ -32,9 +36,42 @@ type TransactionReactor struct {
 	tm         *TransactionManager
 	log        log.Logger
 	ts         *TransactionShare
+
+	rateLock sync.Mutex
+	peerRate int
+	limiters map[string]*rate.Limiter
+}
+
+// SetPeerRate sets the maximum number of transactions per second accepted
+// from a peer. Zero or negative value means no limit.
+func (r *TransactionReactor) SetPeerRate(n int) {
+	r.rateLock.Lock()
+	defer r.rateLock.Unlock()
+
+	r.peerRate = n
+	r.limiters = make(map[string]*rate.Limiter)
+}
+
+func (r *TransactionReactor) allowFrom(id module.PeerID) bool {
+	r.rateLock.Lock()
+	defer r.rateLock.Unlock()
+
+	if r.peerRate <= 0 {
+		return true
+	}
+	l, ok := r.limiters[string(id.Bytes())]
+	if !ok {
+		l = rate.NewLimiter(rate.Limit(r.peerRate), r.peerRate)
+		r.limiters[string(id.Bytes())] = l
+	}
+	return l.Allow()
 }
 
 func (r *TransactionReactor) handleTransactionInBackground(buf []byte, peerId module.PeerID, propagate bool) (bool, error){
+	if !r.allowFrom(peerId) {
+		return false, TransactionRateLimitedError.Errorf(
+			"TooManyTransactions(peer=%s)", peerId)
+	}
 	onResult, err := r.membership.HandleInBackground()
 	if err != network.ErrInProgress {
 		return false, err
@@ -90,6 +127,10 @@ func (r *TransactionReactor) OnJoin(id module.PeerID) {
 
 func (r *TransactionReactor) OnLeave(id module.PeerID) {
 	r.ts.HandleLeave(id)
+
+	r.rateLock.Lock()
+	delete(r.limiters, string(id.Bytes()))
+	r.rateLock.Unlock()
 }
 
 func (r *TransactionReactor) Start(wallet module.Wallet) {
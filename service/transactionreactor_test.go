@@ -0,0 +1,27 @@
+package service
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/icon-project/goloop/common/log"
+	"github.com/icon-project/goloop/network"
+)
+
+func TestTransactionReactor_PeerRate(t *testing.T) {
+	r := &TransactionReactor{log: log.New()}
+	peer1 := network.NewPeerIDFromAddress(mockAddress(1))
+	peer2 := network.NewPeerIDFromAddress(mockAddress(2))
+
+	assert.True(t, r.allowFrom(peer1))
+
+	r.SetPeerRate(2)
+	assert.True(t, r.allowFrom(peer1))
+	assert.True(t, r.allowFrom(peer1))
+	assert.False(t, r.allowFrom(peer1))
+	assert.True(t, r.allowFrom(peer2))
+
+	_, err := r.handleTransactionInBackground(nil, peer1, false)
+	assert.True(t, TransactionRateLimitedError.Equals(err))
+}
@@ -0,0 +1,156 @@
+/*
+ * Copyright 2026 ICON Foundation
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package service
+
+import (
+	"github.com/icon-project/goloop/common/errors"
+	"github.com/icon-project/goloop/common/intconv"
+	"github.com/icon-project/goloop/module"
+	"github.com/icon-project/goloop/service/transaction"
+)
+
+const (
+	TxPoolEventAdd    = "add"
+	TxPoolEventRemove = "remove"
+	TxPoolEventDrop   = "drop"
+)
+
+// TxPoolEvent is notified to watchers when a transaction enters or leaves
+// a transaction pool. Err is the reason for TxPoolEventDrop.
+type TxPoolEvent struct {
+	Type  string
+	Group module.TransactionGroup
+	Tx    module.Transaction
+	Err   error
+}
+
+type TxPoolStatus struct {
+	Policy      string
+	Size        int
+	Used        int
+	Bytes       int
+	Senders     int
+	SenderCount int
+	SenderBytes int
+}
+
+func (s *TxPoolStatus) ToJSON() map[string]interface{} {
+	return map[string]interface{}{
+		"policy":      s.Policy,
+		"size":        intconv.FormatInt(int64(s.Size)),
+		"used":        intconv.FormatInt(int64(s.Used)),
+		"bytes":       intconv.FormatInt(int64(s.Bytes)),
+		"senders":     intconv.FormatInt(int64(s.Senders)),
+		"senderCount": intconv.FormatInt(int64(s.SenderCount)),
+		"senderBytes": intconv.FormatInt(int64(s.SenderBytes)),
+	}
+}
+
+const (
+	TxGroupNamePatch  = "patch"
+	TxGroupNameNormal = "normal"
+)
+
+func TxGroupName(g module.TransactionGroup) string {
+	if g == module.TransactionGroupPatch {
+		return TxGroupNamePatch
+	}
+	return TxGroupNameNormal
+}
+
+// TxGroupsOf returns transaction groups for the name. Empty name is for all
+// groups.
+func TxGroupsOf(name string) ([]module.TransactionGroup, error) {
+	switch name {
+	case "":
+		return []module.TransactionGroup{
+			module.TransactionGroupPatch, module.TransactionGroupNormal,
+		}, nil
+	case TxGroupNamePatch:
+		return []module.TransactionGroup{module.TransactionGroupPatch}, nil
+	case TxGroupNameNormal:
+		return []module.TransactionGroup{module.TransactionGroupNormal}, nil
+	default:
+		return nil, errors.IllegalArgumentError.Errorf("InvalidGroup(%s)", name)
+	}
+}
+
+// GetPendingTransactions returns transactions in the pools of the groups
+// in the order they would be included in blocks. Transactions are filtered
+// by from and to if they are not nil. It skips first skip of them, and
+// returns at most limit of them with the number of all matched ones. A
+// negative limit means no limit.
+func (m *manager) GetPendingTransactions(
+	groups []module.TransactionGroup, from, to module.Address, skip, limit int,
+) (interface{}, error) {
+	filter := func(tx transaction.Transaction) bool {
+		if from != nil && !from.Equal(tx.From()) {
+			return false
+		}
+		if to != nil && !to.Equal(tx.To()) {
+			return false
+		}
+		return true
+	}
+	var txs []transaction.Transaction
+	for _, g := range groups {
+		txs = append(txs, m.tm.getTxPool(g).Pending(filter)...)
+	}
+	total := len(txs)
+	if skip > len(txs) {
+		skip = len(txs)
+	}
+	txs = txs[skip:]
+	if limit >= 0 && limit < len(txs) {
+		txs = txs[:limit]
+	}
+
+	items := make([]interface{}, 0, len(txs))
+	for _, tx := range txs {
+		jso, err := tx.ToJSON(module.JSONVersion3)
+		if err != nil {
+			return nil, err
+		}
+		if obj, ok := jso.(map[string]interface{}); ok {
+			obj["group"] = TxGroupName(tx.Group())
+		}
+		items = append(items, jso)
+	}
+	return map[string]interface{}{
+		"total":        intconv.FormatInt(int64(total)),
+		"transactions": items,
+	}, nil
+}
+
+// GetPoolStatus returns the status of transaction pools by group names.
+func (m *manager) GetPoolStatus() (interface{}, error) {
+	return map[string]interface{}{
+		TxGroupNamePatch:  m.tm.patchTxPool.Status().ToJSON(),
+		TxGroupNameNormal: m.tm.normalTxPool.Status().ToJSON(),
+	}, nil
+}
+
+// WatchTransactionPool registers the callback for all transaction pools.
+// The callback must not block. It returns the function to cancel it.
+func (m *manager) WatchTransactionPool(cb func(ev *TxPoolEvent)) func() {
+	c1 := m.tm.patchTxPool.Watch(cb)
+	c2 := m.tm.normalTxPool.Watch(cb)
+	return func() {
+		c1()
+		c2()
+	}
+}
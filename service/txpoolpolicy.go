@@ -0,0 +1,179 @@
+/*
+ * Copyright 2026 ICON Foundation
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package service
+
+import (
+	"github.com/icon-project/goloop/common/errors"
+	"github.com/icon-project/goloop/service/transaction"
+)
+
+const (
+	TxPoolPolicyFIFO     = "fifo"
+	TxPoolPolicyPriority = "priority"
+	TxPoolPolicyFairness = "fairness"
+)
+
+// txPoolPolicy decides the order of transactions for block candidates,
+// and the transaction to be evicted when the pool is full.
+type txPoolPolicy interface {
+	Name() string
+
+	// Iterate returns a function returning elements of the list in the
+	// order of the policy. The function returns nil at the end.
+	Iterate(l *transactionList) func() *txElement
+
+	// Evict returns the element ranked lower than the new transaction.
+	// It returns nil if the new transaction is ranked lowest.
+	Evict(l *transactionList, tx transaction.Transaction) *txElement
+}
+
+func IsTxPoolPolicy(name string) bool {
+	_, err := newTxPoolPolicy(name)
+	return err == nil
+}
+
+func newTxPoolPolicy(name string) (txPoolPolicy, error) {
+	switch name {
+	case "", TxPoolPolicyFIFO:
+		return fifoPolicy{}, nil
+	case TxPoolPolicyPriority:
+		return priorityPolicy{}, nil
+	case TxPoolPolicyFairness:
+		return fairnessPolicy{}, nil
+	default:
+		return nil, errors.IllegalArgumentError.Errorf("UnknownTxPoolPolicy(%s)", name)
+	}
+}
+
+// fifoPolicy handles transactions in arrival order. The new transaction is
+// always ranked lowest, so it's rejected if the pool is full.
+type fifoPolicy struct{}
+
+func (fifoPolicy) Name() string {
+	return TxPoolPolicyFIFO
+}
+
+func (fifoPolicy) Iterate(l *transactionList) func() *txElement {
+	next := l.Front()
+	return func() *txElement {
+		e := next
+		if e != nil {
+			next = e.Next()
+		}
+		return e
+	}
+}
+
+func (fifoPolicy) Evict(l *transactionList, tx transaction.Transaction) *txElement {
+	return nil
+}
+
+// priorityPolicy handles transactions paying more fee first. Unused steps
+// are refunded, so the step limit isn't what the sender pays. It ranks by
+// the steps charged regardless of the execution, the default steps and the
+// steps for the input data. As the step price and step costs are same for
+// all transactions, it's ranked by the bytes of the data. Transactions with
+// the same fee are handled in arrival order.
+type priorityPolicy struct{}
+
+func (priorityPolicy) Name() string {
+	return TxPoolPolicyPriority
+}
+
+func (priorityPolicy) Iterate(l *transactionList) func() *txElement {
+	idx := 0
+	return func() *txElement {
+		if idx >= len(l.feeOrder) {
+			return nil
+		}
+		e := l.feeOrder[idx]
+		idx += 1
+		return e
+	}
+}
+
+// Evict returns the latest one of the lowest fee if the new transaction
+// pays more.
+func (priorityPolicy) Evict(l *transactionList, tx transaction.Transaction) *txElement {
+	if n := len(l.feeOrder); n > 0 {
+		if victim := l.feeOrder[n-1]; transaction.InputBytesOf(tx) > victim.fee {
+			return victim
+		}
+	}
+	return nil
+}
+
+// fairnessPolicy handles transactions of senders in round-robin, so a
+// sender flooding transactions can't delay transactions of others.
+// Transactions of a sender are handled in timestamp order like nonce.
+type fairnessPolicy struct{}
+
+func (fairnessPolicy) Name() string {
+	return TxPoolPolicyFairness
+}
+
+func (fairnessPolicy) Iterate(l *transactionList) func() *txElement {
+	var heads []*txElement
+	for e := l.Front(); e != nil; e = e.Next() {
+		if e.srcPrev == nil {
+			heads = append(heads, e)
+		}
+	}
+	idx := 0
+	return func() *txElement {
+		if len(heads) == 0 {
+			return nil
+		}
+		if idx >= len(heads) {
+			idx = 0
+		}
+		e := heads[idx]
+		if e.srcNext != nil {
+			heads[idx] = e.srcNext
+			idx += 1
+		} else {
+			heads = append(heads[:idx], heads[idx+1:]...)
+		}
+		return e
+	}
+}
+
+// Evict returns the latest transaction of the sender having the most
+// transactions, as it would be handled last.
+func (fairnessPolicy) Evict(l *transactionList, tx transaction.Transaction) *txElement {
+	var victim *txElement
+	var most int
+	for e := l.listBack; e != nil; e = e.Prev() {
+		if e.srcNext != nil {
+			continue
+		}
+		if cnt, _ := l.UsageOf(e.Value().From()); cnt > most {
+			victim, most = e, cnt
+		}
+	}
+	cnt, _ := l.UsageOf(tx.From())
+	if last := l.LastOf(tx.From()); last != nil && cnt >= most {
+		if last.Value().Timestamp() > tx.Timestamp() {
+			return last
+		}
+		return nil
+	}
+	if most > cnt+1 {
+		return victim
+	}
+	return nil
+}
@@ -0,0 +1,161 @@
+package service
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/icon-project/goloop/common"
+	"github.com/icon-project/goloop/common/db"
+	"github.com/icon-project/goloop/common/log"
+	"github.com/icon-project/goloop/common/txlocator"
+	"github.com/icon-project/goloop/module"
+)
+
+// newMockTransactionWithData returns a transaction with the data of the
+// string of the size, so it pays size+2 bytes for the input.
+func newMockTransactionWithData(id string, from module.Address, ts int64, size int) *mockTransaction {
+	tx := newMockTransaction([]byte(id), from, ts)
+	tx.data = []byte(fmt.Sprintf("%q", strings.Repeat("a", size)))
+	return tx
+}
+
+func idsOf(next func() *txElement) []string {
+	var ids []string
+	for e := next(); e != nil; e = next() {
+		ids = append(ids, string(e.Value().ID()))
+	}
+	return ids
+}
+
+func mockAddress(i int) module.Address {
+	return common.MustNewAddressFromString(fmt.Sprintf("hx%040x", i))
+}
+
+func TestTxPoolPolicy_New(t *testing.T) {
+	for _, name := range []string{"", TxPoolPolicyFIFO, TxPoolPolicyPriority, TxPoolPolicyFairness} {
+		p, err := newTxPoolPolicy(name)
+		assert.NoError(t, err)
+		if name != "" {
+			assert.Equal(t, name, p.Name())
+		}
+		assert.True(t, IsTxPoolPolicy(name))
+	}
+	_, err := newTxPoolPolicy("invalid")
+	assert.Error(t, err)
+	assert.False(t, IsTxPoolPolicy("invalid"))
+}
+
+func TestTxPoolPolicy_FIFO(t *testing.T) {
+	l := newTransactionList()
+	assert.NoError(t, l.Add(newMockTransaction([]byte("tx1"), mockAddress(1), 1), false))
+	assert.NoError(t, l.Add(newMockTransaction([]byte("tx2"), mockAddress(2), 1), false))
+	assert.NoError(t, l.Add(newMockTransaction([]byte("tx3"), mockAddress(1), 2), false))
+
+	p := fifoPolicy{}
+	assert.Equal(t, []string{"tx1", "tx2", "tx3"}, idsOf(p.Iterate(l)))
+	assert.Nil(t, p.Evict(l, newMockTransaction([]byte("tx4"), mockAddress(3), 1)))
+}
+
+func TestTxPoolPolicy_Priority(t *testing.T) {
+	l := newTransactionList()
+	assert.NoError(t, l.Add(newMockTransactionWithData("tx1", mockAddress(1), 1, 10), false))
+	assert.NoError(t, l.Add(newMockTransactionWithData("tx2", mockAddress(2), 1, 30), false))
+	assert.NoError(t, l.Add(newMockTransactionWithData("tx3", mockAddress(3), 1, 20), false))
+	assert.NoError(t, l.Add(newMockTransactionWithData("tx4", mockAddress(4), 1, 10), false))
+	assert.NoError(t, l.Add(newMockTransaction([]byte("tx5"), mockAddress(5), 1), false))
+
+	p := priorityPolicy{}
+	assert.Equal(t, []string{"tx2", "tx3", "tx1", "tx4", "tx5"}, idsOf(p.Iterate(l)))
+
+	// without data, it's ranked lowest
+	assert.Nil(t, p.Evict(l, newMockTransaction([]byte("tx6"), mockAddress(6), 1)))
+	e := p.Evict(l, newMockTransactionWithData("tx6", mockAddress(6), 1, 5))
+	assert.Equal(t, "tx5", string(e.Value().ID()))
+
+	// the order is kept on removal
+	l.Remove(e)
+	ok, _ := l.RemoveTx(newMockTransaction([]byte("tx3"), mockAddress(3), 1))
+	assert.True(t, ok)
+	assert.Equal(t, []string{"tx2", "tx1", "tx4"}, idsOf(p.Iterate(l)))
+
+	// the latest of the lowest fee is evicted only by higher fee
+	assert.Nil(t, p.Evict(l, newMockTransactionWithData("tx6", mockAddress(6), 1, 10)))
+	e = p.Evict(l, newMockTransactionWithData("tx6", mockAddress(6), 1, 11))
+	assert.Equal(t, "tx4", string(e.Value().ID()))
+}
+
+func TestTxPoolPolicy_Fairness(t *testing.T) {
+	l := newTransactionList()
+	for i := 0; i < 4; i++ {
+		id := fmt.Sprintf("a%d", i)
+		assert.NoError(t, l.Add(newMockTransaction([]byte(id), mockAddress(1), int64(4-i)), false))
+	}
+	assert.NoError(t, l.Add(newMockTransaction([]byte("b0"), mockAddress(2), 1), false))
+	assert.NoError(t, l.Add(newMockTransaction([]byte("b1"), mockAddress(2), 2), false))
+	assert.NoError(t, l.Add(newMockTransaction([]byte("c0"), mockAddress(3), 1), false))
+
+	p := fairnessPolicy{}
+	assert.Equal(t,
+		[]string{"a3", "b0", "c0", "a2", "b1", "a1", "a0"},
+		idsOf(p.Iterate(l)))
+
+	// a new sender evicts the latest of the sender with the most
+	e := p.Evict(l, newMockTransaction([]byte("d0"), mockAddress(4), 1))
+	assert.Equal(t, "a0", string(e.Value().ID()))
+
+	// the sender with the most is ranked lowest
+	assert.Nil(t, p.Evict(l, newMockTransaction([]byte("a4"), mockAddress(1), 5)))
+
+	// but an earlier one of the sender with the most evicts its latest
+	e = p.Evict(l, newMockTransaction([]byte("a4"), mockAddress(1), 0))
+	assert.Equal(t, "a0", string(e.Value().ID()))
+
+	// with the same number of transactions, the new one is ranked lowest
+	l2 := newTransactionList()
+	assert.NoError(t, l2.Add(newMockTransaction([]byte("a0"), mockAddress(1), 1), false))
+	assert.NoError(t, l2.Add(newMockTransaction([]byte("b0"), mockAddress(2), 1), false))
+	assert.Nil(t, p.Evict(l2, newMockTransaction([]byte("c0"), mockAddress(3), 1)))
+}
+
+func TestTransactionPool_AddWithEviction(t *testing.T) {
+	dbase := db.NewMapDB()
+	tsc := NewTimestampChecker()
+	logger := log.New()
+	lm, err := txlocator.NewManager(dbase, logger)
+	assert.NoError(t, err)
+	tim, _ := NewTXIDManager(lm, tsc, nil)
+	pool := NewTransactionPool(module.TransactionGroupNormal, 2, tim, &mockMonitor{}, logger)
+
+	tx1 := newMockTransaction([]byte("tx1"), mockAddress(1), 1)
+	tx2 := newMockTransaction([]byte("tx2"), mockAddress(1), 2)
+	tx3 := newMockTransaction([]byte("tx3"), mockAddress(2), 1)
+	assert.NoError(t, pool.Add(tx1, true))
+	assert.NoError(t, pool.Add(tx2, true))
+	err = pool.Add(tx3, true)
+	assert.True(t, TransactionPoolOverflowError.Equals(err))
+
+	assert.Error(t, pool.SetPolicy("invalid"))
+	assert.NoError(t, pool.SetPolicy(TxPoolPolicyFairness))
+	assert.Equal(t, ErrDuplicateTransaction, pool.Add(tx2, true))
+	assert.NoError(t, pool.Add(tx3, true))
+	assert.Equal(t, 2, pool.Used())
+	assert.True(t, pool.HasTx(tx1.ID()))
+	assert.False(t, pool.HasTx(tx2.ID()))
+	assert.True(t, pool.HasTx(tx3.ID()))
+
+	// a cheap transaction can't evict others, but paying more does
+	assert.NoError(t, pool.SetPolicy(TxPoolPolicyPriority))
+	tx4 := newMockTransactionWithData("tx4", mockAddress(3), 1, 10)
+	assert.NoError(t, pool.Add(tx4, true))
+	assert.False(t, pool.HasTx(tx3.ID()))
+	err = pool.Add(newMockTransaction([]byte("tx5"), mockAddress(4), 1), true)
+	assert.True(t, TransactionPoolOverflowError.Equals(err))
+	tx6 := newMockTransactionWithData("tx6", mockAddress(4), 1, 20)
+	assert.NoError(t, pool.Add(tx6, true))
+	assert.False(t, pool.HasTx(tx1.ID()))
+	assert.True(t, pool.HasTx(tx4.ID()))
+	assert.True(t, pool.HasTx(tx6.ID()))
+}
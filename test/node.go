@@ -271,7 +271,7 @@ func (t *Node) NewVoteListForLastBlock() module.CommitVoteSet {
 					t.LastBlock.Votes().VoteRound(),
 					ntd.NetworkTypeSectionHash(),
 				)
-				pp, err := pc.NewProofPart(ntsd.Hash(), t.Chain)
+				pp, err := pc.NewProofPart(ntsd, t.Chain)
 				assert.NoError(t, err)
 				ntsHashEntries = append(ntsHashEntries, module.NTSHashEntryFormat{
 					NetworkTypeID:          ntd.NetworkTypeID(),